@@ -0,0 +1,15 @@
+-- fireball damages whatever it hits and is destroyed on contact
+
+local damage = 50
+
+local fireball = {}
+
+function fireball.on_contact(self, other)
+    if kito.has(other, "HealthComponent") then
+        local health = kito.get(other, "HealthComponent", "Data.Value")
+        kito.set(other, "HealthComponent", "Data.Value", math.max(health - damage, 0))
+    end
+    kito.destroy(self)
+end
+
+return fireball
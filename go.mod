@@ -10,6 +10,7 @@ require (
 	github.com/inkyblackness/imgui-go/v4 v4.5.0
 	github.com/qmuntal/gltf v0.20.2
 	github.com/veandco/go-sdl2 v0.4.18
+	github.com/yuin/gopher-lua v1.1.1
 	google.golang.org/grpc v1.47.0
 	google.golang.org/protobuf v1.27.1
 )
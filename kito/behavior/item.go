@@ -9,29 +9,31 @@ import (
 	"github.com/kkevinchou/kito/lib/logger"
 )
 
+var log = logger.New("behavior")
+
 type PickupItem struct {
 	Entity types.ItemReceiver
 }
 
 func (p *PickupItem) Tick(input any, state behavior.AIState, delta time.Duration) (any, behavior.Status) {
-	logger.Debug("PickupItem - ENTER")
+	log.Debug("PickupItem - ENTER")
 	var item types.Item
 	var ok bool
 
 	if item, ok = input.(types.Item); !ok {
-		logger.Debug("PickupItem - FAIL")
+		log.Debug("PickupItem - FAIL")
 		return nil, behavior.FAILURE
 	}
 
 	itemManager := directory.GetDirectory().ItemManager()
 	err := itemManager.PickUp(p.Entity, item)
 	if err != nil {
-		logger.Debug("PickupItem - FAIL")
+		log.Debug("PickupItem - FAIL")
 		return nil, behavior.FAILURE
 	}
 
 	p.Entity.Give(item)
-	logger.Debug("PickupItem - SUCCESS")
+	log.Debug("PickupItem - SUCCESS")
 	return nil, behavior.SUCCESS
 }
 
@@ -42,24 +44,24 @@ type DropItem struct {
 }
 
 func (d *DropItem) Tick(input any, state behavior.AIState, delta time.Duration) (any, behavior.Status) {
-	logger.Debug("DropItem - ENTER")
+	log.Debug("DropItem - ENTER")
 
 	var item types.Item
 	var ok bool
 
 	if item, ok = input.(types.Item); !ok {
-		logger.Debug("DropItem - FAIL")
+		log.Debug("DropItem - FAIL")
 		return nil, behavior.FAILURE
 	}
 
 	itemManager := directory.GetDirectory().ItemManager()
 	err := itemManager.Drop(d.Entity, item)
 	if err != nil {
-		logger.Debug("DropItem - FAIL")
+		log.Debug("DropItem - FAIL")
 		return nil, behavior.FAILURE
 	}
 
-	logger.Debug("DropItem - SUCCESS")
+	log.Debug("DropItem - SUCCESS")
 	return nil, behavior.SUCCESS
 }
 
@@ -68,11 +70,11 @@ func (d *DropItem) Reset() {}
 type RandomItem struct{}
 
 func (r *RandomItem) Tick(input any, state behavior.AIState, delta time.Duration) (any, behavior.Status) {
-	logger.Debug("RandomItem - ENTER")
+	log.Debug("RandomItem - ENTER")
 	itemManager := directory.GetDirectory().ItemManager()
 	item, err := itemManager.Random()
 	if err != nil {
-		logger.Debug("RandomItem - FAIL")
+		log.Debug("RandomItem - FAIL")
 		return nil, behavior.FAILURE
 	}
 	return item, behavior.SUCCESS
@@ -8,7 +8,6 @@ import (
 	"github.com/kkevinchou/kito/kito/types"
 	"github.com/kkevinchou/kito/lib/behavior"
 	"github.com/kkevinchou/kito/lib/geometry"
-	"github.com/kkevinchou/kito/lib/logger"
 )
 
 type Mover interface {
@@ -23,14 +22,14 @@ type Move struct {
 }
 
 func (m *Move) Tick(input any, state behavior.AIState, delta time.Duration) (any, behavior.Status) {
-	logger.Debug("Move - ENTER")
+	log.Debug("Move - ENTER")
 
 	if m.path == nil {
 		var target mgl64.Vec3
 		var ok bool
 
 		if target, ok = input.(mgl64.Vec3); !ok {
-			logger.Debug("Move - FAIL")
+			log.Debug("Move - FAIL")
 			return nil, behavior.FAILURE
 		}
 
@@ -50,12 +49,12 @@ func (m *Move) Tick(input any, state behavior.AIState, delta time.Duration) (any
 	}
 
 	if m.path == nil {
-		logger.Debug("Move - FAIL")
+		log.Debug("Move - FAIL")
 		return nil, behavior.FAILURE
 	}
 
 	if m.pathIndex == len(m.path) {
-		logger.Debug("Move - SUCCESS")
+		log.Debug("Move - SUCCESS")
 		return nil, behavior.SUCCESS
 	}
 
@@ -67,11 +66,11 @@ func (m *Move) Tick(input any, state behavior.AIState, delta time.Duration) (any
 	}
 
 	if m.pathIndex == len(m.path) {
-		logger.Debug("Move - SUCCESS")
+		log.Debug("Move - SUCCESS")
 		return nil, behavior.SUCCESS
 	}
 
-	logger.Debug("Move - RUNNING")
+	log.Debug("Move - RUNNING")
 	return nil, behavior.RUNNING
 }
 
@@ -0,0 +1,185 @@
+package broadphase
+
+import (
+	"sort"
+
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/kkevinchou/kito/kito/components"
+	"github.com/kkevinchou/kito/kito/entities"
+	"github.com/kkevinchou/kito/lib/collision/aabbtree"
+	"github.com/kkevinchou/kito/lib/collision/collider"
+)
+
+type World interface {
+	QueryEntity(componentFlags ...int) []entities.Entity
+	GetEntityByID(id int) entities.Entity
+}
+
+// Broadphase tracks the bounding boxes of every entity with a collider in a dynamic
+// AABB tree and finds the entities that could be colliding with each other
+type Broadphase struct {
+	world World
+	tree  *aabbtree.Tree
+}
+
+// NewBroadphase creates a broadphase where entities can move up to margin units
+// before the tree needs to be updated
+func NewBroadphase(world World, margin float64) *Broadphase {
+	return &Broadphase{
+		world: world,
+		tree:  aabbtree.New(margin),
+	}
+}
+
+// Sync brings the tree up to date with the entities of the world. New entities are
+// inserted, entities that moved out of their fattened bounds are moved and entities that
+// no longer exist are removed. The cached pairs are then updated for anything that changed
+func (b *Broadphase) Sync() {
+	seen := map[int]bool{}
+	for _, entity := range b.AllCandidates() {
+		boundingBox, ok := entityBoundingBox(entity)
+		if !ok {
+			continue
+		}
+		seen[entity.GetID()] = true
+		b.tree.Update(entity.GetID(), boundingBox)
+	}
+
+	for _, id := range b.tree.IDs() {
+		if !seen[id] {
+			b.tree.Remove(id)
+		}
+	}
+
+	b.tree.UpdatePairs()
+}
+
+// Reinsert replaces the entity's bounds in the tree with its current bounds, for when
+// its collider was replaced. Sync only moves entities that left their fattened bounds,
+// which would keep the bounds of a collider that shrank
+func (b *Broadphase) Reinsert(entity entities.Entity) {
+	b.tree.Remove(entity.GetID())
+	if boundingBox, ok := entityBoundingBox(entity); ok {
+		b.tree.Insert(entity.GetID(), boundingBox)
+	}
+	b.tree.UpdatePairs()
+}
+
+// Pairs returns the pairs of entities whose bounds overlapped as of the last sync and
+// whose collision layers interact, ordered by entity id
+func (b *Broadphase) Pairs() [][]entities.Entity {
+	var pairs [][]entities.Entity
+	for _, pair := range b.tree.Pairs() {
+		e1 := b.world.GetEntityByID(pair.A)
+		e2 := b.world.GetEntityByID(pair.B)
+		if e1 == nil || e2 == nil || !interacts(e1, e2) {
+			continue
+		}
+		pairs = append(pairs, []entities.Entity{e1, e2})
+	}
+	return pairs
+}
+
+// QueryCollisionCandidates returns the entities whose bounds overlap the entity's
+// current bounds and whose collision layers interact with it, excluding the entity itself
+func (b *Broadphase) QueryCollisionCandidates(entity entities.Entity) []entities.Entity {
+	boundingBox, ok := entityBoundingBox(entity)
+	if !ok {
+		return nil
+	}
+
+	var candidates []entities.Entity
+	for _, e := range b.QueryBoundingBox(boundingBox) {
+		if e.GetID() != entity.GetID() && interacts(entity, e) {
+			candidates = append(candidates, e)
+		}
+	}
+	return candidates
+}
+
+func (b *Broadphase) AllCandidates() []entities.Entity {
+	return b.world.QueryEntity(components.ComponentFlagCollider, components.ComponentFlagTransform)
+}
+
+// QueryBoundingBox returns the entities whose bounds overlap the box, ordered by id
+func (b *Broadphase) QueryBoundingBox(boundingBox collider.BoundingBox) []entities.Entity {
+	var ids []int
+	b.tree.QueryBoundingBox(boundingBox, func(id int) bool {
+		ids = append(ids, id)
+		return true
+	})
+	sort.Ints(ids)
+	return b.entities(ids)
+}
+
+// QueryRay returns the entities whose bounds the ray passes through within maxDistance
+// of its origin, ordered from nearest to furthest
+func (b *Broadphase) QueryRay(ray collider.Ray, maxDistance float64) []entities.Entity {
+	directionLength := ray.Direction.Len()
+	if directionLength == 0 {
+		return nil
+	}
+
+	hits := map[int]float64{}
+	b.tree.QueryRay(ray, maxDistance/directionLength, func(id int, t float64) bool {
+		hits[id] = t
+		return true
+	})
+	return b.entities(sortByTime(hits))
+}
+
+// QuerySweep returns the entities whose bounds the box touches while moving by the
+// displacement, ordered by when they're touched
+func (b *Broadphase) QuerySweep(boundingBox collider.BoundingBox, displacement mgl64.Vec3) []entities.Entity {
+	hits := map[int]float64{}
+	b.tree.QuerySweep(boundingBox, displacement, func(id int, t float64) bool {
+		hits[id] = t
+		return true
+	})
+	return b.entities(sortByTime(hits))
+}
+
+// Tree exposes the underlying tree, e.g. for debug rendering
+func (b *Broadphase) Tree() *aabbtree.Tree {
+	return b.tree
+}
+
+func (b *Broadphase) entities(ids []int) []entities.Entity {
+	result := make([]entities.Entity, 0, len(ids))
+	for _, id := range ids {
+		if e := b.world.GetEntityByID(id); e != nil {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+func interacts(e1, e2 entities.Entity) bool {
+	return e1.GetComponentContainer().ColliderComponent.Interacts(e2.GetComponentContainer().ColliderComponent)
+}
+
+func entityBoundingBox(entity entities.Entity) (collider.BoundingBox, bool) {
+	cc := entity.GetComponentContainer()
+	if cc.ColliderComponent == nil || cc.ColliderComponent.BoundingBoxCollider == nil || cc.TransformComponent == nil {
+		return collider.BoundingBox{}, false
+	}
+	boundingBox := cc.ColliderComponent.BoundingBoxCollider
+	if orientation := cc.ColliderOrientation(); orientation != mgl64.QuatIdent() {
+		boundingBox = boundingBox.Rotate(orientation)
+	}
+	return *boundingBox.Transform(cc.TransformComponent.Position), true
+}
+
+func sortByTime(hits map[int]float64) []int {
+	ids := make([]int, 0, len(hits))
+	for id := range hits {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		if hits[ids[i]] != hits[ids[j]] {
+			return hits[ids[i]] < hits[ids[j]]
+		}
+		return ids[i] < ids[j]
+	})
+	return ids
+}
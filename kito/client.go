@@ -6,10 +6,13 @@ import (
 	"github.com/go-gl/gl/v4.1-core/gl"
 	"github.com/inkyblackness/imgui-go/v4"
 	"github.com/kkevinchou/kito/kito/commandframe"
+	"github.com/kkevinchou/kito/kito/components"
+	"github.com/kkevinchou/kito/kito/config"
 	"github.com/kkevinchou/kito/kito/directory"
 	"github.com/kkevinchou/kito/kito/entities"
 	"github.com/kkevinchou/kito/kito/knetwork"
 	"github.com/kkevinchou/kito/kito/managers/player"
+	"github.com/kkevinchou/kito/kito/scheduler"
 	"github.com/kkevinchou/kito/kito/settings"
 	"github.com/kkevinchou/kito/kito/systems/ability"
 	"github.com/kkevinchou/kito/kito/systems/animation"
@@ -19,6 +22,8 @@ import (
 	"github.com/kkevinchou/kito/kito/systems/clientstate"
 	"github.com/kkevinchou/kito/kito/systems/collision"
 	historysys "github.com/kkevinchou/kito/kito/systems/history"
+	"github.com/kkevinchou/kito/kito/systems/hotreload"
+	"github.com/kkevinchou/kito/kito/systems/kinematic"
 	"github.com/kkevinchou/kito/kito/systems/networkdispatch"
 	"github.com/kkevinchou/kito/kito/systems/networkinput"
 	"github.com/kkevinchou/kito/kito/systems/physics"
@@ -29,7 +34,9 @@ import (
 	"github.com/kkevinchou/kito/kito/types"
 	"github.com/kkevinchou/kito/kito/utils/entityutils"
 	"github.com/kkevinchou/kito/lib/assets"
+	"github.com/kkevinchou/kito/lib/console"
 	"github.com/kkevinchou/kito/lib/input"
+	"github.com/kkevinchou/kito/lib/logger"
 	"github.com/kkevinchou/kito/lib/network"
 	"github.com/kkevinchou/kito/lib/shaders"
 	"github.com/veandco/go-sdl2/sdl"
@@ -41,9 +48,10 @@ type Platform interface {
 	FramebufferSize() [2]float32
 }
 
-func NewClientGame(assetsDirectory string, shaderDirectory string) *Game {
+func NewClientGame(assetsDirectory string, shaderDirectory string, configLayers *config.Layers) *Game {
 	initSeed()
 	settings.CurrentGameMode = settings.GameModeClient
+	logger.AddSink(console.GlobalConsole.LogSink(logger.LevelInfo))
 
 	window, err := initializeOpenGL(settings.Width, settings.Height, settings.Fullscreen)
 	if err != nil {
@@ -73,7 +81,7 @@ func NewClientGame(assetsDirectory string, shaderDirectory string) *Game {
 		panic(err)
 	}
 
-	clientSystemSetup(g, window, imguiIO, platform, assetsDirectory, shaderDirectory, settings.RuntimeMaxTextureSize)
+	clientSystemSetup(g, window, imguiIO, platform, assetsDirectory, shaderDirectory, configLayers, settings.RuntimeMaxTextureSize)
 	ackCreatePlayer(g, client)
 
 	initialEntities := clientEntitySetup(g)
@@ -88,7 +96,7 @@ func clientEntitySetup(g *Game) []entities.Entity {
 	return []entities.Entity{}
 }
 
-func clientSystemSetup(g *Game, window *sdl.Window, imguiIO imgui.IO, platform Platform, assetsDirectory, shaderDirectory string, shadowMapDimension int) {
+func clientSystemSetup(g *Game, window *sdl.Window, imguiIO imgui.IO, platform Platform, assetsDirectory, shaderDirectory string, configLayers *config.Layers, shadowMapDimension int) {
 	d := directory.GetDirectory()
 
 	assetManager := assets.NewAssetManager(assetsDirectory, true)
@@ -106,6 +114,7 @@ func clientSystemSetup(g *Game, window *sdl.Window, imguiIO imgui.IO, platform P
 	preframeSystem := preframe.NewPreFrameSystem(g)
 
 	// systems that can manipulate the transform of an entity
+	kinematicSystem := kinematic.NewKinematicSystem(g)
 	characterControllerSystem := charactercontroller.NewCharacterControllerSystem(g)
 	physicsSystem := physics.NewPhysicsSystem(g)
 	collisionSystem := collision.NewCollisionSystem(g)
@@ -116,29 +125,44 @@ func clientSystemSetup(g *Game, window *sdl.Window, imguiIO imgui.IO, platform P
 	pingSystem := ping.NewPingSystem(g)
 	rpcSenderSystem := rpcsender.NewRPCSenderSystem(g)
 	bookKeepingSystem := bookkeeping.NewBookKeepingSystem(g)
+	hotReloadSystem := hotreload.NewHotReloadSystem(g, assetsDirectory, shaderDirectory, configLayers)
 
 	d.RegisterRenderSystem(renderSystem)
 	d.RegisterAssetManager(assetManager)
 	d.RegisterShaderManager(shaderManager)
 	d.RegisterPlayerManager(playerManager)
 
-	g.systems = append(g.systems, []System{
-		cameraSystem,
-		networkInputSystem,
-		networkDispatchSystem,
-		clientStateSystem,
-		preframeSystem,
-		characterControllerSystem,
-		physicsSystem,
-		collisionSystem,
-		abilitySystem,
-		animationSystem,
-		historySystem,
-		pingSystem,
-		renderSystem,
-		rpcSenderSystem,
-		bookKeepingSystem,
-	}...)
+	g.registerSystem(hotReloadSystem, scheduler.PhaseInput, scheduler.WhilePaused())
+	g.registerSystem(cameraSystem, scheduler.PhaseInput)
+	g.registerSystem(networkDispatchSystem, scheduler.PhaseInput, scheduler.WhilePaused())
+	g.registerSystem(clientStateSystem, scheduler.PhaseInput, scheduler.After(networkDispatchSystem.Name()))
+
+	g.registerSystem(kinematicSystem, scheduler.PhaseSimulation, scheduler.Before(preframeSystem.Name()))
+	g.registerSystem(preframeSystem, scheduler.PhaseSimulation, scheduler.Before(characterControllerSystem.Name()))
+	g.registerSystem(characterControllerSystem, scheduler.PhaseSimulation)
+	g.registerSystem(physicsSystem, scheduler.PhaseSimulation, scheduler.After(characterControllerSystem.Name()))
+	g.registerSystem(collisionSystem, scheduler.PhaseSimulation, scheduler.After(physicsSystem.Name()))
+	// abilities spawn entities and touch the singleton's spawn bookkeeping, so they're never
+	// run concurrently with other systems
+	g.registerSystem(abilitySystem, scheduler.PhaseSimulation, scheduler.After(collisionSystem.Name()))
+
+	g.registerSystem(animationSystem, scheduler.PhasePostSimulation,
+		scheduler.Reads(components.ComponentFlagMovement, components.ComponentFlagThirdPersonController, components.ComponentFlagNotepad, components.ComponentFlagAI),
+		scheduler.Writes(components.ComponentFlagAnimation),
+	)
+	g.registerSystem(historySystem, scheduler.PhasePostSimulation)
+	g.registerSystem(renderSystem, scheduler.PhasePostSimulation)
+	g.registerSystem(bookKeepingSystem, scheduler.PhasePostSimulation, scheduler.After(animationSystem.Name(), historySystem.Name()))
+
+	// input is sent after the simulation so that it carries the spawn keys of anything
+	// the client predicted spawning this command frame
+	g.registerSystem(networkInputSystem, scheduler.PhaseNetwork)
+	g.registerSystem(pingSystem, scheduler.PhaseNetwork)
+	g.registerSystem(rpcSenderSystem, scheduler.PhaseNetwork, scheduler.WhilePaused())
+
+	if err := g.scheduler.Build(); err != nil {
+		panic(err)
+	}
 }
 
 func initializeOpenGL(windowWidth, windowHeight int, fullscreen bool) (*sdl.Window, error) {
@@ -174,7 +198,7 @@ func initializeOpenGL(windowWidth, windowHeight int, fullscreen bool) (*sdl.Wind
 		return nil, fmt.Errorf("failed to init OpenGL %s", err)
 	}
 
-	fmt.Println("Open GL Version:", gl.GoStr(gl.GetString(gl.VERSION)))
+	log.Info("initialized OpenGL", logger.F("version", gl.GoStr(gl.GetString(gl.VERSION))))
 
 	return window, nil
 }
@@ -211,22 +235,23 @@ func ackCreatePlayer(g *Game, client *network.Client) {
 		message := client.SyncReceiveMessage()
 		// discard any messages that are not for acking the create player
 		if message.MessageType != network.MessageTypeAckCreatePlayer {
-			fmt.Printf("during ack create player, discarded message %s\n", string(message.Body))
+			log.Debug("discarded message while waiting for the create player ack", logger.F("body", string(message.Body)))
 			continue
 		}
 
 		messageBody = &knetwork.AckCreatePlayerMessage{}
 		err := network.DeserializeBody(message, messageBody)
 		if err != nil {
-			fmt.Println(err)
+			log.Error("failed to deserialize the create player ack", logger.Err(err))
 			return
 		}
 		break
 	}
 
+	g.SetTimeControl(messageBody.TimeControl)
+
 	singleton := g.GetSingleton()
 	singleton.PlayerID = messageBody.PlayerID
-	singleton.CameraID = messageBody.CameraID
 
 	bob := entities.NewBob()
 	bob.ID = messageBody.EntityID
@@ -236,14 +261,17 @@ func ackCreatePlayer(g *Game, client *network.Client) {
 	player := playerManager.GetPlayer(messageBody.PlayerID)
 	player.EntityID = bob.ID
 
+	// the camera only exists on the client so its ID comes from the client namespace
+	// rather than the server's camera for the player
 	camera := entities.NewThirdPersonCamera(settings.CameraStartPosition, settings.CameraStartView, player.ID, player.EntityID)
-	camera.ID = messageBody.CameraID
-	fmt.Println("set camera id", camera.ID)
+	g.RegisterEntity(camera)
+	singleton.CameraID = camera.GetID()
+	log.Debug("set camera id", logger.EntityID(camera.ID))
 
 	tpcComponent := bob.GetComponentContainer().ThirdPersonControllerComponent
 	tpcComponent.CameraID = camera.GetID()
 
-	initialEntities := []entities.Entity{bob, camera}
+	initialEntities := []entities.Entity{bob}
 	for _, snapshot := range messageBody.Entities {
 		entity := entityutils.SpawnWithID(snapshot.ID, types.EntityType(snapshot.Type), snapshot.Position, snapshot.Orientation)
 		initialEntities = append(initialEntities, entity)
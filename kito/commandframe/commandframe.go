@@ -1,13 +1,14 @@
 package commandframe
 
 import (
-	"fmt"
-
 	"github.com/go-gl/mathgl/mgl64"
 	"github.com/kkevinchou/kito/kito/entities"
 	"github.com/kkevinchou/kito/lib/input"
+	"github.com/kkevinchou/kito/lib/logger"
 )
 
+var log = logger.New("commandframe")
+
 type EntityState struct {
 	ID          int
 	Position    mgl64.Vec3
@@ -56,7 +57,7 @@ func (h *CommandFrameHistory) GetCommandFrame(frameNumber int) *CommandFrame {
 		return nil
 	}
 	if frameNumber-startFrameNumber < 0 {
-		fmt.Printf("unexpectedly doing command frame lookup < 0, frame: %d, startFrame %d\n", frameNumber, startFrameNumber)
+		log.Warn("command frame lookup before the start of the history", logger.F("frame", frameNumber), logger.F("start", startFrameNumber))
 		return nil
 	}
 	return &h.CommandFrames[frameNumber-startFrameNumber]
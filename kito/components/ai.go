@@ -1,8 +1,6 @@
 package components
 
 import (
-	"time"
-
 	"github.com/go-gl/mathgl/mgl64"
 	"github.com/kkevinchou/kito/lib/behavior"
 )
@@ -17,7 +15,6 @@ const (
 
 type AIComponent struct {
 	// behaviorTree behavior.BehaviorTree
-	LastUpdate  time.Time
 	MovementDir mgl64.Quat
 	// Velocity    mgl64.Vec3
 
@@ -26,29 +23,8 @@ type AIComponent struct {
 
 func NewAIComponent(behaviorTree behavior.BehaviorTree) *AIComponent {
 	return &AIComponent{
-		LastUpdate:  time.Now(),
 		MovementDir: mgl64.QuatRotate(0, mgl64.Vec3{0, 1, 0}),
 		AIState:     AIStateIdle,
 		// behaviorTree: behaviorTree,
 	}
 }
-
-func (c *AIComponent) AddToComponentContainer(container *ComponentContainer) {
-	container.AIComponent = c
-}
-
-func (c *AIComponent) ComponentFlag() int {
-	return ComponentFlagAI
-}
-
-func (c *AIComponent) Synchronized() bool {
-	return false
-}
-
-func (c *AIComponent) Load(bytes []byte) {
-	panic("wat")
-}
-
-func (c *AIComponent) Serialize() []byte {
-	panic("wat")
-}
@@ -0,0 +1,61 @@
+package components
+
+import "math/bits"
+
+const bitsetWords = (ComponentCount + 63) / 64
+
+// Bitset is a set of component flags. It's sized from the number of declared
+// components so it grows as components are added rather than capping out at the
+// width of an int
+type Bitset [bitsetWords]uint64
+
+func NewBitset(flags ...int) Bitset {
+	var b Bitset
+	for _, flag := range flags {
+		b.Set(flag)
+	}
+	return b
+}
+
+func (b *Bitset) Set(flag int) {
+	b[flag/64] |= 1 << (flag % 64)
+}
+
+func (b *Bitset) Clear(flag int) {
+	b[flag/64] &^= 1 << (flag % 64)
+}
+
+func (b Bitset) Has(flag int) bool {
+	return b[flag/64]&(1<<(flag%64)) != 0
+}
+
+// Contains returns whether every flag in other is also in b
+func (b Bitset) Contains(other Bitset) bool {
+	for i := range b {
+		if b[i]&other[i] != other[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func (b Bitset) Intersects(other Bitset) bool {
+	for i := range b {
+		if b[i]&other[i] != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (b Bitset) Empty() bool {
+	return b == Bitset{}
+}
+
+func (b Bitset) Count() int {
+	count := 0
+	for _, word := range b {
+		count += bits.OnesCount64(word)
+	}
+	return count
+}
@@ -0,0 +1,50 @@
+package components_test
+
+import (
+	"testing"
+
+	"github.com/kkevinchou/kito/kito/components"
+)
+
+func TestBitset(t *testing.T) {
+	b := components.NewBitset(components.ComponentFlagTransform, components.ComponentFlagPhysics)
+	if !b.Has(components.ComponentFlagTransform) || !b.Has(components.ComponentFlagPhysics) {
+		t.Fatal("expected set flags to be in the bitset")
+	}
+	if b.Has(components.ComponentFlagHealth) {
+		t.Fatal("expected unset flag to not be in the bitset")
+	}
+	if b.Count() != 2 {
+		t.Fatalf("expected 2 flags but got %d", b.Count())
+	}
+
+	if !b.Contains(components.NewBitset(components.ComponentFlagPhysics)) {
+		t.Fatal("expected bitset to contain a subset of its flags")
+	}
+	if b.Contains(components.NewBitset(components.ComponentFlagPhysics, components.ComponentFlagHealth)) {
+		t.Fatal("expected bitset to not contain flags it doesn't have")
+	}
+	if !b.Contains(components.Bitset{}) {
+		t.Fatal("expected every bitset to contain the empty bitset")
+	}
+	if !b.Intersects(components.NewBitset(components.ComponentFlagPhysics, components.ComponentFlagHealth)) {
+		t.Fatal("expected bitsets sharing a flag to intersect")
+	}
+
+	b.Clear(components.ComponentFlagTransform)
+	b.Clear(components.ComponentFlagPhysics)
+	if !b.Empty() {
+		t.Fatal("expected cleared bitset to be empty")
+	}
+}
+
+func TestMatchBitFlags(t *testing.T) {
+	cc := components.NewComponentContainer(&components.TransformComponent{}, components.NewHealthComponent(100))
+
+	if !cc.MatchBitFlags(components.NewBitset(components.ComponentFlagTransform, components.ComponentFlagHealth)) {
+		t.Fatal("expected container to match its own components")
+	}
+	if cc.MatchBitFlags(components.NewBitset(components.ComponentFlagTransform, components.ComponentFlagPhysics)) {
+		t.Fatal("expected container to not match a component it doesn't have")
+	}
+}
@@ -1,6 +1,8 @@
 package components
 
 import (
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/kkevinchou/kito/kito/types"
 	"github.com/kkevinchou/kito/lib/collision/collider"
 )
 
@@ -12,32 +14,176 @@ type ColliderComponent struct {
 	// Contacts marks which entities it collided with in the current frame
 	Contacts map[int]bool
 
+	// Layer is the layer the collider is on, CollisionLayerDefault when unset. Mask is the
+	// layers it collides with, every layer when unset. Two colliders only collide when
+	// each is on a layer in the other's mask
+	Layer types.CollisionLayer
+	Mask  types.CollisionLayer
+
+	// TriggerMask is the layers the collider reports overlaps with as trigger events
+	// instead of colliding with them. Trigger colliders never collide with anything and
+	// report overlaps with every layer when TriggerMask is unset
+	Trigger     bool
+	TriggerMask types.CollisionLayer
+
+	// Overlaps marks which entities overlapped the collider in the current frame for the
+	// layers in its TriggerMask
+	Overlaps map[int]bool
+
 	CapsuleCollider     *collider.Capsule
 	TriMeshCollider     *collider.TriMesh
 	BoundingBoxCollider *collider.BoundingBox
+	SphereCollider      *collider.Sphere
+	BoxCollider         *collider.Box
+	ConvexHullCollider  *collider.ConvexHull
 
 	// stores the transformed collider (e.g. if the entity moves)
 	TransformedCapsuleCollider     *collider.Capsule
 	TransformedTriMeshCollider     *collider.TriMesh
 	TransformedBoundingBoxCollider *collider.BoundingBox
+	TransformedSphereCollider      *collider.Sphere
+	TransformedBoxCollider         *collider.Box
+	TransformedConvexHullCollider  *collider.ConvexHull
+
+	// the trimesh and the position and orientation it was last transformed with
+	transformedTriMesh            *collider.TriMesh
+	transformedTriMeshPosition    mgl64.Vec3
+	transformedTriMeshOrientation mgl64.Quat
+}
+
+// TransformedConvexCollider returns the transformed sphere, box or convex hull collider,
+// or nil if the entity has none of them
+func (c *ColliderComponent) TransformedConvexCollider() collider.Convex {
+	if c.TransformedSphereCollider != nil {
+		return *c.TransformedSphereCollider
+	} else if c.TransformedBoxCollider != nil {
+		return *c.TransformedBoxCollider
+	} else if c.TransformedConvexHullCollider != nil {
+		return *c.TransformedConvexHullCollider
+	}
+	return nil
+}
+
+// ConvexCollider returns the transformed collider as a convex shape, capsules included,
+// or nil if there isn't one
+func (c *ColliderComponent) ConvexCollider() collider.Convex {
+	if shape := c.TransformedConvexCollider(); shape != nil {
+		return shape
+	}
+	if c.TransformedCapsuleCollider != nil {
+		return *c.TransformedCapsuleCollider
+	}
+	return nil
+}
+
+// TransformColliders moves the entity's colliders to where the entity is. Rigid and
+// kinematic bodies rotate, so their colliders are rotated with the entity as well
+func (cc *ComponentContainer) TransformColliders() {
+	colliderComponent := cc.ColliderComponent
+	position := cc.TransformComponent.Position
+
+	orientation := cc.ColliderOrientation()
+
+	if colliderComponent.CapsuleCollider != nil {
+		capsule := colliderComponent.CapsuleCollider.Transform(position)
+		colliderComponent.TransformedCapsuleCollider = &capsule
+	} else if colliderComponent.TriMeshCollider != nil {
+		// transforming a trimesh touches every triangle, so it's only redone once the
+		// entity moves or turns, e.g. for kinematic bodies, or its trimesh is replaced
+		if colliderComponent.TransformedTriMeshCollider != nil &&
+			colliderComponent.transformedTriMesh == colliderComponent.TriMeshCollider &&
+			colliderComponent.transformedTriMeshPosition == position &&
+			colliderComponent.transformedTriMeshOrientation == orientation {
+			return
+		}
+		colliderComponent.transformedTriMesh = colliderComponent.TriMeshCollider
+		colliderComponent.transformedTriMeshPosition = position
+		colliderComponent.transformedTriMeshOrientation = orientation
+		transformMatrix := mgl64.Translate3D(position.X(), position.Y(), position.Z()).Mul4(orientation.Mat4())
+		triMesh := colliderComponent.TriMeshCollider.Transform(transformMatrix)
+		colliderComponent.TransformedTriMeshCollider = &triMesh
+	} else if colliderComponent.SphereCollider != nil {
+		sphere := colliderComponent.SphereCollider.Rotate(orientation).Transform(position)
+		colliderComponent.TransformedSphereCollider = &sphere
+	} else if colliderComponent.BoxCollider != nil {
+		box := colliderComponent.BoxCollider.Rotate(orientation).Transform(position)
+		colliderComponent.TransformedBoxCollider = &box
+	} else if colliderComponent.ConvexHullCollider != nil {
+		convexHull := colliderComponent.ConvexHullCollider.Rotate(orientation).Transform(position)
+		colliderComponent.TransformedConvexHullCollider = &convexHull
+	}
+}
+
+// WorldConvexCollider returns the entity's capsule, sphere, box or convex hull collider
+// moved to where the entity is, or nil if it has none. Unlike TransformColliders it
+// doesn't update the transformed colliders
+func (cc *ComponentContainer) WorldConvexCollider() collider.Convex {
+	colliderComponent := cc.ColliderComponent
+	position := cc.TransformComponent.Position
+	orientation := cc.ColliderOrientation()
+
+	if colliderComponent.CapsuleCollider != nil {
+		return colliderComponent.CapsuleCollider.Transform(position)
+	} else if colliderComponent.SphereCollider != nil {
+		return colliderComponent.SphereCollider.Rotate(orientation).Transform(position)
+	} else if colliderComponent.BoxCollider != nil {
+		return colliderComponent.BoxCollider.Rotate(orientation).Transform(position)
+	} else if colliderComponent.ConvexHullCollider != nil {
+		return colliderComponent.ConvexHullCollider.Rotate(orientation).Transform(position)
+	}
+	return nil
+}
+
+// ColliderOrientation is how the entity's colliders are rotated. Only rigid and kinematic
+// bodies rotate their colliders, everything else keeps them upright
+func (cc *ComponentContainer) ColliderOrientation() mgl64.Quat {
+	if cc.PhysicsComponent != nil && (cc.PhysicsComponent.RigidBody != nil || cc.PhysicsComponent.Motion != nil) {
+		return cc.TransformComponent.Orientation
+	}
+	return mgl64.QuatIdent()
+}
+
+// OnLayer returns whether the collider is on any of the layers
+func (c *ColliderComponent) OnLayer(layers types.CollisionLayer) bool {
+	return c.layer()&layers != 0
+}
+
+func (c *ColliderComponent) layer() types.CollisionLayer {
+	if c.Layer == 0 {
+		return types.CollisionLayerDefault
+	}
+	return c.Layer
 }
 
-func (c *ColliderComponent) AddToComponentContainer(container *ComponentContainer) {
-	container.ColliderComponent = c
+func (c *ColliderComponent) mask() types.CollisionLayer {
+	if c.Mask == 0 {
+		return types.CollisionLayerAll
+	}
+	return c.Mask
 }
 
-func (c *ColliderComponent) ComponentFlag() int {
-	return ComponentFlagCollider
+func (c *ColliderComponent) triggerMask() types.CollisionLayer {
+	if c.Trigger && c.TriggerMask == 0 {
+		return types.CollisionLayerAll
+	}
+	return c.TriggerMask
 }
 
-func (c *ColliderComponent) Synchronized() bool {
-	return false
+// Collides returns whether the colliders push each other apart. Overlaps that either
+// collider reports as a trigger are never resolved
+func (c *ColliderComponent) Collides(other *ColliderComponent) bool {
+	if c.Trigger || other.Trigger || c.Triggers(other) || other.Triggers(c) {
+		return false
+	}
+	return c.mask()&other.layer() != 0 && other.mask()&c.layer() != 0
 }
 
-func (c *ColliderComponent) Load(bytes []byte) {
-	panic("wat")
+// Triggers returns whether the collider reports overlapping the other as a trigger event
+func (c *ColliderComponent) Triggers(other *ColliderComponent) bool {
+	return c.triggerMask()&other.layer() != 0
 }
 
-func (c *ColliderComponent) Serialize() []byte {
-	panic("wat")
+// Interacts returns whether the colliders need to be tested against each other at all
+func (c *ColliderComponent) Interacts(other *ColliderComponent) bool {
+	return c.Collides(other) || c.Triggers(other) || other.Triggers(c)
 }
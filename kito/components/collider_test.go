@@ -0,0 +1,98 @@
+package components_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/kkevinchou/kito/kito/components"
+	"github.com/kkevinchou/kito/kito/types"
+	"github.com/kkevinchou/kito/lib/collision/collider"
+	"github.com/kkevinchou/kito/lib/physics"
+)
+
+func TestCollisionLayers(t *testing.T) {
+	unset := &components.ColliderComponent{}
+	character := &components.ColliderComponent{Layer: types.CollisionLayerCharacter}
+	terrain := &components.ColliderComponent{Layer: types.CollisionLayerTerrain}
+	projectile := &components.ColliderComponent{
+		Layer: types.CollisionLayerProjectile,
+		Mask:  types.CollisionLayerTerrain | types.CollisionLayerCharacter,
+	}
+	loot := &components.ColliderComponent{
+		Layer:       types.CollisionLayerLoot,
+		Mask:        types.CollisionLayerTerrain,
+		TriggerMask: types.CollisionLayerCharacter,
+	}
+	zone := &components.ColliderComponent{Layer: types.CollisionLayerTrigger, Trigger: true}
+
+	testCases := []struct {
+		name     string
+		c1       *components.ColliderComponent
+		c2       *components.ColliderComponent
+		collides bool
+		triggers bool
+	}{
+		{"unset colliders collide with everything", unset, character, true, false},
+		{"characters and terrain", character, terrain, true, false},
+		{"projectiles hit characters", projectile, character, true, false},
+		{"projectiles pass through each other", projectile, projectile, false, false},
+		{"projectiles pass through unset colliders", projectile, unset, false, false},
+		{"loot lands on terrain", loot, terrain, true, false},
+		{"loot is picked up by characters", loot, character, false, true},
+		{"loot ignores projectiles", loot, projectile, false, false},
+		{"trigger zones overlap everything", zone, terrain, false, true},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			for _, pair := range [][2]*components.ColliderComponent{{testCase.c1, testCase.c2}, {testCase.c2, testCase.c1}} {
+				if collides := pair[0].Collides(pair[1]); collides != testCase.collides {
+					t.Fatalf("expected collides to be %t but got %t", testCase.collides, collides)
+				}
+			}
+			if triggers := testCase.c1.Triggers(testCase.c2); triggers != testCase.triggers {
+				t.Fatalf("expected triggers to be %t but got %t", testCase.triggers, triggers)
+			}
+			if interacts := testCase.c2.Interacts(testCase.c1); interacts != (testCase.collides || testCase.triggers) {
+				t.Fatalf("expected interacts to be %t but got %t", testCase.collides || testCase.triggers, interacts)
+			}
+		})
+	}
+}
+
+// TestTransformedTriMesh checks that the cached transformed trimesh of a kinematic body
+// is redone whenever the body moves, turns or gets a new trimesh
+func TestTransformedTriMesh(t *testing.T) {
+	triMesh := collider.NewTriMeshFromTriangles([]collider.Triangle{
+		collider.NewTriangle([]mgl64.Vec3{{10, 0, 0}, {10, 0, -1}, {11, 0, 0}}),
+	})
+	transformComponent := &components.TransformComponent{Orientation: mgl64.QuatIdent()}
+	colliderComponent := &components.ColliderComponent{TriMeshCollider: &triMesh}
+	cc := components.NewComponentContainer(
+		transformComponent,
+		colliderComponent,
+		&components.PhysicsComponent{Motion: physics.Path{}},
+	)
+
+	expectFirstPoint := func(expected mgl64.Vec3) {
+		t.Helper()
+		cc.TransformColliders()
+		point := colliderComponent.TransformedTriMeshCollider.Triangles[0].Points[0]
+		if !point.ApproxEqualThreshold(expected, 1e-6) {
+			t.Fatalf("expected the transformed trimesh to start at %v but it starts at %v", expected, point)
+		}
+	}
+
+	expectFirstPoint(mgl64.Vec3{10, 0, 0})
+
+	transformComponent.Position = mgl64.Vec3{0, 5, 0}
+	expectFirstPoint(mgl64.Vec3{10, 5, 0})
+
+	transformComponent.Orientation = mgl64.QuatRotate(math.Pi/2, mgl64.Vec3{0, 1, 0})
+	expectFirstPoint(mgl64.Vec3{0, 5, -10})
+
+	raised := triMesh.Transform(mgl64.Translate3D(0, 1, 0))
+	colliderComponent.TriMeshCollider = &raised
+	expectFirstPoint(mgl64.Vec3{0, 6, -10})
+}
@@ -1,25 +1,12 @@
 package components
 
-const (
-	ComponentFlagAnimation             = 1 << 1
-	ComponentFlagCamera                = 1 << 2
-	ComponentFlagCollider              = 1 << 3
-	ComponentFlagControl               = 1 << 4
-	ComponentFlagFollow                = 1 << 5
-	ComponentFlagMesh                  = 1 << 6
-	ComponentFlagNetwork               = 1 << 7
-	ComponentFlagPhysics               = 1 << 8
-	ComponentFlagRender                = 1 << 9
-	ComponentFlagThirdPersonController = 1 << 10
-	ComponentFlagTransform             = 1 << 11
-	ComponentFlagAI                    = 1 << 12
-	ComponentFlagNotepad               = 1 << 13
-	ComponentFlagHealth                = 1 << 14
-	ComponentFlagLootDropper           = 1 << 15
-	ComponentFlagLoot                  = 1 << 16
-	ComponentFlagInventory             = 1 << 17
-	ComponentFlagMovement              = 1 << 18
-)
+import "sort"
+
+//go:generate go run ./gen
+
+// The component flags, the ComponentContainer fields and each component's
+// Component implementation are generated from components.json. To add a component,
+// declare it in components.json and run go generate.
 
 type Component interface {
 	AddToComponentContainer(container *ComponentContainer)
@@ -29,30 +16,6 @@ type Component interface {
 	Serialize() []byte
 }
 
-type ComponentContainer struct {
-	bitflags     int
-	componentMap map[int]Component
-
-	AIComponent                    *AIComponent
-	AnimationComponent             *AnimationComponent
-	RenderComponent                *RenderComponent
-	TransformComponent             *TransformComponent
-	PhysicsComponent               *PhysicsComponent
-	TopDownViewComponent           *TopDownViewComponent
-	ThirdPersonControllerComponent *ThirdPersonControllerComponent
-	CameraComponent                *CameraComponent
-	NetworkComponent               *NetworkComponent
-	MeshComponent                  *MeshComponent
-	ColliderComponent              *ColliderComponent
-	ControlComponent               *ControlComponent
-	NotepadComponent               *NotepadComponent
-	HealthComponent                *HealthComponent
-	LootDropperComponent           *LootDropperComponent
-	LootComponent                  *LootComponent
-	InventoryComponent             *InventoryComponent
-	MovementComponent              *MovementComponent
-}
-
 func NewComponentContainer(components ...Component) *ComponentContainer {
 	container := &ComponentContainer{
 		componentMap: map[int]Component{},
@@ -60,7 +23,7 @@ func NewComponentContainer(components ...Component) *ComponentContainer {
 	for _, component := range components {
 		component.AddToComponentContainer(container)
 		container.componentMap[component.ComponentFlag()] = component
-		container.bitflags |= component.ComponentFlag()
+		container.bitflags.Set(component.ComponentFlag())
 	}
 	return container
 }
@@ -81,10 +44,32 @@ func (cc *ComponentContainer) Serialize() map[int][]byte {
 	return results
 }
 
-func (cc *ComponentContainer) SetBitFlag(b int) {
-	cc.bitflags |= b
+// Components returns the container's components ordered by component flag
+func (cc *ComponentContainer) Components() []Component {
+	var flags []int
+	for flag := range cc.componentMap {
+		flags = append(flags, flag)
+	}
+	sort.Ints(flags)
+
+	var components []Component
+	for _, flag := range flags {
+		components = append(components, cc.componentMap[flag])
+	}
+	return components
+}
+
+// Component returns the container's component with the given flag
+func (cc *ComponentContainer) Component(flag int) (Component, bool) {
+	component, ok := cc.componentMap[flag]
+	return component, ok
+}
+
+func (cc *ComponentContainer) SetBitFlag(flag int) {
+	cc.bitflags.Set(flag)
 }
 
-func (cc *ComponentContainer) MatchBitFlags(b int) bool {
-	return b&cc.bitflags == b
+// MatchBitFlags returns whether the container has every component in flags
+func (cc *ComponentContainer) MatchBitFlags(flags Bitset) bool {
+	return cc.bitflags.Contains(flags)
 }
@@ -0,0 +1,456 @@
+// Code generated by go run ./gen; DO NOT EDIT.
+
+package components
+
+import (
+	"github.com/kkevinchou/kito/kito/components/protogen/health"
+	"github.com/kkevinchou/kito/kito/components/protogen/inventory"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	ComponentFlagAnimation = iota
+	ComponentFlagCamera
+	ComponentFlagCollider
+	ComponentFlagControl
+	ComponentFlagMesh
+	ComponentFlagNetwork
+	ComponentFlagPhysics
+	ComponentFlagRender
+	ComponentFlagThirdPersonController
+	ComponentFlagTransform
+	ComponentFlagAI
+	ComponentFlagNotepad
+	ComponentFlagHealth
+	ComponentFlagLootDropper
+	ComponentFlagLoot
+	ComponentFlagInventory
+	ComponentFlagMovement
+	ComponentFlagScript
+
+	// ComponentCount is the number of declared components
+	ComponentCount = 18
+)
+
+type ComponentContainer struct {
+	bitflags     Bitset
+	componentMap map[int]Component
+
+	AnimationComponent             *AnimationComponent
+	CameraComponent                *CameraComponent
+	ColliderComponent              *ColliderComponent
+	ControlComponent               *ControlComponent
+	MeshComponent                  *MeshComponent
+	NetworkComponent               *NetworkComponent
+	PhysicsComponent               *PhysicsComponent
+	RenderComponent                *RenderComponent
+	ThirdPersonControllerComponent *ThirdPersonControllerComponent
+	TransformComponent             *TransformComponent
+	AIComponent                    *AIComponent
+	NotepadComponent               *NotepadComponent
+	HealthComponent                *HealthComponent
+	LootDropperComponent           *LootDropperComponent
+	LootComponent                  *LootComponent
+	InventoryComponent             *InventoryComponent
+	MovementComponent              *MovementComponent
+	ScriptComponent                *ScriptComponent
+}
+
+func init() {
+	registerSchema(&AnimationComponent{})
+	registerSchema(&CameraComponent{}, Range("FollowDistance", 0, 1000), Range("MaxFollowDistance", 0, 1000), Range("YOffset", -500, 500), Range("ZoomSpeed", 0, 100), ReadOnly("FollowTargetEntityID"))
+	registerSchema(&ColliderComponent{})
+	registerSchema(&ControlComponent{}, ReadOnly("PlayerID"))
+	registerSchema(&MeshComponent{})
+	registerSchema(&NetworkComponent{}, ReadOnly("SpawnKey", "OwnerPlayerID", "Predicted"))
+	registerSchema(&PhysicsComponent{})
+	registerSchema(&RenderComponent{})
+	registerSchema(&ThirdPersonControllerComponent{}, Range("MovementSpeed", 0, 1000), ReadOnly("CameraID"))
+	registerSchema(&TransformComponent{})
+	registerSchema(&AIComponent{})
+	registerSchema(&NotepadComponent{})
+	registerSchema(&HealthComponent{}, Inline("Data"), Range("Data.Value", 0, 1000))
+	registerSchema(&LootDropperComponent{})
+	registerSchema(&LootComponent{})
+	registerSchema(&InventoryComponent{}, ReadOnly("Width", "Height"))
+	registerSchema(&MovementComponent{})
+	registerSchema(&ScriptComponent{})
+}
+
+func (c *AnimationComponent) AddToComponentContainer(container *ComponentContainer) {
+	container.AnimationComponent = c
+}
+
+func (c *AnimationComponent) ComponentFlag() int {
+	return ComponentFlagAnimation
+}
+
+func (c *AnimationComponent) Synchronized() bool {
+	return false
+}
+
+func (c *AnimationComponent) Load(bytes []byte) {
+	panic("AnimationComponent is not synchronized")
+}
+
+func (c *AnimationComponent) Serialize() []byte {
+	panic("AnimationComponent is not synchronized")
+}
+
+func (c *CameraComponent) AddToComponentContainer(container *ComponentContainer) {
+	container.CameraComponent = c
+}
+
+func (c *CameraComponent) ComponentFlag() int {
+	return ComponentFlagCamera
+}
+
+func (c *CameraComponent) Synchronized() bool {
+	return false
+}
+
+func (c *CameraComponent) Load(bytes []byte) {
+	panic("CameraComponent is not synchronized")
+}
+
+func (c *CameraComponent) Serialize() []byte {
+	panic("CameraComponent is not synchronized")
+}
+
+func (c *ColliderComponent) AddToComponentContainer(container *ComponentContainer) {
+	container.ColliderComponent = c
+}
+
+func (c *ColliderComponent) ComponentFlag() int {
+	return ComponentFlagCollider
+}
+
+func (c *ColliderComponent) Synchronized() bool {
+	return false
+}
+
+func (c *ColliderComponent) Load(bytes []byte) {
+	panic("ColliderComponent is not synchronized")
+}
+
+func (c *ColliderComponent) Serialize() []byte {
+	panic("ColliderComponent is not synchronized")
+}
+
+func (c *ControlComponent) AddToComponentContainer(container *ComponentContainer) {
+	container.ControlComponent = c
+}
+
+func (c *ControlComponent) ComponentFlag() int {
+	return ComponentFlagControl
+}
+
+func (c *ControlComponent) Synchronized() bool {
+	return false
+}
+
+func (c *ControlComponent) Load(bytes []byte) {
+	panic("ControlComponent is not synchronized")
+}
+
+func (c *ControlComponent) Serialize() []byte {
+	panic("ControlComponent is not synchronized")
+}
+
+func (c *MeshComponent) AddToComponentContainer(container *ComponentContainer) {
+	container.MeshComponent = c
+}
+
+func (c *MeshComponent) ComponentFlag() int {
+	return ComponentFlagMesh
+}
+
+func (c *MeshComponent) Synchronized() bool {
+	return false
+}
+
+func (c *MeshComponent) Load(bytes []byte) {
+	panic("MeshComponent is not synchronized")
+}
+
+func (c *MeshComponent) Serialize() []byte {
+	panic("MeshComponent is not synchronized")
+}
+
+func (c *NetworkComponent) AddToComponentContainer(container *ComponentContainer) {
+	container.NetworkComponent = c
+}
+
+func (c *NetworkComponent) ComponentFlag() int {
+	return ComponentFlagNetwork
+}
+
+func (c *NetworkComponent) Synchronized() bool {
+	return false
+}
+
+func (c *NetworkComponent) Load(bytes []byte) {
+	panic("NetworkComponent is not synchronized")
+}
+
+func (c *NetworkComponent) Serialize() []byte {
+	panic("NetworkComponent is not synchronized")
+}
+
+func (c *PhysicsComponent) AddToComponentContainer(container *ComponentContainer) {
+	container.PhysicsComponent = c
+}
+
+func (c *PhysicsComponent) ComponentFlag() int {
+	return ComponentFlagPhysics
+}
+
+func (c *PhysicsComponent) Synchronized() bool {
+	return false
+}
+
+func (c *PhysicsComponent) Load(bytes []byte) {
+	panic("PhysicsComponent is not synchronized")
+}
+
+func (c *PhysicsComponent) Serialize() []byte {
+	panic("PhysicsComponent is not synchronized")
+}
+
+func (c *RenderComponent) AddToComponentContainer(container *ComponentContainer) {
+	container.RenderComponent = c
+}
+
+func (c *RenderComponent) ComponentFlag() int {
+	return ComponentFlagRender
+}
+
+func (c *RenderComponent) Synchronized() bool {
+	return false
+}
+
+func (c *RenderComponent) Load(bytes []byte) {
+	panic("RenderComponent is not synchronized")
+}
+
+func (c *RenderComponent) Serialize() []byte {
+	panic("RenderComponent is not synchronized")
+}
+
+func (c *ThirdPersonControllerComponent) AddToComponentContainer(container *ComponentContainer) {
+	container.ThirdPersonControllerComponent = c
+}
+
+func (c *ThirdPersonControllerComponent) ComponentFlag() int {
+	return ComponentFlagThirdPersonController
+}
+
+func (c *ThirdPersonControllerComponent) Synchronized() bool {
+	return false
+}
+
+func (c *ThirdPersonControllerComponent) Load(bytes []byte) {
+	panic("ThirdPersonControllerComponent is not synchronized")
+}
+
+func (c *ThirdPersonControllerComponent) Serialize() []byte {
+	panic("ThirdPersonControllerComponent is not synchronized")
+}
+
+func (c *TransformComponent) AddToComponentContainer(container *ComponentContainer) {
+	container.TransformComponent = c
+}
+
+func (c *TransformComponent) ComponentFlag() int {
+	return ComponentFlagTransform
+}
+
+func (c *TransformComponent) Synchronized() bool {
+	return false
+}
+
+func (c *TransformComponent) Load(bytes []byte) {
+	panic("TransformComponent is not synchronized")
+}
+
+func (c *TransformComponent) Serialize() []byte {
+	panic("TransformComponent is not synchronized")
+}
+
+func (c *AIComponent) AddToComponentContainer(container *ComponentContainer) {
+	container.AIComponent = c
+}
+
+func (c *AIComponent) ComponentFlag() int {
+	return ComponentFlagAI
+}
+
+func (c *AIComponent) Synchronized() bool {
+	return false
+}
+
+func (c *AIComponent) Load(bytes []byte) {
+	panic("AIComponent is not synchronized")
+}
+
+func (c *AIComponent) Serialize() []byte {
+	panic("AIComponent is not synchronized")
+}
+
+func (c *NotepadComponent) AddToComponentContainer(container *ComponentContainer) {
+	container.NotepadComponent = c
+}
+
+func (c *NotepadComponent) ComponentFlag() int {
+	return ComponentFlagNotepad
+}
+
+func (c *NotepadComponent) Synchronized() bool {
+	return false
+}
+
+func (c *NotepadComponent) Load(bytes []byte) {
+	panic("NotepadComponent is not synchronized")
+}
+
+func (c *NotepadComponent) Serialize() []byte {
+	panic("NotepadComponent is not synchronized")
+}
+
+func (c *HealthComponent) AddToComponentContainer(container *ComponentContainer) {
+	container.HealthComponent = c
+}
+
+func (c *HealthComponent) ComponentFlag() int {
+	return ComponentFlagHealth
+}
+
+func (c *HealthComponent) Synchronized() bool {
+	return true
+}
+
+func (c *HealthComponent) Load(bytes []byte) {
+	data := &health.Health{}
+	err := proto.Unmarshal(bytes, data)
+	if err != nil {
+		panic(err)
+	}
+	c.Data = data
+}
+
+func (c *HealthComponent) Serialize() []byte {
+	bytes, err := proto.Marshal(c.Data)
+	if err != nil {
+		panic(err)
+	}
+	return bytes
+}
+
+func (c *LootDropperComponent) AddToComponentContainer(container *ComponentContainer) {
+	container.LootDropperComponent = c
+}
+
+func (c *LootDropperComponent) ComponentFlag() int {
+	return ComponentFlagLootDropper
+}
+
+func (c *LootDropperComponent) Synchronized() bool {
+	return false
+}
+
+func (c *LootDropperComponent) Load(bytes []byte) {
+	panic("LootDropperComponent is not synchronized")
+}
+
+func (c *LootDropperComponent) Serialize() []byte {
+	panic("LootDropperComponent is not synchronized")
+}
+
+func (c *LootComponent) AddToComponentContainer(container *ComponentContainer) {
+	container.LootComponent = c
+}
+
+func (c *LootComponent) ComponentFlag() int {
+	return ComponentFlagLoot
+}
+
+func (c *LootComponent) Synchronized() bool {
+	return false
+}
+
+func (c *LootComponent) Load(bytes []byte) {
+	panic("LootComponent is not synchronized")
+}
+
+func (c *LootComponent) Serialize() []byte {
+	panic("LootComponent is not synchronized")
+}
+
+func (c *InventoryComponent) AddToComponentContainer(container *ComponentContainer) {
+	container.InventoryComponent = c
+}
+
+func (c *InventoryComponent) ComponentFlag() int {
+	return ComponentFlagInventory
+}
+
+func (c *InventoryComponent) Synchronized() bool {
+	return true
+}
+
+func (c *InventoryComponent) Load(bytes []byte) {
+	data := &inventory.Inventory{}
+	err := proto.Unmarshal(bytes, data)
+	if err != nil {
+		panic(err)
+	}
+	c.Data = data
+}
+
+func (c *InventoryComponent) Serialize() []byte {
+	bytes, err := proto.Marshal(c.Data)
+	if err != nil {
+		panic(err)
+	}
+	return bytes
+}
+
+func (c *MovementComponent) AddToComponentContainer(container *ComponentContainer) {
+	container.MovementComponent = c
+}
+
+func (c *MovementComponent) ComponentFlag() int {
+	return ComponentFlagMovement
+}
+
+func (c *MovementComponent) Synchronized() bool {
+	return false
+}
+
+func (c *MovementComponent) Load(bytes []byte) {
+	panic("MovementComponent is not synchronized")
+}
+
+func (c *MovementComponent) Serialize() []byte {
+	panic("MovementComponent is not synchronized")
+}
+
+func (c *ScriptComponent) AddToComponentContainer(container *ComponentContainer) {
+	container.ScriptComponent = c
+}
+
+func (c *ScriptComponent) ComponentFlag() int {
+	return ComponentFlagScript
+}
+
+func (c *ScriptComponent) Synchronized() bool {
+	return false
+}
+
+func (c *ScriptComponent) Load(bytes []byte) {
+	panic("ScriptComponent is not synchronized")
+}
+
+func (c *ScriptComponent) Serialize() []byte {
+	panic("ScriptComponent is not synchronized")
+}
@@ -0,0 +1,247 @@
+// gen generates the component boilerplate in components_gen.go from the component
+// declarations in components.json. It's run from the components package through
+// go generate:
+//
+//	go generate ./kito/components/...
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/format"
+	"os"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+type declarations struct {
+	Components []componentDeclaration `json:"components"`
+}
+
+// componentDeclaration declares a component. A component named X is expected to be
+// implemented by the type XComponent in the components package. Components are
+// assigned flags in declaration order, so new components should be appended
+type componentDeclaration struct {
+	Name string `json:"name"`
+
+	// Proto marks a synchronized component whose state is the protobuf message
+	// stored in its Data field
+	Proto *protoDeclaration `json:"proto"`
+
+	// schema options, see the Inline, Range and ReadOnly schema options
+	Inline   []string           `json:"inline"`
+	Ranges   []rangeDeclaration `json:"ranges"`
+	ReadOnly []string           `json:"readOnly"`
+}
+
+type protoDeclaration struct {
+	// Import is the import path of the generated protobuf package
+	Import string `json:"import"`
+	// Message is the qualified name of the protobuf message, e.g. health.Health
+	Message string `json:"message"`
+}
+
+type rangeDeclaration struct {
+	Field string  `json:"field"`
+	Min   float64 `json:"min"`
+	Max   float64 `json:"max"`
+}
+
+func (c componentDeclaration) Type() string {
+	return c.Name + "Component"
+}
+
+func (c componentDeclaration) Flag() string {
+	return "ComponentFlag" + c.Name
+}
+
+func (c componentDeclaration) SchemaOptions() string {
+	var options []string
+	if len(c.Inline) > 0 {
+		options = append(options, fmt.Sprintf("Inline(%s)", quoteAll(c.Inline)))
+	}
+	for _, r := range c.Ranges {
+		options = append(options, fmt.Sprintf("Range(%q, %g, %g)", r.Field, r.Min, r.Max))
+	}
+	if len(c.ReadOnly) > 0 {
+		options = append(options, fmt.Sprintf("ReadOnly(%s)", quoteAll(c.ReadOnly)))
+	}
+
+	if len(options) == 0 {
+		return ""
+	}
+	return ", " + strings.Join(options, ", ")
+}
+
+func quoteAll(values []string) string {
+	var quoted []string
+	for _, value := range values {
+		quoted = append(quoted, fmt.Sprintf("%q", value))
+	}
+	return strings.Join(quoted, ", ")
+}
+
+func parse(data []byte) (*declarations, error) {
+	var d declarations
+	if err := json.Unmarshal(data, &d); err != nil {
+		return nil, err
+	}
+
+	names := map[string]bool{}
+	for _, c := range d.Components {
+		if c.Name == "" {
+			return nil, fmt.Errorf("component declared without a name")
+		}
+		if names[c.Name] {
+			return nil, fmt.Errorf("component %s declared twice", c.Name)
+		}
+		names[c.Name] = true
+
+		if c.Proto != nil && (c.Proto.Import == "" || !strings.Contains(c.Proto.Message, ".")) {
+			return nil, fmt.Errorf("component %s must declare a proto import and a qualified message", c.Name)
+		}
+	}
+	return &d, nil
+}
+
+// generate returns the formatted source of components_gen.go
+func generate(d *declarations) ([]byte, error) {
+	importSet := map[string]bool{}
+	for _, c := range d.Components {
+		if c.Proto != nil {
+			importSet[c.Proto.Import] = true
+			importSet["google.golang.org/protobuf/proto"] = true
+		}
+	}
+	var imports []string
+	for i := range importSet {
+		imports = append(imports, i)
+	}
+	sort.Strings(imports)
+
+	var buf bytes.Buffer
+	err := fileTemplate.Execute(&buf, map[string]any{
+		"Imports":    imports,
+		"Components": d.Components,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format generated source: %w\n%s", err, buf.String())
+	}
+	return src, nil
+}
+
+var fileTemplate = template.Must(template.New("components").Parse(`// Code generated by go run ./gen; DO NOT EDIT.
+
+package components
+
+{{if .Imports}}
+import (
+{{- range .Imports}}
+	"{{.}}"
+{{- end}}
+)
+{{end}}
+
+const (
+{{- range $i, $c := .Components}}
+	{{$c.Flag}}{{if eq $i 0}} = iota{{end}}
+{{- end}}
+
+	// ComponentCount is the number of declared components
+	ComponentCount = {{len .Components}}
+)
+
+type ComponentContainer struct {
+	bitflags     Bitset
+	componentMap map[int]Component
+{{range .Components}}
+	{{.Type}} *{{.Type}}
+{{- end}}
+}
+
+func init() {
+{{- range .Components}}
+	registerSchema(&{{.Type}}{}{{.SchemaOptions}})
+{{- end}}
+}
+{{range .Components}}
+func (c *{{.Type}}) AddToComponentContainer(container *ComponentContainer) {
+	container.{{.Type}} = c
+}
+
+func (c *{{.Type}}) ComponentFlag() int {
+	return {{.Flag}}
+}
+{{if .Proto}}
+func (c *{{.Type}}) Synchronized() bool {
+	return true
+}
+
+func (c *{{.Type}}) Load(bytes []byte) {
+	data := &{{.Proto.Message}}{}
+	err := proto.Unmarshal(bytes, data)
+	if err != nil {
+		panic(err)
+	}
+	c.Data = data
+}
+
+func (c *{{.Type}}) Serialize() []byte {
+	bytes, err := proto.Marshal(c.Data)
+	if err != nil {
+		panic(err)
+	}
+	return bytes
+}
+{{else}}
+func (c *{{.Type}}) Synchronized() bool {
+	return false
+}
+
+func (c *{{.Type}}) Load(bytes []byte) {
+	panic("{{.Type}} is not synchronized")
+}
+
+func (c *{{.Type}}) Serialize() []byte {
+	panic("{{.Type}} is not synchronized")
+}
+{{end}}
+{{- end}}
+`))
+
+func main() {
+	in := flag.String("in", "components.json", "component declaration file")
+	out := flag.String("out", "components_gen.go", "generated output file")
+	flag.Parse()
+
+	data, err := os.ReadFile(*in)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	decls, err := parse(data)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	src, err := generate(decls)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	if err := os.WriteFile(*out, src, 0644); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestGeneratedUpToDate(t *testing.T) {
+	data, err := os.ReadFile("../components.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := parse(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	src, err := generate(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	existing, err := os.ReadFile("../components_gen.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(src, existing) {
+		t.Fatal("components_gen.go is out of date with components.json, run go generate ./kito/components/...")
+	}
+}
+
+func TestManyComponents(t *testing.T) {
+	d := &declarations{}
+	for i := 0; i < 100; i++ {
+		d.Components = append(d.Components, componentDeclaration{Name: fmt.Sprintf("Component%d", i)})
+	}
+	src, err := generate(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(src), "ComponentCount = 100") {
+		t.Fatal("expected every declared component to be counted")
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	inputs := []string{
+		`{"components": [{"name": "A"}, {"name": "A"}]}`,
+		`{"components": [{}]}`,
+		`{"components": [{"name": "A", "proto": {"message": "a.A"}}]}`,
+	}
+	for _, input := range inputs {
+		if _, err := parse([]byte(input)); err == nil {
+			t.Errorf("expected %s to fail to parse", input)
+		}
+	}
+}
@@ -1,9 +1,6 @@
 package components
 
-import (
-	"github.com/kkevinchou/kito/kito/components/protogen/health"
-	"google.golang.org/protobuf/proto"
-)
+import "github.com/kkevinchou/kito/kito/components/protogen/health"
 
 type HealthComponent struct {
 	// Value  float64
@@ -15,32 +12,3 @@ func NewHealthComponent(value float64) *HealthComponent {
 		Data: &health.Health{Value: value},
 	}
 }
-
-func (c *HealthComponent) AddToComponentContainer(container *ComponentContainer) {
-	container.HealthComponent = c
-}
-
-func (c *HealthComponent) ComponentFlag() int {
-	return ComponentFlagHealth
-}
-
-func (c *HealthComponent) Synchronized() bool {
-	return true
-}
-
-func (c *HealthComponent) Load(bytes []byte) {
-	h := &health.Health{}
-	err := proto.Unmarshal(bytes, h)
-	if err != nil {
-		panic(err)
-	}
-	c.Data = h
-}
-
-func (c *HealthComponent) Serialize() []byte {
-	bytes, err := proto.Marshal(c.Data)
-	if err != nil {
-		panic(err)
-	}
-	return bytes
-}
@@ -14,23 +14,3 @@ type MeshComponent struct {
 func (c *MeshComponent) GetMeshComponent() *MeshComponent {
 	return c
 }
-
-func (c *MeshComponent) AddToComponentContainer(container *ComponentContainer) {
-	container.MeshComponent = c
-}
-
-func (c *MeshComponent) ComponentFlag() int {
-	return ComponentFlagMesh
-}
-
-func (c *MeshComponent) Synchronized() bool {
-	return false
-}
-
-func (c *MeshComponent) Load(bytes []byte) {
-	panic("wat")
-}
-
-func (c *MeshComponent) Serialize() []byte {
-	panic("wat")
-}
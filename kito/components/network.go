@@ -1,25 +1,20 @@
 package components
 
+import "github.com/go-gl/mathgl/mgl64"
+
 // Mostly work as a type flag atm
 type NetworkComponent struct {
-}
+	// SpawnKey is set on entities that the owning player's client spawned ahead of the
+	// server, and is used to match the client's predicted entity with the server's
+	SpawnKey      int
+	OwnerPlayerID int
 
-func (c *NetworkComponent) AddToComponentContainer(container *ComponentContainer) {
-	container.NetworkComponent = c
-}
-
-func (c *NetworkComponent) ComponentFlag() int {
-	return ComponentFlagNetwork
-}
-
-func (c *NetworkComponent) Synchronized() bool {
-	return false
-}
-
-func (c *NetworkComponent) Load(bytes []byte) {
-	panic("wat")
-}
+	// Predicted is set on the client's local stand in for an entity that the server
+	// has yet to confirm. Predicted entities are simulated locally
+	Predicted bool
 
-func (c *NetworkComponent) Serialize() []byte {
-	panic("wat")
+	// PredictionOffset is the client side visual offset left over from replacing a
+	// predicted entity with its authoritative entity. It decays to zero over a few
+	// command frames so that the replacement doesn't pop
+	PredictionOffset mgl64.Vec3
 }
@@ -13,23 +13,3 @@ type NotepadComponent struct {
 func (c *NotepadComponent) GetNotepadComponent() *NotepadComponent {
 	return c
 }
-
-func (c *NotepadComponent) AddToComponentContainer(container *ComponentContainer) {
-	container.NotepadComponent = c
-}
-
-func (c *NotepadComponent) ComponentFlag() int {
-	return ComponentFlagNotepad
-}
-
-func (c *NotepadComponent) Synchronized() bool {
-	return false
-}
-
-func (c *NotepadComponent) Load(bytes []byte) {
-	panic("wat")
-}
-
-func (c *NotepadComponent) Serialize() []byte {
-	panic("wat")
-}
@@ -3,6 +3,7 @@ package components
 import (
 	"github.com/go-gl/mathgl/mgl64"
 	"github.com/kkevinchou/kito/kito/types"
+	"github.com/kkevinchou/kito/lib/physics"
 )
 
 type PhysicsComponent struct {
@@ -13,28 +14,21 @@ type PhysicsComponent struct {
 
 	// impulses have a name that can be reset or overwritten
 	Impulses map[string]types.Impulse
-}
 
-func (c *PhysicsComponent) ApplyImpulse(name string, impulse types.Impulse) {
-	c.Impulses[name] = impulse
-}
+	// RigidBody makes the entity simulated as a rigid body that rotates and bounces off of
+	// what it hits. Rigid bodies ignore Impulses
+	RigidBody *physics.Body
 
-func (c *PhysicsComponent) AddToComponentContainer(container *ComponentContainer) {
-	container.PhysicsComponent = c
-}
+	// Motion moves the entity as a kinematic body that follows it by command frame rather
+	// than being simulated. Nothing pushes kinematic bodies and characters standing on
+	// them are carried along
+	Motion physics.Motion
 
-func (c *PhysicsComponent) ComponentFlag() int {
-	return ComponentFlagPhysics
+	// AngularVelocity is the axis a kinematic body is turning about scaled by how fast it
+	// turns in radians per second
+	AngularVelocity mgl64.Vec3
 }
 
-func (c *PhysicsComponent) Synchronized() bool {
-	return false
-}
-
-func (c *PhysicsComponent) Load(bytes []byte) {
-	panic("wat")
-}
-
-func (c *PhysicsComponent) Serialize() []byte {
-	panic("wat")
+func (c *PhysicsComponent) ApplyImpulse(name string, impulse types.Impulse) {
+	c.Impulses[name] = impulse
 }
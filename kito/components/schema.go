@@ -0,0 +1,332 @@
+package components
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-gl/mathgl/mgl64"
+)
+
+type FieldKind string
+
+const (
+	FieldKindBool   FieldKind = "bool"
+	FieldKindInt    FieldKind = "int"
+	FieldKindFloat  FieldKind = "float"
+	FieldKindString FieldKind = "string"
+	FieldKindVec3   FieldKind = "vec3"
+	FieldKindQuat   FieldKind = "quat"
+)
+
+// FieldSchema describes a single inspectable field of a component. Fields are read
+// and written through reflection so that tools like the entity inspector don't need
+// to know about every component
+type FieldSchema struct {
+	// Name is the path to the field from the component, e.g. Data.Value
+	Name string
+	Kind FieldKind
+
+	// Min and Max bound numeric fields, and each element of vector fields, when HasRange is set
+	HasRange bool
+	Min      float64
+	Max      float64
+
+	ReadOnly bool
+
+	index []int
+}
+
+// ComponentSchema describes a component type and its inspectable fields
+type ComponentSchema struct {
+	Name         string
+	Flag         int
+	Synchronized bool
+	Fields       []FieldSchema
+}
+
+var (
+	schemasByFlag = map[int]*ComponentSchema{}
+	schemasByName = map[string]*ComponentSchema{}
+)
+
+type schemaOptions struct {
+	inline   map[string]bool
+	ranges   map[string][2]float64
+	readOnly map[string]bool
+}
+
+type SchemaOption func(o *schemaOptions)
+
+// Inline inspects the fields of the struct pointed to by the named fields rather than
+// skipping them, e.g. the protobuf backed Data of synchronized components
+func Inline(fields ...string) SchemaOption {
+	return func(o *schemaOptions) {
+		for _, field := range fields {
+			o.inline[field] = true
+		}
+	}
+}
+
+func Range(field string, min, max float64) SchemaOption {
+	return func(o *schemaOptions) {
+		o.ranges[field] = [2]float64{min, max}
+	}
+}
+
+func ReadOnly(fields ...string) SchemaOption {
+	return func(o *schemaOptions) {
+		for _, field := range fields {
+			o.readOnly[field] = true
+		}
+	}
+}
+
+func registerSchema(prototype Component, options ...SchemaOption) {
+	o := &schemaOptions{
+		inline:   map[string]bool{},
+		ranges:   map[string][2]float64{},
+		readOnly: map[string]bool{},
+	}
+	for _, option := range options {
+		option(o)
+	}
+
+	t := reflect.TypeOf(prototype).Elem()
+	schema := &ComponentSchema{
+		Name:         t.Name(),
+		Flag:         prototype.ComponentFlag(),
+		Synchronized: prototype.Synchronized(),
+		Fields:       reflectFields(t, "", nil, o),
+	}
+
+	if _, ok := schemasByFlag[schema.Flag]; ok {
+		panic(fmt.Sprintf("component flag %d registered twice", schema.Flag))
+	}
+	schemasByFlag[schema.Flag] = schema
+	schemasByName[schema.Name] = schema
+}
+
+var (
+	vec3Type = reflect.TypeOf(mgl64.Vec3{})
+	quatType = reflect.TypeOf(mgl64.Quat{})
+)
+
+func reflectFields(t reflect.Type, prefix string, index []int, o *schemaOptions) []FieldSchema {
+	var fields []FieldSchema
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		name := prefix + f.Name
+		fieldIndex := append(append([]int{}, index...), i)
+
+		if f.Type.Kind() == reflect.Pointer && f.Type.Elem().Kind() == reflect.Struct && o.inline[name] {
+			fields = append(fields, reflectFields(f.Type.Elem(), name+".", fieldIndex, o)...)
+			continue
+		}
+
+		kind, ok := fieldKind(f.Type)
+		if !ok {
+			continue
+		}
+
+		field := FieldSchema{
+			Name:     name,
+			Kind:     kind,
+			ReadOnly: o.readOnly[name] || kind == FieldKindQuat,
+			index:    fieldIndex,
+		}
+		if r, ok := o.ranges[name]; ok {
+			field.HasRange = true
+			field.Min = r[0]
+			field.Max = r[1]
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+func fieldKind(t reflect.Type) (FieldKind, bool) {
+	switch t {
+	case vec3Type:
+		return FieldKindVec3, true
+	case quatType:
+		return FieldKindQuat, true
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return FieldKindBool, true
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		return FieldKindInt, true
+	case reflect.Float32, reflect.Float64:
+		return FieldKindFloat, true
+	case reflect.String:
+		return FieldKindString, true
+	}
+	return "", false
+}
+
+// ComponentSchemas returns every registered component schema ordered by component flag
+func ComponentSchemas() []*ComponentSchema {
+	var schemas []*ComponentSchema
+	for _, schema := range schemasByFlag {
+		schemas = append(schemas, schema)
+	}
+	sort.Slice(schemas, func(i, j int) bool {
+		return schemas[i].Flag < schemas[j].Flag
+	})
+	return schemas
+}
+
+func SchemaByFlag(flag int) (*ComponentSchema, bool) {
+	schema, ok := schemasByFlag[flag]
+	return schema, ok
+}
+
+func SchemaByName(name string) (*ComponentSchema, bool) {
+	schema, ok := schemasByName[name]
+	return schema, ok
+}
+
+func (s *ComponentSchema) Field(name string) (FieldSchema, bool) {
+	for _, field := range s.Fields {
+		if field.Name == name {
+			return field, true
+		}
+	}
+	return FieldSchema{}, false
+}
+
+// value resolves the field on the component. ok is false if a pointer along the way is nil
+func (f FieldSchema) value(component Component) (reflect.Value, bool) {
+	v := reflect.ValueOf(component).Elem()
+	for i, index := range f.index {
+		if i > 0 {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(index)
+	}
+	return v, true
+}
+
+// Get returns the value of the field on the component
+func (f FieldSchema) Get(component Component) (any, bool) {
+	v, ok := f.value(component)
+	if !ok {
+		return nil, false
+	}
+	return v.Interface(), true
+}
+
+// Format returns the value of the field in the same format Set accepts
+func (f FieldSchema) Format(component Component) string {
+	value, ok := f.Get(component)
+	if !ok {
+		return "<nil>"
+	}
+
+	switch f.Kind {
+	case FieldKindVec3:
+		v := value.(mgl64.Vec3)
+		return fmt.Sprintf("%g,%g,%g", v[0], v[1], v[2])
+	case FieldKindQuat:
+		q := value.(mgl64.Quat)
+		return fmt.Sprintf("%g,%g,%g,%g", q.W, q.V[0], q.V[1], q.V[2])
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+// Set parses the value and assigns it to the field on the component. Values that
+// don't parse, are out of range, or target a read only field are rejected
+func (f FieldSchema) Set(component Component, value string) error {
+	if f.ReadOnly {
+		return fmt.Errorf("field %s is read only", f.Name)
+	}
+
+	v, ok := f.value(component)
+	if !ok {
+		return fmt.Errorf("field %s is not set on the component", f.Name)
+	}
+
+	switch f.Kind {
+	case FieldKindBool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case FieldKindInt:
+		i, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return err
+		}
+		if err := f.checkRange(float64(i)); err != nil {
+			return err
+		}
+		v.SetInt(i)
+	case FieldKindFloat:
+		fl, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return err
+		}
+		if err := f.checkRange(fl); err != nil {
+			return err
+		}
+		v.SetFloat(fl)
+	case FieldKindString:
+		v.SetString(value)
+	case FieldKindVec3:
+		tokens := strings.Split(value, ",")
+		if len(tokens) != 3 {
+			return fmt.Errorf("expected x,y,z but got %s", value)
+		}
+		var vec mgl64.Vec3
+		for i, token := range tokens {
+			fl, err := strconv.ParseFloat(strings.TrimSpace(token), 64)
+			if err != nil {
+				return err
+			}
+			if err := f.checkRange(fl); err != nil {
+				return err
+			}
+			vec[i] = fl
+		}
+		v.Set(reflect.ValueOf(vec))
+	default:
+		return fmt.Errorf("field %s of kind %s cannot be set", f.Name, f.Kind)
+	}
+	return nil
+}
+
+func (f FieldSchema) checkRange(value float64) error {
+	if f.HasRange && (value < f.Min || value > f.Max) {
+		return fmt.Errorf("%g is outside of the range [%g, %g] for %s", value, f.Min, f.Max, f.Name)
+	}
+	return nil
+}
+
+// SetField validates and sets a field on one of the container's components
+func (cc *ComponentContainer) SetField(componentName string, fieldName string, value string) error {
+	schema, ok := SchemaByName(componentName)
+	if !ok {
+		return fmt.Errorf("unknown component %s", componentName)
+	}
+	component, ok := cc.componentMap[schema.Flag]
+	if !ok {
+		return fmt.Errorf("entity does not have a %s", componentName)
+	}
+	field, ok := schema.Field(fieldName)
+	if !ok {
+		return fmt.Errorf("%s does not have an inspectable field %s", componentName, fieldName)
+	}
+	return field.Set(component, value)
+}
@@ -0,0 +1,56 @@
+package components_test
+
+import (
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/kkevinchou/kito/kito/components"
+)
+
+func TestSchemaFields(t *testing.T) {
+	schema, ok := components.SchemaByFlag(components.ComponentFlagHealth)
+	if !ok {
+		t.Fatal("expected the health component to be registered")
+	}
+	if !schema.Synchronized {
+		t.Error("expected the health component to be synchronized")
+	}
+
+	field, ok := schema.Field("Data.Value")
+	if !ok {
+		t.Fatal("expected the inlined Data.Value field")
+	}
+	if field.Kind != components.FieldKindFloat || !field.HasRange {
+		t.Fatalf("expected a ranged float field but got %+v", field)
+	}
+}
+
+func TestSetField(t *testing.T) {
+	cc := components.NewComponentContainer(
+		components.NewHealthComponent(100),
+		&components.TransformComponent{},
+	)
+
+	if err := cc.SetField("HealthComponent", "Data.Value", "50"); err != nil {
+		t.Fatal(err)
+	}
+	if cc.HealthComponent.Data.Value != 50 {
+		t.Fatalf("expected health to be 50 but got %f", cc.HealthComponent.Data.Value)
+	}
+
+	if err := cc.SetField("HealthComponent", "Data.Value", "5000"); err == nil {
+		t.Fatal("expected an out of range value to be rejected")
+	}
+	if err := cc.SetField("TransformComponent", "Position", "1,2,3"); err != nil {
+		t.Fatal(err)
+	}
+	if cc.TransformComponent.Position != (mgl64.Vec3{1, 2, 3}) {
+		t.Fatalf("expected position to be set but got %v", cc.TransformComponent.Position)
+	}
+	if err := cc.SetField("TransformComponent", "Orientation", "1,0,0,0"); err == nil {
+		t.Fatal("expected a read only field to be rejected")
+	}
+	if err := cc.SetField("MovementComponent", "Velocity", "1,2,3"); err == nil {
+		t.Fatal("expected a missing component to be rejected")
+	}
+}
@@ -0,0 +1,11 @@
+package components
+
+// ScriptComponent attaches gameplay scripts to an entity. Scripts are referred to
+// by their file name in the scripts directory, without the extension
+type ScriptComponent struct {
+	Scripts []string
+}
+
+func NewScriptComponent(scripts ...string) *ScriptComponent {
+	return &ScriptComponent{Scripts: scripts}
+}
@@ -8,6 +8,9 @@ type ThirdPersonControllerComponent struct {
 	Controlled bool
 	Grounded   bool
 
+	// GroundEntityID is the entity the character is standing on while grounded
+	GroundEntityID *int
+
 	// Velocity      mgl64.Vec3
 	BaseVelocity  mgl64.Vec3
 	MovementSpeed float64
@@ -15,23 +18,3 @@ type ThirdPersonControllerComponent struct {
 	ControllerVelocity mgl64.Vec3
 	ZipVelocity        mgl64.Vec3
 }
-
-func (c *ThirdPersonControllerComponent) AddToComponentContainer(container *ComponentContainer) {
-	container.ThirdPersonControllerComponent = c
-}
-
-func (c *ThirdPersonControllerComponent) ComponentFlag() int {
-	return ComponentFlagThirdPersonController
-}
-
-func (c *ThirdPersonControllerComponent) Synchronized() bool {
-	return false
-}
-
-func (c *ThirdPersonControllerComponent) Load(bytes []byte) {
-	panic("wat")
-}
-
-func (c *ThirdPersonControllerComponent) Serialize() []byte {
-	panic("wat")
-}
@@ -0,0 +1,481 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/kkevinchou/kito/kito/settings"
+	"github.com/kkevinchou/kito/lib/logger"
+)
+
+const (
+	// DefaultPath is the config file that's read when --config isn't passed
+	DefaultPath = "config.json"
+
+	// envPrefix prefixes the environment variable of every setting, e.g. KITO_SERVER_PORT
+	envPrefix = "KITO_"
+)
+
+// Config holds every setting that can be configured without rebuilding. Each setting
+// can be set in the config file by its field name, in the environment with the KITO_
+// prefixed name of its flag, and on the command line with its flag.
+//
+// Settings tagged as runtime are reapplied when the config file changes while the game is
+// running, the rest are only read on startup
+type Config struct {
+	Mode            string `flag:"mode" usage:"client or server"`
+	AssetsDirectory string `flag:"assets" usage:"directory that assets are loaded from"`
+	ShaderDirectory string `flag:"shaders" usage:"directory that shaders are loaded from"`
+
+	ServerIP       string `flag:"server-ip" usage:"address the client connects to"`
+	ServerPort     int    `flag:"server-port" usage:"port the server listens on and the client connects to"`
+	ListenAddress  string `flag:"listen-address" usage:"address the server listens on"`
+	ConnectionType string `flag:"connection-type" usage:"network used for connections, one of tcp, tcp4 or tcp6"`
+
+	Width      int  `flag:"width" usage:"window width in pixels"`
+	Height     int  `flag:"height" usage:"window height in pixels"`
+	Fullscreen bool `flag:"fullscreen" usage:"run the client fullscreen"`
+
+	FPS                          int     `flag:"fps" usage:"frames rendered per second"`
+	MSPerCommandFrame            int     `flag:"ms-per-command-frame" usage:"milliseconds simulated per command frame"`
+	MaxCommandFramesPerTick      int     `flag:"max-command-frames-per-tick" usage:"command frames run per pass of the game loop before time is dropped"`
+	TimeScale                    float64 `flag:"time-scale" usage:"how quickly simulation time advances relative to wall time"`
+	MaxInputBufferCommandFrames  int     `flag:"input-buffer-frames" usage:"command frames of client input buffered on the server"`
+	MaxStateBufferCommandFrames  int     `flag:"state-buffer-frames" usage:"command frames of server state buffered on the client"`
+	CommandFramesPerServerUpdate int     `flag:"frames-per-server-update" usage:"command frames between server updates sent to clients"`
+
+	Seed             int64   `flag:"seed" usage:"random seed"`
+	Gravity          float64 `flag:"gravity" usage:"downward acceleration due to gravity"`
+	BroadphaseMargin float64 `flag:"broadphase-margin" usage:"distance colliders can move before the broadphase updates its tree"`
+
+	PProfEnabled       bool    `flag:"pprof" usage:"serve pprof profiles"`
+	PProfClientPort    int     `flag:"pprof-client-port" usage:"port the client serves pprof profiles on"`
+	PProfServerPort    int     `flag:"pprof-server-port" usage:"port the server serves pprof profiles on"`
+	MetricsEnabled     bool    `flag:"metrics" usage:"serve server metrics in the Prometheus text format"`
+	MetricsPort        int     `flag:"metrics-port" usage:"port the server serves metrics on"`
+	LatencyInjectionMS int     `flag:"latency-injection-ms" usage:"milliseconds of latency added to every connection"`
+	LineThickness      float64 `flag:"line-thickness" usage:"thickness of debug lines"`
+
+	LogFile   string `flag:"log-file" usage:"file that logs are also written to, rotated as it grows"`
+	LogLevels string `flag:"log-levels" usage:"log levels, e.g. info or warn,network=debug" runtime:"true"`
+
+	DebugRenderCollisionVolume bool `flag:"debug-collision-volumes" usage:"render collision volumes" runtime:"true"`
+	DebugRenderBroadphase      bool `flag:"debug-broadphase" usage:"render the broadphase tree and bounding boxes" runtime:"true"`
+	DebugRenderPhysics         bool `flag:"debug-physics" usage:"render the contacts, normals and sweeps recorded by the physics debugger" runtime:"true"`
+	ShowImguiDemoWindow        bool `flag:"imgui-demo" usage:"show the imgui demo window" runtime:"true"`
+	ParallelSystems            bool `flag:"parallel-systems" usage:"run systems with non-conflicting component access concurrently" runtime:"true"`
+}
+
+// defaults are captured from the settings before anything is applied to them so that
+// removing a setting from the config file reverts it to its default on reload
+var defaults = Defaults()
+
+// Defaults returns the config for the current settings
+func Defaults() Config {
+	return Config{
+		Mode:            "client",
+		AssetsDirectory: "_assets",
+		ShaderDirectory: "shaders",
+
+		ServerIP:       settings.Host,
+		ServerPort:     settings.Port,
+		ListenAddress:  settings.ListenAddress,
+		ConnectionType: settings.ConnectionType,
+
+		Width:      settings.Width,
+		Height:     settings.Height,
+		Fullscreen: settings.Fullscreen,
+
+		FPS:                          settings.FPS,
+		MSPerCommandFrame:            settings.MSPerCommandFrame,
+		MaxCommandFramesPerTick:      settings.MaxCommandFramesPerTick,
+		TimeScale:                    settings.TimeScale,
+		MaxInputBufferCommandFrames:  settings.MaxInputBufferCommandFrames,
+		MaxStateBufferCommandFrames:  settings.MaxStateBufferCommandFrames,
+		CommandFramesPerServerUpdate: settings.CommandFramesPerServerUpdate,
+
+		Seed:             settings.Seed,
+		Gravity:          settings.Gravity,
+		BroadphaseMargin: settings.BroadphaseMargin,
+
+		PProfEnabled:       settings.PProfEnabled,
+		PProfClientPort:    settings.PProfClientPort,
+		PProfServerPort:    settings.PProfServerPort,
+		MetricsEnabled:     settings.MetricsEnabled,
+		MetricsPort:        settings.MetricsPort,
+		LatencyInjectionMS: int(settings.LatencyInjection.Milliseconds()),
+		LineThickness:      settings.DefaultLineThickness,
+
+		LogLevels: "info",
+
+		DebugRenderCollisionVolume: settings.DebugRenderCollisionVolume,
+		DebugRenderBroadphase:      settings.DebugRenderBroadphase,
+		DebugRenderPhysics:         settings.DebugRenderPhysics,
+		ShowImguiDemoWindow:        settings.ShowImguiDemoWindow,
+		ParallelSystems:            settings.ParallelSystems,
+	}
+}
+
+// Layers are the sources a config is resolved from. From lowest to highest precedence
+// they are the defaults, the config file, environment variables and command line flags.
+// The environment and flags are captured once on startup so that the config can be
+// resolved again when the config file changes
+type Layers struct {
+	// Path is the config file
+	Path string
+
+	// pathRequired is set when the path was passed explicitly, otherwise a missing config
+	// file means the defaults are used
+	pathRequired bool
+
+	// env and flags map field names to the unparsed values set for them
+	env   map[string]string
+	flags map[string]string
+}
+
+// ParseLayers parses the command line arguments and captures the KITO_ variables from
+// the environment. For backwards compatibility the mode can also be passed as the only
+// positional argument
+func ParseLayers(args []string, environ []string) (*Layers, error) {
+	l := &Layers{
+		env:   map[string]string{},
+		flags: map[string]string{},
+	}
+
+	flagSet := flag.NewFlagSet("kito", flag.ContinueOnError)
+	flagSet.StringVar(&l.Path, "config", DefaultPath, "config file to load")
+
+	envNames := map[string]string{}
+	forEachField(func(field reflect.StructField, value reflect.Value) {
+		name := field.Tag.Get("flag")
+		envNames[envName(name)] = field.Name
+		flagSet.Var(&rawFlag{
+			values:  l.flags,
+			field:   field.Name,
+			isBool:  field.Type.Kind() == reflect.Bool,
+			initial: fmt.Sprint(value.Interface()),
+		}, name, field.Tag.Get("usage"))
+	}, reflect.ValueOf(defaults))
+
+	if err := flagSet.Parse(args); err != nil {
+		return nil, err
+	}
+	flagSet.Visit(func(f *flag.Flag) {
+		if f.Name == "config" {
+			l.pathRequired = true
+		}
+	})
+
+	switch flagSet.NArg() {
+	case 0:
+	case 1:
+		if _, ok := l.flags["Mode"]; !ok {
+			l.flags["Mode"] = flagSet.Arg(0)
+		}
+	default:
+		// reported the same way the flag package reports bad flags
+		err := fmt.Errorf("unexpected arguments %v", flagSet.Args())
+		fmt.Fprintln(flagSet.Output(), err)
+		flagSet.Usage()
+		return nil, err
+	}
+
+	for _, entry := range environ {
+		name, value, ok := strings.Cut(entry, "=")
+		if !ok {
+			continue
+		}
+		if field, ok := envNames[name]; ok {
+			l.env[field] = value
+		}
+	}
+
+	return l, nil
+}
+
+// Resolve layers the config file, environment and flags over the defaults and validates
+// the result
+func (l *Layers) Resolve() (Config, error) {
+	c := defaults
+
+	if err := loadFile(l.Path, &c); err != nil {
+		if !errors.Is(err, fs.ErrNotExist) || l.pathRequired {
+			return c, err
+		}
+	}
+
+	var problems []string
+	for _, layer := range []struct {
+		values map[string]string
+		source func(field reflect.StructField) string
+	}{
+		{values: l.env, source: func(field reflect.StructField) string {
+			return "environment variable " + envName(field.Tag.Get("flag"))
+		}},
+		{values: l.flags, source: func(field reflect.StructField) string {
+			return "flag --" + field.Tag.Get("flag")
+		}},
+	} {
+		forEachField(func(field reflect.StructField, value reflect.Value) {
+			raw, ok := layer.values[field.Name]
+			if !ok {
+				return
+			}
+			if err := setField(value, raw); err != nil {
+				problems = append(problems, fmt.Sprintf("%s: %s", layer.source(field), err))
+			}
+		}, reflect.ValueOf(&c).Elem())
+	}
+	if len(problems) > 0 {
+		return c, newConfigError("invalid settings", problems)
+	}
+
+	c.Mode = strings.ToLower(c.Mode)
+	return c, c.Validate()
+}
+
+// loadFile reads the config file over the config. Unknown settings are reported rather
+// than ignored so that misspelled settings don't silently fall back to their defaults
+func loadFile(path string, c *Config) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(c); err != nil {
+		return fmt.Errorf("failed to load %s: %w", path, err)
+	}
+	return nil
+}
+
+// Validate reports every setting with a bad value
+func (c Config) Validate() error {
+	var problems []string
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			problems = append(problems, fmt.Sprintf(format, args...))
+		}
+	}
+
+	check(c.Mode == "client" || c.Mode == "server", "mode must be client or server but was %q", c.Mode)
+	check(isDirectory(c.AssetsDirectory), "assets directory %q does not exist", c.AssetsDirectory)
+	if c.Mode == "client" {
+		check(isDirectory(c.ShaderDirectory), "shader directory %q does not exist", c.ShaderDirectory)
+	}
+
+	check(validPort(c.ServerPort), "server port %d must be between 1 and 65535", c.ServerPort)
+	check(c.ConnectionType == "tcp" || c.ConnectionType == "tcp4" || c.ConnectionType == "tcp6",
+		"connection type must be one of tcp, tcp4 or tcp6 but was %q", c.ConnectionType)
+	if c.Mode == "client" && !c.Fullscreen {
+		check(c.Width > 0 && c.Height > 0, "window size %dx%d must be positive", c.Width, c.Height)
+	}
+
+	check(c.FPS > 0, "fps must be positive but was %d", c.FPS)
+	check(c.MSPerCommandFrame > 0, "ms per command frame must be positive but was %d", c.MSPerCommandFrame)
+	check(c.MaxCommandFramesPerTick > 0, "max command frames per tick must be positive but was %d", c.MaxCommandFramesPerTick)
+	check(c.TimeScale > 0, "time scale must be positive but was %g", c.TimeScale)
+	check(c.MaxInputBufferCommandFrames > 0, "input buffer frames must be positive but was %d", c.MaxInputBufferCommandFrames)
+	check(c.MaxStateBufferCommandFrames > 0, "state buffer frames must be positive but was %d", c.MaxStateBufferCommandFrames)
+	check(c.CommandFramesPerServerUpdate > 0, "frames per server update must be positive but was %d", c.CommandFramesPerServerUpdate)
+
+	check(c.BroadphaseMargin >= 0, "broadphase margin must not be negative but was %g", c.BroadphaseMargin)
+
+	if c.PProfEnabled {
+		check(validPort(c.PProfClientPort), "pprof client port %d must be between 1 and 65535", c.PProfClientPort)
+		check(validPort(c.PProfServerPort), "pprof server port %d must be between 1 and 65535", c.PProfServerPort)
+	}
+	if c.MetricsEnabled {
+		check(validPort(c.MetricsPort), "metrics port %d must be between 1 and 65535", c.MetricsPort)
+	}
+	check(c.LatencyInjectionMS >= 0, "latency injection must not be negative but was %dms", c.LatencyInjectionMS)
+	check(c.LineThickness > 0, "line thickness must be positive but was %g", c.LineThickness)
+	if _, _, err := logger.ParseLevels(c.LogLevels); err != nil {
+		problems = append(problems, err.Error())
+	}
+
+	if len(problems) > 0 {
+		return newConfigError("invalid config", problems)
+	}
+	return nil
+}
+
+// Apply applies every setting in the config. Settings other than the runtime settings
+// are only read on startup so this should be called before the game is created
+func Apply(c Config) {
+	settings.Host = c.ServerIP
+	settings.Port = c.ServerPort
+	settings.ListenAddress = c.ListenAddress
+	settings.ConnectionType = c.ConnectionType
+
+	settings.Width = c.Width
+	settings.Height = c.Height
+	settings.Fullscreen = c.Fullscreen
+
+	settings.FPS = c.FPS
+	settings.MSPerCommandFrame = c.MSPerCommandFrame
+	settings.MaxCommandFramesPerTick = c.MaxCommandFramesPerTick
+	settings.TimeScale = c.TimeScale
+	settings.MaxInputBufferCommandFrames = c.MaxInputBufferCommandFrames
+	settings.MaxStateBufferCommandFrames = c.MaxStateBufferCommandFrames
+	settings.CommandFramesPerServerUpdate = c.CommandFramesPerServerUpdate
+
+	settings.Seed = c.Seed
+	settings.Gravity = c.Gravity
+	settings.AccelerationDueToGravity = mgl64.Vec3{0, -c.Gravity, 0}
+	settings.BroadphaseMargin = c.BroadphaseMargin
+
+	settings.PProfEnabled = c.PProfEnabled
+	settings.PProfClientPort = c.PProfClientPort
+	settings.PProfServerPort = c.PProfServerPort
+	settings.MetricsEnabled = c.MetricsEnabled
+	settings.MetricsPort = c.MetricsPort
+	settings.LatencyInjection = time.Duration(c.LatencyInjectionMS) * time.Millisecond
+	settings.DefaultLineThickness = c.LineThickness
+
+	settings.DebugRenderCollisionVolume = c.DebugRenderCollisionVolume
+	settings.DebugRenderBroadphase = c.DebugRenderBroadphase
+	settings.DebugRenderPhysics = c.DebugRenderPhysics
+	settings.ShowImguiDemoWindow = c.ShowImguiDemoWindow
+	settings.ParallelSystems = c.ParallelSystems
+
+	// the levels were checked when the config was validated
+	logger.SetLevels(c.LogLevels)
+}
+
+// ApplyRuntime applies the runtime settings that changed between the configs. Unchanged
+// settings are left alone so that changes made from the console aren't undone by
+// unrelated edits to the config file
+func ApplyRuntime(previous Config, current Config) {
+	if previous.DebugRenderCollisionVolume != current.DebugRenderCollisionVolume {
+		settings.DebugRenderCollisionVolume = current.DebugRenderCollisionVolume
+	}
+	if previous.DebugRenderBroadphase != current.DebugRenderBroadphase {
+		settings.DebugRenderBroadphase = current.DebugRenderBroadphase
+	}
+	if previous.DebugRenderPhysics != current.DebugRenderPhysics {
+		settings.DebugRenderPhysics = current.DebugRenderPhysics
+	}
+	if previous.ShowImguiDemoWindow != current.ShowImguiDemoWindow {
+		settings.ShowImguiDemoWindow = current.ShowImguiDemoWindow
+	}
+	if previous.ParallelSystems != current.ParallelSystems {
+		settings.ParallelSystems = current.ParallelSystems
+	}
+	if previous.LogLevels != current.LogLevels {
+		logger.SetLevels(current.LogLevels)
+	}
+}
+
+// RestartRequired returns the settings that differ between the configs that only take
+// effect on startup
+func RestartRequired(previous Config, current Config) []string {
+	var changed []string
+	p := reflect.ValueOf(previous)
+	forEachField(func(field reflect.StructField, value reflect.Value) {
+		if field.Tag.Get("runtime") == "true" {
+			return
+		}
+		if value.Interface() != p.FieldByIndex(field.Index).Interface() {
+			changed = append(changed, field.Name)
+		}
+	}, reflect.ValueOf(current))
+	return changed
+}
+
+type configError struct {
+	message  string
+	problems []string
+}
+
+func newConfigError(message string, problems []string) error {
+	return &configError{message: message, problems: problems}
+}
+
+func (e *configError) Error() string {
+	return e.message + ":\n\t" + strings.Join(e.problems, "\n\t")
+}
+
+// rawFlag records the unparsed value of a flag so that flags can be layered over the
+// config file, which is only read after the flags have been parsed
+type rawFlag struct {
+	values  map[string]string
+	field   string
+	isBool  bool
+	initial string
+}
+
+func (f *rawFlag) String() string {
+	if f == nil {
+		return ""
+	}
+	return f.initial
+}
+
+func (f *rawFlag) Set(value string) error {
+	f.values[f.field] = value
+	return nil
+}
+
+func (f *rawFlag) IsBoolFlag() bool {
+	return f.isBool
+}
+
+func forEachField(fn func(field reflect.StructField, value reflect.Value), config reflect.Value) {
+	t := config.Type()
+	for i := 0; i < t.NumField(); i++ {
+		fn(t.Field(i), config.Field(i))
+	}
+}
+
+func setField(value reflect.Value, raw string) error {
+	switch value.Kind() {
+	case reflect.String:
+		value.SetString(raw)
+	case reflect.Int, reflect.Int64:
+		parsed, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return fmt.Errorf("%q is not an integer", raw)
+		}
+		value.SetInt(parsed)
+	case reflect.Float64:
+		parsed, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return fmt.Errorf("%q is not a number", raw)
+		}
+		value.SetFloat(parsed)
+	case reflect.Bool:
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("%q is not a boolean", raw)
+		}
+		value.SetBool(parsed)
+	default:
+		return fmt.Errorf("unsupported setting type %s", value.Type())
+	}
+	return nil
+}
+
+func envName(flagName string) string {
+	return envPrefix + strings.ToUpper(strings.ReplaceAll(flagName, "-", "_"))
+}
+
+func validPort(port int) bool {
+	return port > 0 && port <= 65535
+}
+
+func isDirectory(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
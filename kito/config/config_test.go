@@ -0,0 +1,173 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kkevinchou/kito/kito/config"
+	"github.com/kkevinchou/kito/kito/settings"
+)
+
+func writeConfig(t *testing.T, contents string) string {
+	directory := t.TempDir()
+	path := filepath.Join(directory, "config.json")
+	if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// directoryArgs points the config at directories that exist so that it passes validation
+func directoryArgs(t *testing.T) []string {
+	return []string{"--assets", t.TempDir(), "--shaders", t.TempDir()}
+}
+
+func resolve(t *testing.T, args []string, environ []string) (config.Config, error) {
+	layers, err := config.ParseLayers(args, environ)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return layers.Resolve()
+}
+
+func TestLayering(t *testing.T) {
+	path := writeConfig(t, `{"serverip": "file", "serverport": 9000, "width": 1920, "fps": 30}`)
+	args := append(directoryArgs(t), "--config", path, "--server-port", "9002", "--fullscreen")
+	environ := []string{"KITO_SERVER_PORT=9001", "KITO_WIDTH=800", "PATH=/bin"}
+
+	c, err := resolve(t, args, environ)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if c.ServerIP != "file" || c.FPS != 30 {
+		t.Errorf("expected settings only in the file to come from the file but got %q and %d", c.ServerIP, c.FPS)
+	}
+	if c.Width != 800 {
+		t.Errorf("expected the environment to override the file but got width %d", c.Width)
+	}
+	if c.ServerPort != 9002 {
+		t.Errorf("expected flags to override the environment but got port %d", c.ServerPort)
+	}
+	if !c.Fullscreen {
+		t.Error("expected a boolean flag without a value to be set")
+	}
+	if c.Height != config.Defaults().Height || c.MSPerCommandFrame != settings.MSPerCommandFrame {
+		t.Error("expected unset settings to keep their defaults")
+	}
+}
+
+func TestMode(t *testing.T) {
+	directories := directoryArgs(t)
+	missing := filepath.Join(t.TempDir(), "config.json")
+
+	c, err := resolve(t, append([]string{"--config", missing}, directories...), nil)
+	if err == nil {
+		t.Fatal("expected an explicitly passed config file to be required")
+	}
+
+	layers, err := config.ParseLayers(append(directories, "SERVER"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	layers.Path = missing
+	if c, err = layers.Resolve(); err != nil {
+		t.Fatal(err)
+	}
+	if c.Mode != "server" {
+		t.Errorf("expected the positional mode to be used but got %q", c.Mode)
+	}
+
+	layers, err = config.ParseLayers(append(directories, "--mode", "client", "server"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	layers.Path = missing
+	if c, err = layers.Resolve(); err != nil {
+		t.Fatal(err)
+	}
+	if c.Mode != "client" {
+		t.Errorf("expected --mode to take precedence over the positional mode but got %q", c.Mode)
+	}
+}
+
+func TestValidation(t *testing.T) {
+	path := writeConfig(t, `{"serverport": 70000, "fps": 0}`)
+	args := []string{"--config", path, "--mode", "local", "--assets", filepath.Join(t.TempDir(), "missing"), "--log-levels", "network=loud"}
+
+	_, err := resolve(t, args, []string{"KITO_TIME_SCALE=-1"})
+	if err == nil {
+		t.Fatal("expected the config to be invalid")
+	}
+	for _, expected := range []string{"mode", "assets directory", "server port", "fps", "time scale", "log level"} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("expected %q to be reported in %q", expected, err)
+		}
+	}
+}
+
+func TestBadValues(t *testing.T) {
+	path := writeConfig(t, `{"serverport": 8080}`)
+	args := append(directoryArgs(t), "--config", path, "--fps", "fast")
+
+	_, err := resolve(t, args, []string{"KITO_FULLSCREEN=maybe"})
+	if err == nil {
+		t.Fatal("expected values that can't be parsed to be reported")
+	}
+	for _, expected := range []string{"flag --fps", "KITO_FULLSCREEN"} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("expected %q to be reported in %q", expected, err)
+		}
+	}
+
+	for _, contents := range []string{`{"serverport": "not a port"}`, `{"serverprot": 8080}`} {
+		args := append(directoryArgs(t), "--config", writeConfig(t, contents))
+		if _, err := resolve(t, args, nil); err == nil {
+			t.Errorf("expected %s to fail to load", contents)
+		}
+	}
+
+	if _, err := config.ParseLayers([]string{"--not-a-flag"}, nil); err == nil {
+		t.Error("expected an unknown flag to fail")
+	}
+	if _, err := config.ParseLayers([]string{"client", "server"}, nil); err == nil {
+		t.Error("expected more than one positional argument to fail")
+	}
+}
+
+func TestApplyRuntime(t *testing.T) {
+	previous := config.Defaults()
+	current := previous
+	current.DebugRenderCollisionVolume = !previous.DebugRenderCollisionVolume
+
+	settings.DebugRenderCollisionVolume = previous.DebugRenderCollisionVolume
+	settings.DebugRenderBroadphase = !previous.DebugRenderBroadphase
+	config.ApplyRuntime(previous, current)
+
+	if settings.DebugRenderCollisionVolume != current.DebugRenderCollisionVolume {
+		t.Error("expected the changed runtime setting to be applied")
+	}
+	if settings.DebugRenderBroadphase == current.DebugRenderBroadphase {
+		t.Error("expected an unchanged runtime setting to be left alone")
+	}
+	settings.DebugRenderCollisionVolume = previous.DebugRenderCollisionVolume
+	settings.DebugRenderBroadphase = previous.DebugRenderBroadphase
+}
+
+func TestRestartRequired(t *testing.T) {
+	previous := config.Config{ServerIP: "localhost", ServerPort: 8080, Width: 1280, Height: 720}
+	current := previous
+	current.Width = 1920
+	current.Height = 1080
+	current.ParallelSystems = true
+
+	restart := config.RestartRequired(previous, current)
+	if len(restart) != 2 || restart[0] != "Width" || restart[1] != "Height" {
+		t.Fatalf("expected Width and Height to require a restart but got %v", restart)
+	}
+	if restart := config.RestartRequired(previous, previous); len(restart) != 0 {
+		t.Fatalf("expected no changes but got %v", restart)
+	}
+}
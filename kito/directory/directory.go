@@ -18,6 +18,8 @@ type IAssetManager interface {
 	GetTexture(name string) *textures.Texture
 	GetFont(name string) font.Font
 	GetModel(name string) *modelspec.ModelSpecification
+	ReloadModel(name string, path string) (*modelspec.ModelSpecification, *modelspec.ModelSpecification, error)
+	ReloadTexture(name string, path string) error
 }
 
 type IRenderSystem interface {
@@ -27,6 +29,7 @@ type IRenderSystem interface {
 type IShaderManager interface {
 	CompileShaderProgram(name, vertexShader, fragmentShader string) error
 	GetShaderProgram(name string) *shaders.ShaderProgram
+	ReloadShader(path string) error
 }
 
 type IPlayerManager interface {
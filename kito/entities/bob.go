@@ -52,6 +52,7 @@ func NewBob() *EntityImpl {
 		CapsuleCollider:     &capsule,
 		BoundingBoxCollider: boundingBox,
 		Contacts:            map[int]bool{},
+		Layer:               types.CollisionLayerCharacter,
 	}
 
 	thirdPersonControllerComponent := &components.ThirdPersonControllerComponent{
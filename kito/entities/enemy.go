@@ -48,6 +48,7 @@ func NewEnemy() *EntityImpl {
 		BoundingBoxCollider: boundingBox,
 		CapsuleCollider:     &capsule,
 		Contacts:            map[int]bool{},
+		Layer:               types.CollisionLayerCharacter,
 	}
 
 	entityComponents := []components.Component{
@@ -1,20 +1,13 @@
 package entities
 
 import (
-	"sync"
-
 	"github.com/kkevinchou/kito/kito/components"
-	"github.com/kkevinchou/kito/kito/settings"
 	"github.com/kkevinchou/kito/kito/types"
 )
 
-var (
-	nextEntityID      int = settings.EntityIDStart
-	nextEntityIDMutex sync.Mutex
-)
-
 type Entity interface {
 	GetID() int
+	SetID(id int)
 	Type() types.EntityType
 	GetName() string
 	GetComponentContainer() *components.ComponentContainer
@@ -27,10 +20,10 @@ type EntityImpl struct {
 	ComponentContainer *components.ComponentContainer
 }
 
+// NewEntity creates an entity without an ID. The world assigns it an ID from its
+// entity ID allocator when the entity is registered
 func NewEntity(name string, entityType types.EntityType, componentContainer *components.ComponentContainer) *EntityImpl {
-	entityID := GetAndIncNextEntityID()
 	e := EntityImpl{
-		ID:                 entityID,
 		entityType:         entityType,
 		Name:               name,
 		ComponentContainer: componentContainer,
@@ -50,15 +43,10 @@ func (e *EntityImpl) GetID() int {
 	return e.ID
 }
 
-func (e *EntityImpl) Type() types.EntityType {
-	return e.entityType
+func (e *EntityImpl) SetID(id int) {
+	e.ID = id
 }
 
-func GetAndIncNextEntityID() int {
-	nextEntityIDMutex.Lock()
-	defer nextEntityIDMutex.Unlock()
-	nextID := nextEntityID
-	nextEntityID += 1
-
-	return nextID
+func (e *EntityImpl) Type() types.EntityType {
+	return e.entityType
 }
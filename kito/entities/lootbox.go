@@ -7,6 +7,7 @@ import (
 	"github.com/kkevinchou/kito/kito/types"
 	"github.com/kkevinchou/kito/lib/collision/collider"
 	"github.com/kkevinchou/kito/lib/model"
+	"github.com/kkevinchou/kito/lib/physics"
 )
 
 func NewLootbox() *EntityImpl {
@@ -32,20 +33,34 @@ func NewLootbox() *EntityImpl {
 		Model:       m,
 	}
 
-	// triMeshCollider := collider.NewTriMesh(m)
-	// boundingBox := collider.BoundingBoxFromModel(m)
-	capsuleCollider := collider.NewCapsuleFromModel(m)
-	boundingBox := collider.BoundingBoxFromCapsule(capsuleCollider)
+	// the box is rotated the same way as the mesh so that it lines up with the model
+	boxCollider := collider.NewBoxFromBoundingBox(*collider.BoundingBoxFromModel(m)).Rotate(mgl64.Mat4ToQuat(yr))
 
+	// the bounding box has to contain the box however the lootbox is rotated
+	reach := boxCollider.Center.Len() + boxCollider.HalfExtents.Len()
+	boundingBox := &collider.BoundingBox{
+		MinVertex: mgl64.Vec3{-reach, -reach, -reach},
+		MaxVertex: mgl64.Vec3{reach, reach, reach},
+	}
+
+	// lootboxes land on the ground and each other but are picked up by characters walking
+	// into them rather than blocking them
 	colliderComponent := &components.ColliderComponent{
-		CapsuleCollider: &capsuleCollider,
-		// TriMeshCollider:     &triMeshCollider,
+		BoxCollider:         &boxCollider,
 		BoundingBoxCollider: boundingBox,
 		Contacts:            map[int]bool{},
+		Layer:               types.CollisionLayerLoot,
+		Mask:                types.CollisionLayerDefault | types.CollisionLayerTerrain | types.CollisionLayerLoot,
+		TriggerMask:         types.CollisionLayerCharacter,
 	}
 
+	mass := 1.0
+	rigidBody := physics.NewBody(mass, physics.BoxInertia(mass, boxCollider.HalfExtents), 0.3, 0.6)
+	rigidBody.CenterOfMass = boxCollider.Center
+
 	physicsComponent := &components.PhysicsComponent{
-		Impulses: map[string]types.Impulse{},
+		Impulses:  map[string]types.Impulse{},
+		RigidBody: rigidBody,
 	}
 
 	entityComponents := []components.Component{
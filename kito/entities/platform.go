@@ -0,0 +1,26 @@
+package entities
+
+import (
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/kkevinchou/kito/kito/types"
+	"github.com/kkevinchou/kito/lib/physics"
+)
+
+// the path is fixed per entity type since the client spawns platforms from their type
+// and has to move them the same way the server does
+var platformPath = physics.Path{
+	Waypoints: []mgl64.Vec3{{-200, 0, -200}, {-200, 75, -200}, {-50, 75, -200}},
+	Speed:     40,
+}
+
+// NewPlatform creates a kinematic body that carries characters along its path
+func NewPlatform() *EntityImpl {
+	entity := NewRigidBody("cubetest2", mgl64.Ident4(), mgl64.Ident4(), types.EntityTypePlatform)
+
+	cc := entity.GetComponentContainer()
+	cc.PhysicsComponent.Static = false
+	cc.PhysicsComponent.Motion = platformPath
+	cc.TransformComponent.Position = platformPath.PositionAt(0)
+
+	return entity
+}
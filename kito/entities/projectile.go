@@ -40,6 +40,8 @@ func NewProjectile(position mgl64.Vec3) *EntityImpl {
 		SkipSeparation:      true,
 		CapsuleCollider:     &capsule,
 		Contacts:            map[int]bool{},
+		Layer:               types.CollisionLayerProjectile,
+		Mask:                types.CollisionLayerDefault | types.CollisionLayerTerrain | types.CollisionLayerCharacter,
 	}
 
 	physicsComponent := &components.PhysicsComponent{
@@ -54,6 +56,7 @@ func NewProjectile(position mgl64.Vec3) *EntityImpl {
 		meshComponent,
 		colliderComponent,
 		renderComponent,
+		components.NewScriptComponent("fireball"),
 	}
 
 	entity := NewEntity(
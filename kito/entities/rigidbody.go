@@ -56,6 +56,7 @@ func NewRigidBody(modelName string, Scale mgl64.Mat4, Orientation mgl64.Mat4, en
 		TriMeshCollider:     &triMesh,
 		BoundingBoxCollider: boundingBox,
 		Contacts:            map[int]bool{},
+		Layer:               types.CollisionLayerTerrain,
 	}
 
 	renderComponent := &components.RenderComponent{
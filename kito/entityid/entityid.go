@@ -0,0 +1,149 @@
+package entityid
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Entity IDs are handles that pack a namespace, a generation and an index into a single
+// int so that they can keep being passed around and serialized as plain ints.
+//
+//	bit 30       namespace
+//	bits 20 - 29 generation
+//	bits 0 - 19  index
+//
+// Indices are recycled once their entity is released and the generation is bumped
+// every time an index is reused, which lets stale handles to a released entity be
+// told apart from the entity that reused its index.
+const (
+	indexBits      = 20
+	generationBits = 10
+
+	indexMask      = 1<<indexBits - 1
+	generationMask = 1<<generationBits - 1
+	namespaceShift = indexBits + generationBits
+
+	MaxIndex = indexMask
+)
+
+// Namespace separates IDs allocated by the server from IDs allocated locally by a
+// client, e.g. for predicted or purely cosmetic entities, so that they never collide
+type Namespace int
+
+const (
+	NamespaceServer Namespace = 0
+	NamespaceClient Namespace = 1
+)
+
+func (n Namespace) String() string {
+	if n == NamespaceServer {
+		return "server"
+	}
+	return "client"
+}
+
+func New(namespace Namespace, generation int, index int) int {
+	return int(namespace)<<namespaceShift | (generation&generationMask)<<indexBits | index&indexMask
+}
+
+func NamespaceOf(id int) Namespace {
+	return Namespace(id >> namespaceShift)
+}
+
+func Generation(id int) int {
+	return (id >> indexBits) & generationMask
+}
+
+func Index(id int) int {
+	return id & indexMask
+}
+
+func Format(id int) string {
+	return fmt.Sprintf("%s:%d:%d", NamespaceOf(id), Index(id), Generation(id))
+}
+
+// Allocator hands out entity IDs within a single namespace
+type Allocator struct {
+	// systems can spawn entities concurrently when the scheduler runs them in parallel
+	mutex sync.Mutex
+
+	namespace   Namespace
+	generations []int
+	alive       []bool
+
+	// released indices are reused oldest first so that a handle has as long as
+	// possible before its index comes back around
+	free []int
+}
+
+func NewAllocator(namespace Namespace) *Allocator {
+	return &Allocator{
+		namespace: namespace,
+		// index 0 is never handed out so that an ID of 0 always means no entity
+		generations: []int{0},
+		alive:       []bool{false},
+	}
+}
+
+func (a *Allocator) Namespace() Namespace {
+	return a.namespace
+}
+
+func (a *Allocator) Allocate() int {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	var index int
+	if len(a.free) > 0 {
+		index = a.free[0]
+		a.free = a.free[1:]
+	} else {
+		index = len(a.generations)
+		if index > MaxIndex {
+			panic(fmt.Sprintf("exhausted entity indices in the %s namespace", a.namespace))
+		}
+		a.generations = append(a.generations, 0)
+		a.alive = append(a.alive, false)
+	}
+
+	a.alive[index] = true
+	return New(a.namespace, a.generations[index], index)
+}
+
+// Release frees the ID's index for reuse. Releasing an ID from another namespace or a
+// stale ID is a no-op
+func (a *Allocator) Release(id int) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if !a.current(id) {
+		return
+	}
+
+	index := Index(id)
+	a.alive[index] = false
+	a.generations[index] = (a.generations[index] + 1) & generationMask
+	a.free = append(a.free, index)
+}
+
+// Stale returns true if the ID was allocated from this allocator but has since been released
+func (a *Allocator) Stale(id int) bool {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if id == 0 || NamespaceOf(id) != a.namespace || Index(id) >= len(a.generations) {
+		return false
+	}
+	return !a.current(id)
+}
+
+func (a *Allocator) current(id int) bool {
+	if NamespaceOf(id) != a.namespace {
+		return false
+	}
+	index := Index(id)
+	if index == 0 || index >= len(a.generations) {
+		return false
+	}
+	return a.alive[index] && a.generations[index] == Generation(id)
+}
@@ -0,0 +1,66 @@
+package entityid_test
+
+import (
+	"testing"
+
+	"github.com/kkevinchou/kito/kito/entityid"
+)
+
+func TestAllocate(t *testing.T) {
+	a := entityid.NewAllocator(entityid.NamespaceServer)
+	first := a.Allocate()
+	second := a.Allocate()
+
+	if first == 0 || second == 0 {
+		t.Fatal("expected 0 to never be allocated")
+	}
+	if first == second {
+		t.Fatalf("expected unique ids but got %d twice", first)
+	}
+}
+
+func TestReuse(t *testing.T) {
+	a := entityid.NewAllocator(entityid.NamespaceServer)
+	id := a.Allocate()
+	a.Release(id)
+
+	reused := a.Allocate()
+	if entityid.Index(reused) != entityid.Index(id) {
+		t.Fatalf("expected index %d to be reused but got %d", entityid.Index(id), entityid.Index(reused))
+	}
+	if reused == id {
+		t.Fatal("expected the reused index to have a new generation")
+	}
+	if !a.Stale(id) {
+		t.Fatal("expected the released id to be stale")
+	}
+	if a.Stale(reused) {
+		t.Fatal("expected the reused id to not be stale")
+	}
+
+	// releasing a stale handle must not free the index out from under the new entity
+	a.Release(id)
+	if a.Stale(reused) {
+		t.Fatal("expected releasing a stale id to be a no-op")
+	}
+}
+
+func TestNamespaces(t *testing.T) {
+	server := entityid.NewAllocator(entityid.NamespaceServer)
+	client := entityid.NewAllocator(entityid.NamespaceClient)
+
+	serverID := server.Allocate()
+	clientID := client.Allocate()
+
+	if serverID == clientID {
+		t.Fatalf("expected ids from different namespaces to differ but got %d", serverID)
+	}
+	if entityid.NamespaceOf(clientID) != entityid.NamespaceClient {
+		t.Fatalf("expected %s to be in the client namespace", entityid.Format(clientID))
+	}
+
+	client.Release(serverID)
+	if client.Stale(serverID) || server.Stale(serverID) {
+		t.Fatal("expected ids to only be released by their own namespace")
+	}
+}
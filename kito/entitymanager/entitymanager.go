@@ -1,10 +1,15 @@
 package entitymanager
 
 import (
+	"sync"
+
+	"github.com/kkevinchou/kito/kito/components"
 	"github.com/kkevinchou/kito/kito/entities"
 )
 
 type EntityManager struct {
+	// systems can register and query entities concurrently when the scheduler runs them in parallel
+	mutex     sync.RWMutex
 	entities  map[int]entities.Entity
 	entityIDs []int
 }
@@ -16,21 +21,29 @@ func NewEntityManager() *EntityManager {
 }
 
 func (em *EntityManager) RegisterEntity(e entities.Entity) {
+	em.mutex.Lock()
+	defer em.mutex.Unlock()
 	em.entities[e.GetID()] = e
 	em.entityIDs = append(em.entityIDs, e.GetID())
 }
 
 func (em *EntityManager) GetEntityByID(id int) entities.Entity {
+	em.mutex.RLock()
+	defer em.mutex.RUnlock()
 	return em.entities[id]
 }
 
 // TODO: cache queries
-func (em *EntityManager) Query(componentFlags int) []entities.Entity {
+func (em *EntityManager) Query(componentFlags ...int) []entities.Entity {
+	em.mutex.RLock()
+	defer em.mutex.RUnlock()
+	flags := components.NewBitset(componentFlags...)
+
 	var matches []entities.Entity
 	for _, id := range em.entityIDs {
 		e := em.entities[id]
 		cc := e.GetComponentContainer()
-		if cc.MatchBitFlags(componentFlags) {
+		if cc.MatchBitFlags(flags) {
 			matches = append(matches, e)
 		}
 	}
@@ -43,6 +56,8 @@ func (em *EntityManager) UnregisterEntity(e entities.Entity) {
 }
 
 func (em *EntityManager) UnregisterEntityByID(entityID int) {
+	em.mutex.Lock()
+	defer em.mutex.Unlock()
 	delete(em.entities, entityID)
 	var newEntityIDs []int
 	for _, id := range em.entityIDs {
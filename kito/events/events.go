@@ -8,6 +8,9 @@ var EventTypeUnregisterEntity EventType = "UNREGISTER"
 var EventTypePlayerCommand EventType = "PLAYERCOMMAND"
 var EventTypeConsoleEnabled EventType = "CONSOLE_ENABLED"
 var EventTypeRPC EventType = "RPC"
+var EventTypeTriggerEnter EventType = "TRIGGER_ENTER"
+var EventTypeTriggerStay EventType = "TRIGGER_STAY"
+var EventTypeTriggerExit EventType = "TRIGGER_EXIT"
 
 type Event interface {
 	Type() EventType
@@ -45,3 +48,34 @@ type RPCEvent struct {
 func (e *RPCEvent) Type() EventType {
 	return EventTypeRPC
 }
+
+// TriggerEnterEvent is published on the first frame an entity overlaps a trigger
+type TriggerEnterEvent struct {
+	TriggerEntityID int
+	EntityID        int
+}
+
+func (e *TriggerEnterEvent) Type() EventType {
+	return EventTypeTriggerEnter
+}
+
+// TriggerStayEvent is published on every later frame the entity still overlaps the trigger
+type TriggerStayEvent struct {
+	TriggerEntityID int
+	EntityID        int
+}
+
+func (e *TriggerStayEvent) Type() EventType {
+	return EventTypeTriggerStay
+}
+
+// TriggerExitEvent is published on the first frame the entity stops overlapping the
+// trigger, including when either of them is removed
+type TriggerExitEvent struct {
+	TriggerEntityID int
+	EntityID        int
+}
+
+func (e *TriggerExitEvent) Type() EventType {
+	return EventTypeTriggerExit
+}
@@ -0,0 +1,79 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// Registration describes how an event type is constructed and whether it's
+// replicated from the server to clients
+type Registration struct {
+	Type EventType
+
+	// Replicated events that are broadcast on the server are serialized and sent to
+	// clients with the next game state update
+	Replicated bool
+
+	// New returns an empty event of the registered type to deserialize into
+	New func() Event
+}
+
+var (
+	registryMutex sync.RWMutex
+	registry      = map[EventType]Registration{}
+)
+
+func init() {
+	Register(EventTypeUnregisterEntity, true, func() Event { return &UnregisterEntityEvent{} })
+	Register(EventTypePlayerCommand, false, func() Event { return &PlayerCommandEvent{} })
+	Register(EventTypeConsoleEnabled, false, func() Event { return &ConsoleEnabledEvent{} })
+	Register(EventTypeRPC, false, func() Event { return &RPCEvent{} })
+	Register(EventTypeTriggerEnter, false, func() Event { return &TriggerEnterEvent{} })
+	Register(EventTypeTriggerStay, false, func() Event { return &TriggerStayEvent{} })
+	Register(EventTypeTriggerExit, false, func() Event { return &TriggerExitEvent{} })
+}
+
+// Register adds an event type to the registry. Registering the same type twice panics
+func Register(eventType EventType, replicated bool, new func() Event) {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+
+	if _, ok := registry[eventType]; ok {
+		panic(fmt.Sprintf("event type %s is already registered", eventType))
+	}
+	registry[eventType] = Registration{Type: eventType, Replicated: replicated, New: new}
+}
+
+func Lookup(eventType EventType) (Registration, bool) {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+	registration, ok := registry[eventType]
+	return registration, ok
+}
+
+func IsReplicated(eventType EventType) bool {
+	registration, ok := Lookup(eventType)
+	return ok && registration.Replicated
+}
+
+func Serialize(event Event) ([]byte, error) {
+	if _, ok := Lookup(event.Type()); !ok {
+		return nil, fmt.Errorf("cannot serialize unregistered event type %s", event.Type())
+	}
+	return json.Marshal(event)
+}
+
+// Deserialize constructs an event of the registered type from its serialized bytes
+func Deserialize(eventType EventType, bytes []byte) (Event, error) {
+	registration, ok := Lookup(eventType)
+	if !ok {
+		return nil, fmt.Errorf("cannot deserialize unregistered event type %s", eventType)
+	}
+
+	event := registration.New()
+	if err := json.Unmarshal(bytes, event); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
@@ -33,6 +33,12 @@ func (g *Game) HandleInput(frameInput input.Input) {
 		}
 	}
 
+	if keyEvent, ok := keyboardInput[input.KeyboardKeyF2]; ok {
+		if keyEvent.Event == input.KeyboardEventUp {
+			g.ToggleWindowVisibility(types.WindowInspector)
+		}
+	}
+
 	if keyEvent, ok := keyboardInput[input.KeyboardKeyI]; ok {
 		if g.GetFocusedWindow() == types.WindowGame || g.GetFocusedWindow() == types.WindowInventory {
 			if keyEvent.Event == input.KeyboardEventUp {
@@ -1,15 +1,17 @@
 package inputbuffer
 
 import (
-	"fmt"
 	"time"
 
 	"github.com/kkevinchou/kito/kito/knetwork"
 	"github.com/kkevinchou/kito/kito/playercommand/protogen/playercommand"
 	"github.com/kkevinchou/kito/lib/input"
+	"github.com/kkevinchou/kito/lib/logger"
 	"google.golang.org/protobuf/proto"
 )
 
+var log = logger.New("inputbuffer")
+
 // InputBuffer is a buffer of inputs. Inputs are sent from clients and stored in the buffer
 // until the server is ready to consume them. The internet is a wild and scary place - inputs
 // from clients can arrive in bursts or with huge delays between each input. What the server
@@ -29,6 +31,7 @@ type BufferedInput struct {
 	Input                    input.Input
 	ReceivedTimestamp        time.Time
 	PlayerCommands           *playercommand.PlayerCommandList
+	SpawnKey                 int
 }
 
 type InputBuffer struct {
@@ -63,14 +66,14 @@ func (inputBuffer *InputBuffer) PushInput(globalCommandFrame int, localCommandFr
 		// the next command frame
 		if commandFrameDelta <= 0 {
 			commandFrameDelta = 1
-			fmt.Println("warning: received more than one input for a given command frame")
+			log.Warn("received more than one input for a command frame", logger.PlayerID(playerID))
 		}
 
 		targetGlobalCommandFrame = lastPlayerInput.TargetGlobalCommandFrame + commandFrameDelta
 
 		// target exceeds the buffer size. clamp it and send a warning message
 		if targetGlobalCommandFrame > maxTargetGlobalCommandFrame {
-			fmt.Printf("target gcf exceeded buffer size %d > %d\n", targetGlobalCommandFrame, (globalCommandFrame + inputBuffer.maxCommandFrames))
+			log.Warn("target command frame exceeded the buffer size", logger.PlayerID(playerID), logger.F("target", targetGlobalCommandFrame), logger.F("max", globalCommandFrame+inputBuffer.maxCommandFrames))
 			targetGlobalCommandFrame = maxTargetGlobalCommandFrame
 		}
 	}
@@ -88,6 +91,7 @@ func (inputBuffer *InputBuffer) PushInput(globalCommandFrame int, localCommandFr
 		Input:                    networkInput.Input,
 		ReceivedTimestamp:        receivedTime,
 		PlayerCommands:           playerCommands,
+		SpawnKey:                 networkInput.SpawnKey,
 	}
 	inputBuffer.lastPlayerInput[playerID] = targetGlobalCommandFrame
 }
@@ -121,6 +125,6 @@ func (inputBuffer *InputBuffer) PullInput(globalCommandFrame int, playerID int)
 		}
 	}
 
-	fmt.Printf("failed to fetch input for player %d cf %d\n", playerID, globalCommandFrame)
+	log.Debug("no buffered input for player", logger.PlayerID(playerID))
 	return nil
 }
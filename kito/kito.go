@@ -1,27 +1,31 @@
 package kito
 
 import (
-	"fmt"
 	"math/rand"
 	"time"
 
+	"github.com/kkevinchou/kito/kito/broadphase"
 	"github.com/kkevinchou/kito/kito/commandframe"
 	"github.com/kkevinchou/kito/kito/directory"
+	"github.com/kkevinchou/kito/kito/entityid"
 	"github.com/kkevinchou/kito/kito/entitymanager"
+	"github.com/kkevinchou/kito/kito/knetwork"
 	"github.com/kkevinchou/kito/kito/managers/eventbroker"
+	"github.com/kkevinchou/kito/kito/managers/timer"
+	"github.com/kkevinchou/kito/kito/scheduler"
 	"github.com/kkevinchou/kito/kito/settings"
-	"github.com/kkevinchou/kito/kito/spatialpartition"
 	"github.com/kkevinchou/kito/lib/input"
 	"github.com/kkevinchou/kito/lib/metrics"
+	"github.com/kkevinchou/kito/lib/physicsdebug"
+	"github.com/kkevinchou/kito/lib/profiler"
 
 	"github.com/kkevinchou/kito/kito/singleton"
 	"github.com/kkevinchou/kito/kito/types"
+	"github.com/kkevinchou/kito/kito/utils"
+	"github.com/kkevinchou/kito/lib/logger"
 )
 
-type System interface {
-	Name() string
-	Update(delta time.Duration)
-}
+var log = logger.New("kito")
 
 type RenderFunction func(delta time.Duration)
 
@@ -31,12 +35,14 @@ type Game struct {
 	gameOver bool
 	gameMode types.GameMode
 
-	singleton        *singleton.Singleton
-	entityManager    *entitymanager.EntityManager
-	spatialPartition *spatialpartition.SpatialPartition
-	systems          []System
+	singleton     *singleton.Singleton
+	entityManager *entitymanager.EntityManager
+	entityIDs     *entityid.Allocator
+	broadphase    *broadphase.Broadphase
+	scheduler     *scheduler.Scheduler
 
 	eventBroker     eventbroker.EventBroker
+	timerManager    *timer.Manager
 	metricsRegistry *metrics.MetricsRegistry
 
 	inputPollingFn input.InputPoller
@@ -47,6 +53,10 @@ type Game struct {
 	windowVisibility    map[types.Window]bool
 
 	serverStats map[string]string
+
+	// server authoritative, propagated to clients when changed
+	timeControl knetwork.TimeControlMessage
+	tick        int
 }
 
 func NewBaseGame() *Game {
@@ -54,8 +64,11 @@ func NewBaseGame() *Game {
 		gameMode:        types.GameModePlaying,
 		singleton:       singleton.NewSingleton(),
 		entityManager:   entitymanager.NewEntityManager(),
-		eventBroker:     eventbroker.NewEventBroker(),
+		entityIDs:       newEntityIDAllocator(),
+		eventBroker:     eventbroker.NewEventBroker(utils.IsServer()),
+		timerManager:    timer.NewManager(),
 		metricsRegistry: metrics.New(),
+		scheduler:       scheduler.New(),
 		inputPollingFn:  input.NullInputPoller,
 		focusedWindow:   types.WindowGame,
 		windowVisibility: map[types.Window]bool{
@@ -63,90 +76,150 @@ func NewBaseGame() *Game {
 		},
 	}
 
-	s := spatialpartition.NewSpatialPartition(g, settings.SpatialPartitionDimensionSize, settings.SpatialPartitionNumPartitions)
-	g.spatialPartition = s
+	g.scheduler.SetParallel(settings.ParallelSystems)
+	describeMetrics(g.metricsRegistry)
+	logger.SetCommandFrameFunc(g.CommandFrame)
+	g.timeControl = knetwork.TimeControlMessage{
+		TimeScale:               settings.TimeScale,
+		MaxCommandFramesPerTick: settings.MaxCommandFramesPerTick,
+	}
+
+	g.broadphase = broadphase.NewBroadphase(g, settings.BroadphaseMargin)
 	return g
 }
 
 func (g *Game) Start() {
-	var accumulator float64
-	var renderAccumulator float64
+	var accumulator time.Duration
+	var renderAccumulator time.Duration
 
-	msPerFrame := float64(1000) / float64(settings.FPS)
-	previousTimeStamp := float64(time.Now().UnixNano()) / 1000000
+	commandFrameDuration := time.Duration(settings.MSPerCommandFrame) * time.Millisecond
+	frameDuration := time.Second / time.Duration(settings.FPS)
+	previousTimeStamp := time.Now()
 
 	frameCount := 0
 	renderFunction := getRenderFunction()
 	for !g.gameOver {
-		now := float64(time.Now().UnixNano()) / 1000000
-		delta := now - previousTimeStamp
+		now := time.Now()
+		delta := now.Sub(previousTimeStamp)
 		previousTimeStamp = now
 
-		accumulator += delta
+		// time scale only affects how quickly command frames are run. the simulated delta
+		// of each command frame is fixed so that the simulation remains deterministic.
+		// while paused we keep ticking at the normal rate to poll input and run the
+		// systems needed to unpause
+		if g.timeControl.Paused {
+			accumulator += delta
+		} else {
+			accumulator += time.Duration(float64(delta) * g.timeControl.TimeScale)
+		}
 		renderAccumulator += delta
 
 		runCount := 0
-		timings := map[string]int{}
-		for accumulator >= float64(settings.MSPerCommandFrame) {
+		for accumulator >= commandFrameDuration && runCount < g.timeControl.MaxCommandFramesPerTick {
 			// input is handled once per command frame
+			g.tick++
 			g.HandleInput(g.inputPollingFn())
-			curTimings := g.runCommandFrame(time.Duration(settings.MSPerCommandFrame) * time.Millisecond)
-			for k, v := range curTimings {
-				timings[k] += v
-			}
 
-			// if timings["CollisionSystem"] != 0 {
-			// 	fmt.Println(timings["CollisionSystem"])
-			// }
+			if g.timeControl.Paused && g.timeControl.StepFrames == 0 {
+				g.eventBroker.FlushNextFrame()
+				g.scheduler.RunPaused(commandFrameDuration)
+				g.eventBroker.FlushEndOfFrame()
+			} else {
+				if g.timeControl.Paused {
+					g.timeControl.StepFrames--
+				}
 
-			accumulator -= float64(settings.MSPerCommandFrame)
-			runCount++
-			// var timingsTotal int
-			// for _, v := range timings {
-			// 	timingsTotal += v
-			// }
-			// fmt.Println(timingsTotal)
-
-		}
+				g.runCommandFrame(commandFrameDuration)
+			}
 
-		// prevents lighting my CPU on fire
-		if accumulator < float64(settings.MSPerCommandFrame)-10 {
-			time.Sleep(5 * time.Millisecond)
+			accumulator -= commandFrameDuration
+			runCount++
 		}
 
 		if runCount > 1 {
 			g.metricsRegistry.Inc("frameCatchup", 1)
 		}
 
-		if renderAccumulator >= msPerFrame {
+		// we weren't able to catch up within the allowed number of command frames, drop the
+		// remaining time rather than falling further behind on the next tick
+		if accumulator >= commandFrameDuration {
+			g.metricsRegistry.Inc("frameCatchupDropped", float64(accumulator/commandFrameDuration))
+			g.metricsRegistry.Counter("kito_command_frames_dropped_total").Add(float64(accumulator / commandFrameDuration))
+			accumulator %= commandFrameDuration
+		}
+
+		if renderAccumulator >= frameDuration {
 			frameCount++
 			g.metricsRegistry.Inc("fps", 1)
 			start := time.Now()
-			renderFunction(time.Duration(msPerFrame) * time.Millisecond)
+			timer := profiler.Begin("render")
+			renderFunction(frameDuration)
+			timer.End()
 			g.metricsRegistry.Inc("rendertime", float64(time.Since(start).Milliseconds()))
-			renderAccumulator -= msPerFrame
+			renderAccumulator -= frameDuration
+		}
+
+		// prevents lighting my CPU on fire. sleep until the next command frame or render
+		// is due, leaving some slack for the scheduler's wake up latency
+		untilNextFrame := frameDuration - renderAccumulator
+		untilNextCommandFrame := commandFrameDuration - accumulator
+		if !g.timeControl.Paused && g.timeControl.TimeScale > 0 {
+			untilNextCommandFrame = time.Duration(float64(untilNextCommandFrame) / g.timeControl.TimeScale)
+		}
+		sleepDuration := untilNextFrame
+		if untilNextCommandFrame < sleepDuration {
+			sleepDuration = untilNextCommandFrame
+		}
+		if sleepDuration > 2*time.Millisecond {
+			time.Sleep(sleepDuration - time.Millisecond)
 		}
 	}
 }
 
-func (g *Game) runCommandFrame(delta time.Duration) map[string]int {
-	result := map[string]int{}
+func (g *Game) runCommandFrame(delta time.Duration) {
 	g.singleton.CommandFrame++
-	var total int
-	for _, system := range g.systems {
-		start := time.Now()
-		system.Update(delta)
-		systemTime := int(time.Since(start).Milliseconds())
-		result[system.Name()] = systemTime
-		total += systemTime
+
+	// rendering between command frames is recorded as part of the preceding frame
+	profiler.BeginFrame(g.singleton.CommandFrame)
+	physicsdebug.BeginFrame(g.singleton.CommandFrame)
+	timer := profiler.Begin("command frame")
+	defer timer.End()
+
+	g.timerManager.Update(g.singleton.CommandFrame)
+
+	// systems can run concurrently so the frame time is measured rather than summed
+	start := time.Now()
+	g.eventBroker.FlushNextFrame()
+	result := g.scheduler.Run(delta)
+	g.eventBroker.FlushEndOfFrame()
+	frameTime := time.Since(start)
+
+	g.metricsRegistry.Inc("frametime", float64(frameTime.Milliseconds()))
+	g.metricsRegistry.Histogram("kito_frame_time_ms").Observe(metrics.Milliseconds(frameTime))
+	g.metricsRegistry.Counter("kito_command_frames_total").Inc()
+	for name, systemTime := range result {
+		g.metricsRegistry.Histogram("kito_system_time_ms", metrics.L("system", name)).Observe(metrics.Milliseconds(systemTime))
+	}
+}
+
+func (g *Game) registerSystem(system scheduler.System, phase scheduler.Phase, options ...scheduler.Option) {
+	if err := g.scheduler.Register(system, phase, options...); err != nil {
+		panic(err)
+	}
+}
+
+// the server allocates the IDs of every replicated entity, clients allocate from their
+// own namespace for entities that only exist locally
+func newEntityIDAllocator() *entityid.Allocator {
+	if utils.IsServer() {
+		return entityid.NewAllocator(entityid.NamespaceServer)
 	}
-	g.MetricsRegistry().Inc("frametime", float64(total))
-	return result
+	return entityid.NewAllocator(entityid.NamespaceClient)
 }
 
 func initSeed() {
 	seed := settings.Seed
-	fmt.Printf("initializing with seed %d ...\n", seed)
+	log.Info("initializing", logger.F("seed", seed))
 	rand.Seed(seed)
 }
 
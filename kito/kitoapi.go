@@ -1,15 +1,19 @@
 package kito
 
 import (
+	"github.com/kkevinchou/kito/kito/broadphase"
 	"github.com/kkevinchou/kito/kito/commandframe"
 	"github.com/kkevinchou/kito/kito/directory"
 	"github.com/kkevinchou/kito/kito/entities"
+	"github.com/kkevinchou/kito/kito/knetwork"
 	"github.com/kkevinchou/kito/kito/managers/eventbroker"
 	"github.com/kkevinchou/kito/kito/managers/player"
+	"github.com/kkevinchou/kito/kito/managers/timer"
+	"github.com/kkevinchou/kito/kito/scheduler"
 	"github.com/kkevinchou/kito/kito/singleton"
-	"github.com/kkevinchou/kito/kito/spatialpartition"
 	"github.com/kkevinchou/kito/kito/types"
 	"github.com/kkevinchou/kito/kito/utils"
+	"github.com/kkevinchou/kito/lib/logger"
 	"github.com/kkevinchou/kito/lib/metrics"
 )
 
@@ -18,6 +22,12 @@ func (g *Game) GetSingleton() *singleton.Singleton {
 }
 
 func (g *Game) GetEntityByID(id int) entities.Entity {
+	if g.entityIDs.Stale(id) {
+		// the entity was unregistered and its index may have been reused by another
+		// entity, so the handle must not resolve to anything
+		g.metricsRegistry.Counter("kito_stale_entity_lookups_total").Inc()
+		return nil
+	}
 	return g.entityManager.GetEntityByID(id)
 }
 
@@ -62,10 +72,18 @@ func (g *Game) CommandFrame() int {
 	return g.singleton.CommandFrame
 }
 
+func (g *Game) Tick() int {
+	return g.tick
+}
+
 func (g *Game) GetEventBroker() eventbroker.EventBroker {
 	return g.eventBroker
 }
 
+func (g *Game) TimerManager() *timer.Manager {
+	return g.timerManager
+}
+
 func (g *Game) GetCommandFrameHistory() *commandframe.CommandFrameHistory {
 	return g.commandFrameHistory
 }
@@ -74,20 +92,26 @@ func (g *Game) MetricsRegistry() *metrics.MetricsRegistry {
 	return g.metricsRegistry
 }
 
+// RegisterEntity registers the entity with the world, allocating an ID for it if it
+// doesn't have one yet. Entities replicated from the server arrive with their ID set
 func (g *Game) RegisterEntity(e entities.Entity) {
+	if e.GetID() == 0 {
+		e.SetID(g.entityIDs.Allocate())
+	}
 	g.entityManager.RegisterEntity(e)
 }
 
-func (g *Game) QueryEntity(componentFlags int) []entities.Entity {
-	return g.entityManager.Query(componentFlags)
+func (g *Game) QueryEntity(componentFlags ...int) []entities.Entity {
+	return g.entityManager.Query(componentFlags...)
 }
 
 func (g *Game) UnregisterEntity(entity entities.Entity) {
-	g.entityManager.UnregisterEntity(entity)
+	g.UnregisterEntityByID(entity.GetID())
 }
 
 func (g *Game) UnregisterEntityByID(entityID int) {
 	g.entityManager.UnregisterEntityByID(entityID)
+	g.entityIDs.Release(entityID)
 }
 
 func (g *Game) SetFocusedWindow(focusedWindow types.Window) {
@@ -111,8 +135,8 @@ func (g *Game) ToggleWindowVisibility(window types.Window) bool {
 	return g.windowVisibility[window]
 }
 
-func (g *Game) SpatialPartition() *spatialpartition.SpatialPartition {
-	return g.spatialPartition
+func (g *Game) Broadphase() *broadphase.Broadphase {
+	return g.broadphase
 }
 
 func (g *Game) SetServerStats(serverStats map[string]string) {
@@ -122,3 +146,27 @@ func (g *Game) SetServerStats(serverStats map[string]string) {
 func (g *Game) ServerStats() map[string]string {
 	return g.serverStats
 }
+
+func (g *Game) Scheduler() *scheduler.Scheduler {
+	return g.scheduler
+}
+
+func (g *Game) TimeControl() knetwork.TimeControlMessage {
+	return g.timeControl
+}
+
+// SetTimeControl updates the simulation clock. The server is the authority on the clock
+// and forwards any changes to every connected client
+func (g *Game) SetTimeControl(timeControl knetwork.TimeControlMessage) {
+	g.timeControl = timeControl
+	if !utils.IsServer() {
+		return
+	}
+
+	playerManager := directory.GetDirectory().PlayerManager()
+	for _, player := range playerManager.GetPlayers() {
+		if err := player.Client.SendMessage(knetwork.MessageTypeTimeControl, timeControl); err != nil {
+			log.Error("failed to send time control message", logger.PlayerID(player.ID), logger.Err(err))
+		}
+	}
+}
@@ -17,6 +17,8 @@ const (
 	MessageTypePing
 	MessageTypeAckPing
 	MessageTypeRPC
+	MessageTypeTimeControl
+	MessageTypeComponentEdit
 )
 
 type AcceptMessage struct {
@@ -29,11 +31,11 @@ type CreatePlayerMessage struct {
 type AckCreatePlayerMessage struct {
 	PlayerID    int
 	EntityID    int
-	CameraID    int
 	Position    mgl64.Vec3
 	Orientation mgl64.Quat
 
-	Entities map[int]EntitySnapshot
+	Entities    map[int]EntitySnapshot
+	TimeControl TimeControlMessage
 }
 
 type EntitySnapshot struct {
@@ -47,6 +49,11 @@ type EntitySnapshot struct {
 
 	Animation string
 
+	// SpawnKey and OwnerPlayerID are set for entities spawned in response to an input
+	// the owning client predicted the spawn for
+	SpawnKey      int
+	OwnerPlayerID int
+
 	Components map[int][]byte // protobuf
 }
 
@@ -73,10 +80,20 @@ type InputMessage struct {
 	PlayerCommands []byte // protobuf
 	CommandFrame   int
 	Input          input.Input
+
+	// SpawnKey is non zero when the client predicted spawning an entity on this command
+	// frame. The server tags the entity it spawns for the input with the same key
+	SpawnKey int
 }
 
+// PingMessage is sent by clients every command frame. Clients also report the network
+// health they observed over the last second so that the server can export it per player
 type PingMessage struct {
 	SendTime time.Time
+
+	RTTMilliseconds  float64
+	PredictionHits   int
+	PredictionMisses int
 }
 
 type AckPingMessage struct {
@@ -86,3 +103,31 @@ type AckPingMessage struct {
 type RPCMessage struct {
 	Command string
 }
+
+// ComponentEditMessage is the server's response to a component edit from the entity
+// inspector. Error is empty when the server applied the edit, in which case the client
+// applies it as well
+type ComponentEditMessage struct {
+	EntityID  int
+	Component string
+	Field     string
+	Value     string
+	Error     string
+}
+
+// TimeControlMessage is the server authoritative state of the simulation clock. It's
+// sent to clients whenever it changes so that they advance command frames at the
+// same rate as the server.
+type TimeControlMessage struct {
+	// TimeScale scales how quickly simulation time advances relative to wall time
+	TimeScale float64
+
+	// Paused freezes the simulation while still rendering
+	Paused bool
+
+	// StepFrames is the number of command frames to run while paused
+	StepFrames int
+
+	// MaxCommandFramesPerTick caps how many command frames are run to catch up after a hitch
+	MaxCommandFramesPerTick int
+}
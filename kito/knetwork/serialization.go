@@ -1,18 +1,19 @@
 package knetwork
 
 import (
-	"encoding/json"
-
 	"github.com/kkevinchou/kito/kito/events"
 )
 
-func Serialize(e events.Event) ([]byte, error) {
-	return json.Marshal(e)
-}
-
-func Deserialize(bytes []byte, event events.Event) {
-	err := json.Unmarshal(bytes, &event)
+// SerializeEvent converts an event into its network representation
+func SerializeEvent(e events.Event) (Event, error) {
+	bytes, err := events.Serialize(e)
 	if err != nil {
-		panic(err)
+		return Event{}, err
 	}
+	return Event{Type: e.Type(), Bytes: bytes}, nil
+}
+
+// DeserializeEvent reconstructs an event through the event registry
+func DeserializeEvent(e Event) (events.Event, error) {
+	return events.Deserialize(e.Type, e.Bytes)
 }
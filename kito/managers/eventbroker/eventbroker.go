@@ -1,36 +1,166 @@
 package eventbroker
 
 import (
+	"sort"
+	"sync"
+
 	"github.com/kkevinchou/kito/kito/events"
 )
 
+// Delivery controls when a published event is handed to its subscribers
+type Delivery int
+
+const (
+	// DeliveryImmediate notifies subscribers before Publish returns
+	DeliveryImmediate Delivery = iota
+
+	// DeliveryEndOfFrame notifies subscribers after every system has run for the
+	// current command frame
+	DeliveryEndOfFrame
+
+	// DeliveryNextFrame notifies subscribers before any system runs on the next
+	// command frame
+	DeliveryNextFrame
+)
+
 type Observer interface {
 	Observe(event events.Event)
 }
 
+type Handler func(event events.Event)
+
 type EventBroker interface {
+	// Broadcast publishes the event for immediate delivery
 	Broadcast(event events.Event)
+	Publish(event events.Event, delivery Delivery)
 	AddObserver(observer Observer, eventTypes []events.EventType)
+	AddHandler(eventType events.EventType, priority int, handler Handler)
+
+	FlushNextFrame()
+	FlushEndOfFrame()
+
+	// DrainReplicated returns the replicated events delivered since the last drain
+	DrainReplicated() []events.Event
+}
+
+type subscription struct {
+	priority int
+	handler  Handler
 }
 
 type EventBrokerImpl struct {
-	observations map[events.EventType][]Observer
+	// systems can publish concurrently when the scheduler runs them in parallel
+	mutex         sync.Mutex
+	subscriptions map[events.EventType][]subscription
+
+	endOfFrame []events.Event
+	nextFrame  []events.Event
+
+	replicate  bool
+	replicated []events.Event
 }
 
-func NewEventBroker() *EventBrokerImpl {
+// NewEventBroker creates an event broker. When replicate is set, events of replicated
+// types are collected as they're delivered so that they can be sent to clients
+func NewEventBroker(replicate bool) *EventBrokerImpl {
 	return &EventBrokerImpl{
-		observations: map[events.EventType][]Observer{},
+		subscriptions: map[events.EventType][]subscription{},
+		replicate:     replicate,
 	}
 }
 
 func (e *EventBrokerImpl) Broadcast(event events.Event) {
-	for _, observer := range e.observations[event.Type()] {
-		observer.Observe(event)
+	e.Publish(event, DeliveryImmediate)
+}
+
+func (e *EventBrokerImpl) Publish(event events.Event, delivery Delivery) {
+	switch delivery {
+	case DeliveryEndOfFrame:
+		e.mutex.Lock()
+		e.endOfFrame = append(e.endOfFrame, event)
+		e.mutex.Unlock()
+	case DeliveryNextFrame:
+		e.mutex.Lock()
+		e.nextFrame = append(e.nextFrame, event)
+		e.mutex.Unlock()
+	default:
+		e.deliver(event)
 	}
 }
 
+func (e *EventBrokerImpl) deliver(event events.Event) {
+	e.mutex.Lock()
+	subscriptions := e.subscriptions[event.Type()]
+	if e.replicate && events.IsReplicated(event.Type()) {
+		e.replicated = append(e.replicated, event)
+	}
+	e.mutex.Unlock()
+
+	// handlers are run without holding the lock so that they can publish events of their own
+	for _, s := range subscriptions {
+		s.handler(event)
+	}
+}
+
+// FlushNextFrame delivers the events that were published for the next command frame
+func (e *EventBrokerImpl) FlushNextFrame() {
+	e.mutex.Lock()
+	queued := e.nextFrame
+	e.nextFrame = nil
+	e.mutex.Unlock()
+
+	for _, event := range queued {
+		e.deliver(event)
+	}
+}
+
+// FlushEndOfFrame delivers the events that were published for the end of the current
+// command frame. Events published for the end of the frame while flushing are
+// delivered in the same flush
+func (e *EventBrokerImpl) FlushEndOfFrame() {
+	for {
+		e.mutex.Lock()
+		queued := e.endOfFrame
+		e.endOfFrame = nil
+		e.mutex.Unlock()
+
+		if len(queued) == 0 {
+			return
+		}
+		for _, event := range queued {
+			e.deliver(event)
+		}
+	}
+}
+
+func (e *EventBrokerImpl) DrainReplicated() []events.Event {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	replicated := e.replicated
+	e.replicated = nil
+	return replicated
+}
+
+// AddObserver subscribes the observer to the event types with the default priority
 func (e *EventBrokerImpl) AddObserver(observer Observer, eventTypes []events.EventType) {
 	for _, eventType := range eventTypes {
-		e.observations[eventType] = append(e.observations[eventType], observer)
+		e.AddHandler(eventType, 0, observer.Observe)
 	}
 }
+
+// AddHandler subscribes the handler to the event type. Handlers with a higher priority
+// are notified first and handlers with equal priority are notified in the order they
+// subscribed
+func (e *EventBrokerImpl) AddHandler(eventType events.EventType, priority int, handler Handler) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	// copy on write so that deliveries in progress keep the slice they started with
+	subscriptions := make([]subscription, len(e.subscriptions[eventType]), len(e.subscriptions[eventType])+1)
+	copy(subscriptions, e.subscriptions[eventType])
+	subscriptions = append(subscriptions, subscription{priority: priority, handler: handler})
+	sort.SliceStable(subscriptions, func(i, j int) bool {
+		return subscriptions[i].priority > subscriptions[j].priority
+	})
+	e.subscriptions[eventType] = subscriptions
+}
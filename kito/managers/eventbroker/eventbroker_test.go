@@ -0,0 +1,80 @@
+package eventbroker_test
+
+import (
+	"testing"
+
+	"github.com/kkevinchou/kito/kito/events"
+	"github.com/kkevinchou/kito/kito/managers/eventbroker"
+)
+
+func TestPriority(t *testing.T) {
+	broker := eventbroker.NewEventBroker(false)
+
+	var log []string
+	eventbroker.Subscribe(broker, 0, func(e *events.RPCEvent) { log = append(log, "default") })
+	eventbroker.Subscribe(broker, 10, func(e *events.RPCEvent) { log = append(log, "high") })
+	eventbroker.Subscribe(broker, -10, func(e *events.RPCEvent) { log = append(log, "low") })
+	eventbroker.Subscribe(broker, 10, func(e *events.RPCEvent) { log = append(log, "high2") })
+
+	broker.Broadcast(&events.RPCEvent{Command: "test"})
+
+	expected := []string{"high", "high2", "default", "low"}
+	if len(log) != len(expected) {
+		t.Fatalf("expected %v but got %v", expected, log)
+	}
+	for i := range expected {
+		if log[i] != expected[i] {
+			t.Fatalf("expected %v but got %v", expected, log)
+		}
+	}
+}
+
+func TestDelivery(t *testing.T) {
+	broker := eventbroker.NewEventBroker(false)
+	queue := eventbroker.NewQueue[*events.RPCEvent](broker, 0)
+
+	broker.Publish(&events.RPCEvent{Command: "next"}, eventbroker.DeliveryNextFrame)
+	broker.Publish(&events.RPCEvent{Command: "end"}, eventbroker.DeliveryEndOfFrame)
+	broker.Publish(&events.RPCEvent{Command: "now"}, eventbroker.DeliveryImmediate)
+
+	if queued := queue.Drain(); len(queued) != 1 || queued[0].Command != "now" {
+		t.Fatalf("expected only the immediate event to be delivered but got %v", queued)
+	}
+
+	broker.FlushEndOfFrame()
+	if queued := queue.Drain(); len(queued) != 1 || queued[0].Command != "end" {
+		t.Fatalf("expected the end of frame event to be delivered but got %v", queued)
+	}
+
+	broker.FlushNextFrame()
+	if queued := queue.Drain(); len(queued) != 1 || queued[0].Command != "next" {
+		t.Fatalf("expected the next frame event to be delivered but got %v", queued)
+	}
+}
+
+func TestReplication(t *testing.T) {
+	broker := eventbroker.NewEventBroker(true)
+	broker.Broadcast(&events.UnregisterEntityEvent{EntityID: 7})
+	broker.Broadcast(&events.RPCEvent{Command: "local"})
+
+	replicated := broker.DrainReplicated()
+	if len(replicated) != 1 {
+		t.Fatalf("expected 1 replicated event but got %d", len(replicated))
+	}
+
+	bytes, err := events.Serialize(replicated[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	event, err := events.Deserialize(replicated[0].Type(), bytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e, ok := event.(*events.UnregisterEntityEvent); !ok || e.EntityID != 7 {
+		t.Fatalf("expected the unregister event to round trip but got %v", event)
+	}
+
+	if len(broker.DrainReplicated()) != 0 {
+		t.Fatal("expected replicated events to be drained")
+	}
+}
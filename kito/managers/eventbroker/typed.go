@@ -0,0 +1,47 @@
+package eventbroker
+
+import (
+	"sync"
+
+	"github.com/kkevinchou/kito/kito/events"
+)
+
+// Subscribe registers a handler for the event type T, e.g.
+//
+//	Subscribe(broker, 0, func(e *events.RPCEvent) { ... })
+//
+// T must be a pointer to an event struct whose Type method doesn't dereference its receiver
+func Subscribe[T events.Event](broker EventBroker, priority int, handler func(event T)) {
+	var zero T
+	broker.AddHandler(zero.Type(), priority, func(event events.Event) {
+		if e, ok := event.(T); ok {
+			handler(e)
+		}
+	})
+}
+
+// Queue collects events of type T as they're delivered so that a system can process
+// them during its update
+type Queue[T events.Event] struct {
+	mutex  sync.Mutex
+	events []T
+}
+
+func NewQueue[T events.Event](broker EventBroker, priority int) *Queue[T] {
+	q := &Queue[T]{}
+	Subscribe(broker, priority, func(event T) {
+		q.mutex.Lock()
+		defer q.mutex.Unlock()
+		q.events = append(q.events, event)
+	})
+	return q
+}
+
+// Drain returns the queued events in delivery order and empties the queue
+func (q *Queue[T]) Drain() []T {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	queued := q.events
+	q.events = nil
+	return queued
+}
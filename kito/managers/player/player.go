@@ -6,7 +6,9 @@ import (
 )
 
 type World interface {
-	CommandFrame() int
+	// Tick advances once per fixed step of the game loop, including steps where the
+	// simulation is paused and the command frame doesn't advance
+	Tick() int
 }
 
 type Player struct {
@@ -17,18 +19,18 @@ type Player struct {
 	LastInputLocalCommandFrame  int // the player's last command frame
 	LastInputGlobalCommandFrame int // the gcf when this input was received
 
-	lastNetworkPullCommandFrame    int
+	lastNetworkPullTick            int
 	lastNetworkPullNetworkMessages []*network.Message
 	world                          World
 }
 
 // NetworkMessages pulls network messages for the player. This function caches network
-// messages for the same command frame
+// messages for the same tick
 func (p *Player) NetworkMessages() []*network.Message {
-	commandFrame := p.world.CommandFrame()
-	if p.lastNetworkPullCommandFrame != commandFrame {
+	tick := p.world.Tick()
+	if p.lastNetworkPullTick != tick {
 		p.lastNetworkPullNetworkMessages = p.Client.PullIncomingMessages()
-		p.lastNetworkPullCommandFrame = commandFrame
+		p.lastNetworkPullTick = tick
 	}
 
 	return p.lastNetworkPullNetworkMessages
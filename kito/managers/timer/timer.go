@@ -0,0 +1,264 @@
+package timer
+
+import (
+	"container/heap"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/kkevinchou/kito/lib/logger"
+)
+
+var log = logger.New("timer")
+
+type TimerID int
+
+// Handler is invoked when a timer fires. Handlers are registered by name rather than
+// stored on the timer so that pending timers can be serialized
+type Handler func(t Timer)
+
+// Timer is a callback scheduled against command frames rather than wall clock time,
+// which keeps it deterministic under replay and prediction
+type Timer struct {
+	ID TimerID
+
+	// Handler is the name of a registered handler to run when the timer fires. Timers
+	// without a handler do nothing when they fire and are useful as cooldowns
+	Handler string
+
+	// Key optionally names the timer so that it can be looked up, e.g. for cooldowns
+	Key      string
+	EntityID int
+
+	FireCommandFrame int
+
+	// Interval is the number of command frames between firings for repeating timers
+	// and 0 for one shot timers
+	Interval int
+}
+
+// Manager tracks pending timers and fires them as command frames advance
+type Manager struct {
+	// systems can schedule timers concurrently when the scheduler runs them in parallel
+	mutex sync.Mutex
+
+	commandFrame int
+	nextID       TimerID
+	pending      timerHeap
+	timers       map[TimerID]*Timer
+	keys         map[string]TimerID
+	handlers     map[string]Handler
+}
+
+func NewManager() *Manager {
+	return &Manager{
+		nextID:   1,
+		timers:   map[TimerID]*Timer{},
+		keys:     map[string]TimerID{},
+		handlers: map[string]Handler{},
+	}
+}
+
+func (m *Manager) RegisterHandler(name string, handler Handler) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.handlers[name] = handler
+}
+
+// After schedules a timer to fire once after the given number of command frames
+func (m *Manager) After(frames int, handler string, entityID int) TimerID {
+	return m.schedule(Timer{Handler: handler, EntityID: entityID, FireCommandFrame: m.CommandFrame() + frames})
+}
+
+// Every schedules a timer that fires every interval command frames until cancelled
+func (m *Manager) Every(interval int, handler string, entityID int) TimerID {
+	if interval < 1 {
+		panic(fmt.Sprintf("timer interval must be positive, got %d", interval))
+	}
+	return m.schedule(Timer{Handler: handler, EntityID: entityID, FireCommandFrame: m.CommandFrame() + interval, Interval: interval})
+}
+
+func (m *Manager) schedule(t Timer) TimerID {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	t.ID = m.nextID
+	m.nextID++
+	m.add(&t)
+	return t.ID
+}
+
+func (m *Manager) add(t *Timer) {
+	if t.Key != "" {
+		if existing, ok := m.keys[t.Key]; ok {
+			m.cancel(existing)
+		}
+		m.keys[t.Key] = t.ID
+	}
+	m.timers[t.ID] = t
+	heap.Push(&m.pending, t)
+}
+
+// Cancel removes a pending timer. Cancelling an unknown or already fired timer is a no-op
+func (m *Manager) Cancel(id TimerID) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.cancel(id)
+}
+
+func (m *Manager) cancel(id TimerID) {
+	t, ok := m.timers[id]
+	if !ok {
+		return
+	}
+	delete(m.timers, id)
+	if t.Key != "" && m.keys[t.Key] == id {
+		delete(m.keys, t.Key)
+	}
+	// the timer is left in the heap and skipped when it's popped
+}
+
+// StartCooldown puts the key on cooldown for the given number of command frames,
+// replacing any existing cooldown for the key
+func (m *Manager) StartCooldown(key string, frames int) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	t := &Timer{ID: m.nextID, Key: key, FireCommandFrame: m.commandFrame + frames}
+	m.nextID++
+	m.add(t)
+}
+
+func (m *Manager) OnCooldown(key string) bool {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	_, ok := m.keys[key]
+	return ok
+}
+
+// Pending returns the timer with the given ID if it has yet to fire
+func (m *Manager) Pending(id TimerID) (Timer, bool) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	t, ok := m.timers[id]
+	if !ok {
+		return Timer{}, false
+	}
+	return *t, true
+}
+
+func (m *Manager) CommandFrame() int {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.commandFrame
+}
+
+// Update advances the manager to the given command frame and fires every timer that is
+// due, ordered by fire frame and then by scheduling order
+func (m *Manager) Update(commandFrame int) {
+	m.mutex.Lock()
+	m.commandFrame = commandFrame
+
+	var due []Timer
+	for m.pending.Len() > 0 && m.pending[0].FireCommandFrame <= commandFrame {
+		t := heap.Pop(&m.pending).(*Timer)
+		if m.timers[t.ID] != t {
+			// cancelled
+			continue
+		}
+
+		due = append(due, *t)
+		if t.Interval > 0 {
+			t.FireCommandFrame += t.Interval
+			heap.Push(&m.pending, t)
+		} else {
+			m.cancel(t.ID)
+		}
+	}
+	m.mutex.Unlock()
+
+	// handlers are run without holding the lock so that they can schedule new timers
+	for _, t := range due {
+		if t.Handler == "" {
+			continue
+		}
+		m.mutex.Lock()
+		handler, ok := m.handlers[t.Handler]
+		m.mutex.Unlock()
+		if !ok {
+			log.Warn("timer fired with an unregistered handler", logger.F("timer", t.ID), logger.F("handler", t.Handler))
+			continue
+		}
+		handler(t)
+	}
+}
+
+type serializedTimers struct {
+	CommandFrame int
+	NextID       TimerID
+	Timers       []Timer
+}
+
+// Serialize returns the pending timers. Handlers are not serialized and must be
+// registered again on the manager the timers are loaded into
+func (m *Manager) Serialize() ([]byte, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var timers []Timer
+	for _, t := range m.pending {
+		if m.timers[t.ID] == t {
+			timers = append(timers, *t)
+		}
+	}
+
+	return json.Marshal(serializedTimers{CommandFrame: m.commandFrame, NextID: m.nextID, Timers: timers})
+}
+
+// Load replaces the pending timers with previously serialized timers
+func (m *Manager) Load(bytes []byte) error {
+	var s serializedTimers
+	if err := json.Unmarshal(bytes, &s); err != nil {
+		return err
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.commandFrame = s.CommandFrame
+	m.nextID = s.NextID
+	m.pending = nil
+	m.timers = map[TimerID]*Timer{}
+	m.keys = map[string]TimerID{}
+	for i := range s.Timers {
+		t := s.Timers[i]
+		m.add(&t)
+	}
+	return nil
+}
+
+type timerHeap []*Timer
+
+func (h timerHeap) Len() int { return len(h) }
+
+func (h timerHeap) Less(i, j int) bool {
+	if h[i].FireCommandFrame != h[j].FireCommandFrame {
+		return h[i].FireCommandFrame < h[j].FireCommandFrame
+	}
+	return h[i].ID < h[j].ID
+}
+
+func (h timerHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+}
+
+func (h *timerHeap) Push(x any) {
+	*h = append(*h, x.(*Timer))
+}
+
+func (h *timerHeap) Pop() any {
+	n := len(*h)
+	item := (*h)[n-1]
+	*h = (*h)[0 : n-1]
+	return item
+}
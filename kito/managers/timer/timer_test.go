@@ -0,0 +1,103 @@
+package timer_test
+
+import (
+	"testing"
+
+	"github.com/kkevinchou/kito/kito/managers/timer"
+)
+
+func TestAfterAndEvery(t *testing.T) {
+	m := timer.NewManager()
+	var fired []int
+	m.RegisterHandler("record", func(t timer.Timer) {
+		fired = append(fired, t.EntityID)
+	})
+
+	m.After(2, "record", 1)
+	m.Every(3, "record", 2)
+
+	for cf := 1; cf <= 6; cf++ {
+		m.Update(cf)
+	}
+
+	expected := []int{1, 2, 2}
+	if len(fired) != len(expected) {
+		t.Fatalf("expected %v but got %v", expected, fired)
+	}
+	for i := range expected {
+		if fired[i] != expected[i] {
+			t.Fatalf("expected %v but got %v", expected, fired)
+		}
+	}
+}
+
+func TestCancel(t *testing.T) {
+	m := timer.NewManager()
+	count := 0
+	m.RegisterHandler("count", func(t timer.Timer) {
+		count++
+	})
+
+	id := m.Every(1, "count", 0)
+	m.Update(1)
+	m.Update(2)
+	m.Cancel(id)
+	m.Update(3)
+
+	if count != 2 {
+		t.Fatalf("expected 2 firings but got %d", count)
+	}
+	if _, ok := m.Pending(id); ok {
+		t.Fatal("expected cancelled timer to not be pending")
+	}
+}
+
+func TestCooldown(t *testing.T) {
+	m := timer.NewManager()
+	m.StartCooldown("cast", 5)
+
+	for cf := 1; cf < 5; cf++ {
+		m.Update(cf)
+		if !m.OnCooldown("cast") {
+			t.Fatalf("expected cast to be on cooldown on command frame %d", cf)
+		}
+	}
+
+	m.Update(5)
+	if m.OnCooldown("cast") {
+		t.Fatal("expected cast to be off cooldown")
+	}
+}
+
+func TestSerialize(t *testing.T) {
+	m := timer.NewManager()
+	m.Update(10)
+	m.After(5, "record", 7)
+	m.StartCooldown("cast", 2)
+
+	bytes, err := m.Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loaded := timer.NewManager()
+	var fired []int
+	loaded.RegisterHandler("record", func(t timer.Timer) {
+		fired = append(fired, t.EntityID)
+	})
+	if err := loaded.Load(bytes); err != nil {
+		t.Fatal(err)
+	}
+
+	if !loaded.OnCooldown("cast") {
+		t.Fatal("expected loaded cooldown to be pending")
+	}
+
+	loaded.Update(15)
+	if len(fired) != 1 || fired[0] != 7 {
+		t.Fatalf("expected loaded timer to fire for entity 7 but got %v", fired)
+	}
+	if loaded.OnCooldown("cast") {
+		t.Fatal("expected loaded cooldown to have expired")
+	}
+}
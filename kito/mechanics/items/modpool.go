@@ -3,8 +3,12 @@ package items
 import (
 	"fmt"
 	"math/rand"
+
+	"github.com/kkevinchou/kito/lib/logger"
 )
 
+var log = logger.New("items")
+
 type AffixType string
 
 const (
@@ -81,7 +85,7 @@ func (m *ModPool) ChooseMods(rarity Rarity) []*Mod {
 	}
 
 	if guard >= maxGuard {
-		fmt.Println("WARNING, max guard hit in ChooseMods")
+		log.Warn("hit the max iterations choosing mods")
 	}
 
 	return mods
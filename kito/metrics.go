@@ -0,0 +1,19 @@
+package kito
+
+import "github.com/kkevinchou/kito/lib/metrics"
+
+// describeMetrics documents the metrics that are exported from the server's metrics
+// endpoint
+func describeMetrics(registry *metrics.MetricsRegistry) {
+	registry.SetHelp("kito_frame_time_ms", "Time spent running each command frame")
+	registry.SetHelp("kito_system_time_ms", "Time spent in each system per command frame")
+	registry.SetHelp("kito_command_frames_total", "Command frames run")
+	registry.SetHelp("kito_command_frames_dropped_total", "Command frames dropped after failing to catch up")
+	registry.SetHelp("kito_entities", "Registered entities")
+	registry.SetHelp("kito_stale_entity_lookups_total", "Entity lookups with a handle to an entity that was unregistered")
+	registry.SetHelp("kito_players", "Connected players")
+	registry.SetHelp("kito_messages_received_total", "Messages received from clients by message type")
+	registry.SetHelp("kito_message_size_bytes", "Size of message bodies by direction and message type")
+	registry.SetHelp("kito_player_rtt_ms", "Round trip time reported by each player")
+	registry.SetHelp("kito_player_prediction_hit_ratio", "Fraction of client side predictions that matched the server over the last second, reported by each player")
+}
@@ -0,0 +1,253 @@
+package netsync
+
+import (
+	"math"
+
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/kkevinchou/kito/kito/entities"
+	"github.com/kkevinchou/kito/kito/settings"
+	"github.com/kkevinchou/kito/lib/collision/collider"
+)
+
+const (
+	// characters stop this far away from what they hit so that the next sweep doesn't
+	// start out touching it
+	characterSkinWidth float64 = 0.01
+)
+
+// characterWalkableStrictness is how much the normal of what a character is on has to face
+// up for the character to stand on it. Only the character controller uses the slope limit,
+// everything else is grounded by groundedStrictness
+var characterWalkableStrictness = math.Cos(mgl64.DegToRad(settings.CharacterMaxSlopeAngle))
+
+// characterMove is where a character ends up after a move and what it hit along the way
+type characterMove struct {
+	position mgl64.Vec3
+	hits     []entities.Entity
+
+	// grounded is set when the character landed on or walked along walkable ground, which
+	// belongs to the ground entity
+	grounded bool
+	ground   entities.Entity
+
+	// ceiling is set when the character hit something above it while moving up
+	ceiling bool
+
+	// blocked is set when the character ran into something too steep to walk up
+	blocked bool
+}
+
+// characterMover sweeps a character's capsule through the world from arbitrary positions
+// without moving the character, so that moves can be tried and thrown away
+type characterMover struct {
+	entity  entities.Entity
+	capsule collider.Capsule
+	world   World
+}
+
+// moveCharacter moves a character by the displacement. The horizontal and vertical parts of
+// the move are swept separately so that characters walk up slopes they can stand on and
+// slide along walls and slopes they can't, without gravity pulling them down the slopes
+// they stand on. Characters on the ground step onto ledges and stay on the ground when
+// walking down slopes and off of ledges. Everything only depends on where entities are,
+// so replaying the same inputs moves characters the same way
+func moveCharacter(entity entities.Entity, displacement mgl64.Vec3, world World) {
+	cc := entity.GetComponentContainer()
+	transformComponent := cc.TransformComponent
+	colliderComponent := cc.ColliderComponent
+	tpcComponent := cc.ThirdPersonControllerComponent
+
+	if colliderComponent == nil || colliderComponent.CapsuleCollider == nil {
+		transformComponent.Position = transformComponent.Position.Add(displacement)
+		return
+	}
+
+	mover := characterMover{entity: entity, capsule: *colliderComponent.CapsuleCollider, world: world}
+	wasGrounded := tpcComponent != nil && tpcComponent.Grounded
+	start := transformComponent.Position
+
+	horizontal := mover.slide(start, mgl64.Vec3{displacement.X(), 0, displacement.Z()}, false)
+	if horizontal.blocked && wasGrounded {
+		if step, ok := mover.step(start, mgl64.Vec3{displacement.X(), 0, displacement.Z()}); ok {
+			if horizontalDistance(step.position, start) > horizontalDistance(horizontal.position, start)+equalThreshold {
+				horizontal = step
+			}
+		}
+	}
+
+	vertical := mover.slide(horizontal.position, mgl64.Vec3{0, displacement.Y(), 0}, true)
+	result := characterMove{
+		position: vertical.position,
+		hits:     append(horizontal.hits, vertical.hits...),
+		grounded: horizontal.grounded || vertical.grounded,
+		ground:   horizontal.ground,
+		ceiling:  vertical.ceiling,
+	}
+	if vertical.grounded {
+		result.ground = vertical.ground
+	}
+
+	// walking down slopes and off of ledges moves further horizontally than gravity pulls
+	// down in a frame, so characters that were on the ground are pulled back down to it
+	if wasGrounded && !result.grounded && displacement.Y() <= 0 {
+		if snap, ok := mover.snap(result.position); ok {
+			result.position = snap.position
+			result.hits = append(result.hits, snap.hits...)
+			result.grounded = true
+			result.ground = snap.ground
+		}
+	}
+
+	transformComponent.Position = result.position
+	for _, hitEntity := range result.hits {
+		colliderComponent.Contacts[hitEntity.GetID()] = true
+		hitEntity.GetComponentContainer().ColliderComponent.Contacts[entity.GetID()] = true
+	}
+
+	if result.grounded {
+		ground(entity)
+	}
+	if tpcComponent != nil {
+		tpcComponent.GroundEntityID = nil
+		if result.grounded {
+			groundEntityID := result.ground.GetID()
+			tpcComponent.GroundEntityID = &groundEntityID
+		}
+	}
+	if result.ceiling {
+		hitCeiling(entity)
+	}
+}
+
+// slide sweeps the character from the position by the displacement, sliding along what it
+// hits with the rest of the movement. Horizontal moves treat anything too steep to walk up
+// as a wall so that they never push the character up it. Vertical moves stop when they
+// land on walkable ground so that characters don't slide down the slopes they stand on
+func (m characterMover) slide(position mgl64.Vec3, displacement mgl64.Vec3, vertical bool) characterMove {
+	result := characterMove{position: position}
+
+	for i := 0; i < maxSlideCount && displacement.Len() > equalThreshold; i++ {
+		hit, hitEntity := sweepCapsule(m.entity, m.capsule.Transform(result.position), displacement, m.world)
+		if hit == nil {
+			result.position = result.position.Add(displacement)
+			return result
+		}
+
+		result.position = result.position.Add(displacement.Mul(hit.TimeOfImpact)).Add(hit.Normal.Mul(characterSkinWidth))
+		result.hits = append(result.hits, hitEntity)
+
+		normal := hit.Normal
+		if walkable(normal) {
+			result.grounded = true
+			result.ground = hitEntity
+			if vertical && displacement.Y() < 0 {
+				return result
+			}
+		} else {
+			if vertical && displacement.Y() > 0 && normal.Y() < 0 {
+				result.ceiling = true
+			}
+			if !vertical {
+				result.blocked = true
+				normal = flatten(normal)
+			}
+		}
+
+		remaining := displacement.Mul(1 - hit.TimeOfImpact)
+		displacement = remaining.Sub(normal.Mul(remaining.Dot(normal)))
+	}
+
+	return result
+}
+
+// step tries moving the character up by the step height, then forward, then back down.
+// The step is only taken when the character lands on walkable ground
+func (m characterMover) step(start mgl64.Vec3, horizontal mgl64.Vec3) (characterMove, bool) {
+	raised, hitEntity, _ := m.cast(start, mgl64.Vec3{0, settings.CharacterStepHeight, 0})
+	climb := raised.Y() - start.Y()
+	if climb < equalThreshold {
+		return characterMove{}, false
+	}
+
+	result := m.slide(raised, horizontal, false)
+	if hitEntity != nil {
+		result.hits = append(result.hits, hitEntity)
+	}
+
+	lowered, hitEntity, normal := m.cast(result.position, mgl64.Vec3{0, -climb - characterSkinWidth, 0})
+	if hitEntity == nil || !walkable(normal) {
+		return characterMove{}, false
+	}
+
+	// the raised capsule can round over the edge of a ledge that's a little taller than
+	// the step height, which would leave the character standing on top of it
+	if lowered.Y()-start.Y() > settings.CharacterStepHeight+characterSkinWidth {
+		return characterMove{}, false
+	}
+
+	result.position = lowered
+	result.hits = append(result.hits, hitEntity)
+	result.grounded = true
+	result.ground = hitEntity
+	result.blocked = false
+	return result, true
+}
+
+// snap pulls the character down onto walkable ground within the snap distance below it
+func (m characterMover) snap(position mgl64.Vec3) (characterMove, bool) {
+	snapped, hitEntity, normal := m.cast(position, mgl64.Vec3{0, -settings.CharacterGroundSnapDistance, 0})
+	if hitEntity == nil || !walkable(normal) {
+		return characterMove{}, false
+	}
+	return characterMove{position: snapped, hits: []entities.Entity{hitEntity}, grounded: true, ground: hitEntity}, true
+}
+
+// cast sweeps the character from the position by the displacement and stops at the first
+// thing it hits, returning where it stopped along with what it hit and the hit normal
+func (m characterMover) cast(position mgl64.Vec3, displacement mgl64.Vec3) (mgl64.Vec3, entities.Entity, mgl64.Vec3) {
+	hit, hitEntity := sweepCapsule(m.entity, m.capsule.Transform(position), displacement, m.world)
+	if hit == nil {
+		return position.Add(displacement), nil, mgl64.Vec3{}
+	}
+
+	// back off along the move rather than the normal so that the character doesn't drift
+	// sideways off of the edges it stops on
+	distance := math.Max(0, hit.TimeOfImpact*displacement.Len()-characterSkinWidth)
+	return position.Add(displacement.Normalize().Mul(distance)), hitEntity, hit.Normal
+}
+
+// hitCeiling stops the character from moving up any further
+func hitCeiling(entity entities.Entity) {
+	cc := entity.GetComponentContainer()
+	if cc.MovementComponent != nil && cc.MovementComponent.Velocity[1] > 0 {
+		cc.MovementComponent.Velocity[1] = 0
+	}
+
+	if tpcComponent := cc.ThirdPersonControllerComponent; tpcComponent != nil {
+		if tpcComponent.BaseVelocity[1] > 0 {
+			tpcComponent.BaseVelocity[1] = 0
+		}
+		if tpcComponent.ZipVelocity[1] > 0 {
+			tpcComponent.ZipVelocity[1] = 0
+		}
+	}
+}
+
+// walkable returns whether a surface with the normal is flat enough for a character to
+// stand on
+func walkable(normal mgl64.Vec3) bool {
+	return normal.Dot(mgl64.Vec3{0, 1, 0}) >= characterWalkableStrictness
+}
+
+// flatten turns the normal of a steep surface into the normal of a vertical wall
+func flatten(normal mgl64.Vec3) mgl64.Vec3 {
+	flat := mgl64.Vec3{normal.X(), 0, normal.Z()}
+	if flat.Len() < equalThreshold {
+		return normal
+	}
+	return flat.Normalize()
+}
+
+func horizontalDistance(a mgl64.Vec3, b mgl64.Vec3) float64 {
+	return mgl64.Vec2{a.X() - b.X(), a.Z() - b.Z()}.Len()
+}
@@ -0,0 +1,302 @@
+package netsync
+
+import (
+	"math"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/kkevinchou/kito/kito/broadphase"
+	"github.com/kkevinchou/kito/kito/components"
+	"github.com/kkevinchou/kito/kito/entities"
+	"github.com/kkevinchou/kito/kito/entitymanager"
+	"github.com/kkevinchou/kito/kito/settings"
+	"github.com/kkevinchou/kito/kito/types"
+	"github.com/kkevinchou/kito/lib/collision/collider"
+)
+
+// testWorld is just enough of a world to move characters through terrain
+type testWorld struct {
+	*entitymanager.EntityManager
+	broadphase *broadphase.Broadphase
+	nextID     int
+}
+
+func newTestWorld() *testWorld {
+	w := &testWorld{EntityManager: entitymanager.NewEntityManager()}
+	w.broadphase = broadphase.NewBroadphase(w, settings.BroadphaseMargin)
+	return w
+}
+
+func (w *testWorld) QueryEntity(componentFlags ...int) []entities.Entity {
+	return w.Query(componentFlags...)
+}
+
+func (w *testWorld) GetPlayerEntity() entities.Entity {
+	return nil
+}
+
+func (w *testWorld) Broadphase() *broadphase.Broadphase {
+	return w.broadphase
+}
+
+func (w *testWorld) add(entity *entities.EntityImpl) *entities.EntityImpl {
+	w.nextID++
+	entity.SetID(w.nextID)
+	w.RegisterEntity(entity)
+	w.broadphase.Sync()
+	return entity
+}
+
+// frame moves the character by the displacement and does the book keeping the end of a
+// command frame does
+func (w *testWorld) frame(character entities.Entity, displacement mgl64.Vec3) {
+	moveCharacter(character, displacement, w)
+	for _, entity := range w.QueryEntity(components.ComponentFlagCollider) {
+		CollisionBookKeeping(entity)
+	}
+	w.broadphase.Sync()
+}
+
+// addTerrain adds a static trimesh made of the triangles
+func (w *testWorld) addTerrain(position mgl64.Vec3, triangles ...collider.Triangle) *entities.EntityImpl {
+	var vertices []mgl64.Vec3
+	for _, triangle := range triangles {
+		vertices = append(vertices, triangle.Points...)
+	}
+	triMesh := collider.NewTriMeshFromTriangles(triangles)
+
+	return w.add(entities.NewEntity("terrain", types.EntityTypeStaticSlime, components.NewComponentContainer(
+		&components.TransformComponent{Position: position, Orientation: mgl64.QuatIdent()},
+		&components.ColliderComponent{
+			TriMeshCollider:     &triMesh,
+			BoundingBoxCollider: collider.BoundingBoxFromVertices(vertices),
+			Contacts:            map[int]bool{},
+			Layer:               types.CollisionLayerTerrain,
+		},
+	)))
+}
+
+// addCharacter adds a character standing at the position, the bottom of its capsule is at
+// the position
+func (w *testWorld) addCharacter(position mgl64.Vec3, grounded bool) *entities.EntityImpl {
+	capsule := collider.NewCapsule(mgl64.Vec3{0, 15, 0}, mgl64.Vec3{0, 5, 0}, 5)
+
+	return w.add(entities.NewEntity("character", types.EntityTypeBob, components.NewComponentContainer(
+		&components.TransformComponent{Position: position, Orientation: mgl64.QuatIdent()},
+		&components.MovementComponent{},
+		&components.ThirdPersonControllerComponent{Controlled: true, Grounded: grounded},
+		&components.ColliderComponent{
+			CapsuleCollider:     &capsule,
+			BoundingBoxCollider: collider.BoundingBoxFromCapsule(capsule),
+			Contacts:            map[int]bool{},
+			Layer:               types.CollisionLayerCharacter,
+		},
+	)))
+}
+
+// quad returns the two triangles of the quad with the corners in counter clockwise order
+func quad(a, b, c, d mgl64.Vec3) []collider.Triangle {
+	return []collider.Triangle{
+		collider.NewTriangle([]mgl64.Vec3{a, b, c}),
+		collider.NewTriangle([]mgl64.Vec3{c, d, a}),
+	}
+}
+
+// floor is a flat quad at the height spanning minX to maxX
+func floor(minX, maxX, y float64) []collider.Triangle {
+	return quad(mgl64.Vec3{minX, y, 100}, mgl64.Vec3{maxX, y, 100}, mgl64.Vec3{maxX, y, -100}, mgl64.Vec3{minX, y, -100})
+}
+
+// ramp starts at x = 0 on the floor and rises along x at the angle, or falls for negative
+// angles
+func ramp(angle float64, length float64) []collider.Triangle {
+	rise := math.Tan(mgl64.DegToRad(angle)) * length
+	return quad(mgl64.Vec3{0, 0, 100}, mgl64.Vec3{length, rise, 100}, mgl64.Vec3{length, rise, -100}, mgl64.Vec3{0, 0, -100})
+}
+
+// ledge is a box of the height whose front face is at x = 0
+func ledge(height float64) []collider.Triangle {
+	box := collider.NewBoxTriMesh(100, 200, height)
+	return box.Transform(mgl64.Translate3D(50, 0, 0)).Triangles
+}
+
+func TestCharacterSlopes(t *testing.T) {
+	testCases := []struct {
+		name     string
+		angle    float64
+		climbs   bool
+		maxReach float64
+	}{
+		{"gentle ramp", 20, true, 0},
+		{"ramp at the slope limit", settings.CharacterMaxSlopeAngle - 1, true, 0},
+		{"ramp past the slope limit", settings.CharacterMaxSlopeAngle + 5, false, 5},
+		{"wall", 80, false, 5},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			w := newTestWorld()
+			w.addTerrain(mgl64.Vec3{}, floor(-100, 0, 0)...)
+			w.addTerrain(mgl64.Vec3{}, ramp(testCase.angle, 100)...)
+			character := w.addCharacter(mgl64.Vec3{-20, characterSkinWidth, 0}, true)
+
+			for i := 0; i < 10; i++ {
+				w.frame(character, mgl64.Vec3{5, -1, 0})
+			}
+
+			position := character.GetComponentContainer().TransformComponent.Position
+			if testCase.climbs {
+				// the capsule touches the ramp with the side of its bottom sphere so the
+				// bottom of the capsule is a little above the ramp
+				expected := math.Tan(mgl64.DegToRad(testCase.angle)) * position.X()
+				if position.X() < 5 || position.Y() < expected-1 {
+					t.Fatalf("expected the character to walk up the ramp but it's at %v", position)
+				}
+				if !character.GetComponentContainer().ThirdPersonControllerComponent.Grounded {
+					t.Fatalf("expected the character to be grounded on the ramp")
+				}
+			} else {
+				if position.X() > testCase.maxReach || position.Y() > 1 {
+					t.Fatalf("expected the character to be stopped by the ramp but it's at %v", position)
+				}
+			}
+		})
+	}
+}
+
+func TestCharacterStepUp(t *testing.T) {
+	testCases := []struct {
+		name   string
+		height float64
+		steps  bool
+	}{
+		{"low ledge", settings.CharacterStepHeight / 2, true},
+		{"ledge at the step height", settings.CharacterStepHeight - characterSkinWidth*2, true},
+		{"ledge above the step height", settings.CharacterStepHeight + 1, false},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			w := newTestWorld()
+			w.addTerrain(mgl64.Vec3{}, floor(-100, 100, 0)...)
+			w.addTerrain(mgl64.Vec3{}, ledge(testCase.height)...)
+			character := w.addCharacter(mgl64.Vec3{-20, characterSkinWidth, 0}, true)
+
+			for i := 0; i < 10; i++ {
+				w.frame(character, mgl64.Vec3{5, -1, 0})
+			}
+
+			position := character.GetComponentContainer().TransformComponent.Position
+			if testCase.steps {
+				if position.X() < 20 || math.Abs(position.Y()-testCase.height) > 2*characterSkinWidth {
+					t.Fatalf("expected the character to step up onto the ledge but it's at %v", position)
+				}
+			} else if position.X() > -4 || position.Y() > 1 {
+				t.Fatalf("expected the character to be blocked by the ledge but it's at %v", position)
+			}
+		})
+	}
+}
+
+func TestCharacterGroundSnap(t *testing.T) {
+	testCases := []struct {
+		name     string
+		grounded bool
+		angle    float64
+		snaps    bool
+	}{
+		{"walking down a slope", true, -30, true},
+		{"falling down a slope", false, -30, false},
+		{"walking off a cliff", true, -85, false},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			w := newTestWorld()
+			w.addTerrain(mgl64.Vec3{}, floor(-100, 0, 0)...)
+			w.addTerrain(mgl64.Vec3{}, ramp(testCase.angle, 100)...)
+			character := w.addCharacter(mgl64.Vec3{-1, characterSkinWidth, 0}, testCase.grounded)
+			tpcComponent := character.GetComponentContainer().ThirdPersonControllerComponent
+
+			// a frame moves further along the slope than it drops
+			w.frame(character, mgl64.Vec3{10, -1, 0})
+
+			position := character.GetComponentContainer().TransformComponent.Position
+			if testCase.snaps {
+				if !tpcComponent.Grounded || position.Y() > -3 {
+					t.Fatalf("expected the character to snap down onto the slope but it's at %v", position)
+				}
+			} else {
+				if tpcComponent.Grounded || position.Y() != -1+characterSkinWidth {
+					t.Fatalf("expected the character to leave the ground but it's at %v", position)
+				}
+			}
+		})
+	}
+}
+
+func TestCharacterCeiling(t *testing.T) {
+	w := newTestWorld()
+	w.addTerrain(mgl64.Vec3{}, floor(-100, 100, 0)...)
+	w.addTerrain(mgl64.Vec3{}, floor(-100, 100, 25)...)
+	character := w.addCharacter(mgl64.Vec3{0, characterSkinWidth, 0}, true)
+	tpcComponent := character.GetComponentContainer().ThirdPersonControllerComponent
+	tpcComponent.BaseVelocity = mgl64.Vec3{0, 150, 0}
+	tpcComponent.Grounded = false
+
+	w.frame(character, mgl64.Vec3{0, 10, 0})
+
+	position := character.GetComponentContainer().TransformComponent.Position
+	if position.Y() > 5 || position.Y() < 5-2*characterSkinWidth {
+		t.Fatalf("expected the character to stop at the ceiling but it's at %v", position)
+	}
+	if tpcComponent.BaseVelocity.Y() != 0 {
+		t.Fatalf("expected hitting the ceiling to stop the character from moving up but its base velocity is %v", tpcComponent.BaseVelocity)
+	}
+}
+
+// TestCharacterReplay checks that replaying the same moves from the same start puts the
+// character in the same place every frame, which client side prediction relies on
+func TestCharacterReplay(t *testing.T) {
+	w := newTestWorld()
+	w.addTerrain(mgl64.Vec3{}, floor(-100, 0, 0)...)
+	w.addTerrain(mgl64.Vec3{}, ramp(30, 100)...)
+	w.addTerrain(mgl64.Vec3{60, 0, 0}, ledge(3)...)
+	character := w.addCharacter(mgl64.Vec3{-20, characterSkinWidth, 0}, true)
+	cc := character.GetComponentContainer()
+
+	moves := []mgl64.Vec3{
+		{5, -1, 0}, {5, -1, 0}, {5, 3, 2}, {7, -1, -3}, {5, -1, 0},
+		{8, -2, 1}, {5, 10, 0}, {5, -1, 0}, {6, -4, 0}, {5, -1, 0},
+	}
+
+	start := *cc.TransformComponent
+	var positions []mgl64.Vec3
+	for _, move := range moves {
+		w.frame(character, move)
+		positions = append(positions, cc.TransformComponent.Position)
+	}
+
+	*cc.TransformComponent = start
+	cc.ThirdPersonControllerComponent.Grounded = true
+	w.broadphase.Sync()
+	for i, move := range moves {
+		w.frame(character, move)
+		if cc.TransformComponent.Position != positions[i] {
+			t.Fatalf("frame %d replayed to %v rather than %v", i, cc.TransformComponent.Position, positions[i])
+		}
+	}
+}
+
+func TestCharacterSmallMoves(t *testing.T) {
+	w := newTestWorld()
+	w.addTerrain(mgl64.Vec3{}, floor(-100, 100, 0)...)
+	character := w.addCharacter(mgl64.Vec3{0, characterSkinWidth, 0}, true)
+
+	w.frame(character, mgl64.Vec3{0.25, -0.1, 0.25})
+
+	position := character.GetComponentContainer().TransformComponent.Position
+	if !position.ApproxEqualThreshold(mgl64.Vec3{0.25, characterSkinWidth, 0.25}, 1e-9) {
+		t.Fatalf("expected the character to move a quarter unit along the floor but it's at %v", position)
+	}
+}
@@ -1,82 +1,89 @@
 package netsync
 
 import (
-	"fmt"
 	"sort"
 
 	"github.com/go-gl/mathgl/mgl64"
+	"github.com/kkevinchou/kito/kito/broadphase"
 	"github.com/kkevinchou/kito/kito/entities"
-	"github.com/kkevinchou/kito/kito/spatialpartition"
 	"github.com/kkevinchou/kito/lib/collision"
+	"github.com/kkevinchou/kito/lib/collision/collider"
+	"github.com/kkevinchou/kito/lib/logger"
+	"github.com/kkevinchou/kito/lib/physicsdebug"
+	"github.com/kkevinchou/kito/lib/profiler"
 )
 
+var log = logger.New("netsync")
+
 type World interface {
-	QueryEntity(componentFlags int) []entities.Entity
+	QueryEntity(componentFlags ...int) []entities.Entity
 	GetPlayerEntity() entities.Entity
 	GetEntityByID(id int) entities.Entity
-	SpatialPartition() *spatialpartition.SpatialPartition
+	Broadphase() *broadphase.Broadphase
 }
 
-func ResolveCollisionsForPlayer(playerEntity entities.Entity, world World) {
-	entityPairs := [][]entities.Entity{}
-	entityList := world.SpatialPartition().QueryCollisionCandidates(playerEntity)
-	for _, e2 := range entityList {
-		if playerEntity.GetID() == e2.GetID() {
-			continue
-		}
-		entityPairs = append(entityPairs, []entities.Entity{playerEntity, e2})
-	}
-	detectAndResolveCollisionsForEntityPairs(entityPairs, entityList, world)
-}
+// ResolveCollisionsForPlayer resolves the player's collisions, profiled inside of the
+// parent scope
+func ResolveCollisionsForPlayer(playerEntity entities.Entity, world World, parent profiler.Timer) {
+	timer := parent.Begin("collision")
+	defer timer.End()
 
-func ResolveCollisions(world World) {
+	broadphaseTimer := timer.Begin("collision.broadphase")
 	entityPairs := [][]entities.Entity{}
-	// pairExists stores the pairs of entities that we've already created,
-	// don't create a pair for both (e1, e2) and (e2, e1), just one of them
-	pairExists := map[int]map[int]bool{}
-	entityList := world.SpatialPartition().AllCandidates()
-	for _, e := range entityList {
-		pairExists[e.GetID()] = map[int]bool{}
+	candidates := world.Broadphase().QueryCollisionCandidates(playerEntity)
+	for _, e2 := range candidates {
+		entityPairs = append(entityPairs, []entities.Entity{playerEntity, e2})
 	}
+	entityList := append(candidates, playerEntity)
+	broadphaseTimer.End()
 
-	for _, e1 := range entityList {
-		candidates := world.SpatialPartition().QueryCollisionCandidates(e1)
-		for _, e2 := range candidates {
-			if e1.GetID() == e2.GetID() {
-				continue
-			}
-
-			if pairExists[e1.GetID()][e2.GetID()] || pairExists[e2.GetID()][e1.GetID()] {
-				continue
-			}
+	detectAndResolveCollisionsForEntityPairs(entityPairs, entityList, world, timer)
+}
 
-			entityPairs = append(entityPairs, []entities.Entity{e1, e2})
-			pairExists[e1.GetID()][e2.GetID()] = true
-			pairExists[e2.GetID()][e1.GetID()] = true
-		}
-	}
-	detectAndResolveCollisionsForEntityPairs(entityPairs, entityList, world)
+// ResolveCollisions resolves the collisions of every entity, profiled inside of the
+// parent scope
+func ResolveCollisions(world World, parent profiler.Timer) {
+	timer := parent.Begin("collision")
+	defer timer.End()
+
+	// entities have moved since the start of the frame, sync so that the cached pairs
+	// reflect where they are now
+	broadphaseTimer := timer.Begin("collision.broadphase")
+	world.Broadphase().Sync()
+	entityPairs := world.Broadphase().Pairs()
+	entityList := world.Broadphase().AllCandidates()
+	broadphaseTimer.End()
+
+	detectAndResolveCollisionsForEntityPairs(entityPairs, entityList, world, timer)
 }
 
-func detectAndResolveCollisionsForEntityPairs(entityPairs [][]entities.Entity, entityList []entities.Entity, world World) {
+func detectAndResolveCollisionsForEntityPairs(entityPairs [][]entities.Entity, entityList []entities.Entity, world World, timer profiler.Timer) {
 	// 1. collect pairs of entities that are colliding, sorted by separating vector
 	// 2. perform collision resolution for any colliding entities
 	// 3. this can cause more collisions, repeat until no more further detected collisions, or we hit the configured max
 
 	positionalResolutionEntityPairs := [][]entities.Entity{}
 	nonPositionalResolutionEntityPairs := [][]entities.Entity{}
+	triggerEntityPairs := [][]entities.Entity{}
 
 	for _, pair := range entityPairs {
+		physicsdebug.RecordPair(pair[0].GetID(), pair[1].GetID())
+
 		cc1 := pair[0].GetComponentContainer()
 		cc2 := pair[1].GetComponentContainer()
 
-		if cc1.ColliderComponent.SkipSeparation || cc2.ColliderComponent.SkipSeparation {
+		if cc1.ColliderComponent.Triggers(cc2.ColliderComponent) || cc2.ColliderComponent.Triggers(cc1.ColliderComponent) {
+			triggerEntityPairs = append(triggerEntityPairs, pair)
+		} else if !cc1.ColliderComponent.Collides(cc2.ColliderComponent) {
+			continue
+		} else if cc1.ColliderComponent.SkipSeparation || cc2.ColliderComponent.SkipSeparation {
 			nonPositionalResolutionEntityPairs = append(nonPositionalResolutionEntityPairs, pair)
 		} else {
 			positionalResolutionEntityPairs = append(positionalResolutionEntityPairs, pair)
 		}
 	}
 
+	rigidBodyContacts := newRigidBodyContacts()
 	resolveCount := map[int]int{}
 	maximallyCollidingEntities := map[int]bool{}
 	absoluteMaxRunCount := len(entityList) * resolveCountMax
@@ -86,12 +93,21 @@ func detectAndResolveCollisionsForEntityPairs(entityPairs [][]entities.Entity, e
 	// the number of entities times the cap.
 	collisionRuns := 0
 	for collisionRuns = 0; collisionRuns < absoluteMaxRunCount; collisionRuns++ {
+		narrowphase := timer.Begin("collision.narrowphase")
 		collisionCandidates := collectSortedCollisionCandidates(positionalResolutionEntityPairs, entityList, maximallyCollidingEntities, world)
+		narrowphase.End()
 		if len(collisionCandidates) == 0 {
 			break
 		}
 
+		recordContacts(collisionCandidates, collisionRuns, world)
+		for _, contact := range collisionCandidates {
+			rigidBodyContacts.add(contact, world)
+		}
+
+		resolve := timer.Begin("collision.resolve")
 		resolvedEntities := resolveCollisions(collisionCandidates, world)
+		resolve.End()
 		for entityID, otherEntityID := range resolvedEntities {
 			e1 := world.GetEntityByID(entityID)
 			e2 := world.GetEntityByID(otherEntityID)
@@ -115,12 +131,19 @@ func detectAndResolveCollisionsForEntityPairs(entityPairs [][]entities.Entity, e
 	}
 
 	if collisionRuns == absoluteMaxRunCount {
-		fmt.Println("hit absolute max collision run count")
+		log.Warn("hit the max collision resolution count")
 	}
+	physicsdebug.RecordResolutions(resolveCount, maximallyCollidingEntities)
+
+	solve := timer.Begin("collision.rigidbody")
+	solveRigidBodyContacts(rigidBodyContacts.contacts, world)
+	solve.End()
 
 	// handle entities that we skip separation for. i.e. these entities just want to know if they've collided with something
 	// but it don't want its positon changed
+	narrowphase := timer.Begin("collision.narrowphase")
 	collisionCandidates := collectSortedCollisionCandidates(nonPositionalResolutionEntityPairs, entityList, map[int]bool{}, world)
+	narrowphase.End()
 	for _, candidate := range collisionCandidates {
 		e1 := world.GetEntityByID(*candidate.EntityID)
 		e2 := world.GetEntityByID(*candidate.SourceEntityID)
@@ -131,6 +154,30 @@ func detectAndResolveCollisionsForEntityPairs(entityPairs [][]entities.Entity, e
 		e1.GetComponentContainer().ColliderComponent.Contacts[e2.GetID()] = true
 		e2.GetComponentContainer().ColliderComponent.Contacts[e1.GetID()] = true
 	}
+
+	// triggers only record what overlaps them, which is turned into trigger events by the
+	// collision system
+	narrowphase = timer.Begin("collision.narrowphase")
+	collisionCandidates = collectSortedCollisionCandidates(triggerEntityPairs, entityList, map[int]bool{}, world)
+	narrowphase.End()
+	for _, candidate := range collisionCandidates {
+		e1 := world.GetEntityByID(*candidate.EntityID)
+		e2 := world.GetEntityByID(*candidate.SourceEntityID)
+		recordOverlap(e1, e2)
+		recordOverlap(e2, e1)
+	}
+}
+
+// recordOverlap marks the entity as overlapping the trigger if the trigger reports it
+func recordOverlap(trigger entities.Entity, entity entities.Entity) {
+	triggerColliderComponent := trigger.GetComponentContainer().ColliderComponent
+	if !triggerColliderComponent.Triggers(entity.GetComponentContainer().ColliderComponent) {
+		return
+	}
+	if triggerColliderComponent.Overlaps == nil {
+		triggerColliderComponent.Overlaps = map[int]bool{}
+	}
+	triggerColliderComponent.Overlaps[entity.GetID()] = true
 }
 
 // collectSortedCollisionCandidates collects all potential collisions that can occur in the frame.
@@ -139,15 +186,7 @@ func detectAndResolveCollisionsForEntityPairs(entityPairs [][]entities.Entity, e
 func collectSortedCollisionCandidates(entityPairs [][]entities.Entity, entityList []entities.Entity, skipEntitySet map[int]bool, world World) []*collision.Contact {
 	// initialize collision state
 	for _, e := range entityList {
-		cc := e.GetComponentContainer()
-		if cc.ColliderComponent.CapsuleCollider != nil {
-			capsule := cc.ColliderComponent.CapsuleCollider.Transform(cc.TransformComponent.Position)
-			cc.ColliderComponent.TransformedCapsuleCollider = &capsule
-		} else if cc.ColliderComponent.TriMeshCollider != nil {
-			transformMatrix := mgl64.Translate3D(cc.TransformComponent.Position.X(), cc.TransformComponent.Position.Y(), cc.TransformComponent.Position.Z())
-			triMesh := cc.ColliderComponent.TriMeshCollider.Transform(transformMatrix)
-			cc.ColliderComponent.TransformedTriMeshCollider = &triMesh
-		}
+		e.GetComponentContainer().TransformColliders()
 	}
 
 	var allContacts []*collision.Contact
@@ -206,6 +245,35 @@ func collide(e1 entities.Entity, e2 entities.Entity) []*collision.Contact {
 		contact.EntityID = &e1ID
 		contact.SourceEntityID = &e2ID
 		result = append(result, contact)
+	} else if ok, convexEntity, triMeshEntity := isConvexTriMeshCollision(e1, e2); ok {
+		contacts := collision.CheckCollisionConvexTriMesh(
+			convexCollider(convexEntity),
+			*triMeshEntity.GetComponentContainer().ColliderComponent.TransformedTriMeshCollider,
+		)
+		if len(contacts) == 0 {
+			return nil
+		}
+
+		triEntityID := triMeshEntity.GetID()
+		convexEntityID := convexEntity.GetID()
+
+		for _, contact := range contacts {
+			contact.EntityID = &convexEntityID
+			contact.SourceEntityID = &triEntityID
+		}
+
+		result = contacts
+	} else if ok, entity, sourceEntity := isConvexConvexCollision(e1, e2); ok {
+		contact := collision.CheckCollisionConvexConvex(convexCollider(entity), convexCollider(sourceEntity))
+		if contact == nil {
+			return nil
+		}
+
+		entityID := entity.GetID()
+		sourceEntityID := sourceEntity.GetID()
+		contact.EntityID = &entityID
+		contact.SourceEntityID = &sourceEntityID
+		result = append(result, contact)
 	}
 
 	// filter out contacts that have tiny separating distances
@@ -234,7 +302,9 @@ func resolveCollisions(contacts []*collision.Contact, world World) map[int]int {
 }
 
 func resolveCollision(entity entities.Entity, sourceEntity entities.Entity, contact *collision.Contact) {
-	if contact.Type == collision.ContactTypeCapsuleTriMesh {
+	if rigidBody(entity) != nil {
+		resolveRigidBodyCollision(entity, sourceEntity, contact)
+	} else if contact.Type == collision.ContactTypeCapsuleTriMesh || contact.Type == collision.ContactTypeConvexTriMesh || contact.Type == collision.ContactTypeConvexConvex {
 		cc := entity.GetComponentContainer()
 		transformComponent := cc.TransformComponent
 		tpcComponent := cc.ThirdPersonControllerComponent
@@ -296,9 +366,11 @@ func CollisionBookKeeping(entity entities.Entity) {
 	if len(cc.ColliderComponent.Contacts) == 0 {
 		if cc.ThirdPersonControllerComponent != nil {
 			cc.ThirdPersonControllerComponent.Grounded = false
+			cc.ThirdPersonControllerComponent.GroundEntityID = nil
 		}
 	}
 	cc.ColliderComponent.Contacts = map[int]bool{}
+	cc.ColliderComponent.Overlaps = map[int]bool{}
 }
 
 func isCapsuleTriMeshCollision(e1, e2 entities.Entity) (bool, entities.Entity, entities.Entity) {
@@ -332,3 +404,64 @@ func isCapsuleCapsuleCollision(e1, e2 entities.Entity) bool {
 
 	return false
 }
+
+// convexCollider returns the entity's transformed collider as a convex shape, capsules
+// included, or nil if it doesn't have one
+func convexCollider(entity entities.Entity) collider.Convex {
+	return entity.GetComponentContainer().ColliderComponent.ConvexCollider()
+}
+
+// isConvexTriMeshCollision checks for a sphere, box or convex hull against a trimesh.
+// capsules have their own tests against trimeshes
+func isConvexTriMeshCollision(e1, e2 entities.Entity) (bool, entities.Entity, entities.Entity) {
+	e1cc := e1.GetComponentContainer()
+	e2cc := e2.GetComponentContainer()
+
+	if e1cc.ColliderComponent.TransformedConvexCollider() != nil && e2cc.ColliderComponent.TriMeshCollider != nil {
+		return true, e1, e2
+	}
+
+	if e2cc.ColliderComponent.TransformedConvexCollider() != nil && e1cc.ColliderComponent.TriMeshCollider != nil {
+		return true, e2, e1
+	}
+
+	return false, nil, nil
+}
+
+// isConvexConvexCollision checks for a pair of convex shapes where at least one is a
+// sphere, box or convex hull. The first entity returned is the one that gets moved when
+// the collision is resolved. Characters are moved out of rigid bodies rather than the
+// other way around, rigid bodies are pushed by the character's velocity instead
+func isConvexConvexCollision(e1, e2 entities.Entity) (bool, entities.Entity, entities.Entity) {
+	if convexCollider(e1) == nil || convexCollider(e2) == nil {
+		return false, nil, nil
+	}
+
+	if e2.GetComponentContainer().ThirdPersonControllerComponent != nil && e1.GetComponentContainer().ThirdPersonControllerComponent == nil {
+		return true, e2, e1
+	}
+
+	if movable(e1) {
+		return true, e1, e2
+	} else if movable(e2) {
+		return true, e2, e1
+	}
+
+	// neither can be moved so there's nothing to resolve, but triggers still want to
+	// know that they overlap
+	cc1 := e1.GetComponentContainer().ColliderComponent
+	cc2 := e2.GetComponentContainer().ColliderComponent
+	if cc1.Triggers(cc2) || cc2.Triggers(cc1) {
+		return true, e1, e2
+	}
+
+	return false, nil, nil
+}
+
+func movable(entity entities.Entity) bool {
+	cc := entity.GetComponentContainer()
+	if cc.ThirdPersonControllerComponent != nil {
+		return true
+	}
+	return cc.PhysicsComponent != nil && !cc.PhysicsComponent.Static && cc.PhysicsComponent.Motion == nil
+}
@@ -26,13 +26,16 @@ const (
 
 // BaseVelocity - does not involve controller velocities (e.g. WASD)
 // Velocity - actual observable velocity by external systems that includes movement velocities (e.g. WASD)
-//          - computed each frame
-func UpdateCharacterController(delta time.Duration, entity entities.Entity, camera entities.Entity, frameInput input.Input) {
+//   - computed each frame
+func UpdateCharacterController(delta time.Duration, entity entities.Entity, camera entities.Entity, frameInput input.Input, world World) {
 	componentContainer := entity.GetComponentContainer()
 	transformComponent := componentContainer.TransformComponent
 	tpcComponent := componentContainer.ThirdPersonControllerComponent
 	movementComponent := componentContainer.MovementComponent
 
+	// characters standing on kinematic bodies move with them
+	carry(delta, entity, world)
+
 	keyboardInput := frameInput.KeyboardInput
 	controlVector := getControlVector(keyboardInput)
 
@@ -69,7 +72,7 @@ func UpdateCharacterController(delta time.Duration, entity entities.Entity, came
 	movementComponent.Velocity = movementComponent.Velocity.Add(tpcComponent.ControllerVelocity)
 	movementComponent.Velocity = movementComponent.Velocity.Add(tpcComponent.ZipVelocity)
 
-	transformComponent.Position = transformComponent.Position.Add(movementComponent.Velocity.Mul(delta.Seconds()))
+	moveCharacter(entity, movementComponent.Velocity.Mul(delta.Seconds()), world)
 
 	// safeguard falling off the map
 	if transformComponent.Position[1] < -1000 {
@@ -0,0 +1,63 @@
+package netsync
+
+import (
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/kkevinchou/kito/kito/entities"
+	"github.com/kkevinchou/kito/lib/collision"
+	"github.com/kkevinchou/kito/lib/collision/collider"
+	"github.com/kkevinchou/kito/lib/physicsdebug"
+)
+
+// recordContacts records the contacts found on an iteration of collision resolution
+func recordContacts(contacts []*collision.Contact, iteration int, world World) {
+	if !physicsdebug.Enabled() {
+		return
+	}
+
+	for _, contact := range contacts {
+		physicsdebug.RecordContact(physicsdebug.Contact{
+			EntityID:           *contact.EntityID,
+			SourceEntityID:     *contact.SourceEntityID,
+			Iteration:          iteration,
+			Point:              contact.Point,
+			Normal:             contact.Normal,
+			SeparatingVector:   contact.SeparatingVector,
+			SeparatingDistance: contact.SeparatingDistance,
+			Triangle:           worldTriangle(world.GetEntityByID(*contact.SourceEntityID), contact.TriIndex),
+		})
+	}
+}
+
+// recordSweep records where a capsule sweeping through the world stopped
+func recordSweep(entity entities.Entity, capsule collider.Capsule, displacement mgl64.Vec3, hit *collision.SweepHit, hitEntity entities.Entity) {
+	if !physicsdebug.Enabled() {
+		return
+	}
+
+	physicsdebug.RecordSweep(physicsdebug.Sweep{
+		EntityID:     entity.GetID(),
+		HitEntityID:  hitEntity.GetID(),
+		Start:        capsule.Top.Add(capsule.Bottom).Mul(0.5),
+		Displacement: displacement,
+		TimeOfImpact: hit.TimeOfImpact,
+		Point:        hit.Point,
+		Normal:       hit.Normal,
+		Triangle:     worldTriangle(hitEntity, hit.TriIndex),
+	})
+}
+
+// worldTriangle returns the triangle of the entity's trimesh moved to where the entity is
+func worldTriangle(entity entities.Entity, triIndex *int) *collider.Triangle {
+	if entity == nil || triIndex == nil {
+		return nil
+	}
+
+	cc := entity.GetComponentContainer()
+	if cc.ColliderComponent.TriMeshCollider == nil {
+		return nil
+	}
+
+	position := cc.TransformComponent.Position
+	triangle := cc.ColliderComponent.TriMeshCollider.Triangles[*triIndex].Transform(mgl64.Translate3D(position.X(), position.Y(), position.Z()))
+	return &triangle
+}
@@ -0,0 +1,94 @@
+package netsync
+
+import (
+	"math"
+	"time"
+
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/kkevinchou/kito/kito/components"
+	"github.com/kkevinchou/kito/kito/entities"
+	"github.com/kkevinchou/kito/kito/settings"
+)
+
+// UpdateKinematicBodies moves every kinematic body to where its motion puts it on the
+// command frame. The server moves them to its own command frame while the client moves
+// them to the server command frame its predicted inputs will be simulated on, so that
+// characters standing on them are predicted the same way the server simulates them
+func UpdateKinematicBodies(commandFrame int, world World) {
+	frameDuration := time.Duration(settings.MSPerCommandFrame) * time.Millisecond
+	elapsed := time.Duration(commandFrame) * frameDuration
+
+	for _, entity := range world.QueryEntity(components.ComponentFlagPhysics, components.ComponentFlagTransform) {
+		cc := entity.GetComponentContainer()
+		physicsComponent := cc.PhysicsComponent
+		if physicsComponent.Motion == nil {
+			continue
+		}
+
+		position := physicsComponent.Motion.PositionAt(elapsed)
+		previousPosition := physicsComponent.Motion.PositionAt(elapsed - frameDuration)
+		orientation := physicsComponent.Motion.OrientationAt(elapsed)
+		previousOrientation := physicsComponent.Motion.OrientationAt(elapsed - frameDuration)
+
+		cc.TransformComponent.Position = position
+		cc.TransformComponent.Orientation = orientation
+		physicsComponent.Velocity = position.Sub(previousPosition).Mul(1 / frameDuration.Seconds())
+		physicsComponent.AngularVelocity = angularVelocity(previousOrientation, orientation, frameDuration)
+	}
+}
+
+// angularVelocity returns the angular velocity that turns the previous orientation into
+// the orientation over the duration
+func angularVelocity(previous mgl64.Quat, orientation mgl64.Quat, duration time.Duration) mgl64.Vec3 {
+	rotation := orientation.Mul(previous.Inverse()).Normalize()
+	// q and -q are the same rotation, the one with a positive W turns the short way around
+	if rotation.W < 0 {
+		rotation = rotation.Scale(-1)
+	}
+
+	sinHalfAngle := rotation.V.Len()
+	if sinHalfAngle < 1e-12 {
+		return mgl64.Vec3{}
+	}
+	angle := 2 * math.Atan2(sinHalfAngle, rotation.W)
+	return rotation.V.Mul(angle / sinHalfAngle / duration.Seconds())
+}
+
+// carry moves a character standing on a kinematic body by how far the body moved and
+// turns it by how far the body turned this frame. Kinematic bodies move before
+// characters do, so the character starts its own move from where the body left it
+func carry(delta time.Duration, entity entities.Entity, world World) {
+	cc := entity.GetComponentContainer()
+	tpcComponent := cc.ThirdPersonControllerComponent
+	if !tpcComponent.Grounded || tpcComponent.GroundEntityID == nil {
+		return
+	}
+
+	body := world.GetEntityByID(*tpcComponent.GroundEntityID)
+	if !kinematic(body) {
+		return
+	}
+	bodyTransform := body.GetComponentContainer().TransformComponent
+	physicsComponent := body.GetComponentContainer().PhysicsComponent
+
+	// the character turns about where the body was before it moved this frame
+	previousBodyPosition := bodyTransform.Position.Sub(physicsComponent.Velocity.Mul(delta.Seconds()))
+	offset := cc.TransformComponent.Position.Sub(previousBodyPosition)
+
+	rotation := mgl64.QuatIdent()
+	if angle := physicsComponent.AngularVelocity.Len() * delta.Seconds(); angle > 0 {
+		rotation = mgl64.QuatRotate(angle, physicsComponent.AngularVelocity.Normalize())
+	}
+
+	cc.TransformComponent.Position = bodyTransform.Position.Add(rotation.Rotate(offset))
+	cc.TransformComponent.Orientation = rotation.Mul(cc.TransformComponent.Orientation).Normalize()
+}
+
+// kinematic returns whether the entity is a kinematic body
+func kinematic(entity entities.Entity) bool {
+	if entity == nil {
+		return false
+	}
+	physicsComponent := entity.GetComponentContainer().PhysicsComponent
+	return physicsComponent != nil && physicsComponent.Motion != nil
+}
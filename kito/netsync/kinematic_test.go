@@ -0,0 +1,154 @@
+package netsync
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/kkevinchou/kito/kito/components"
+	"github.com/kkevinchou/kito/kito/entities"
+	"github.com/kkevinchou/kito/kito/settings"
+	"github.com/kkevinchou/kito/kito/types"
+	"github.com/kkevinchou/kito/lib/collision/collider"
+	"github.com/kkevinchou/kito/lib/physics"
+	"github.com/kkevinchou/kito/lib/profiler"
+)
+
+// addPlatform adds a kinematic body with a 100 x 100 floor that follows the motion
+func (w *testWorld) addPlatform(motion physics.Motion) *entities.EntityImpl {
+	triangles := quad(mgl64.Vec3{-50, 0, 50}, mgl64.Vec3{50, 0, 50}, mgl64.Vec3{50, 0, -50}, mgl64.Vec3{-50, 0, -50})
+	var vertices []mgl64.Vec3
+	for _, triangle := range triangles {
+		vertices = append(vertices, triangle.Points...)
+	}
+	triMesh := collider.NewTriMeshFromTriangles(triangles)
+
+	return w.add(entities.NewEntity("platform", types.EntityTypePlatform, components.NewComponentContainer(
+		&components.TransformComponent{Position: motion.PositionAt(0), Orientation: motion.OrientationAt(0)},
+		&components.PhysicsComponent{Motion: motion},
+		&components.ColliderComponent{
+			TriMeshCollider:     &triMesh,
+			BoundingBoxCollider: collider.BoundingBoxFromVertices(vertices),
+			Contacts:            map[int]bool{},
+			Layer:               types.CollisionLayerTerrain,
+		},
+	)))
+}
+
+// kinematicFrame simulates the character standing still on the command frame the same
+// way the client replays its inputs
+func (w *testWorld) kinematicFrame(character entities.Entity, commandFrame int) {
+	UpdateKinematicBodies(commandFrame, w)
+	w.broadphase.Sync()
+	carry(time.Duration(settings.MSPerCommandFrame)*time.Millisecond, character, w)
+	moveCharacter(character, mgl64.Vec3{0, -1, 0}, w)
+	ResolveCollisionsForPlayer(character, w, profiler.Timer{})
+	for _, entity := range w.QueryEntity(components.ComponentFlagCollider) {
+		CollisionBookKeeping(entity)
+	}
+	w.broadphase.Sync()
+}
+
+// TestCarry checks that characters stay put on moving and turning platforms and that
+// replaying frames from the middle carries them the same way again
+func TestCarry(t *testing.T) {
+	stationary := physics.Path{Waypoints: []mgl64.Vec3{{0, 0, 0}}}
+	moving := physics.Path{Waypoints: []mgl64.Vec3{{0, 0, 0}, {200, 50, 0}}, Speed: 40}
+
+	testCases := []struct {
+		name   string
+		motion physics.Motion
+	}{
+		{"moving platform", moving},
+		{"rotating platform", physics.Spin{Motion: stationary, Axis: mgl64.Vec3{0, 1, 0}, Speed: 1}},
+		{"moving and rotating platform", physics.Spin{Motion: moving, Axis: mgl64.Vec3{0, 1, 0}, Speed: -1.5}},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			w := newTestWorld()
+			platform := w.addPlatform(testCase.motion)
+			character := w.addCharacter(mgl64.Vec3{30, characterSkinWidth, 10}, true)
+			cc := character.GetComponentContainer()
+
+			// the first frame lands the character on the platform so that later frames
+			// know what it's standing on
+			w.kinematicFrame(character, 0)
+			platformTransform := platform.GetComponentContainer().TransformComponent
+			localPosition := func() mgl64.Vec3 {
+				offset := cc.TransformComponent.Position.Sub(platformTransform.Position)
+				return platformTransform.Orientation.Inverse().Rotate(offset)
+			}
+			start := localPosition()
+
+			const frames, replayFrom = 60, 20
+			var positions []mgl64.Vec3
+			var replayStart components.TransformComponent
+			var replayGroundEntityID int
+			for cf := 1; cf <= frames; cf++ {
+				if cf == replayFrom {
+					replayStart = *cc.TransformComponent
+					replayGroundEntityID = *cc.ThirdPersonControllerComponent.GroundEntityID
+				}
+				w.kinematicFrame(character, cf)
+				positions = append(positions, cc.TransformComponent.Position)
+
+				if !cc.ThirdPersonControllerComponent.Grounded {
+					t.Fatalf("expected the character to stay on the platform but it left it on frame %d", cf)
+				}
+				if !localPosition().ApproxEqualThreshold(start, 0.01) {
+					t.Fatalf("expected the character to stay at %v on the platform but it's at %v on frame %d", start, localPosition(), cf)
+				}
+				expectTransformedPlatform(t, platform)
+			}
+
+			// rewinding puts the platform back where it was, so its cached trimesh has to
+			// be redone for the replayed frames to match
+			*cc.TransformComponent = replayStart
+			cc.ThirdPersonControllerComponent.Grounded = true
+			cc.ThirdPersonControllerComponent.GroundEntityID = &replayGroundEntityID
+			for cf := replayFrom; cf <= frames; cf++ {
+				w.kinematicFrame(character, cf)
+				if cc.TransformComponent.Position != positions[cf-1] {
+					t.Fatalf("frame %d replayed to %v rather than %v", cf, cc.TransformComponent.Position, positions[cf-1])
+				}
+				expectTransformedPlatform(t, platform)
+			}
+		})
+	}
+}
+
+// expectTransformedPlatform checks that collisions are resolved against the platform
+// where it is rather than where it was when its trimesh was last transformed
+func expectTransformedPlatform(t *testing.T, platform entities.Entity) {
+	t.Helper()
+	cc := platform.GetComponentContainer()
+	local := cc.ColliderComponent.TriMeshCollider.Triangles[0].Points[0]
+	expected := cc.TransformComponent.Orientation.Rotate(local).Add(cc.TransformComponent.Position)
+	if point := cc.ColliderComponent.TransformedTriMeshCollider.Triangles[0].Points[0]; !point.ApproxEqualThreshold(expected, 1e-6) {
+		t.Fatalf("expected the platform's transformed trimesh to start at %v but it starts at %v", expected, point)
+	}
+}
+
+func TestAngularVelocity(t *testing.T) {
+	duration := 100 * time.Millisecond
+	previous := mgl64.QuatRotate(0.3, mgl64.Vec3{1, 0, 0})
+	orientation := mgl64.QuatRotate(0.2, mgl64.Vec3{0, 1, 0}).Mul(previous)
+
+	velocity := angularVelocity(previous, orientation, duration)
+	if !velocity.ApproxEqualThreshold(mgl64.Vec3{0, 2, 0}, 1e-9) {
+		t.Fatalf("expected to turn at 2 radians per second about y but got %v", velocity)
+	}
+
+	// turning almost all the way around one way is a short turn the other way
+	orientation = mgl64.QuatRotate(2*math.Pi-0.1, mgl64.Vec3{0, 1, 0})
+	velocity = angularVelocity(mgl64.QuatIdent(), orientation, duration)
+	if !velocity.ApproxEqualThreshold(mgl64.Vec3{0, -1, 0}, 1e-9) {
+		t.Fatalf("expected to turn at 1 radian per second the other way but got %v", velocity)
+	}
+
+	if velocity := angularVelocity(previous, previous, duration); velocity != (mgl64.Vec3{}) {
+		t.Fatalf("expected no angular velocity without turning but got %v", velocity)
+	}
+}
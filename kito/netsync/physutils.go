@@ -15,12 +15,18 @@ const (
 	minYPosition       float64 = -1000
 )
 
-func PhysicsStep(delta time.Duration, entity entities.Entity) {
+func PhysicsStep(delta time.Duration, entity entities.Entity, world World) {
 	componentContainer := entity.GetComponentContainer()
 	physicsComponent := componentContainer.PhysicsComponent
 	transformComponent := componentContainer.TransformComponent
 
-	if physicsComponent.Static {
+	// kinematic bodies are moved by their motion instead, see UpdateKinematicBodies
+	if physicsComponent.Static || physicsComponent.Motion != nil {
+		return
+	}
+
+	if physicsComponent.RigidBody != nil {
+		rigidBodyStep(delta, entity)
 		return
 	}
 
@@ -54,18 +60,16 @@ func PhysicsStep(delta time.Duration, entity entities.Entity) {
 	physicsComponent.Velocity = physicsComponent.Velocity.Add(totalAcceleration.Mul(delta.Seconds()))
 
 	velocity := physicsComponent.Velocity.Add(totalImpulse)
-	newPos := transformComponent.Position.Add(velocity.Mul(delta.Seconds()))
+	move(entity, velocity.Mul(delta.Seconds()), world)
 
 	// temporary hack to not fall through the ground
-	if newPos[1] < minYPosition {
-		newPos[1] = 0
+	if transformComponent.Position[1] < minYPosition {
+		transformComponent.Position[1] = 0
 		velocity[1] = 0
 		physicsComponent.Velocity[1] = 0
 		delete(physicsComponent.Impulses, types.JumpImpulse)
 	}
 
-	transformComponent.Position = newPos
-
 	// updating orientation along velocity
 	velocityWithoutY := mgl64.Vec3{velocity[0], 0, velocity[2]}
 	if !libutils.Vec3IsZero(velocityWithoutY) {
@@ -0,0 +1,208 @@
+package netsync
+
+import (
+	"time"
+
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/kkevinchou/kito/kito/entities"
+	"github.com/kkevinchou/kito/kito/settings"
+	"github.com/kkevinchou/kito/lib/collision"
+	"github.com/kkevinchou/kito/lib/collision/checks"
+	"github.com/kkevinchou/kito/lib/physics"
+)
+
+const (
+	// corners of a shape that are within contactPointTolerance of its deepest point are
+	// also treated as touching, so that shapes lying flat are held up at every corner
+	contactPointTolerance float64 = 0.1
+
+	// the friction of things rigid bodies hit that aren't rigid bodies themselves
+	kinematicFriction float64 = 0.5
+)
+
+func rigidBodyConfig() physics.Config {
+	return physics.Config{
+		Iterations:           settings.RigidBodyIterations,
+		RestitutionThreshold: settings.RigidBodyRestitutionThreshold,
+		SleepSpeed:           settings.RigidBodySleepSpeed,
+		SleepAngularSpeed:    settings.RigidBodySleepAngularSpeed,
+		TimeToSleep:          settings.RigidBodyTimeToSleep,
+	}
+}
+
+func rigidBody(entity entities.Entity) *physics.Body {
+	physicsComponent := entity.GetComponentContainer().PhysicsComponent
+	if physicsComponent == nil {
+		return nil
+	}
+	return physicsComponent.RigidBody
+}
+
+// rigidBodyStep moves and rotates a rigid body by its velocities. Bouncing off of and
+// sliding along what it hits is handled when collisions are resolved
+func rigidBodyStep(delta time.Duration, entity entities.Entity) {
+	cc := entity.GetComponentContainer()
+	physicsComponent := cc.PhysicsComponent
+	transformComponent := cc.TransformComponent
+	body := physicsComponent.RigidBody
+
+	body.Position = transformComponent.Position
+	body.Orientation = transformComponent.Orientation
+	body.UpdateSleep(delta, rigidBodyConfig())
+
+	var acceleration mgl64.Vec3
+	if !physicsComponent.IgnoreGravity {
+		acceleration = settings.AccelerationDueToGravity
+	}
+	body.Integrate(delta, acceleration)
+
+	transformComponent.Position = body.Position
+	transformComponent.Orientation = body.Orientation
+	physicsComponent.Velocity = body.Velocity
+}
+
+// resolveRigidBodyCollision separates a rigid body from what it hit. When it hit another
+// rigid body the separation is shared between them by their masses. Velocities are left
+// alone since they're handled by solveRigidBodyContacts
+func resolveRigidBodyCollision(entity entities.Entity, sourceEntity entities.Entity, contact *collision.Contact) {
+	body := rigidBody(entity)
+	separatingVector := contact.SeparatingVector
+
+	if sourceBody := rigidBody(sourceEntity); sourceBody != nil {
+		inverseMass, sourceInverseMass := awakeInverseMass(body), awakeInverseMass(sourceBody)
+		if total := inverseMass + sourceInverseMass; total > 0 {
+			sourceTransformComponent := sourceEntity.GetComponentContainer().TransformComponent
+			sourceTransformComponent.Position = sourceTransformComponent.Position.Sub(separatingVector.Mul(sourceInverseMass / total))
+			separatingVector = separatingVector.Mul(inverseMass / total)
+		}
+	}
+
+	cc := entity.GetComponentContainer()
+	if contact.Normal.Dot(mgl64.Vec3{0, 1, 0}) >= groundedStrictness {
+		cc.PhysicsComponent.Grounded = true
+	}
+	cc.TransformComponent.Position = cc.TransformComponent.Position.Add(separatingVector)
+}
+
+// awakeInverseMass treats sleeping bodies as immovable, the same way the solver does
+func awakeInverseMass(body *physics.Body) float64 {
+	if body.Sleeping {
+		return 0
+	}
+	return body.InverseMass
+}
+
+type rigidBodyContactKey struct {
+	entityID       int
+	sourceEntityID int
+	triIndex       int
+}
+
+// rigidBodyContacts collects the latest contact for each pair of shapes that a rigid
+// body was part of while collisions were resolved, in the order they were first found
+type rigidBodyContacts struct {
+	indices  map[rigidBodyContactKey]int
+	contacts []*collision.Contact
+}
+
+func newRigidBodyContacts() *rigidBodyContacts {
+	return &rigidBodyContacts{indices: map[rigidBodyContactKey]int{}}
+}
+
+func (c *rigidBodyContacts) add(contact *collision.Contact, world World) {
+	if rigidBody(world.GetEntityByID(*contact.EntityID)) == nil && rigidBody(world.GetEntityByID(*contact.SourceEntityID)) == nil {
+		return
+	}
+
+	key := rigidBodyContactKey{entityID: *contact.EntityID, sourceEntityID: *contact.SourceEntityID, triIndex: -1}
+	if contact.TriIndex != nil {
+		key.triIndex = *contact.TriIndex
+	}
+
+	if index, ok := c.indices[key]; ok {
+		c.contacts[index] = contact
+		return
+	}
+	c.indices[key] = len(c.contacts)
+	c.contacts = append(c.contacts, contact)
+}
+
+// solveRigidBodyContacts applies the impulses from the contacts to the velocities of the
+// rigid bodies. Entities that aren't rigid bodies act as immovable bodies moving with
+// their own velocity so that e.g. characters can push rigid bodies around
+func solveRigidBodyContacts(contacts []*collision.Contact, world World) {
+	if len(contacts) == 0 {
+		return
+	}
+
+	var bodyEntities []entities.Entity
+	bodies := map[int]*physics.Body{}
+	bodyForEntity := func(entity entities.Entity) *physics.Body {
+		if body, ok := bodies[entity.GetID()]; ok {
+			return body
+		}
+
+		cc := entity.GetComponentContainer()
+		body := rigidBody(entity)
+		if body != nil {
+			body.Position = cc.TransformComponent.Position
+			body.Orientation = cc.TransformComponent.Orientation
+			bodyEntities = append(bodyEntities, entity)
+		} else {
+			var velocity mgl64.Vec3
+			if cc.MovementComponent != nil {
+				velocity = cc.MovementComponent.Velocity
+			} else if cc.PhysicsComponent != nil {
+				velocity = cc.PhysicsComponent.Velocity
+			}
+			body = physics.NewKinematicBody(cc.TransformComponent.Position, velocity, kinematicFriction)
+		}
+
+		bodies[entity.GetID()] = body
+		return body
+	}
+
+	var solverContacts []*physics.Contact
+	for _, contact := range contacts {
+		entity := world.GetEntityByID(*contact.EntityID)
+		sourceEntity := world.GetEntityByID(*contact.SourceEntityID)
+		a, b := bodyForEntity(entity), bodyForEntity(sourceEntity)
+
+		for _, point := range contactPoints(entity, sourceEntity, contact) {
+			solverContacts = append(solverContacts, &physics.Contact{A: a, B: b, Point: point, Normal: contact.Normal})
+		}
+	}
+
+	physics.Solve(solverContacts, rigidBodyConfig())
+
+	for _, entity := range bodyEntities {
+		physicsComponent := entity.GetComponentContainer().PhysicsComponent
+		physicsComponent.Velocity = physicsComponent.RigidBody.Velocity
+	}
+}
+
+// contactPoints returns the points the entity touches the source entity at. Points off
+// of the triangle that was hit are dropped since the rest of the mesh may not be there
+func contactPoints(entity entities.Entity, sourceEntity entities.Entity, contact *collision.Contact) []mgl64.Vec3 {
+	shape := convexCollider(entity)
+	if shape == nil {
+		return []mgl64.Vec3{contact.Point}
+	}
+
+	points := collision.ContactPoints(shape, contact, contactPointTolerance)
+	if contact.TriIndex == nil {
+		return points
+	}
+
+	triangle := sourceEntity.GetComponentContainer().ColliderComponent.TransformedTriMeshCollider.Triangles[*contact.TriIndex]
+	var onTriangle []mgl64.Vec3
+	for _, point := range points {
+		if checks.PointInTriangle(point, triangle) {
+			onTriangle = append(onTriangle, point)
+		}
+	}
+	if len(onTriangle) == 0 {
+		return []mgl64.Vec3{contact.Point}
+	}
+	return onTriangle
+}
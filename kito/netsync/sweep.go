@@ -0,0 +1,125 @@
+package netsync
+
+import (
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/kkevinchou/kito/kito/entities"
+	"github.com/kkevinchou/kito/lib/collision"
+	"github.com/kkevinchou/kito/lib/collision/collider"
+	"github.com/kkevinchou/kito/lib/libutils"
+)
+
+const (
+	// the maximum number of times a sweeping entity slides along what it hits before
+	// the rest of its movement is dropped
+	maxSlideCount = 3
+)
+
+// move moves the entity by the displacement. Entities that could move further than their
+// radius in a frame would be able to pass through thin geometry between collision checks,
+// so they sweep their capsule through the world and stop when they hit something. Entities
+// that skip separation stay where they hit, everything else slides along the surface with
+// the rest of its movement
+func move(entity entities.Entity, displacement mgl64.Vec3, world World) {
+	cc := entity.GetComponentContainer()
+	transformComponent := cc.TransformComponent
+	colliderComponent := cc.ColliderComponent
+
+	if colliderComponent == nil || colliderComponent.CapsuleCollider == nil || displacement.Len() <= colliderComponent.CapsuleCollider.Radius {
+		transformComponent.Position = transformComponent.Position.Add(displacement)
+		return
+	}
+
+	for i := 0; i < maxSlideCount && !libutils.Vec3ApproxEqualZero(displacement); i++ {
+		capsule := colliderComponent.CapsuleCollider.Transform(transformComponent.Position)
+		hit, hitEntity := sweepCapsule(entity, capsule, displacement, world)
+		if hit == nil {
+			transformComponent.Position = transformComponent.Position.Add(displacement)
+			return
+		}
+
+		transformComponent.Position = transformComponent.Position.Add(displacement.Mul(hit.TimeOfImpact))
+		colliderComponent.Contacts[hitEntity.GetID()] = true
+		hitEntity.GetComponentContainer().ColliderComponent.Contacts[entity.GetID()] = true
+
+		if colliderComponent.SkipSeparation {
+			return
+		}
+
+		if hit.Normal.Dot(mgl64.Vec3{0, 1, 0}) >= groundedStrictness {
+			ground(entity)
+		}
+
+		remaining := displacement.Mul(1 - hit.TimeOfImpact)
+		displacement = remaining.Sub(hit.Normal.Mul(remaining.Dot(hit.Normal)))
+	}
+}
+
+// sweepCapsule finds the first entity the capsule hits while moving by the displacement
+func sweepCapsule(entity entities.Entity, capsule collider.Capsule, displacement mgl64.Vec3, world World) (*collision.SweepHit, entities.Entity) {
+	colliderComponent := entity.GetComponentContainer().ColliderComponent
+	skipSeparation := colliderComponent.SkipSeparation
+
+	var earliestHit *collision.SweepHit
+	var earliestEntity entities.Entity
+	for _, candidate := range world.Broadphase().QuerySweep(*collider.BoundingBoxFromCapsule(capsule), displacement) {
+		if candidate.GetID() == entity.GetID() {
+			continue
+		}
+
+		// triggers and colliders on layers that don't collide never stop the sweep
+		cc := candidate.GetComponentContainer()
+		if !colliderComponent.Collides(cc.ColliderComponent) {
+			continue
+		}
+		position := cc.TransformComponent.Position
+
+		var hit *collision.SweepHit
+		if cc.ColliderComponent.TriMeshCollider != nil {
+			// sweep in the mesh's space rather than transforming the whole mesh
+			orientation := cc.ColliderOrientation()
+			inverse := orientation.Inverse()
+			localCapsule := capsule.Transform(position.Mul(-1)).Rotate(inverse)
+			hit = collision.SweepCapsuleTriMesh(localCapsule, inverse.Rotate(displacement), *cc.ColliderComponent.TriMeshCollider)
+			if hit != nil {
+				hit.Point = orientation.Rotate(hit.Point).Add(position)
+				hit.Normal = orientation.Rotate(hit.Normal)
+			}
+		} else if !skipSeparation && cc.ColliderComponent.SkipSeparation {
+			// entities that separate don't stop for entities that only want to know
+			// what they touched
+			continue
+		} else if cc.ColliderComponent.CapsuleCollider != nil {
+			hit = collision.SweepCapsuleCapsule(capsule, displacement, cc.ColliderComponent.CapsuleCollider.Transform(position))
+		} else if shape := cc.WorldConvexCollider(); shape != nil {
+			hit = collision.SweepConvexConvex(capsule, displacement, shape)
+		}
+
+		if hit != nil && (earliestHit == nil || hit.TimeOfImpact < earliestHit.TimeOfImpact) {
+			earliestHit = hit
+			earliestEntity = candidate
+		}
+	}
+
+	if earliestHit != nil {
+		recordSweep(entity, capsule, displacement, earliestHit, earliestEntity)
+	}
+	return earliestHit, earliestEntity
+}
+
+// ground lands the entity on what it hit, the same way resolving a collision with the
+// ground does
+func ground(entity entities.Entity) {
+	cc := entity.GetComponentContainer()
+	if cc.MovementComponent != nil {
+		cc.MovementComponent.Velocity[1] = 0
+	}
+
+	if tpcComponent := cc.ThirdPersonControllerComponent; tpcComponent != nil {
+		tpcComponent.BaseVelocity[1] = 0
+		tpcComponent.ZipVelocity = mgl64.Vec3{}
+		tpcComponent.Grounded = true
+	} else if physicsComponent := cc.PhysicsComponent; physicsComponent != nil {
+		physicsComponent.Grounded = true
+		physicsComponent.Velocity[1] = 0
+	}
+}
@@ -0,0 +1,272 @@
+// Package query finds what's in the world along rays, swept shapes and within volumes.
+// Queries go through the broadphase and only test the entities whose bounds they touch
+package query
+
+import (
+	"sort"
+
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/kkevinchou/kito/kito/broadphase"
+	"github.com/kkevinchou/kito/kito/entities"
+	"github.com/kkevinchou/kito/kito/types"
+	"github.com/kkevinchou/kito/lib/collision"
+	"github.com/kkevinchou/kito/lib/collision/checks"
+	"github.com/kkevinchou/kito/lib/collision/collider"
+)
+
+type World interface {
+	Broadphase() *broadphase.Broadphase
+}
+
+// Hit is where a query touched an entity
+type Hit struct {
+	EntityID int
+	Point    mgl64.Vec3
+
+	// Normal points from the entity towards the query
+	Normal mgl64.Vec3
+
+	// TriIndex is the triangle that was hit when the entity has a trimesh collider
+	TriIndex *int
+
+	// Distance is how far a cast travelled before it hit, or for overlaps how far the
+	// point is from the center of the query shape
+	Distance float64
+}
+
+// Options filter what a query can hit
+type Options struct {
+	// Mask is the collision layers the query hits, every layer when unset
+	Mask types.CollisionLayer
+
+	// IgnoreEntityIDs are never hit, e.g. the entity doing the query
+	IgnoreEntityIDs []int
+
+	// triggers aren't solid and are skipped unless IncludeTriggers is set
+	IncludeTriggers bool
+}
+
+// Raycast returns where the ray hits each entity within maxDistance of its origin,
+// nearest first. Like the rest of the ray checks, triangles are only hit from the front.
+// Rays without a direction or distance hit nothing
+func Raycast(world World, ray collider.Ray, maxDistance float64, options Options) []Hit {
+	if !castable(ray.Direction) || maxDistance <= 0 {
+		return nil
+	}
+	direction := ray.Direction.Normalize()
+	ray = collider.Ray{Origin: ray.Origin, Direction: direction}
+	// a ray is swept against convex shapes as a point
+	point := collider.NewSphere(ray.Origin, 0)
+
+	return cast(world, collider.BoundingBox{MinVertex: ray.Origin, MaxVertex: ray.Origin}, direction.Mul(maxDistance), options,
+		func(triMesh collider.TriMesh, position mgl64.Vec3, inverse mgl64.Quat) *collision.SweepHit {
+			localRay := collider.Ray{Origin: inverse.Rotate(ray.Origin.Sub(position)), Direction: inverse.Rotate(direction)}
+			localPoint, triIndex := checks.IntersectRayTriMeshTriangle(localRay, triMesh)
+			if localPoint == nil || localPoint.Sub(localRay.Origin).Len() > maxDistance {
+				return nil
+			}
+			return &collision.SweepHit{
+				TimeOfImpact: localPoint.Sub(localRay.Origin).Len() / maxDistance,
+				TriIndex:     &triIndex,
+				Point:        *localPoint,
+				Normal:       triMesh.Triangles[triIndex].Normal,
+			}
+		},
+		func(shape collider.Convex) *collision.SweepHit {
+			return collision.SweepConvexConvex(point, direction.Mul(maxDistance), shape)
+		},
+	)
+}
+
+// SphereCast returns where the sphere moving up to maxDistance along the direction
+// first touches each entity, nearest first. Casts without a direction hit nothing
+func SphereCast(world World, sphere collider.Sphere, direction mgl64.Vec3, maxDistance float64, options Options) []Hit {
+	if !castable(direction) {
+		return nil
+	}
+	displacement := direction.Normalize().Mul(maxDistance)
+	return cast(world, *collider.BoundingBoxFromConvex(sphere), displacement, options,
+		func(triMesh collider.TriMesh, position mgl64.Vec3, inverse mgl64.Quat) *collision.SweepHit {
+			return collision.SweepSphereTriMesh(sphere.Transform(position.Mul(-1)).Rotate(inverse), inverse.Rotate(displacement), triMesh)
+		},
+		func(shape collider.Convex) *collision.SweepHit {
+			return collision.SweepConvexConvex(sphere, displacement, shape)
+		},
+	)
+}
+
+// CapsuleCast returns where the capsule moving up to maxDistance along the direction
+// first touches each entity, nearest first. Casts without a direction hit nothing
+func CapsuleCast(world World, capsule collider.Capsule, direction mgl64.Vec3, maxDistance float64, options Options) []Hit {
+	if !castable(direction) {
+		return nil
+	}
+	displacement := direction.Normalize().Mul(maxDistance)
+	return cast(world, *collider.BoundingBoxFromCapsule(capsule), displacement, options,
+		func(triMesh collider.TriMesh, position mgl64.Vec3, inverse mgl64.Quat) *collision.SweepHit {
+			return collision.SweepCapsuleTriMesh(capsule.Transform(position.Mul(-1)).Rotate(inverse), inverse.Rotate(displacement), triMesh)
+		},
+		func(shape collider.Convex) *collision.SweepHit {
+			return collision.SweepConvexConvex(capsule, displacement, shape)
+		},
+	)
+}
+
+// castable returns whether a cast can move along the direction. Normalizing a zero
+// direction gives NaNs, which would poison every check along the way
+func castable(direction mgl64.Vec3) bool {
+	return direction.LenSqr() > 0
+}
+
+// OverlapSphere returns the entities that the sphere overlaps, with the point of each
+// that's deepest in the sphere. Hits are ordered by how close the point is to the center
+func OverlapSphere(world World, sphere collider.Sphere, options Options) []Hit {
+	return overlap(world, sphere, sphere.Center, options, func(position mgl64.Vec3, inverse mgl64.Quat) collider.Convex {
+		return sphere.Transform(position.Mul(-1)).Rotate(inverse)
+	})
+}
+
+// OverlapAABB returns the entities that the box overlaps, with the point of each that's
+// deepest in the box. Hits are ordered by how close the point is to the center
+func OverlapAABB(world World, boundingBox collider.BoundingBox, options Options) []Hit {
+	box := collider.NewBoxFromBoundingBox(boundingBox)
+	return overlap(world, box, box.Center, options, func(position mgl64.Vec3, inverse mgl64.Quat) collider.Convex {
+		return box.Transform(position.Mul(-1)).Rotate(inverse)
+	})
+}
+
+// cast sweeps a shape with the bounds through the world. Trimeshes are swept in their
+// own space rather than transforming the whole mesh, sweepTriMesh is given the mesh's
+// position and inverse orientation to move the shape into it
+func cast(
+	world World,
+	bounds collider.BoundingBox,
+	displacement mgl64.Vec3,
+	options Options,
+	sweepTriMesh func(triMesh collider.TriMesh, position mgl64.Vec3, inverse mgl64.Quat) *collision.SweepHit,
+	sweepConvex func(shape collider.Convex) *collision.SweepHit,
+) []Hit {
+	distance := displacement.Len()
+	if distance == 0 {
+		return nil
+	}
+
+	var hits []Hit
+	for _, entity := range world.Broadphase().QuerySweep(bounds, displacement) {
+		cc := entity.GetComponentContainer()
+		if !options.matches(entity) {
+			continue
+		}
+
+		var hit *collision.SweepHit
+		if cc.ColliderComponent.TriMeshCollider != nil {
+			position := cc.TransformComponent.Position
+			orientation := cc.ColliderOrientation()
+			if hit = sweepTriMesh(*cc.ColliderComponent.TriMeshCollider, position, orientation.Inverse()); hit != nil {
+				hit.Point = orientation.Rotate(hit.Point).Add(position)
+				hit.Normal = orientation.Rotate(hit.Normal)
+			}
+		} else if shape := cc.WorldConvexCollider(); shape != nil {
+			hit = sweepConvex(shape)
+		}
+
+		if hit == nil {
+			continue
+		}
+		hits = append(hits, Hit{
+			EntityID: entity.GetID(),
+			Point:    hit.Point,
+			Normal:   hit.Normal,
+			TriIndex: hit.TriIndex,
+			Distance: hit.TimeOfImpact * distance,
+		})
+	}
+
+	sortByDistance(hits)
+	return hits
+}
+
+// overlap tests the shape against the entities its bounds touch. Trimeshes are tested
+// against the shape moved into their own space by local, which is given the mesh's
+// position and inverse orientation
+func overlap(world World, shape collider.Convex, center mgl64.Vec3, options Options, local func(position mgl64.Vec3, inverse mgl64.Quat) collider.Convex) []Hit {
+	var hits []Hit
+	for _, entity := range world.Broadphase().QueryBoundingBox(*collider.BoundingBoxFromConvex(shape)) {
+		cc := entity.GetComponentContainer()
+		if !options.matches(entity) {
+			continue
+		}
+
+		var contacts []*collision.Contact
+		position := mgl64.Vec3{}
+		orientation := mgl64.QuatIdent()
+		if cc.ColliderComponent.TriMeshCollider != nil {
+			position = cc.TransformComponent.Position
+			orientation = cc.ColliderOrientation()
+			contacts = collision.CheckCollisionConvexTriMesh(local(position, orientation.Inverse()), *cc.ColliderComponent.TriMeshCollider)
+		} else if entityShape := cc.WorldConvexCollider(); entityShape != nil {
+			if contact := collision.CheckCollisionConvexConvex(shape, entityShape); contact != nil {
+				contacts = append(contacts, contact)
+			}
+		}
+
+		// keep the contact closest to the center of the shape
+		var closest *Hit
+		for _, contact := range contacts {
+			point := orientation.Rotate(contact.Point).Add(position)
+			distance := point.Sub(center).Len()
+			if closest == nil || distance < closest.Distance {
+				closest = &Hit{
+					EntityID: entity.GetID(),
+					Point:    point,
+					Normal:   orientation.Rotate(contact.Normal),
+					TriIndex: contact.TriIndex,
+					Distance: distance,
+				}
+			}
+		}
+		if closest != nil {
+			hits = append(hits, *closest)
+		}
+	}
+
+	sortByDistance(hits)
+	return hits
+}
+
+func (o Options) matches(entity entities.Entity) bool {
+	colliderComponent := entity.GetComponentContainer().ColliderComponent
+	if colliderComponent == nil {
+		return false
+	}
+
+	mask := o.Mask
+	if mask == 0 {
+		mask = types.CollisionLayerAll
+	}
+	if !colliderComponent.OnLayer(mask) {
+		return false
+	}
+
+	if colliderComponent.Trigger && !o.IncludeTriggers {
+		return false
+	}
+
+	for _, id := range o.IgnoreEntityIDs {
+		if entity.GetID() == id {
+			return false
+		}
+	}
+	return true
+}
+
+// sortByDistance orders the hits nearest first, breaking ties by entity id so that
+// queries return the same order every time
+func sortByDistance(hits []Hit) {
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Distance != hits[j].Distance {
+			return hits[i].Distance < hits[j].Distance
+		}
+		return hits[i].EntityID < hits[j].EntityID
+	})
+}
@@ -0,0 +1,163 @@
+package query_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/kkevinchou/kito/kito/broadphase"
+	"github.com/kkevinchou/kito/kito/components"
+	"github.com/kkevinchou/kito/kito/entities"
+	"github.com/kkevinchou/kito/kito/entitymanager"
+	"github.com/kkevinchou/kito/kito/query"
+	"github.com/kkevinchou/kito/kito/settings"
+	"github.com/kkevinchou/kito/kito/types"
+	"github.com/kkevinchou/kito/lib/collision/collider"
+	"github.com/kkevinchou/kito/lib/physics"
+)
+
+type testWorld struct {
+	*entitymanager.EntityManager
+	broadphase *broadphase.Broadphase
+}
+
+func (w *testWorld) QueryEntity(componentFlags ...int) []entities.Entity {
+	return w.Query(componentFlags...)
+}
+
+func (w *testWorld) Broadphase() *broadphase.Broadphase {
+	return w.broadphase
+}
+
+// newFloorWorld is a world with a 200 x 200 floor at y = 0
+func newFloorWorld() *testWorld {
+	return newPlatformWorld(mgl64.Vec3{}, mgl64.QuatIdent())
+}
+
+// newPlatformWorld is a world with a 200 x 200 floor on a kinematic body, which has its
+// trimesh rotated by its orientation
+func newPlatformWorld(position mgl64.Vec3, orientation mgl64.Quat) *testWorld {
+	w := &testWorld{EntityManager: entitymanager.NewEntityManager()}
+	w.broadphase = broadphase.NewBroadphase(w, settings.BroadphaseMargin)
+
+	vertices := []mgl64.Vec3{{-100, 0, 100}, {100, 0, 100}, {100, 0, -100}, {-100, 0, -100}}
+	triMesh := collider.NewTriMeshFromTriangles([]collider.Triangle{
+		collider.NewTriangle([]mgl64.Vec3{vertices[0], vertices[1], vertices[2]}),
+		collider.NewTriangle([]mgl64.Vec3{vertices[2], vertices[3], vertices[0]}),
+	})
+	floor := entities.NewEntity("floor", types.EntityTypeStaticSlime, components.NewComponentContainer(
+		&components.TransformComponent{Position: position, Orientation: orientation},
+		&components.PhysicsComponent{Motion: physics.Path{Waypoints: []mgl64.Vec3{position}}},
+		&components.ColliderComponent{
+			TriMeshCollider:     &triMesh,
+			BoundingBoxCollider: collider.BoundingBoxFromVertices(vertices),
+			Contacts:            map[int]bool{},
+			Layer:               types.CollisionLayerTerrain,
+		},
+	))
+	floor.SetID(1)
+	w.RegisterEntity(floor)
+	w.broadphase.Sync()
+	return w
+}
+
+func TestRaycast(t *testing.T) {
+	w := newFloorWorld()
+	origin := mgl64.Vec3{10, 20, 10}
+
+	testCases := []struct {
+		name        string
+		direction   mgl64.Vec3
+		maxDistance float64
+		hit         bool
+	}{
+		{"down onto the floor", mgl64.Vec3{0, -1, 0}, 100, true},
+		{"unnormalized direction", mgl64.Vec3{0, -5, 0}, 100, true},
+		{"short of the floor", mgl64.Vec3{0, -1, 0}, 10, false},
+		{"away from the floor", mgl64.Vec3{0, 1, 0}, 100, false},
+		{"zero direction", mgl64.Vec3{}, 100, false},
+		{"zero distance", mgl64.Vec3{0, -1, 0}, 0, false},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			hits := query.Raycast(w, collider.Ray{Origin: origin, Direction: testCase.direction}, testCase.maxDistance, query.Options{})
+			if !testCase.hit {
+				if len(hits) != 0 {
+					t.Fatalf("expected no hits but got %+v", hits)
+				}
+				return
+			}
+
+			if len(hits) != 1 {
+				t.Fatalf("expected to hit the floor but got %+v", hits)
+			}
+			if !hits[0].Point.ApproxEqualThreshold(mgl64.Vec3{10, 0, 10}, 1e-6) || hits[0].Distance != 20 {
+				t.Fatalf("expected to hit the floor 20 units below the origin but got %+v", hits[0])
+			}
+		})
+	}
+}
+
+func TestCastsWithoutADirection(t *testing.T) {
+	w := newFloorWorld()
+
+	sphere := collider.NewSphere(mgl64.Vec3{0, 10, 0}, 5)
+	capsule := collider.NewCapsule(mgl64.Vec3{0, 20, 0}, mgl64.Vec3{0, 10, 0}, 5)
+	down := mgl64.Vec3{0, -1, 0}
+
+	if hits := query.SphereCast(w, sphere, down, 10, query.Options{}); len(hits) != 1 {
+		t.Fatalf("expected a sphere cast down to hit the floor but got %+v", hits)
+	}
+	if hits := query.SphereCast(w, sphere, mgl64.Vec3{}, 10, query.Options{}); len(hits) != 0 {
+		t.Fatalf("expected a sphere cast without a direction to hit nothing but got %+v", hits)
+	}
+
+	if hits := query.CapsuleCast(w, capsule, down, 10, query.Options{}); len(hits) != 1 {
+		t.Fatalf("expected a capsule cast down to hit the floor but got %+v", hits)
+	}
+	if hits := query.CapsuleCast(w, capsule, mgl64.Vec3{}, 10, query.Options{}); len(hits) != 0 {
+		t.Fatalf("expected a capsule cast without a direction to hit nothing but got %+v", hits)
+	}
+}
+
+// TestRotatedTriMesh queries a floor that's been turned on its side into a wall facing
+// -x at x = 20
+func TestRotatedTriMesh(t *testing.T) {
+	w := newPlatformWorld(mgl64.Vec3{20, 0, 0}, mgl64.QuatRotate(mgl64.DegToRad(90), mgl64.Vec3{0, 0, 1}))
+	wallNormal := mgl64.Vec3{-1, 0, 0}
+	right := mgl64.Vec3{1, 0, 0}
+
+	expectHit := func(name string, hits []query.Hit, point mgl64.Vec3) {
+		t.Helper()
+		if len(hits) != 1 {
+			t.Fatalf("expected the %s to hit the wall but got %+v", name, hits)
+		}
+		if !hits[0].Point.ApproxEqualThreshold(point, 1e-6) || !hits[0].Normal.ApproxEqualThreshold(wallNormal, 1e-6) {
+			t.Fatalf("expected the %s to hit the wall at %v facing %v but got %+v", name, point, wallNormal, hits[0])
+		}
+	}
+
+	ray := collider.Ray{Origin: mgl64.Vec3{0, 5, 5}, Direction: right}
+	expectHit("ray", query.Raycast(w, ray, 100, query.Options{}), mgl64.Vec3{20, 5, 5})
+
+	sphere := collider.NewSphere(mgl64.Vec3{0, 5, 5}, 2)
+	expectHit("sphere cast", query.SphereCast(w, sphere, right, 100, query.Options{}), mgl64.Vec3{20, 5, 5})
+
+	capsule := collider.NewCapsule(mgl64.Vec3{0, 10, 5}, mgl64.Vec3{0, 5, 5}, 2)
+	hits := query.CapsuleCast(w, capsule, right, 100, query.Options{})
+	if len(hits) != 1 || !hits[0].Normal.ApproxEqualThreshold(wallNormal, 1e-6) || math.Abs(hits[0].Distance-18) > 0.01 {
+		t.Fatalf("expected the capsule cast to hit the wall facing %v after 18 units but got %+v", wallNormal, hits)
+	}
+
+	// overlaps are found iteratively so they're only close to the exact contact
+	hits = query.OverlapSphere(w, collider.NewSphere(mgl64.Vec3{19, 5, 5}, 2), query.Options{})
+	if len(hits) != 1 || !hits[0].Normal.ApproxEqualThreshold(wallNormal, 1e-3) || !hits[0].Point.ApproxEqualThreshold(mgl64.Vec3{20, 5, 5}, 0.05) {
+		t.Fatalf("expected the sphere to overlap the wall at x = 20 facing %v but got %+v", wallNormal, hits)
+	}
+
+	// the unrotated floor would have been hit straight below
+	if hits := query.Raycast(w, collider.Ray{Origin: mgl64.Vec3{40, 5, 5}, Direction: mgl64.Vec3{0, -1, 0}}, 100, query.Options{}); len(hits) != 0 {
+		t.Fatalf("expected nothing below the wall but got %+v", hits)
+	}
+}
@@ -0,0 +1,68 @@
+package scheduler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// HandleCommand executes a console command against the scheduler and returns
+// its output. Supported commands are:
+//
+//	list
+//	enable <system>
+//	disable <system>
+//	step <system>
+//	parallel <true|false>
+func (s *Scheduler) HandleCommand(args []string) (string, error) {
+	if len(args) == 0 {
+		return "", fmt.Errorf("expected one of list, enable, disable, step, parallel")
+	}
+
+	if args[0] == "list" {
+		var lines []string
+		for _, info := range s.Systems() {
+			state := "enabled"
+			if !info.Enabled {
+				state = "disabled"
+			}
+			lines = append(lines, fmt.Sprintf("[%s:%d] %s (%s)", info.Phase, info.Stage, info.Name, state))
+		}
+		lines = append(lines, fmt.Sprintf("parallel: %t", s.Parallel()))
+		return strings.Join(lines, "\n"), nil
+	}
+
+	if len(args) != 2 {
+		return "", fmt.Errorf("expected a system name for %s", args[0])
+	}
+
+	if args[0] == "parallel" {
+		parallel, err := strconv.ParseBool(args[1])
+		if err != nil {
+			return "", err
+		}
+		s.SetParallel(parallel)
+		return fmt.Sprintf("parallel: %t", parallel), nil
+	}
+
+	name := args[1]
+	switch args[0] {
+	case "enable":
+		if err := s.SetEnabled(name, true); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("enabled %s", name), nil
+	case "disable":
+		if err := s.SetEnabled(name, false); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("disabled %s", name), nil
+	case "step":
+		if err := s.Step(name); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("stepping %s", name), nil
+	}
+
+	return "", fmt.Errorf("unknown scheduler command %s", args[0])
+}
@@ -0,0 +1,457 @@
+package scheduler
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kkevinchou/kito/lib/profiler"
+)
+
+type System interface {
+	Name() string
+	Update(delta time.Duration)
+}
+
+// TimedSystem is a system that profiles parts of its update. Systems can run
+// concurrently, so rather than Update they're run with UpdateTimed and handed the scope
+// the scheduler records them in to begin their own scopes from
+type TimedSystem interface {
+	System
+	UpdateTimed(delta time.Duration, timer profiler.Timer)
+}
+
+// Phase is a coarse grouping of systems within a command frame. Every system in
+// an earlier phase runs before every system in a later phase. Finer grained ordering
+// within a phase is expressed through Before and After dependencies.
+type Phase int
+
+const (
+	PhaseInput Phase = iota
+	PhaseSimulation
+	PhasePostSimulation
+	PhaseNetwork
+)
+
+var phaseNames = map[Phase]string{
+	PhaseInput:          "input",
+	PhaseSimulation:     "simulation",
+	PhasePostSimulation: "postsimulation",
+	PhaseNetwork:        "network",
+}
+
+func (p Phase) String() string {
+	if name, ok := phaseNames[p]; ok {
+		return name
+	}
+	return fmt.Sprintf("phase(%d)", int(p))
+}
+
+type Option func(e *entry)
+
+// After declares that the system must run after the named systems
+func After(names ...string) Option {
+	return func(e *entry) {
+		e.after = append(e.after, names...)
+	}
+}
+
+// Before declares that the system must run before the named systems
+func Before(names ...string) Option {
+	return func(e *entry) {
+		e.before = append(e.before, names...)
+	}
+}
+
+// Reads declares the component types (as component flags) that the system reads
+func Reads(componentFlags ...int) Option {
+	return func(e *entry) {
+		e.declaredAccess = true
+		for _, flag := range componentFlags {
+			e.reads[flag] = true
+		}
+	}
+}
+
+// Writes declares the component types (as component flags) that the system writes
+func Writes(componentFlags ...int) Option {
+	return func(e *entry) {
+		e.declaredAccess = true
+		for _, flag := range componentFlags {
+			e.writes[flag] = true
+		}
+	}
+}
+
+// WhilePaused marks a system that keeps running while the simulation is paused, e.g.
+// systems that are needed to receive the command to unpause
+func WhilePaused() Option {
+	return func(e *entry) {
+		e.whilePaused = true
+	}
+}
+
+type entry struct {
+	system System
+	phase  Phase
+	before []string
+	after  []string
+
+	// component access declared by the system. systems that don't declare their
+	// access are assumed to touch everything and never run concurrently
+	declaredAccess bool
+	reads          map[int]bool
+	writes         map[int]bool
+
+	// registration order, used to break ties so that the resulting order is stable
+	index int
+
+	enabled      bool
+	pendingSteps int
+	whilePaused  bool
+}
+
+type SystemInfo struct {
+	Name    string
+	Phase   Phase
+	Enabled bool
+
+	// Stage is the index of the group of systems this system may run concurrently with
+	Stage int
+}
+
+// Scheduler owns the set of systems that run every command frame and the order
+// they run in. Systems are registered with a phase and optional dependencies and
+// Build validates them into a single execution order.
+type Scheduler struct {
+	entries map[string]*entry
+
+	registered []*entry
+	order      []*entry
+	built      bool
+
+	// stages partition the order into groups of systems that are safe to run concurrently
+	stages   [][]*entry
+	parallel bool
+}
+
+func New() *Scheduler {
+	return &Scheduler{
+		entries: map[string]*entry{},
+	}
+}
+
+func (s *Scheduler) Register(system System, phase Phase, options ...Option) error {
+	name := system.Name()
+	if _, ok := s.entries[name]; ok {
+		return fmt.Errorf("system %s is already registered", name)
+	}
+	if _, ok := phaseNames[phase]; !ok {
+		return fmt.Errorf("system %s registered with unknown phase %d", name, phase)
+	}
+
+	e := &entry{
+		system:  system,
+		phase:   phase,
+		index:   len(s.registered),
+		enabled: true,
+		reads:   map[int]bool{},
+		writes:  map[int]bool{},
+	}
+	for _, option := range options {
+		option(e)
+	}
+
+	s.entries[name] = e
+	s.registered = append(s.registered, e)
+	s.built = false
+	return nil
+}
+
+// Build validates the declared dependencies and computes the execution order.
+// Dependencies on unknown systems, dependencies that contradict phase ordering,
+// and dependency cycles are reported as errors.
+func (s *Scheduler) Build() error {
+	// edges[a] contains every system that must run after a
+	edges := map[*entry][]*entry{}
+	inDegree := map[*entry]int{}
+
+	addEdge := func(from, to *entry) error {
+		if from.phase > to.phase {
+			return fmt.Errorf("%s (%s) cannot run before %s (%s)", from.system.Name(), from.phase, to.system.Name(), to.phase)
+		}
+		if from.phase < to.phase {
+			// already satisfied by phase ordering
+			return nil
+		}
+		edges[from] = append(edges[from], to)
+		inDegree[to]++
+		return nil
+	}
+
+	for _, e := range s.registered {
+		for _, name := range e.after {
+			dep, ok := s.entries[name]
+			if !ok {
+				return fmt.Errorf("%s declared a dependency on unknown system %s", e.system.Name(), name)
+			}
+			if err := addEdge(dep, e); err != nil {
+				return err
+			}
+		}
+		for _, name := range e.before {
+			dep, ok := s.entries[name]
+			if !ok {
+				return fmt.Errorf("%s declared a dependency on unknown system %s", e.system.Name(), name)
+			}
+			if err := addEdge(e, dep); err != nil {
+				return err
+			}
+		}
+	}
+
+	var order []*entry
+	var ready []*entry
+	for _, e := range s.registered {
+		if inDegree[e] == 0 {
+			ready = append(ready, e)
+		}
+	}
+
+	for len(ready) > 0 {
+		sort.Slice(ready, func(i, j int) bool {
+			if ready[i].phase != ready[j].phase {
+				return ready[i].phase < ready[j].phase
+			}
+			return ready[i].index < ready[j].index
+		})
+
+		next := ready[0]
+		ready = ready[1:]
+		order = append(order, next)
+
+		for _, e := range edges[next] {
+			inDegree[e]--
+			if inDegree[e] == 0 {
+				ready = append(ready, e)
+			}
+		}
+	}
+
+	if len(order) != len(s.registered) {
+		var cycle []string
+		for _, e := range s.registered {
+			if inDegree[e] > 0 {
+				cycle = append(cycle, e.system.Name())
+			}
+		}
+		return fmt.Errorf("dependency cycle detected between systems: %s", strings.Join(cycle, ", "))
+	}
+
+	// systems can be moved ahead of the systems they don't depend on to share a stage, so
+	// the stages decide the order systems run in
+	s.stages = computeStages(order, edges)
+	s.order = nil
+	for _, stage := range s.stages {
+		s.order = append(s.order, stage...)
+	}
+	s.built = true
+	return nil
+}
+
+// computeStages groups the systems into stages of systems that are safe to run
+// concurrently. Each system is placed in the earliest stage of its phase that comes
+// after every system it depends on and that has no system it conflicts with. Only the
+// declared dependencies order systems within a phase, so a system that didn't declare
+// its component access gets a stage to itself without splitting up the systems
+// registered around it
+func computeStages(order []*entry, edges map[*entry][]*entry) [][]*entry {
+	dependencies := map[*entry][]*entry{}
+	for _, e := range order {
+		for _, next := range edges[e] {
+			dependencies[next] = append(dependencies[next], e)
+		}
+	}
+
+	var stages [][]*entry
+	stageIndex := map[*entry]int{}
+	phaseStart := 0
+	for i, e := range order {
+		if i > 0 && e.phase != order[i-1].phase {
+			phaseStart = len(stages)
+		}
+
+		earliest := phaseStart
+		for _, dependency := range dependencies[e] {
+			if stageIndex[dependency]+1 > earliest {
+				earliest = stageIndex[dependency] + 1
+			}
+		}
+
+		index := len(stages)
+		for j := earliest; j < len(stages); j++ {
+			if canJoinStage(e, stages[j]) {
+				index = j
+				break
+			}
+		}
+		if index == len(stages) {
+			stages = append(stages, nil)
+		}
+		stages[index] = append(stages[index], e)
+		stageIndex[e] = index
+	}
+	return stages
+}
+
+func canJoinStage(e *entry, stage []*entry) bool {
+	if !e.declaredAccess {
+		return false
+	}
+	for _, other := range stage {
+		if !other.declaredAccess {
+			return false
+		}
+		if intersects(e.writes, other.reads) || intersects(e.writes, other.writes) || intersects(other.writes, e.reads) {
+			return false
+		}
+	}
+	return true
+}
+
+func intersects(a, b map[int]bool) bool {
+	for flag := range a {
+		if b[flag] {
+			return true
+		}
+	}
+	return false
+}
+
+// Run updates every enabled system in the built order and returns the time spent in
+// each system, keyed by system name
+func (s *Scheduler) Run(delta time.Duration) map[string]time.Duration {
+	if !s.built {
+		panic("scheduler run before being built")
+	}
+
+	timings := map[string]time.Duration{}
+	for _, stage := range s.stages {
+		var runnable []*entry
+		for _, e := range stage {
+			if !e.enabled {
+				if e.pendingSteps == 0 {
+					continue
+				}
+				e.pendingSteps--
+			}
+			runnable = append(runnable, e)
+		}
+
+		if !s.parallel || len(runnable) < 2 {
+			for _, e := range runnable {
+				timings[e.system.Name()] = runSystem(e.system, delta, 0)
+			}
+			continue
+		}
+
+		stageTimings := make([]time.Duration, len(runnable))
+		var wg sync.WaitGroup
+		wg.Add(len(runnable))
+		for i, e := range runnable {
+			// each concurrent system is profiled on its own track so that their scopes
+			// don't nest into each other
+			go func(i int, system System) {
+				defer wg.Done()
+				stageTimings[i] = runSystem(system, delta, i+1)
+			}(i, e.system)
+		}
+		wg.Wait()
+
+		for i, e := range runnable {
+			timings[e.system.Name()] = stageTimings[i]
+		}
+	}
+	return timings
+}
+
+// RunPaused updates the enabled systems that were registered to run while the
+// simulation is paused
+func (s *Scheduler) RunPaused(delta time.Duration) {
+	if !s.built {
+		panic("scheduler run before being built")
+	}
+
+	for _, e := range s.order {
+		if e.enabled && e.whilePaused {
+			e.system.Update(delta)
+		}
+	}
+}
+
+func runSystem(system System, delta time.Duration, track int) time.Duration {
+	timer := profiler.BeginTrack(system.Name(), track)
+	defer timer.End()
+
+	start := time.Now()
+	if timedSystem, ok := system.(TimedSystem); ok {
+		timedSystem.UpdateTimed(delta, timer)
+	} else {
+		system.Update(delta)
+	}
+	return time.Since(start)
+}
+
+// SetParallel toggles concurrent execution of systems within a stage. When disabled
+// the scheduler runs every system serially in the built order, which is the
+// deterministic mode.
+func (s *Scheduler) SetParallel(parallel bool) {
+	s.parallel = parallel
+}
+
+func (s *Scheduler) Parallel() bool {
+	return s.parallel
+}
+
+func (s *Scheduler) SetEnabled(name string, enabled bool) error {
+	e, ok := s.entries[name]
+	if !ok {
+		return fmt.Errorf("unknown system %s", name)
+	}
+	e.enabled = enabled
+	e.pendingSteps = 0
+	return nil
+}
+
+// Step runs a disabled system for a single command frame
+func (s *Scheduler) Step(name string) error {
+	e, ok := s.entries[name]
+	if !ok {
+		return fmt.Errorf("unknown system %s", name)
+	}
+	if e.enabled {
+		return fmt.Errorf("system %s must be disabled before it can be stepped", name)
+	}
+	e.pendingSteps++
+	return nil
+}
+
+// Systems returns the registered systems in execution order
+func (s *Scheduler) Systems() []SystemInfo {
+	var infos []SystemInfo
+	if !s.built {
+		for _, e := range s.registered {
+			infos = append(infos, SystemInfo{Name: e.system.Name(), Phase: e.phase, Enabled: e.enabled})
+		}
+		return infos
+	}
+
+	for i, stage := range s.stages {
+		for _, e := range stage {
+			infos = append(infos, SystemInfo{Name: e.system.Name(), Phase: e.phase, Enabled: e.enabled, Stage: i})
+		}
+	}
+	return infos
+}
@@ -0,0 +1,261 @@
+package scheduler_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kkevinchou/kito/kito/scheduler"
+	"github.com/kkevinchou/kito/lib/profiler"
+)
+
+type testSystem struct {
+	name string
+	log  *[]string
+}
+
+func (s *testSystem) Name() string {
+	return s.name
+}
+
+func (s *testSystem) Update(delta time.Duration) {
+	*s.log = append(*s.log, s.name)
+}
+
+func checkOrder(t *testing.T, expected []string, actual []string) {
+	if len(expected) != len(actual) {
+		t.Fatalf("expected order %v but got %v", expected, actual)
+	}
+	for i := range expected {
+		if expected[i] != actual[i] {
+			t.Fatalf("expected order %v but got %v", expected, actual)
+		}
+	}
+}
+
+func TestOrder(t *testing.T) {
+	var log []string
+	s := scheduler.New()
+	s.Register(&testSystem{name: "network", log: &log}, scheduler.PhaseNetwork)
+	s.Register(&testSystem{name: "collision", log: &log}, scheduler.PhaseSimulation, scheduler.After("physics"))
+	s.Register(&testSystem{name: "physics", log: &log}, scheduler.PhaseSimulation)
+	s.Register(&testSystem{name: "input", log: &log}, scheduler.PhaseInput)
+	s.Register(&testSystem{name: "ai", log: &log}, scheduler.PhaseSimulation, scheduler.Before("physics"))
+
+	if err := s.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	s.Run(time.Millisecond)
+	checkOrder(t, []string{"input", "ai", "physics", "collision", "network"}, log)
+}
+
+func TestCycle(t *testing.T) {
+	var log []string
+	s := scheduler.New()
+	s.Register(&testSystem{name: "a", log: &log}, scheduler.PhaseSimulation, scheduler.After("c"))
+	s.Register(&testSystem{name: "b", log: &log}, scheduler.PhaseSimulation, scheduler.After("a"))
+	s.Register(&testSystem{name: "c", log: &log}, scheduler.PhaseSimulation, scheduler.After("b"))
+
+	if err := s.Build(); err == nil {
+		t.Fatal("expected a cycle to be detected")
+	}
+}
+
+func TestPhaseConflict(t *testing.T) {
+	var log []string
+	s := scheduler.New()
+	s.Register(&testSystem{name: "input", log: &log}, scheduler.PhaseInput, scheduler.After("network"))
+	s.Register(&testSystem{name: "network", log: &log}, scheduler.PhaseNetwork)
+
+	if err := s.Build(); err == nil {
+		t.Fatal("expected a phase conflict to be detected")
+	}
+}
+
+func TestUnknownDependency(t *testing.T) {
+	var log []string
+	s := scheduler.New()
+	s.Register(&testSystem{name: "a", log: &log}, scheduler.PhaseSimulation, scheduler.After("missing"))
+
+	if err := s.Build(); err == nil {
+		t.Fatal("expected an unknown dependency to be reported")
+	}
+}
+
+func TestDisableAndStep(t *testing.T) {
+	var log []string
+	s := scheduler.New()
+	s.Register(&testSystem{name: "a", log: &log}, scheduler.PhaseSimulation)
+	s.Register(&testSystem{name: "b", log: &log}, scheduler.PhaseSimulation)
+	if err := s.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.Step("a"); err == nil {
+		t.Fatal("expected stepping an enabled system to fail")
+	}
+
+	s.SetEnabled("a", false)
+	s.Run(time.Millisecond)
+	checkOrder(t, []string{"b"}, log)
+
+	log = nil
+	s.Step("a")
+	s.Run(time.Millisecond)
+	s.Run(time.Millisecond)
+	checkOrder(t, []string{"a", "b", "b"}, log)
+}
+
+type blockingSystem struct {
+	name    string
+	started chan bool
+	release chan bool
+}
+
+func (s *blockingSystem) Name() string {
+	return s.name
+}
+
+func (s *blockingSystem) Update(delta time.Duration) {
+	s.started <- true
+	<-s.release
+}
+
+func TestStages(t *testing.T) {
+	const (
+		flagA = 1 << 1
+		flagB = 1 << 2
+		flagC = 1 << 3
+	)
+
+	var log []string
+	s := scheduler.New()
+	s.Register(&testSystem{name: "a", log: &log}, scheduler.PhaseSimulation, scheduler.Writes(flagA))
+	s.Register(&testSystem{name: "b", log: &log}, scheduler.PhaseSimulation, scheduler.Reads(flagC), scheduler.Writes(flagB))
+	s.Register(&testSystem{name: "c", log: &log}, scheduler.PhaseSimulation, scheduler.Reads(flagB))
+	s.Register(&testSystem{name: "d", log: &log}, scheduler.PhaseSimulation, scheduler.Reads(flagA), scheduler.After("c"))
+	s.Register(&testSystem{name: "e", log: &log}, scheduler.PhaseSimulation)
+	if err := s.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	expectedStages := map[string]int{"a": 0, "b": 0, "c": 1, "d": 2, "e": 3}
+	for _, info := range s.Systems() {
+		if expectedStages[info.Name] != info.Stage {
+			t.Errorf("expected %s to be in stage %d but was in %d", info.Name, expectedStages[info.Name], info.Stage)
+		}
+	}
+}
+
+func TestUndeclaredSystemsDontSplitStages(t *testing.T) {
+	const (
+		flagA = 1 << 1
+		flagB = 1 << 2
+	)
+
+	var log []string
+	s := scheduler.New()
+	s.Register(&testSystem{name: "a", log: &log}, scheduler.PhaseSimulation, scheduler.Writes(flagA))
+	s.Register(&testSystem{name: "undeclared", log: &log}, scheduler.PhaseSimulation)
+	s.Register(&testSystem{name: "b", log: &log}, scheduler.PhaseSimulation, scheduler.Writes(flagB))
+	s.Register(&testSystem{name: "c", log: &log}, scheduler.PhaseSimulation, scheduler.Reads(flagA), scheduler.After("undeclared"))
+	if err := s.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	expectedStages := map[string]int{"a": 0, "b": 0, "undeclared": 1, "c": 2}
+	for _, info := range s.Systems() {
+		if expectedStages[info.Name] != info.Stage {
+			t.Errorf("expected %s to be in stage %d but was in %d", info.Name, expectedStages[info.Name], info.Stage)
+		}
+	}
+
+	s.Run(time.Millisecond)
+	checkOrder(t, []string{"a", "b", "undeclared", "c"}, log)
+}
+
+func TestParallel(t *testing.T) {
+	started := make(chan bool)
+	release := make(chan bool)
+
+	s := scheduler.New()
+	s.Register(&blockingSystem{name: "a", started: started, release: release}, scheduler.PhaseSimulation, scheduler.Writes(1<<1))
+	s.Register(&blockingSystem{name: "b", started: started, release: release}, scheduler.PhaseSimulation, scheduler.Writes(1<<2))
+	if err := s.Build(); err != nil {
+		t.Fatal(err)
+	}
+	s.SetParallel(true)
+
+	done := make(chan bool)
+	go func() {
+		s.Run(time.Millisecond)
+		done <- true
+	}()
+
+	// both systems must be running at the same time for both starts to be observed
+	// before either is released
+	<-started
+	<-started
+	release <- true
+	release <- true
+	<-done
+}
+
+type timedSystem struct {
+	blockingSystem
+}
+
+func (s *timedSystem) UpdateTimed(delta time.Duration, timer profiler.Timer) {
+	defer timer.Begin(s.name + ".collision").End()
+	s.Update(delta)
+}
+
+func TestTimedSystemsScopesStayOnTheirTrack(t *testing.T) {
+	started := make(chan bool)
+	release := make(chan bool)
+
+	s := scheduler.New()
+	s.Register(&timedSystem{blockingSystem{name: "a", started: started, release: release}}, scheduler.PhaseSimulation, scheduler.Writes(1<<1))
+	s.Register(&timedSystem{blockingSystem{name: "b", started: started, release: release}}, scheduler.PhaseSimulation, scheduler.Writes(1<<2))
+	if err := s.Build(); err != nil {
+		t.Fatal(err)
+	}
+	s.SetParallel(true)
+
+	defer profiler.GlobalProfiler.Clear()
+	profiler.BeginFrame(1)
+	done := make(chan bool)
+	go func() {
+		s.Run(time.Millisecond)
+		done <- true
+	}()
+	<-started
+	<-started
+	release <- true
+	release <- true
+	<-done
+
+	tracks := map[string]int{}
+	for _, scope := range profiler.GlobalProfiler.Frames()[0].Scopes {
+		tracks[scope.Name] = scope.Track
+		if depth := map[string]int{"a": 0, "b": 0, "a.collision": 1, "b.collision": 1}[scope.Name]; scope.Depth != depth {
+			t.Fatalf("expected %s at depth %d but got %+v", scope.Name, depth, scope)
+		}
+	}
+	if len(tracks) != 4 || tracks["a"] == tracks["b"] || tracks["a.collision"] != tracks["a"] || tracks["b.collision"] != tracks["b"] {
+		t.Fatalf("expected each system's scopes on a track of its own but got %v", tracks)
+	}
+}
+
+func TestRunPaused(t *testing.T) {
+	var log []string
+	s := scheduler.New()
+	s.Register(&testSystem{name: "network", log: &log}, scheduler.PhaseInput, scheduler.WhilePaused())
+	s.Register(&testSystem{name: "physics", log: &log}, scheduler.PhaseSimulation)
+	if err := s.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	s.RunPaused(time.Millisecond)
+	checkOrder(t, []string{"network"}, log)
+}
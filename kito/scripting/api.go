@@ -0,0 +1,364 @@
+package scripting
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/kkevinchou/kito/kito/components"
+	"github.com/kkevinchou/kito/kito/entities"
+	"github.com/kkevinchou/kito/kito/events"
+	"github.com/kkevinchou/kito/kito/managers/timer"
+	"github.com/kkevinchou/kito/kito/types"
+	"github.com/kkevinchou/kito/kito/utils/entityutils"
+	"github.com/kkevinchou/kito/lib/logger"
+	lua "github.com/yuin/gopher-lua"
+)
+
+const apiName = "kito"
+
+// the entity types scripts are allowed to spawn
+var spawnableEntityTypes = map[string]types.EntityType{
+	"enemy":      types.EntityTypeEnemy,
+	"lootbox":    types.EntityTypeLootbox,
+	"projectile": types.EntityTypeProjectile,
+	"slime":      types.EntityTypeStaticSlime,
+}
+
+// registerAPI exposes the kito table to scripts. Components and fields are
+// addressed by their schema names, e.g. kito.get(id, "HealthComponent", "Data.Value"),
+// so scripts are held to the same read only fields and ranges as the inspector
+func (e *Engine) registerAPI() {
+	api := e.state.SetFuncs(e.state.NewTable(), map[string]lua.LGFunction{
+		"command_frame": e.apiCommandFrame,
+		"query":         e.apiQuery,
+		"has":           e.apiHas,
+		"get":           e.apiGet,
+		"set":           e.apiSet,
+		"spawn":         e.apiSpawn,
+		"destroy":       e.apiDestroy,
+		"broadcast":     e.apiBroadcast,
+		"after":         e.apiAfter,
+		"every":         e.apiEvery,
+		"cancel":        e.apiCancel,
+		"random":        e.apiRandom,
+		"log":           e.apiLog,
+	})
+	e.state.SetGlobal(apiName, api)
+
+	// math.random is backed by a global generator, replace it with the engine's seeded one
+	if math, ok := e.state.GetGlobal(lua.MathLibName).(*lua.LTable); ok {
+		math.RawSetString("random", e.state.NewFunction(e.apiRandom))
+		math.RawSetString("randomseed", lua.LNil)
+	}
+}
+
+func (e *Engine) apiCommandFrame(L *lua.LState) int {
+	L.Push(lua.LNumber(e.world.CommandFrame()))
+	return 1
+}
+
+// kito.query(component, ...) returns the IDs of the entities that have every component
+func (e *Engine) apiQuery(L *lua.LState) int {
+	var flags []int
+	for i := 1; i <= L.GetTop(); i++ {
+		flags = append(flags, checkSchema(L, i).Flag)
+	}
+
+	ids := L.NewTable()
+	for _, entity := range e.world.QueryEntity(flags...) {
+		ids.Append(lua.LNumber(entity.GetID()))
+	}
+	L.Push(ids)
+	return 1
+}
+
+// kito.has(id, component) returns whether the entity exists and has the component
+func (e *Engine) apiHas(L *lua.LState) int {
+	entity := e.world.GetEntityByID(L.CheckInt(1))
+	schema := checkSchema(L, 2)
+	if entity == nil {
+		L.Push(lua.LFalse)
+		return 1
+	}
+	_, ok := entity.GetComponentContainer().Component(schema.Flag)
+	L.Push(lua.LBool(ok))
+	return 1
+}
+
+// kito.get(id, component, field) returns the value of a component field, vectors are
+// returned as arrays
+func (e *Engine) apiGet(L *lua.LState) int {
+	component, field := e.checkField(L)
+	value, ok := field.Get(component)
+	if !ok {
+		L.Push(lua.LNil)
+		return 1
+	}
+	L.Push(toLua(L, value))
+	return 1
+}
+
+// kito.set(id, component, field, value) sets a component field. Invalid values,
+// values out of the field's range and read only fields raise an error
+func (e *Engine) apiSet(L *lua.LState) int {
+	component, field := e.checkField(L)
+	value, err := formatValue(L.CheckAny(4))
+	if err != nil {
+		L.ArgError(4, err.Error())
+		return 0
+	}
+	if err := field.Set(component, value); err != nil {
+		L.RaiseError("%s", err)
+	}
+	return 0
+}
+
+// kito.spawn(type, position) registers a new entity and returns its ID
+func (e *Engine) apiSpawn(L *lua.LState) int {
+	entityType, ok := spawnableEntityTypes[L.CheckString(1)]
+	if !ok {
+		L.ArgError(1, fmt.Sprintf("%s can't be spawned from scripts", L.CheckString(1)))
+		return 0
+	}
+	position, err := toVec3(L.CheckTable(2))
+	if err != nil {
+		L.ArgError(2, err.Error())
+		return 0
+	}
+
+	entity := entityutils.Spawn(entityType, position, mgl64.QuatIdent())
+	e.world.RegisterEntities([]entities.Entity{entity})
+	L.Push(lua.LNumber(entity.GetID()))
+	return 1
+}
+
+// kito.destroy(id) unregisters the entity at the end of the frame
+func (e *Engine) apiDestroy(L *lua.LState) int {
+	event := &events.UnregisterEntityEvent{
+		GlobalCommandFrame: e.world.CommandFrame(),
+		EntityID:           L.CheckInt(1),
+	}
+	e.world.GetEventBroker().Broadcast(event)
+	return 0
+}
+
+// kito.broadcast(type, fields) broadcasts a registered event built from the fields table
+func (e *Engine) apiBroadcast(L *lua.LState) int {
+	eventType := events.EventType(L.CheckString(1))
+	bytes, err := json.Marshal(toGo(L.OptTable(2, L.NewTable())))
+	if err != nil {
+		L.ArgError(2, err.Error())
+		return 0
+	}
+	event, err := events.Deserialize(eventType, bytes)
+	if err != nil {
+		L.RaiseError("%s", err)
+		return 0
+	}
+	e.world.GetEventBroker().Broadcast(event)
+	return 0
+}
+
+// kito.after(frames, hook, id) calls the hook of the calling script with the entity ID
+// once after the given number of command frames and returns the timer ID
+func (e *Engine) apiAfter(L *lua.LState) int {
+	handler := e.timerHandler(L.CheckString(2))
+	id := e.world.TimerManager().After(L.CheckInt(1), handler, L.OptInt(3, 0))
+	L.Push(lua.LNumber(id))
+	return 1
+}
+
+// kito.every(frames, hook, id) calls the hook of the calling script with the entity ID
+// every given number of command frames until cancelled and returns the timer ID
+func (e *Engine) apiEvery(L *lua.LState) int {
+	interval := L.CheckInt(1)
+	if interval < 1 {
+		L.ArgError(1, "interval must be positive")
+		return 0
+	}
+	handler := e.timerHandler(L.CheckString(2))
+	id := e.world.TimerManager().Every(interval, handler, L.OptInt(3, 0))
+	L.Push(lua.LNumber(id))
+	return 1
+}
+
+func (e *Engine) apiCancel(L *lua.LState) int {
+	e.world.TimerManager().Cancel(timer.TimerID(L.CheckInt(1)))
+	return 0
+}
+
+// kito.random behaves like math.random but draws from the engine's seeded generator
+func (e *Engine) apiRandom(L *lua.LState) int {
+	switch L.GetTop() {
+	case 0:
+		L.Push(lua.LNumber(e.rand.Float64()))
+	case 1:
+		n := L.CheckInt(1)
+		if n < 1 {
+			L.ArgError(1, "interval is empty")
+			return 0
+		}
+		L.Push(lua.LNumber(e.rand.Intn(n) + 1))
+	default:
+		low, high := L.CheckInt(1), L.CheckInt(2)
+		if low > high {
+			L.ArgError(2, "interval is empty")
+			return 0
+		}
+		L.Push(lua.LNumber(e.rand.Intn(high-low+1) + low))
+	}
+	return 1
+}
+
+func (e *Engine) apiLog(L *lua.LState) int {
+	var values []string
+	for i := 1; i <= L.GetTop(); i++ {
+		values = append(values, L.ToStringMeta(L.Get(i)).String())
+	}
+	log.Info(strings.Join(values, " "), logger.F("script", e.current))
+	return 0
+}
+
+// timerHandler returns the name of the timer handler that calls the hook of the
+// calling script. Handlers are named rather than bound to the script so that the
+// timers survive serialization and script reloads
+func (e *Engine) timerHandler(hook string) string {
+	name := fmt.Sprintf("script:%s:%s", e.current, hook)
+	if e.timerHandlers[name] {
+		return name
+	}
+
+	scriptName := e.current
+	e.world.TimerManager().RegisterHandler(name, func(t timer.Timer) {
+		if err := e.Call(scriptName, hook, lua.LNumber(t.EntityID)); err != nil {
+			log.Error("timer handler failed", logger.Err(err))
+		}
+	})
+	e.timerHandlers[name] = true
+	return name
+}
+
+func checkSchema(L *lua.LState, n int) *components.ComponentSchema {
+	name := L.CheckString(n)
+	schema, ok := components.SchemaByName(name)
+	if !ok {
+		L.ArgError(n, fmt.Sprintf("unknown component %s", name))
+	}
+	return schema
+}
+
+func (e *Engine) checkField(L *lua.LState) (components.Component, components.FieldSchema) {
+	entityID := L.CheckInt(1)
+	schema := checkSchema(L, 2)
+	fieldName := L.CheckString(3)
+
+	entity := e.world.GetEntityByID(entityID)
+	if entity == nil {
+		L.ArgError(1, fmt.Sprintf("unknown entity %d", entityID))
+	}
+	component, ok := entity.GetComponentContainer().Component(schema.Flag)
+	if !ok {
+		L.ArgError(2, fmt.Sprintf("entity %d does not have a %s", entityID, schema.Name))
+	}
+	field, ok := schema.Field(fieldName)
+	if !ok {
+		L.ArgError(3, fmt.Sprintf("%s does not have a field %s", schema.Name, fieldName))
+	}
+	return component, field
+}
+
+func toLua(L *lua.LState, value any) lua.LValue {
+	switch v := value.(type) {
+	case bool:
+		return lua.LBool(v)
+	case int:
+		return lua.LNumber(v)
+	case int32:
+		return lua.LNumber(v)
+	case int64:
+		return lua.LNumber(v)
+	case float32:
+		return lua.LNumber(v)
+	case float64:
+		return lua.LNumber(v)
+	case string:
+		return lua.LString(v)
+	case mgl64.Vec3:
+		t := L.NewTable()
+		for _, f := range v {
+			t.Append(lua.LNumber(f))
+		}
+		return t
+	case mgl64.Quat:
+		t := L.NewTable()
+		for _, f := range []float64{v.W, v.V[0], v.V[1], v.V[2]} {
+			t.Append(lua.LNumber(f))
+		}
+		return t
+	}
+	return lua.LString(fmt.Sprintf("%v", value))
+}
+
+// formatValue converts a Lua value to the string format that component fields are set from
+func formatValue(value lua.LValue) (string, error) {
+	switch v := value.(type) {
+	case lua.LBool:
+		return strconv.FormatBool(bool(v)), nil
+	case lua.LNumber:
+		return strconv.FormatFloat(float64(v), 'f', -1, 64), nil
+	case lua.LString:
+		return string(v), nil
+	case *lua.LTable:
+		vec, err := toVec3(v)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%g,%g,%g", vec[0], vec[1], vec[2]), nil
+	}
+	return "", fmt.Errorf("unsupported value of type %s", value.Type())
+}
+
+func toVec3(t *lua.LTable) (mgl64.Vec3, error) {
+	var vec mgl64.Vec3
+	if t.Len() != 3 {
+		return vec, fmt.Errorf("expected a vector of 3 numbers")
+	}
+	for i := range vec {
+		n, ok := t.RawGetInt(i + 1).(lua.LNumber)
+		if !ok {
+			return vec, fmt.Errorf("expected a vector of 3 numbers")
+		}
+		vec[i] = float64(n)
+	}
+	return vec, nil
+}
+
+// toGo converts a Lua value to a value that can be marshalled to JSON. Tables with an
+// array part become slices and other tables become maps keyed by strings
+func toGo(value lua.LValue) any {
+	switch v := value.(type) {
+	case lua.LBool:
+		return bool(v)
+	case lua.LNumber:
+		return float64(v)
+	case lua.LString:
+		return string(v)
+	case *lua.LTable:
+		if n := v.MaxN(); n > 0 {
+			var values []any
+			for i := 1; i <= n; i++ {
+				values = append(values, toGo(v.RawGetInt(i)))
+			}
+			return values
+		}
+		values := map[string]any{}
+		v.ForEach(func(key lua.LValue, value lua.LValue) {
+			values[key.String()] = toGo(value)
+		})
+		return values
+	}
+	return nil
+}
@@ -0,0 +1,303 @@
+package scripting
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kkevinchou/kito/kito/entities"
+	"github.com/kkevinchou/kito/kito/managers/eventbroker"
+	"github.com/kkevinchou/kito/kito/managers/timer"
+	"github.com/kkevinchou/kito/lib/logger"
+	lua "github.com/yuin/gopher-lua"
+)
+
+var log = logger.New("scripting")
+
+const (
+	scriptExtension = ".lua"
+
+	// InstructionBudget bounds how many Lua instructions a single hook may run so that a
+	// runaway script can't stall the frame. Hooks that run out are aborted after the same
+	// instruction every time, no matter how loaded the machine is
+	InstructionBudget = 1000000
+
+	// slowCallDuration is how long a hook can take before it's reported. The budget is
+	// what aborts hooks, this only catches hooks that are slow without running many
+	// instructions, e.g. by calling into expensive parts of the kito API
+	slowCallDuration = 50 * time.Millisecond
+)
+
+type World interface {
+	QueryEntity(componentFlags ...int) []entities.Entity
+	GetEntityByID(id int) entities.Entity
+	RegisterEntities(es []entities.Entity)
+	CommandFrame() int
+	GetEventBroker() eventbroker.EventBroker
+	TimerManager() *timer.Manager
+}
+
+type script struct {
+	name    string
+	path    string
+	modTime time.Time
+
+	// module is the table returned by the script, its functions are the script's hooks
+	module *lua.LTable
+}
+
+// Engine runs gameplay scripts from a directory in a single sandboxed Lua state.
+// Each script is a .lua file that returns a table of hook functions, and is
+// referred to by its file name without the extension. Scripts only see the
+// kito API table and a subset of the standard library, and get a seeded random
+// number generator so that they run deterministically on the server.
+//
+// An Engine is not safe for concurrent use
+type Engine struct {
+	world     World
+	directory string
+	state     *lua.LState
+	scripts   map[string]*script
+	rand      *rand.Rand
+
+	// current is the script whose hook or top level chunk is running
+	current string
+
+	// timerHandlers tracks the script timer handlers registered with the timer manager
+	timerHandlers map[string]bool
+}
+
+func NewEngine(world World, directory string, seed int64) *Engine {
+	e := &Engine{
+		world:         world,
+		directory:     directory,
+		scripts:       map[string]*script{},
+		rand:          rand.New(rand.NewSource(seed)),
+		timerHandlers: map[string]bool{},
+	}
+	e.state = newSandbox()
+	e.registerAPI()
+	return e
+}
+
+func newSandbox() *lua.LState {
+	L := lua.NewState(lua.Options{SkipOpenLibs: true})
+	for _, lib := range []struct {
+		name string
+		open lua.LGFunction
+	}{
+		{lua.BaseLibName, lua.OpenBase},
+		{lua.TabLibName, lua.OpenTable},
+		{lua.StringLibName, lua.OpenString},
+		{lua.MathLibName, lua.OpenMath},
+	} {
+		L.Push(L.NewFunction(lib.open))
+		L.Push(lua.LString(lib.name))
+		L.Call(1, 0)
+	}
+
+	// scripts can only be loaded by the engine
+	for _, name := range []string{"dofile", "loadfile", "load", "loadstring", "require", "module", "collectgarbage", "print"} {
+		L.SetGlobal(name, lua.LNil)
+	}
+	return L
+}
+
+// LoadAll loads every script in the engine's directory. Scripts that fail to load
+// are reported in the returned error and the rest are still loaded
+func (e *Engine) LoadAll() error {
+	paths, err := filepath.Glob(filepath.Join(e.directory, "*"+scriptExtension))
+	if err != nil {
+		return err
+	}
+	sort.Strings(paths)
+
+	var failures []string
+	for _, path := range paths {
+		if err := e.loadFile(path); err != nil {
+			failures = append(failures, err.Error())
+		}
+	}
+	if len(failures) > 0 {
+		return fmt.Errorf("failed to load scripts: %s", strings.Join(failures, "; "))
+	}
+	return nil
+}
+
+// Reload loads any script that was added or modified since it was last loaded and
+// returns the names of the reloaded scripts. A script that fails to reload keeps
+// running its previous version
+func (e *Engine) Reload() []string {
+	paths, err := filepath.Glob(filepath.Join(e.directory, "*"+scriptExtension))
+	if err != nil {
+		log.Error("failed to list scripts", logger.Err(err))
+		return nil
+	}
+	sort.Strings(paths)
+
+	var reloaded []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		if s, ok := e.scripts[scriptName(path)]; ok && !info.ModTime().After(s.modTime) {
+			continue
+		}
+		if err := e.loadFile(path); err != nil {
+			log.Error("failed to reload script", logger.Err(err))
+			continue
+		}
+		reloaded = append(reloaded, scriptName(path))
+	}
+	return reloaded
+}
+
+func scriptName(path string) string {
+	return strings.TrimSuffix(filepath.Base(path), scriptExtension)
+}
+
+func (e *Engine) loadFile(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	source, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	name := scriptName(path)
+	if err := e.Load(name, string(source)); err != nil {
+		return err
+	}
+	e.scripts[name].path = path
+	e.scripts[name].modTime = info.ModTime()
+	return nil
+}
+
+// Load compiles and runs the source of a script, replacing any previously loaded
+// script with the same name
+func (e *Engine) Load(name string, source string) error {
+	fn, err := e.state.LoadString(source)
+	if err != nil {
+		return fmt.Errorf("failed to compile script %s: %w", name, err)
+	}
+
+	results, err := e.call(name, fn, 1)
+	if err != nil {
+		return fmt.Errorf("failed to run script %s: %w", name, err)
+	}
+	module, ok := results[0].(*lua.LTable)
+	if !ok {
+		return fmt.Errorf("script %s must return a table of hooks but returned %s", name, results[0].Type())
+	}
+
+	e.scripts[name] = &script{name: name, module: module}
+	return nil
+}
+
+func (e *Engine) Loaded(name string) bool {
+	_, ok := e.scripts[name]
+	return ok
+}
+
+// HasHook returns whether the named script defines the hook
+func (e *Engine) HasHook(name string, hook string) bool {
+	s, ok := e.scripts[name]
+	if !ok {
+		return false
+	}
+	_, ok = s.module.RawGetString(hook).(*lua.LFunction)
+	return ok
+}
+
+// Call runs a hook of the named script. Scripts aren't required to define every
+// hook so calling a missing hook does nothing
+func (e *Engine) Call(name string, hook string, args ...lua.LValue) error {
+	s, ok := e.scripts[name]
+	if !ok {
+		return fmt.Errorf("unknown script %s", name)
+	}
+	fn, ok := s.module.RawGetString(hook).(*lua.LFunction)
+	if !ok {
+		return nil
+	}
+
+	if _, err := e.call(name, fn, 0, args...); err != nil {
+		return fmt.Errorf("%s.%s: %w", name, hook, err)
+	}
+	return nil
+}
+
+func (e *Engine) call(name string, fn *lua.LFunction, nret int, args ...lua.LValue) ([]lua.LValue, error) {
+	previous := e.current
+	e.current = name
+	defer func() { e.current = previous }()
+
+	e.state.SetContext(newInstructionBudget(InstructionBudget))
+	defer e.state.RemoveContext()
+
+	start := time.Now()
+	defer func() {
+		if elapsed := time.Since(start); elapsed > slowCallDuration {
+			log.Error("script call was slow", logger.F("script", name), logger.F("elapsed", elapsed))
+		}
+	}()
+
+	top := e.state.GetTop()
+	if err := e.state.CallByParam(lua.P{Fn: fn, NRet: nret, Protect: true}, args...); err != nil {
+		e.state.SetTop(top)
+		return nil, err
+	}
+
+	var results []lua.LValue
+	for i := 1; i <= nret; i++ {
+		results = append(results, e.state.Get(top+i))
+	}
+	e.state.SetTop(top)
+	return results, nil
+}
+
+// instructionBudget is a context that's done once the Lua VM has run its budget of
+// instructions. The VM checks whether its context is done before every instruction it
+// runs, so counting the checks counts the instructions
+type instructionBudget struct {
+	context.Context
+	instructions int
+	remaining    int
+	done         chan struct{}
+}
+
+func newInstructionBudget(instructions int) *instructionBudget {
+	return &instructionBudget{
+		Context:      context.Background(),
+		instructions: instructions,
+		remaining:    instructions,
+		done:         make(chan struct{}),
+	}
+}
+
+func (b *instructionBudget) Done() <-chan struct{} {
+	b.remaining--
+	if b.remaining == 0 {
+		close(b.done)
+	}
+	return b.done
+}
+
+func (b *instructionBudget) Err() error {
+	if b.remaining > 0 {
+		return nil
+	}
+	return fmt.Errorf("exceeded the budget of %d instructions", b.instructions)
+}
+
+func (e *Engine) Close() {
+	e.state.Close()
+}
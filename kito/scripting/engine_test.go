@@ -0,0 +1,338 @@
+package scripting_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kkevinchou/kito/kito/components"
+	"github.com/kkevinchou/kito/kito/entities"
+	"github.com/kkevinchou/kito/kito/entitymanager"
+	"github.com/kkevinchou/kito/kito/events"
+	"github.com/kkevinchou/kito/kito/managers/eventbroker"
+	"github.com/kkevinchou/kito/kito/managers/timer"
+	"github.com/kkevinchou/kito/kito/scripting"
+	"github.com/kkevinchou/kito/kito/types"
+	lua "github.com/yuin/gopher-lua"
+)
+
+type testWorld struct {
+	entityManager *entitymanager.EntityManager
+	eventBroker   eventbroker.EventBroker
+	timerManager  *timer.Manager
+	nextID        int
+}
+
+func newTestWorld() *testWorld {
+	return &testWorld{
+		entityManager: entitymanager.NewEntityManager(),
+		eventBroker:   eventbroker.NewEventBroker(false),
+		timerManager:  timer.NewManager(),
+		nextID:        1,
+	}
+}
+
+func (w *testWorld) QueryEntity(componentFlags ...int) []entities.Entity {
+	return w.entityManager.Query(componentFlags...)
+}
+
+func (w *testWorld) GetEntityByID(id int) entities.Entity {
+	return w.entityManager.GetEntityByID(id)
+}
+
+func (w *testWorld) RegisterEntities(es []entities.Entity) {
+	for _, e := range es {
+		e.SetID(w.nextID)
+		w.nextID++
+		w.entityManager.RegisterEntity(e)
+	}
+}
+
+func (w *testWorld) CommandFrame() int {
+	return w.timerManager.CommandFrame()
+}
+
+func (w *testWorld) GetEventBroker() eventbroker.EventBroker {
+	return w.eventBroker
+}
+
+func (w *testWorld) TimerManager() *timer.Manager {
+	return w.timerManager
+}
+
+func (w *testWorld) spawnWithHealth(value float64) entities.Entity {
+	e := entities.NewEntity("test", types.EntityTypeEnemy, components.NewComponentContainer(
+		&components.TransformComponent{},
+		components.NewHealthComponent(value),
+	))
+	w.RegisterEntities([]entities.Entity{e})
+	return e
+}
+
+func TestHooks(t *testing.T) {
+	world := newTestWorld()
+	target := world.spawnWithHealth(100)
+
+	engine := scripting.NewEngine(world, "", 0)
+	defer engine.Close()
+
+	err := engine.Load("damage", `
+		local damage = {}
+		function damage.hit(target)
+			local value = kito.get(target, "HealthComponent", "Data.Value")
+			kito.set(target, "HealthComponent", "Data.Value", value - 30)
+		end
+		return damage
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := engine.Call("damage", "hit", lua.LNumber(target.GetID())); err != nil {
+		t.Fatal(err)
+	}
+	if value := target.GetComponentContainer().HealthComponent.Data.Value; value != 70 {
+		t.Fatalf("expected health to be 70 but was %f", value)
+	}
+
+	// missing hooks are ignored
+	if err := engine.Call("damage", "missing"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFieldValidation(t *testing.T) {
+	world := newTestWorld()
+	target := world.spawnWithHealth(100)
+
+	engine := scripting.NewEngine(world, "", 0)
+	defer engine.Close()
+
+	err := engine.Load("invalid", `
+		local invalid = {}
+		function invalid.overheal(target)
+			kito.set(target, "HealthComponent", "Data.Value", 5000)
+		end
+		function invalid.unknown(target)
+			kito.set(target, "HealthComponent", "Missing", 1)
+		end
+		return invalid
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, hook := range []string{"overheal", "unknown"} {
+		if err := engine.Call("invalid", hook, lua.LNumber(target.GetID())); err == nil {
+			t.Errorf("expected %s to fail", hook)
+		}
+	}
+	if value := target.GetComponentContainer().HealthComponent.Data.Value; value != 100 {
+		t.Fatalf("expected health to be unchanged but was %f", value)
+	}
+}
+
+func TestSandbox(t *testing.T) {
+	engine := scripting.NewEngine(newTestWorld(), "", 0)
+	defer engine.Close()
+
+	for _, source := range []string{
+		`return os.exit(1)`,
+		`return io.open("file")`,
+		`return dofile("file.lua")`,
+		`return require("module")`,
+	} {
+		if err := engine.Load("sandbox", source); err == nil {
+			t.Errorf("expected %s to be sandboxed", source)
+		}
+	}
+
+	if err := engine.Load("loop", `while true do end`); err == nil {
+		t.Error("expected a runaway script to time out")
+	}
+}
+
+func TestInstructionBudget(t *testing.T) {
+	source := `
+		local spin = {}
+		local count = 0
+		function spin.loop(n)
+			for i = 1, n do end
+		end
+		function spin.forever()
+			while true do
+				count = count + 1
+			end
+		end
+		function spin.report()
+			error("count " .. count)
+		end
+		return spin
+	`
+
+	var reports []string
+	for i := 0; i < 3; i++ {
+		engine := scripting.NewEngine(newTestWorld(), "", 0)
+		if err := engine.Load("spin", source); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := engine.Call("spin", "loop", lua.LNumber(scripting.InstructionBudget/2)); err != nil {
+			t.Fatalf("expected a loop within the budget to finish but got %s", err)
+		}
+		if err := engine.Call("spin", "loop", lua.LNumber(scripting.InstructionBudget*2)); err == nil {
+			t.Fatal("expected a loop over the budget to be aborted")
+		}
+		if err := engine.Call("spin", "forever"); err == nil {
+			t.Fatal("expected a runaway hook to be aborted")
+		}
+
+		reports = append(reports, engine.Call("spin", "report").Error())
+		engine.Close()
+	}
+
+	// the runaway hook is aborted after the same instruction every time
+	for _, report := range reports[1:] {
+		if report != reports[0] {
+			t.Fatalf("expected the runaway hook to be aborted at the same point every run but got %v", reports)
+		}
+	}
+}
+
+func TestDeterministicRandom(t *testing.T) {
+	source := `
+		local rolls = {}
+		function rolls.roll(id)
+			local value = math.random(1, 1000)
+			kito.set(id, "HealthComponent", "Data.Value", value)
+		end
+		return rolls
+	`
+
+	var results []float64
+	for i := 0; i < 2; i++ {
+		world := newTestWorld()
+		target := world.spawnWithHealth(0)
+		engine := scripting.NewEngine(world, "", 42)
+		if err := engine.Load("rolls", source); err != nil {
+			t.Fatal(err)
+		}
+		if err := engine.Call("rolls", "roll", lua.LNumber(target.GetID())); err != nil {
+			t.Fatal(err)
+		}
+		results = append(results, target.GetComponentContainer().HealthComponent.Data.Value)
+		engine.Close()
+	}
+
+	if results[0] != results[1] {
+		t.Fatalf("expected engines with the same seed to roll the same values but got %v", results)
+	}
+}
+
+func TestEventsAndTimers(t *testing.T) {
+	world := newTestWorld()
+	target := world.spawnWithHealth(100)
+
+	var unregistered []int
+	eventbroker.Subscribe(world.eventBroker, 0, func(e *events.UnregisterEntityEvent) {
+		unregistered = append(unregistered, e.EntityID)
+	})
+
+	engine := scripting.NewEngine(world, "", 0)
+	defer engine.Close()
+
+	err := engine.Load("fuse", `
+		local fuse = {}
+		function fuse.light(id)
+			kito.after(3, "explode", id)
+		end
+		function fuse.explode(id)
+			kito.broadcast("UNREGISTER", { entity_id = id })
+		end
+		return fuse
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := engine.Call("fuse", "light", lua.LNumber(target.GetID())); err != nil {
+		t.Fatal(err)
+	}
+	for cf := 1; cf <= 3; cf++ {
+		if len(unregistered) > 0 {
+			t.Fatalf("expected the fuse to not have exploded by command frame %d", cf)
+		}
+		world.timerManager.Update(cf)
+	}
+
+	if len(unregistered) != 1 || unregistered[0] != target.GetID() {
+		t.Fatalf("expected entity %d to be unregistered but got %v", target.GetID(), unregistered)
+	}
+}
+
+func TestReload(t *testing.T) {
+	directory := t.TempDir()
+	path := filepath.Join(directory, "heal.lua")
+	write := func(amount string, modTime time.Time) {
+		source := `
+			local heal = {}
+			function heal.apply(id)
+				kito.set(id, "HealthComponent", "Data.Value", ` + amount + `)
+			end
+			return heal
+		`
+		if err := os.WriteFile(path, []byte(source), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	world := newTestWorld()
+	target := world.spawnWithHealth(0)
+	engine := scripting.NewEngine(world, directory, 0)
+	defer engine.Close()
+
+	start := time.Now()
+	write("10", start)
+	if err := engine.LoadAll(); err != nil {
+		t.Fatal(err)
+	}
+	if reloaded := engine.Reload(); len(reloaded) != 0 {
+		t.Fatalf("expected unmodified scripts to not be reloaded but got %v", reloaded)
+	}
+
+	write("20", start.Add(time.Second))
+	if reloaded := engine.Reload(); len(reloaded) != 1 || reloaded[0] != "heal" {
+		t.Fatalf("expected heal to be reloaded but got %v", reloaded)
+	}
+	if err := engine.Call("heal", "apply", lua.LNumber(target.GetID())); err != nil {
+		t.Fatal(err)
+	}
+	if value := target.GetComponentContainer().HealthComponent.Data.Value; value != 20 {
+		t.Fatalf("expected the reloaded script to set health to 20 but got %f", value)
+	}
+
+	// a script that fails to compile keeps its previous version
+	if err := os.WriteFile(path, []byte("not lua"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	os.Chtimes(path, start.Add(2*time.Second), start.Add(2*time.Second))
+	engine.Reload()
+	if !engine.HasHook("heal", "apply") {
+		t.Fatal("expected the previous version of heal to still be loaded")
+	}
+}
+
+func TestAssetScripts(t *testing.T) {
+	engine := scripting.NewEngine(newTestWorld(), "../../_assets/scripts", 0)
+	defer engine.Close()
+	if err := engine.LoadAll(); err != nil {
+		t.Fatal(err)
+	}
+	if !engine.HasHook("fireball", "on_contact") {
+		t.Fatal("expected the fireball script to handle contacts")
+	}
+}
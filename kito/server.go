@@ -3,11 +3,15 @@ package kito
 import (
 	"fmt"
 	"math/rand"
+	"path/filepath"
 
 	"github.com/go-gl/mathgl/mgl64"
+	"github.com/kkevinchou/kito/kito/components"
+	"github.com/kkevinchou/kito/kito/config"
 	"github.com/kkevinchou/kito/kito/directory"
 	"github.com/kkevinchou/kito/kito/entities"
 	"github.com/kkevinchou/kito/kito/managers/player"
+	"github.com/kkevinchou/kito/kito/scheduler"
 	"github.com/kkevinchou/kito/kito/settings"
 	"github.com/kkevinchou/kito/kito/systems/ability"
 	"github.com/kkevinchou/kito/kito/systems/ai"
@@ -16,6 +20,8 @@ import (
 	"github.com/kkevinchou/kito/kito/systems/charactercontroller"
 	"github.com/kkevinchou/kito/kito/systems/collision"
 	"github.com/kkevinchou/kito/kito/systems/combat"
+	"github.com/kkevinchou/kito/kito/systems/hotreload"
+	"github.com/kkevinchou/kito/kito/systems/kinematic"
 	"github.com/kkevinchou/kito/kito/systems/loot"
 	"github.com/kkevinchou/kito/kito/systems/networkdispatch"
 	"github.com/kkevinchou/kito/kito/systems/networkupdate"
@@ -24,16 +30,17 @@ import (
 	"github.com/kkevinchou/kito/kito/systems/playerregistration"
 	"github.com/kkevinchou/kito/kito/systems/preframe"
 	"github.com/kkevinchou/kito/kito/systems/rpcreceiver"
+	"github.com/kkevinchou/kito/kito/systems/script"
 	"github.com/kkevinchou/kito/lib/assets"
 )
 
-func NewServerGame(assetsDirectory string) *Game {
+func NewServerGame(assetsDirectory string, configLayers *config.Layers) *Game {
 	initSeed()
 	settings.CurrentGameMode = settings.GameModeServer
 
 	g := NewBaseGame()
 
-	serverSystemSetup(g, assetsDirectory)
+	serverSystemSetup(g, assetsDirectory, configLayers)
 	initialEntities := serverEntitySetup(g)
 	g.RegisterEntities(initialEntities)
 
@@ -53,16 +60,18 @@ func serverEntitySetup(g *Game) []entities.Entity {
 	}
 
 	lootbox := entities.NewLootbox()
+	platform := entities.NewPlatform()
 
 	entities := []entities.Entity{
 		scene,
 		lootbox,
+		platform,
 	}
 	entities = append(entities, enemies...)
 	return entities
 }
 
-func serverSystemSetup(g *Game, assetsDirectory string) {
+func serverSystemSetup(g *Game, assetsDirectory string, configLayers *config.Layers) {
 	d := directory.GetDirectory()
 
 	playerManager := player.NewPlayerManager(g)
@@ -81,6 +90,7 @@ func serverSystemSetup(g *Game, assetsDirectory string) {
 	preframeSystem := preframe.NewPreFrameSystem(g)
 
 	// systems that can manipulate the transform of an entity
+	kinematicSystem := kinematic.NewKinematicSystem(g)
 	characterControllerSystem := charactercontroller.NewCharacterControllerSystem(g)
 	physicsSystem := physics.NewPhysicsSystem(g)
 	collisionSystem := collision.NewCollisionSystem(g)
@@ -88,25 +98,49 @@ func serverSystemSetup(g *Game, assetsDirectory string) {
 	abilitySystem := ability.NewAbilitySystem(g)
 	combatSystem := combat.NewCombatSystem(g)
 	lootSystem := loot.NewLootSystem(g)
+	scriptSystem := script.NewScriptSystem(g, filepath.Join(assetsDirectory, "scripts"))
 	animationSystem := animation.NewAnimationSystem(g)
 	networkUpdateSystem := networkupdate.NewNetworkUpdateSystem(g)
 	bookKeepingSystem := bookkeeping.NewBookKeepingSystem(g)
-
-	g.systems = append(g.systems, []System{
-		playerRegistrationSystem,
-		networkDispatchSystem,
-		playerInputSystem,
-		rpcReceiverSystem,
-		aiSystem,
-		preframeSystem,
-		characterControllerSystem,
-		physicsSystem,
-		collisionSystem,
-		abilitySystem,
-		combatSystem,
-		lootSystem,
-		animationSystem,
-		bookKeepingSystem,
-		networkUpdateSystem,
-	}...)
+	hotReloadSystem := hotreload.NewHotReloadSystem(g, assetsDirectory, "", configLayers)
+
+	g.registerSystem(hotReloadSystem, scheduler.PhaseInput, scheduler.WhilePaused())
+	g.registerSystem(playerRegistrationSystem, scheduler.PhaseInput, scheduler.WhilePaused())
+	g.registerSystem(networkDispatchSystem, scheduler.PhaseInput, scheduler.After(playerRegistrationSystem.Name()), scheduler.WhilePaused())
+	g.registerSystem(playerInputSystem, scheduler.PhaseInput, scheduler.After(networkDispatchSystem.Name()))
+	g.registerSystem(rpcReceiverSystem, scheduler.PhaseInput, scheduler.After(networkDispatchSystem.Name()), scheduler.WhilePaused())
+
+	g.registerSystem(aiSystem, scheduler.PhaseSimulation,
+		scheduler.Before(characterControllerSystem.Name()),
+		scheduler.Writes(components.ComponentFlagAI, components.ComponentFlagTransform, components.ComponentFlagMovement),
+	)
+	g.registerSystem(kinematicSystem, scheduler.PhaseSimulation, scheduler.Before(preframeSystem.Name()))
+	g.registerSystem(preframeSystem, scheduler.PhaseSimulation, scheduler.Before(characterControllerSystem.Name()))
+	g.registerSystem(characterControllerSystem, scheduler.PhaseSimulation)
+	g.registerSystem(physicsSystem, scheduler.PhaseSimulation, scheduler.After(characterControllerSystem.Name()))
+	g.registerSystem(collisionSystem, scheduler.PhaseSimulation, scheduler.After(physicsSystem.Name()))
+	// abilities spawn entities and touch the singleton's spawn bookkeeping, so they're never
+	// run concurrently with other systems
+	g.registerSystem(abilitySystem, scheduler.PhaseSimulation, scheduler.After(collisionSystem.Name()))
+	g.registerSystem(combatSystem, scheduler.PhaseSimulation,
+		scheduler.After(collisionSystem.Name()),
+		scheduler.Reads(components.ComponentFlagCollider),
+		scheduler.Writes(components.ComponentFlagHealth),
+	)
+	// loot drops are spawned as new entities, so loot isn't run concurrently either
+	g.registerSystem(lootSystem, scheduler.PhaseSimulation, scheduler.After(collisionSystem.Name()))
+	// scripts can touch any component so they're never run concurrently with other systems
+	g.registerSystem(scriptSystem, scheduler.PhaseSimulation, scheduler.After(collisionSystem.Name()), scheduler.Before(combatSystem.Name()))
+
+	g.registerSystem(animationSystem, scheduler.PhasePostSimulation,
+		scheduler.Reads(components.ComponentFlagMovement, components.ComponentFlagThirdPersonController, components.ComponentFlagNotepad, components.ComponentFlagAI),
+		scheduler.Writes(components.ComponentFlagAnimation),
+	)
+	g.registerSystem(bookKeepingSystem, scheduler.PhasePostSimulation, scheduler.After(animationSystem.Name()))
+
+	g.registerSystem(networkUpdateSystem, scheduler.PhaseNetwork)
+
+	if err := g.scheduler.Build(); err != nil {
+		panic(err)
+	}
 }
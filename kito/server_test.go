@@ -0,0 +1,39 @@
+package kito
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/kkevinchou/kito/kito/config"
+	"github.com/kkevinchou/kito/kito/settings"
+)
+
+// TestServerStages checks that the server's systems that spawn entities never share a
+// stage, registering entities and the spawn bookkeeping aren't safe to do concurrently
+func TestServerStages(t *testing.T) {
+	settings.Port = 0
+	settings.CurrentGameMode = settings.GameModeServer
+
+	directory := t.TempDir()
+	g := NewBaseGame()
+	serverSystemSetup(g, directory, &config.Layers{Path: filepath.Join(directory, "config.json")})
+
+	systems := g.Scheduler().Systems()
+	stageSizes := map[int]int{}
+	for _, info := range systems {
+		stageSizes[info.Stage]++
+	}
+
+	spawners := map[string]bool{"AbilitySystem": true, "LootSystem": true, "ScriptSystem": true}
+	for _, info := range systems {
+		if spawners[info.Name] {
+			delete(spawners, info.Name)
+			if stageSizes[info.Stage] != 1 {
+				t.Fatalf("expected %s to run in a stage of its own but it shares stage %d: %v", info.Name, info.Stage, systems)
+			}
+		}
+	}
+	if len(spawners) != 0 {
+		t.Fatalf("expected the server to register %v", spawners)
+	}
+}
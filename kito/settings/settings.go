@@ -14,40 +14,64 @@ var (
 	CameraStartView              = mgl64.Vec2{0, 0}
 	ListenAddress       string   = "localhost"
 
-	AccelerationDueToGravity = mgl64.Vec3{0, -gravity, 0}
+	AccelerationDueToGravity = mgl64.Vec3{0, -Gravity, 0}
 
 	// dynamic settings loaded from config
 	Host       string = "localhost"
 	Port       int    = 8080
-	Width      int    = 0
-	Height     int    = 0
+	Width      int    = 1280
+	Height     int    = 720
 	Fullscreen bool   = false
 
 	// dynamic settings configurable from the console
-	DebugRenderCollisionVolume  = false
-	DebugRenderSpatialPartition = false
+	DebugRenderCollisionVolume = false
+	DebugRenderBroadphase      = false
+	DebugRenderPhysics         = false
+
+	// ParallelSystems lets the scheduler run systems with non-conflicting component access
+	// concurrently. Disabled by default since concurrent systems are not deterministic
+	ParallelSystems = false
+
+	// TimeScale scales how quickly simulation time advances relative to wall time
+	TimeScale float64 = 1
+
+	// MaxCommandFramesPerTick caps how many command frames are run in one pass of the game
+	// loop. Any time beyond the cap is dropped rather than caught up on to avoid a spiral
+	// of death where catching up takes longer than the time being caught up on
+	MaxCommandFramesPerTick = 5
 
 	ShowImguiDemoWindow   = false
 	RuntimeMaxTextureSize int
 )
 
+// settings that can't change without rebuilding, either because they're part of the
+// network protocol or because they're baked into vertex layouts and shaders
 const (
-	LoggingLevel          = 1
-	Seed           int64  = 1234567
-	ConnectionType string = "tcp"
-
 	GameModeUndefined GameMode = "UNDEFINED"
 	GameModeClient    GameMode = "CLIENT"
 	GameModeServer    GameMode = "SERVER"
 
 	ServerID      int = 69
 	ClientIDStart int = 70000
-	EntityIDStart int = 80000
+
+	// Animation
+	AnimationMaxJointWeights = 4
+)
+
+// startup settings, loaded from the config, environment and command line before the game
+// is created. See the config package for how they're layered
+var (
+	Seed           int64  = 1234567
+	ConnectionType string = "tcp"
 
 	PProfEnabled    bool = false
 	PProfClientPort int  = 6060
 	PProfServerPort int  = 6061
 
+	// the server serves metrics in the Prometheus text format on localhost
+	MetricsEnabled bool = true
+	MetricsPort    int  = 6062
+
 	// MSPerCommandFrame is the size of the simulation step for reading input,
 	// physics, etc.
 	MSPerCommandFrame int = 16
@@ -78,17 +102,30 @@ const (
 	// The number of command frames on the server before a server update is sent to clients
 	CommandFramesPerServerUpdate = 5
 
-	// Animation
-	AnimationMaxJointWeights = 4
-
 	// Physics
-	gravity float64 = 250
-	// gravity float64 = 1
-
-	SpatialPartitionNumPartitions int = 10
-	SpatialPartitionDimensionSize int = 200
-
-	// Debugging constants
+	Gravity float64 = 250
+	// Gravity float64 = 1
+
+	// Characters can't walk up slopes steeper than CharacterMaxSlopeAngle, in degrees. They
+	// step onto ledges up to CharacterStepHeight high and stick to the ground within
+	// CharacterGroundSnapDistance below them when walking down slopes and off of ledges
+	CharacterMaxSlopeAngle      float64 = 45
+	CharacterStepHeight         float64 = 5
+	CharacterGroundSnapDistance float64 = 5
+
+	// BroadphaseMargin is how far colliders can move before the broadphase has to update
+	// its tree
+	BroadphaseMargin float64 = 5
+
+	// Rigid bodies. RigidBodyIterations is how many times contacts between rigid bodies
+	// are solved each command frame. Speeds are in units per second
+	RigidBodyIterations                   = 10
+	RigidBodyRestitutionThreshold float64 = 10
+	RigidBodySleepSpeed           float64 = 5
+	RigidBodySleepAngularSpeed    float64 = 0.5
+	RigidBodyTimeToSleep                  = 500 * time.Millisecond
+
+	// Debugging settings
 	LatencyInjection = 0 * time.Millisecond
 
 	// Mostly for debug rendering
@@ -15,9 +15,22 @@ type Singleton struct {
 	CameraID    int
 	StateBuffer *statebuffer.StateBuffer
 
+	// PredictedSpawns tracks the entities the client spawned ahead of the server, keyed
+	// by spawn key. OutgoingSpawnKey is sent with the next input
+	PredictedSpawns  map[int]*PredictedSpawn
+	OutgoingSpawnKey int
+	lastSpawnKey     int
+
+	// ServerCommandFrameOffset is how far ahead the server's command frame is from the
+	// client's command frame whose input it's simulating. Kinematic bodies are moved to
+	// CommandFrame + ServerCommandFrameOffset so that they're where the server will
+	// have them when it simulates the client's input
+	ServerCommandFrameOffset int
+
 	// server fields
-	InputBuffer    *inputbuffer.InputBuffer
-	PlayerCommands map[int]*playercommand.PlayerCommandList
+	InputBuffer     *inputbuffer.InputBuffer
+	PlayerCommands  map[int]*playercommand.PlayerCommandList
+	PlayerSpawnKeys map[int]int
 
 	// Common
 	PlayerInput  map[int]input.Input
@@ -26,9 +39,32 @@ type Singleton struct {
 
 func NewSingleton() *Singleton {
 	return &Singleton{
-		PlayerInput:    map[int]input.Input{},
-		PlayerCommands: map[int]*playercommand.PlayerCommandList{},
-		StateBuffer:    statebuffer.NewStateBuffer(settings.MaxStateBufferCommandFrames),
-		InputBuffer:    inputbuffer.NewInputBuffer(settings.MaxInputBufferCommandFrames),
+		PlayerInput:     map[int]input.Input{},
+		PlayerCommands:  map[int]*playercommand.PlayerCommandList{},
+		PlayerSpawnKeys: map[int]int{},
+		PredictedSpawns: map[int]*PredictedSpawn{},
+		StateBuffer:     statebuffer.NewStateBuffer(settings.MaxStateBufferCommandFrames),
+		InputBuffer:     inputbuffer.NewInputBuffer(settings.MaxInputBufferCommandFrames),
 	}
 }
+
+// PredictedSpawn is an entity the client spawned locally in anticipation of the
+// server spawning it in response to the same input
+type PredictedSpawn struct {
+	// EntityID is the client local ID of the predicted entity
+	EntityID     int
+	CommandFrame int
+
+	// AuthoritativeEntityID is the server's entity once the server has confirmed the
+	// spawn, and 0 until then
+	AuthoritativeEntityID int
+}
+
+// PredictSpawn records a predicted spawn for the current command frame and returns the
+// spawn key that's sent to the server with this command frame's input
+func (s *Singleton) PredictSpawn(entityID int) int {
+	s.lastSpawnKey++
+	s.PredictedSpawns[s.lastSpawnKey] = &PredictedSpawn{EntityID: entityID, CommandFrame: s.CommandFrame}
+	s.OutgoingSpawnKey = s.lastSpawnKey
+	return s.lastSpawnKey
+}
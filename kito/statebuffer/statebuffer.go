@@ -1,13 +1,14 @@
 package statebuffer
 
 import (
-	"fmt"
-
 	"github.com/kkevinchou/kito/kito/events"
 	"github.com/kkevinchou/kito/kito/knetwork"
 	"github.com/kkevinchou/kito/lib/libutils"
+	"github.com/kkevinchou/kito/lib/logger"
 )
 
+var log = logger.New("statebuffer")
+
 type BufferedState struct {
 	InterpolatedEntities map[int]knetwork.EntitySnapshot
 	Events               []knetwork.Event
@@ -76,12 +77,16 @@ func (s *StateBuffer) generateIntermediateStateUpdates(start IncomingEntityUpdat
 	// TODO(kevin) doing deserialization here is probably omegaslow
 
 	unregisteredEntities := map[int]any{}
-	for _, event := range end.gameStateUpdateMessage.Events {
-		if event.Type == events.EventTypeUnregisterEntity {
-			var e events.UnregisterEntityEvent
-			knetwork.Deserialize(event.Bytes, &e)
-			unregisteredEntities[e.EntityID] = true
+	for _, networkEvent := range end.gameStateUpdateMessage.Events {
+		if networkEvent.Type != events.EventTypeUnregisterEntity {
+			continue
+		}
+		event, err := knetwork.DeserializeEvent(networkEvent)
+		if err != nil {
+			log.Error("failed to deserialize event", logger.Err(err))
+			continue
 		}
+		unregisteredEntities[event.(*events.UnregisterEntityEvent).EntityID] = true
 	}
 
 	// if _, ok := start.gameStateUpdateMessage.Entities[80007]; ok {
@@ -95,7 +100,7 @@ func (s *StateBuffer) generateIntermediateStateUpdates(start IncomingEntityUpdat
 			if _, ok := end.gameStateUpdateMessage.Entities[id]; !ok {
 				// an entity may be deleted in between two game state updates.
 				if _, ok := unregisteredEntities[id]; !ok {
-					fmt.Printf("warning, entity from start update (%d) did not exist in the next one and a deletion event for it was not found\n", id)
+					log.Debug("entity from the start update is missing from the next one without a deletion event", logger.EntityID(id))
 				}
 
 				// drop the entity at the last cf
@@ -8,12 +8,21 @@ import (
 	"github.com/kkevinchou/kito/kito/components"
 	"github.com/kkevinchou/kito/kito/directory"
 	"github.com/kkevinchou/kito/kito/entities"
+	"github.com/kkevinchou/kito/kito/managers/timer"
+	"github.com/kkevinchou/kito/kito/settings"
 	"github.com/kkevinchou/kito/kito/singleton"
 	"github.com/kkevinchou/kito/kito/systems/base"
 	"github.com/kkevinchou/kito/kito/types"
 	"github.com/kkevinchou/kito/kito/utils"
 	"github.com/kkevinchou/kito/kito/utils/entityutils"
 	"github.com/kkevinchou/kito/lib/input"
+	"github.com/kkevinchou/kito/lib/logger"
+)
+
+var log = logger.New("ability")
+
+const (
+	castCooldown = 500 * time.Millisecond
 )
 
 type World interface {
@@ -21,20 +30,17 @@ type World interface {
 	GetSingleton() *singleton.Singleton
 	GetEntityByID(int) entities.Entity
 	RegisterEntities([]entities.Entity)
+	TimerManager() *timer.Manager
 }
 
 type AbilitySystem struct {
 	*base.BaseSystem
 	world World
-
-	// probably put this in a component
-	cooldowns map[string]int64
 }
 
 func NewAbilitySystem(world World) *AbilitySystem {
 	return &AbilitySystem{
-		world:     world,
-		cooldowns: map[string]int64{},
+		world: world,
 	}
 }
 
@@ -46,39 +52,52 @@ func (s *AbilitySystem) Update(delta time.Duration) {
 		playerInput := singleton.PlayerInput[player.ID]
 		entity := s.world.GetEntityByID(player.EntityID)
 		if entity == nil {
-			fmt.Printf("ability system couldn't find player %d\n", player.ID)
-			continue
-		}
-
-		if entity == nil {
+			log.Warn("could not find player entity", logger.PlayerID(player.ID))
 			continue
 		}
 
 		cc := entity.GetComponentContainer()
 
 		if key, ok := playerInput.KeyboardInput[input.KeyboardKeyQ]; ok && key.Event == input.KeyboardEventDown {
+			timerManager := s.world.TimerManager()
 			cooldownLookup := fmt.Sprintf("%d_%s", player.ID, input.KeyboardKeyQ)
-			if time.Now().UnixMilli()-s.cooldowns[cooldownLookup] < 500 {
+			if timerManager.OnCooldown(cooldownLookup) {
 				continue
 			}
-			s.cooldowns[cooldownLookup] = time.Now().UnixMilli()
+			timerManager.StartCooldown(cooldownLookup, int(castCooldown.Milliseconds())/settings.MSPerCommandFrame)
 
 			cc.NotepadComponent.LastAction = components.ActionCast
 
+			proj := spawnProjectile(entity)
+			networkComponent := proj.GetComponentContainer().NetworkComponent
+			networkComponent.OwnerPlayerID = player.ID
+			s.world.RegisterEntities([]entities.Entity{proj})
+
 			if utils.IsServer() {
-				projSpeed := 200
-				cc := entity.GetComponentContainer()
-				direction := cc.TransformComponent.Orientation.Rotate(mgl64.Vec3{0, 0, -1})
-				position := cc.TransformComponent.Position.Add(mgl64.Vec3{0, 15, 0}).Add(direction.Mul(10))
-				proj := entityutils.Spawn(types.EntityTypeProjectile, position, cc.TransformComponent.Orientation)
-				projcc := proj.GetComponentContainer()
-				projcc.PhysicsComponent.Velocity = direction.Mul(float64(projSpeed))
-				s.world.RegisterEntities([]entities.Entity{proj})
+				// tag the projectile with the key the client predicted it under so that the
+				// client can match it with its predicted projectile
+				networkComponent.SpawnKey = singleton.PlayerSpawnKeys[player.ID]
+			} else {
+				// the client spawns the projectile right away rather than waiting a round trip
+				// for the server's. it's replaced by the server's projectile once it arrives
+				networkComponent.Predicted = true
+				networkComponent.SpawnKey = singleton.PredictSpawn(proj.GetID())
 			}
 		}
 	}
 }
 
+func spawnProjectile(caster entities.Entity) entities.Entity {
+	projSpeed := 200
+	cc := caster.GetComponentContainer()
+	direction := cc.TransformComponent.Orientation.Rotate(mgl64.Vec3{0, 0, -1})
+	position := cc.TransformComponent.Position.Add(mgl64.Vec3{0, 15, 0}).Add(direction.Mul(10))
+	proj := entityutils.Spawn(types.EntityTypeProjectile, position, cc.TransformComponent.Orientation)
+	projcc := proj.GetComponentContainer()
+	projcc.PhysicsComponent.Velocity = direction.Mul(float64(projSpeed))
+	return proj
+}
+
 func (s *AbilitySystem) Name() string {
 	return "AbilitySystem"
 }
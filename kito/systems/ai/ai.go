@@ -9,20 +9,25 @@ import (
 	"github.com/kkevinchou/kito/kito/components"
 	"github.com/kkevinchou/kito/kito/directory"
 	"github.com/kkevinchou/kito/kito/entities"
+	"github.com/kkevinchou/kito/kito/managers/timer"
 	"github.com/kkevinchou/kito/kito/settings"
 	"github.com/kkevinchou/kito/kito/systems/base"
 	"github.com/kkevinchou/kito/kito/types"
 	"github.com/kkevinchou/kito/lib/libutils"
+	"github.com/kkevinchou/kito/lib/logger"
 )
 
+var log = logger.New("ai")
+
 const (
 	enemyMoveSpeed = 40
 )
 
 type World interface {
-	QueryEntity(componentFlags int) []entities.Entity
+	QueryEntity(componentFlags ...int) []entities.Entity
 	GetEntityByID(id int) entities.Entity
 	RegisterEntities(es []entities.Entity)
+	TimerManager() *timer.Manager
 }
 
 type AISystem struct {
@@ -55,6 +60,7 @@ func (s *AISystem) Update(delta time.Duration) {
 		return
 	}
 	playerPosition := playerEntities[0].GetComponentContainer().TransformComponent.Position
+	timerManager := s.world.TimerManager()
 
 	for _, entity := range s.world.QueryEntity(components.ComponentFlagAI) {
 		cc := entity.GetComponentContainer()
@@ -63,9 +69,12 @@ func (s *AISystem) Update(delta time.Duration) {
 		movementComponent := cc.MovementComponent
 
 		if entity.Type() == types.EntityTypeEnemy {
-			if time.Since(aiComponent.LastUpdate) > time.Duration(rand.Intn(5)+2)*time.Second {
+			decisionKey := fmt.Sprintf("ai_decision_%d", entity.GetID())
+			if !timerManager.OnCooldown(decisionKey) {
+				decisionInterval := time.Duration(rand.Intn(5)+2) * time.Second
+				timerManager.StartCooldown(decisionKey, int(decisionInterval.Milliseconds())/settings.MSPerCommandFrame)
+
 				aiComponent.AIState = components.AIStateWalk
-				aiComponent.LastUpdate = time.Now()
 				aiToPlayer := playerPosition.Sub(transformComponent.Position)
 				aiToPlayer[1] = 0
 
@@ -80,7 +89,7 @@ func (s *AISystem) Update(delta time.Duration) {
 				aiComponent.MovementDir = libutils.Vec3ToQuat(dir)
 			}
 		} else {
-			fmt.Println("unhandled ai entity type")
+			log.Warn("unhandled ai entity type", logger.EntityID(entity.GetID()))
 			continue
 		}
 
@@ -12,7 +12,7 @@ import (
 )
 
 type World interface {
-	QueryEntity(componentFlags int) []entities.Entity
+	QueryEntity(componentFlags ...int) []entities.Entity
 	GetPlayerEntity() entities.Entity
 	GetEntityByID(id int) entities.Entity
 }
@@ -17,42 +17,27 @@ import (
 
 type World interface {
 	GetSingleton() *singleton.Singleton
-	QueryEntity(componentFlags int) []entities.Entity
+	QueryEntity(componentFlags ...int) []entities.Entity
 	GetEventBroker() eventbroker.EventBroker
 	UnregisterEntityByID(id int)
 }
 
 type BookKeepingSystem struct {
 	*base.BaseSystem
-	events []events.Event
+	unregisterEvents *eventbroker.Queue[*events.UnregisterEntityEvent]
 
 	world World
 }
 
 func NewBookKeepingSystem(world World) *BookKeepingSystem {
-	s := &BookKeepingSystem{
-		world: world,
-	}
-
-	eventBroker := world.GetEventBroker()
-	eventBroker.AddObserver(s, []events.EventType{
-		events.EventTypeUnregisterEntity,
-	})
-
-	return s
-}
-func (s *BookKeepingSystem) clearEvents() {
-	s.events = nil
-}
-
-func (s *BookKeepingSystem) Observe(event events.Event) {
-	if event.Type() == events.EventTypeUnregisterEntity {
-		s.events = append(s.events, event)
+	return &BookKeepingSystem{
+		unregisterEvents: eventbroker.NewQueue[*events.UnregisterEntityEvent](world.GetEventBroker(), 0),
+		world:            world,
 	}
 }
 
 func (s *BookKeepingSystem) Update(delta time.Duration) {
-	defer s.clearEvents()
+	unregisterEvents := s.unregisterEvents.Drain()
 
 	if utils.IsServer() {
 		singleton := s.world.GetSingleton()
@@ -62,11 +47,12 @@ func (s *BookKeepingSystem) Update(delta time.Duration) {
 		for i, _ := range singleton.PlayerCommands {
 			singleton.PlayerCommands[i] = &playercommand.PlayerCommandList{}
 		}
+		for i := range singleton.PlayerSpawnKeys {
+			delete(singleton.PlayerSpawnKeys, i)
+		}
 
-		for _, event := range s.events {
-			if e, ok := event.(*events.UnregisterEntityEvent); ok {
-				s.world.UnregisterEntityByID(e.EntityID)
-			}
+		for _, e := range unregisterEvents {
+			s.world.UnregisterEntityByID(e.EntityID)
 		}
 	}
 
@@ -1,22 +1,26 @@
 package camera
 
 import (
-	"fmt"
 	"math"
 	"time"
 
 	"github.com/go-gl/mathgl/mgl64"
+	"github.com/kkevinchou/kito/kito/broadphase"
 	"github.com/kkevinchou/kito/kito/components"
+	"github.com/kkevinchou/kito/kito/query"
 	"github.com/kkevinchou/kito/kito/singleton"
+	"github.com/kkevinchou/kito/kito/types"
 
 	"github.com/kkevinchou/kito/kito/entities"
 	"github.com/kkevinchou/kito/kito/systems/base"
-	"github.com/kkevinchou/kito/lib/collision/checks"
 	"github.com/kkevinchou/kito/lib/collision/collider"
 	"github.com/kkevinchou/kito/lib/input"
 	"github.com/kkevinchou/kito/lib/libutils"
+	"github.com/kkevinchou/kito/lib/logger"
 )
 
+var log = logger.New("camera")
+
 const (
 	farMouseWheelSensitivity  float64 = 2.5
 	nearMouseWheelSensitivity float64 = 1.5
@@ -25,7 +29,8 @@ const (
 type World interface {
 	GetSingleton() *singleton.Singleton
 	GetEntityByID(id int) entities.Entity
-	QueryEntity(componentFlags int) []entities.Entity
+	QueryEntity(componentFlags ...int) []entities.Entity
+	Broadphase() *broadphase.Broadphase
 }
 
 type CameraSystem struct {
@@ -44,7 +49,7 @@ func NewCameraSystem(world World) *CameraSystem {
 func (s *CameraSystem) Update(delta time.Duration) {
 	singleton := s.world.GetSingleton()
 
-	for _, camera := range s.world.QueryEntity(components.ComponentFlagCamera | components.ComponentFlagControl) {
+	for _, camera := range s.world.QueryEntity(components.ComponentFlagCamera, components.ComponentFlagControl) {
 		playerID := camera.GetComponentContainer().ControlComponent.PlayerID
 		newOrientation := s.handleCameraControls(delta, camera, s.world, singleton.PlayerInput[playerID])
 		currentInput := singleton.PlayerInput[playerID]
@@ -127,7 +132,7 @@ func (s *CameraSystem) handleCameraControls(delta time.Duration, entity entities
 
 	target := world.GetEntityByID(cameraComponent.FollowTargetEntityID)
 	if target == nil {
-		fmt.Println("failed to find target entity with ID", cameraComponent.FollowTargetEntityID)
+		log.Warn("failed to find camera target", logger.EntityID(cameraComponent.FollowTargetEntityID))
 		return mgl64.QuatIdent()
 	}
 	targetComponentContainer := target.GetComponentContainer()
@@ -138,50 +143,14 @@ func (s *CameraSystem) handleCameraControls(delta time.Duration, entity entities
 	// figure out if the target we're looking at would be occluded, if so, pull the camera in
 	dir := transformComponent.Position.Sub(targetPosition).Normalize()
 	ray := collider.Ray{Origin: targetPosition, Direction: dir}
-	if occlusionPosition := s.calculateOcclusionPosition(ray); occlusionPosition != nil {
-		if targetPosition.Sub(*occlusionPosition).Len() < targetPosition.Sub(transformComponent.Position).Len() {
-			transformComponent.Position = occlusionPosition.Sub(ray.Direction.Mul(5))
-		}
+	hits := query.Raycast(world, ray, cameraComponent.FollowDistance, query.Options{Mask: types.CollisionLayerTerrain})
+	if len(hits) > 0 {
+		transformComponent.Position = hits[0].Point.Sub(ray.Direction.Mul(5))
 	}
 
 	return newOrientation
 }
 
-func (s *CameraSystem) calculateOcclusionPosition(ray collider.Ray) *mgl64.Vec3 {
-	var minDistSqr float64
-	var minPoint *mgl64.Vec3
-
-	// figure out if the target we're looking at would be occluded, if so, pull the camera in
-	entities := s.world.QueryEntity(components.ComponentFlagCollider)
-	for _, e := range entities {
-		cc := e.GetComponentContainer()
-		colliderComponent := cc.ColliderComponent
-		if colliderComponent.TriMeshCollider == nil {
-			continue
-		}
-
-		transformMatrix := mgl64.Translate3D(cc.TransformComponent.Position.X(), cc.TransformComponent.Position.Y(), cc.TransformComponent.Position.Z())
-		triMesh := cc.ColliderComponent.TriMeshCollider.Transform(transformMatrix)
-		point := checks.IntersectRayTriMesh(ray, triMesh)
-		if point == nil {
-			continue
-		}
-
-		if minPoint == nil {
-			minPoint = point
-			minDistSqr = point.LenSqr()
-		} else {
-			dist := point.LenSqr()
-			if dist < minDistSqr {
-				minPoint = point
-				minDistSqr = dist
-			}
-		}
-	}
-
-	return minPoint
-}
-
 func (s *CameraSystem) Name() string {
 	return "CameraSystem"
 }
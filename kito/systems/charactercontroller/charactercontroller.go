@@ -1,9 +1,9 @@
 package charactercontroller
 
 import (
-	"fmt"
 	"time"
 
+	"github.com/kkevinchou/kito/kito/broadphase"
 	"github.com/kkevinchou/kito/kito/directory"
 	"github.com/kkevinchou/kito/kito/entities"
 	"github.com/kkevinchou/kito/kito/managers/player"
@@ -11,13 +11,18 @@ import (
 	"github.com/kkevinchou/kito/kito/singleton"
 	"github.com/kkevinchou/kito/kito/systems/base"
 	"github.com/kkevinchou/kito/kito/utils"
+	"github.com/kkevinchou/kito/lib/logger"
 )
 
+var log = logger.New("charactercontroller")
+
 type World interface {
 	GetSingleton() *singleton.Singleton
 	GetEntityByID(id int) entities.Entity
 	GetPlayerEntity() entities.Entity
 	GetPlayer() *player.Player
+	QueryEntity(componentFlags ...int) []entities.Entity
+	Broadphase() *broadphase.Broadphase
 }
 
 type CharacterControllerSystem struct {
@@ -47,18 +52,18 @@ func (s *CharacterControllerSystem) Update(delta time.Duration) {
 	for _, player := range players {
 		entity := s.world.GetEntityByID(player.EntityID)
 		if entity == nil {
-			fmt.Printf("character controller could not find player entity with id %d\n", player.EntityID)
+			log.Warn("could not find player entity", logger.PlayerID(player.ID), logger.EntityID(player.EntityID))
 			continue
 		}
 
 		cameraID := entity.GetComponentContainer().ThirdPersonControllerComponent.CameraID
 		camera := s.world.GetEntityByID(cameraID)
 		if camera == nil {
-			fmt.Printf("character controller could not find camera with entity id %d\n", cameraID)
+			log.Warn("could not find camera", logger.PlayerID(player.ID), logger.EntityID(cameraID))
 			continue
 		}
 
-		netsync.UpdateCharacterController(delta, entity, camera, singleton.PlayerInput[player.ID])
+		netsync.UpdateCharacterController(delta, entity, camera, singleton.PlayerInput[player.ID], s.world)
 	}
 }
 
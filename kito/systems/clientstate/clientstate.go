@@ -1,9 +1,9 @@
 package clientstate
 
 import (
-	"fmt"
 	"time"
 
+	"github.com/go-gl/mathgl/mgl64"
 	"github.com/kkevinchou/kito/kito/entities"
 	"github.com/kkevinchou/kito/kito/events"
 	"github.com/kkevinchou/kito/kito/knetwork"
@@ -13,9 +13,18 @@ import (
 	"github.com/kkevinchou/kito/kito/systems/base"
 	"github.com/kkevinchou/kito/kito/types"
 	"github.com/kkevinchou/kito/kito/utils/entityutils"
+	"github.com/kkevinchou/kito/lib/logger"
 	"github.com/kkevinchou/kito/lib/metrics"
 )
 
+var log = logger.New("clientstate")
+
+const (
+	// fraction of the prediction offset that remains after each command frame
+	predictionOffsetDecay = 0.8
+	minPredictionOffset   = 0.01
+)
+
 type World interface {
 	CommandFrame() int
 	GetSingleton() *singleton.Singleton
@@ -64,6 +73,7 @@ func spawnNewEntities(bufferedState *statebuffer.BufferedState, world World) {
 		entity := world.GetEntityByID(snapshot.ID)
 		if entity == nil {
 			newEntity := entityutils.SpawnWithID(snapshot.ID, types.EntityType(snapshot.Type), snapshot.Position, snapshot.Orientation)
+			replacePredictedSpawn(snapshot, newEntity, world)
 			newEntities = append(newEntities, newEntity)
 		}
 	}
@@ -71,13 +81,45 @@ func spawnNewEntities(bufferedState *statebuffer.BufferedState, world World) {
 	world.RegisterEntities(newEntities)
 }
 
+// replacePredictedSpawn hands off a predicted entity to the server's entity that was
+// spawned for the same input. The server's entity starts out where the predicted entity
+// was and eases towards its interpolated position
+func replacePredictedSpawn(snapshot knetwork.EntitySnapshot, newEntity entities.Entity, world World) {
+	singleton := world.GetSingleton()
+	if snapshot.SpawnKey == 0 || snapshot.OwnerPlayerID != singleton.PlayerID {
+		return
+	}
+
+	predictedSpawn, ok := singleton.PredictedSpawns[snapshot.SpawnKey]
+	if !ok {
+		return
+	}
+	delete(singleton.PredictedSpawns, snapshot.SpawnKey)
+
+	predictedEntity := world.GetEntityByID(predictedSpawn.EntityID)
+	if predictedEntity == nil {
+		return
+	}
+
+	predictedPosition := predictedEntity.GetComponentContainer().TransformComponent.Position
+	if networkComponent := newEntity.GetComponentContainer().NetworkComponent; networkComponent != nil {
+		networkComponent.PredictionOffset = predictedPosition.Sub(snapshot.Position)
+	}
+	world.UnregisterEntityByID(predictedSpawn.EntityID)
+}
+
 func applyState(bufferedState *statebuffer.BufferedState, world World) {
 	playerEntity := world.GetPlayerEntity()
-	for _, event := range bufferedState.Events {
-		if event.Type == events.EventTypeUnregisterEntity {
-			var e events.UnregisterEntityEvent
-			knetwork.Deserialize(event.Bytes, &e)
+	for _, networkEvent := range bufferedState.Events {
+		event, err := knetwork.DeserializeEvent(networkEvent)
+		if err != nil {
+			log.Error("failed to deserialize event", logger.Err(err))
+			continue
+		}
+
+		if e, ok := event.(*events.UnregisterEntityEvent); ok {
 			world.UnregisterEntityByID(e.EntityID)
+			unregisterPredictedSpawn(e.EntityID, world)
 		}
 	}
 
@@ -85,7 +127,7 @@ func applyState(bufferedState *statebuffer.BufferedState, world World) {
 
 		foundEntity := world.GetEntityByID(entitySnapshot.ID)
 		if foundEntity == nil {
-			fmt.Printf("[%d] failed to find entity with id %d type %d to interpolate\n", world.CommandFrame(), entitySnapshot.ID, entitySnapshot.Type)
+			log.Debug("failed to find entity to interpolate", logger.EntityID(entitySnapshot.ID), logger.F("type", entitySnapshot.Type))
 		} else {
 			cc := foundEntity.GetComponentContainer()
 			cc.Load(entitySnapshot.Components)
@@ -97,6 +139,13 @@ func applyState(bufferedState *statebuffer.BufferedState, world World) {
 
 			cc.TransformComponent.Position = entitySnapshot.Position
 			cc.TransformComponent.Orientation = entitySnapshot.Orientation
+			if networkComponent := cc.NetworkComponent; networkComponent != nil && networkComponent.PredictionOffset != (mgl64.Vec3{}) {
+				cc.TransformComponent.Position = cc.TransformComponent.Position.Add(networkComponent.PredictionOffset)
+				networkComponent.PredictionOffset = networkComponent.PredictionOffset.Mul(predictionOffsetDecay)
+				if networkComponent.PredictionOffset.Len() < minPredictionOffset {
+					networkComponent.PredictionOffset = mgl64.Vec3{}
+				}
+			}
 			if cc.MovementComponent != nil {
 				cc.MovementComponent.Velocity = entitySnapshot.Velocity
 			}
@@ -107,6 +156,19 @@ func applyState(bufferedState *statebuffer.BufferedState, world World) {
 	}
 }
 
+// unregisterPredictedSpawn destroys the predicted entity for a confirmed spawn whose
+// server entity was destroyed before it was ever interpolated in
+func unregisterPredictedSpawn(authoritativeEntityID int, world World) {
+	singleton := world.GetSingleton()
+	for spawnKey, predictedSpawn := range singleton.PredictedSpawns {
+		if predictedSpawn.AuthoritativeEntityID == authoritativeEntityID {
+			world.UnregisterEntityByID(predictedSpawn.EntityID)
+			delete(singleton.PredictedSpawns, spawnKey)
+			return
+		}
+	}
+}
+
 func (s *ClientStateSystem) Name() string {
 	return "ClientStateSystem"
 }
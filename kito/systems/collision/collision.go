@@ -1,15 +1,20 @@
 package collision
 
 import (
+	"sort"
 	"time"
 
+	"github.com/kkevinchou/kito/kito/broadphase"
+	"github.com/kkevinchou/kito/kito/components"
 	"github.com/kkevinchou/kito/kito/entities"
+	"github.com/kkevinchou/kito/kito/events"
+	"github.com/kkevinchou/kito/kito/managers/eventbroker"
 	"github.com/kkevinchou/kito/kito/managers/player"
 	"github.com/kkevinchou/kito/kito/netsync"
 	"github.com/kkevinchou/kito/kito/singleton"
-	"github.com/kkevinchou/kito/kito/spatialpartition"
 	"github.com/kkevinchou/kito/kito/systems/base"
 	"github.com/kkevinchou/kito/kito/utils"
+	"github.com/kkevinchou/kito/lib/profiler"
 )
 
 const (
@@ -21,31 +26,85 @@ const (
 type World interface {
 	GetSingleton() *singleton.Singleton
 	GetPlayerEntity() entities.Entity
-	QueryEntity(componentFlags int) []entities.Entity
+	QueryEntity(componentFlags ...int) []entities.Entity
 	GetPlayer() *player.Player
 	GetEntityByID(id int) entities.Entity
-	SpatialPartition() *spatialpartition.SpatialPartition
+	Broadphase() *broadphase.Broadphase
+	GetEventBroker() eventbroker.EventBroker
 }
 
 type CollisionSystem struct {
 	*base.BaseSystem
 	world World
+
+	// the entities that overlapped each trigger last frame
+	overlaps map[int]map[int]bool
 }
 
 func NewCollisionSystem(world World) *CollisionSystem {
 	return &CollisionSystem{
 		BaseSystem: &base.BaseSystem{},
 		world:      world,
+		overlaps:   map[int]map[int]bool{},
 	}
 }
 
 func (s *CollisionSystem) Update(delta time.Duration) {
+	s.UpdateTimed(delta, profiler.Timer{})
+}
+
+func (s *CollisionSystem) UpdateTimed(delta time.Duration, timer profiler.Timer) {
 	if utils.IsClient() {
 		player := s.world.GetPlayerEntity()
-		netsync.ResolveCollisionsForPlayer(player, s.world)
+		netsync.ResolveCollisionsForPlayer(player, s.world, timer)
 	} else {
-		netsync.ResolveCollisions(s.world)
+		netsync.ResolveCollisions(s.world, timer)
+	}
+
+	s.publishTriggerEvents()
+}
+
+// publishTriggerEvents compares what overlaps each trigger with the last frame to publish
+// enter, stay and exit events. Events are published in order of entity id so that every
+// run of the same frame publishes them the same way
+func (s *CollisionSystem) publishTriggerEvents() {
+	eventBroker := s.world.GetEventBroker()
+
+	overlaps := map[int]map[int]bool{}
+	for _, entity := range s.world.QueryEntity(components.ComponentFlagCollider) {
+		if entityOverlaps := entity.GetComponentContainer().ColliderComponent.Overlaps; len(entityOverlaps) > 0 {
+			overlaps[entity.GetID()] = entityOverlaps
+		}
+	}
+
+	for _, triggerID := range sortedKeys(overlaps) {
+		for _, entityID := range sortedKeys(overlaps[triggerID]) {
+			if s.overlaps[triggerID][entityID] {
+				eventBroker.Broadcast(&events.TriggerStayEvent{TriggerEntityID: triggerID, EntityID: entityID})
+			} else {
+				eventBroker.Broadcast(&events.TriggerEnterEvent{TriggerEntityID: triggerID, EntityID: entityID})
+			}
+		}
+	}
+
+	for _, triggerID := range sortedKeys(s.overlaps) {
+		for _, entityID := range sortedKeys(s.overlaps[triggerID]) {
+			if !overlaps[triggerID][entityID] {
+				eventBroker.Broadcast(&events.TriggerExitEvent{TriggerEntityID: triggerID, EntityID: entityID})
+			}
+		}
+	}
+
+	s.overlaps = overlaps
+}
+
+func sortedKeys[V any](m map[int]V) []int {
+	var keys []int
+	for key := range m {
+		keys = append(keys, key)
 	}
+	sort.Ints(keys)
+	return keys
 }
 
 func (s *CollisionSystem) Name() string {
@@ -13,7 +13,7 @@ import (
 )
 
 type World interface {
-	QueryEntity(componentFlags int) []entities.Entity
+	QueryEntity(componentFlags ...int) []entities.Entity
 	GetEntityByID(id int) entities.Entity
 	CommandFrame() int
 	UnregisterEntity(entity entities.Entity)
@@ -37,9 +37,9 @@ func (s *CombatSystem) Update(delta time.Duration) {
 		return
 	}
 
-	// handle fireball collisions
+	// handle fireball collisions. scripted projectiles handle their own collisions
 	for _, entity := range s.world.QueryEntity(components.ComponentFlagCollider) {
-		if entity.Type() == types.EntityTypeProjectile {
+		if entity.Type() == types.EntityTypeProjectile && entity.GetComponentContainer().ScriptComponent == nil {
 			contacts := entity.GetComponentContainer().ColliderComponent.Contacts
 			if len(contacts) == 0 {
 				continue
@@ -0,0 +1,175 @@
+package hotreload
+
+import (
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/kkevinchou/kito/kito/broadphase"
+	"github.com/kkevinchou/kito/kito/components"
+	"github.com/kkevinchou/kito/kito/config"
+	"github.com/kkevinchou/kito/kito/directory"
+	"github.com/kkevinchou/kito/kito/entities"
+	"github.com/kkevinchou/kito/kito/scheduler"
+	"github.com/kkevinchou/kito/kito/settings"
+	"github.com/kkevinchou/kito/kito/systems/base"
+	"github.com/kkevinchou/kito/kito/utils"
+	"github.com/kkevinchou/kito/lib/collision/collider"
+	"github.com/kkevinchou/kito/lib/filewatcher"
+	"github.com/kkevinchou/kito/lib/logger"
+)
+
+var log = logger.New("hotreload")
+
+const (
+	// pollInterval is how often, in command frames, watched files are checked for changes
+	pollInterval = 30
+)
+
+type World interface {
+	QueryEntity(componentFlags ...int) []entities.Entity
+	Scheduler() *scheduler.Scheduler
+	Broadphase() *broadphase.Broadphase
+}
+
+// HotReloadSystem watches assets, shaders and the config for changes while the game is
+// running and swaps the reloaded versions into the game. Reload failures are reported
+// and the previously loaded version is kept
+type HotReloadSystem struct {
+	*base.BaseSystem
+
+	world        World
+	watcher      *filewatcher.Watcher
+	configLayers *config.Layers
+	config       config.Config
+
+	framesSincePoll int
+}
+
+// NewHotReloadSystem watches the assets directory, the shader directory and the config
+// file. The shader directory is optional since shaders are only loaded on the client
+func NewHotReloadSystem(world World, assetsDirectory string, shaderDirectory string, configLayers *config.Layers) *HotReloadSystem {
+	paths := []string{assetsDirectory, configLayers.Path}
+	if shaderDirectory != "" {
+		paths = append(paths, shaderDirectory)
+	}
+
+	// the config was already resolved on startup so this only fails if the config file
+	// was changed in between, in which case the change is reported on the first reload
+	c, _ := configLayers.Resolve()
+
+	return &HotReloadSystem{
+		BaseSystem:   &base.BaseSystem{},
+		world:        world,
+		watcher:      filewatcher.New([]string{".gltf", ".png", ".vs", ".fs"}, paths...),
+		configLayers: configLayers,
+		config:       c,
+	}
+}
+
+func (s *HotReloadSystem) Update(delta time.Duration) {
+	s.framesSincePoll++
+	if s.framesSincePoll < pollInterval {
+		return
+	}
+	s.framesSincePoll = 0
+
+	for _, event := range s.watcher.Poll() {
+		if event.Op == filewatcher.OpRemoved {
+			// removed assets stay loaded until the game is restarted
+			continue
+		}
+
+		var err error
+		if filepath.Clean(event.Path) == filepath.Clean(s.configLayers.Path) {
+			err = s.reloadConfig()
+		} else {
+			switch event.Extension {
+			case ".gltf":
+				err = s.reloadModel(event)
+			case ".png":
+				err = s.reloadTexture(event)
+			case ".vs", ".fs":
+				err = s.reloadShader(event)
+			}
+		}
+
+		if err != nil {
+			log.Error("failed to reload", logger.F("path", event.Path), logger.Err(err))
+		} else {
+			log.Info("reloaded", logger.F("path", event.Path))
+		}
+	}
+}
+
+func (s *HotReloadSystem) reloadModel(event filewatcher.Event) error {
+	if strings.HasPrefix(event.Name, "_") {
+		return nil
+	}
+
+	previous, current, err := directory.GetDirectory().AssetManager().ReloadModel(event.Name, event.Path)
+	if err != nil {
+		return err
+	}
+	if previous == nil {
+		return nil
+	}
+
+	// models are built per entity, swap the new model into every entity built from the
+	// previous one. Trimesh colliders are built from the model so they're rebuilt along
+	// with it
+	for _, entity := range s.world.QueryEntity(components.ComponentFlagMesh) {
+		cc := entity.GetComponentContainer()
+		m := cc.MeshComponent.Model
+		if m == nil || m.Spec() != previous {
+			continue
+		}
+
+		m.SetSpec(current)
+		if cc.AnimationComponent != nil && cc.AnimationComponent.Player != nil {
+			cc.AnimationComponent.Player.SetModel(m)
+		}
+		if cc.ColliderComponent != nil && cc.ColliderComponent.TriMeshCollider != nil {
+			triMesh := collider.NewTriMesh(m)
+			cc.ColliderComponent.TriMeshCollider = &triMesh
+			cc.ColliderComponent.BoundingBoxCollider = collider.BoundingBoxFromModel(m)
+			s.world.Broadphase().Reinsert(entity)
+		}
+	}
+	return nil
+}
+
+func (s *HotReloadSystem) reloadTexture(event filewatcher.Event) error {
+	if !utils.IsClient() {
+		return nil
+	}
+	return directory.GetDirectory().AssetManager().ReloadTexture(event.Name, event.Path)
+}
+
+func (s *HotReloadSystem) reloadShader(event filewatcher.Event) error {
+	if !utils.IsClient() {
+		return nil
+	}
+	return directory.GetDirectory().ShaderManager().ReloadShader(event.Path)
+}
+
+func (s *HotReloadSystem) reloadConfig() error {
+	// the environment and flags still take precedence over the reloaded config file
+	c, err := s.configLayers.Resolve()
+	if err != nil {
+		return err
+	}
+
+	config.ApplyRuntime(s.config, c)
+	s.world.Scheduler().SetParallel(settings.ParallelSystems)
+
+	if restart := config.RestartRequired(s.config, c); len(restart) > 0 {
+		log.Warn("settings changed and will be applied on restart", logger.F("settings", strings.Join(restart, ",")))
+	}
+	s.config = c
+	return nil
+}
+
+func (s *HotReloadSystem) Name() string {
+	return "HotReloadSystem"
+}
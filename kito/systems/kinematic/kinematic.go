@@ -0,0 +1,42 @@
+package kinematic
+
+import (
+	"time"
+
+	"github.com/kkevinchou/kito/kito/broadphase"
+	"github.com/kkevinchou/kito/kito/entities"
+	"github.com/kkevinchou/kito/kito/netsync"
+	"github.com/kkevinchou/kito/kito/singleton"
+	"github.com/kkevinchou/kito/kito/systems/base"
+)
+
+type World interface {
+	GetSingleton() *singleton.Singleton
+	GetPlayerEntity() entities.Entity
+	GetEntityByID(id int) entities.Entity
+	QueryEntity(componentFlags ...int) []entities.Entity
+	Broadphase() *broadphase.Broadphase
+}
+
+type KinematicSystem struct {
+	*base.BaseSystem
+	world World
+}
+
+func NewKinematicSystem(world World) *KinematicSystem {
+	return &KinematicSystem{
+		BaseSystem: &base.BaseSystem{},
+		world:      world,
+	}
+}
+
+func (s *KinematicSystem) Update(delta time.Duration) {
+	// kinematic bodies are moved on both the server and the client since where they are
+	// only depends on the command frame. the offset is always 0 on the server
+	singleton := s.world.GetSingleton()
+	netsync.UpdateKinematicBodies(singleton.CommandFrame+singleton.ServerCommandFrameOffset, s.world)
+}
+
+func (s *KinematicSystem) Name() string {
+	return "KinematicSystem"
+}
@@ -15,7 +15,7 @@ import (
 )
 
 type World interface {
-	QueryEntity(componentFlags int) []entities.Entity
+	QueryEntity(componentFlags ...int) []entities.Entity
 	RegisterEntities([]entities.Entity)
 	GetEntityByID(id int) entities.Entity
 	CommandFrame() int
@@ -26,6 +26,8 @@ type LootSystem struct {
 	*base.BaseSystem
 	world   World
 	modPool *items.ModPool
+
+	triggerEnterEvents *eventbroker.Queue[*events.TriggerEnterEvent]
 }
 
 func NewLootSystem(world World) *LootSystem {
@@ -39,9 +41,10 @@ func NewLootSystem(world World) *LootSystem {
 	}
 
 	return &LootSystem{
-		BaseSystem: &base.BaseSystem{},
-		world:      world,
-		modPool:    modPool,
+		BaseSystem:         &base.BaseSystem{},
+		world:              world,
+		modPool:            modPool,
+		triggerEnterEvents: eventbroker.NewQueue[*events.TriggerEnterEvent](world.GetEventBroker(), 0),
 	}
 }
 
@@ -69,25 +72,25 @@ func (s *LootSystem) Update(delta time.Duration) {
 		s.world.RegisterEntities([]entities.Entity{lootbox})
 	}
 
-	// add loot to a player's inventory
-	inventoryEntities := s.world.QueryEntity(components.ComponentFlagInventory)
-	for _, entity := range inventoryEntities {
-		cc := entity.GetComponentContainer()
-		if cc.ColliderComponent == nil || len(cc.ColliderComponent.Contacts) == 0 {
+	// add loot to the inventory of whoever walks into a lootbox first
+	pickedUp := map[int]bool{}
+	for _, event := range s.triggerEnterEvents.Drain() {
+		lootbox := s.world.GetEntityByID(event.TriggerEntityID)
+		entity := s.world.GetEntityByID(event.EntityID)
+		if lootbox == nil || entity == nil || pickedUp[lootbox.GetID()] {
+			continue
+		}
+		if lootbox.GetComponentContainer().LootComponent == nil || entity.GetComponentContainer().InventoryComponent == nil {
 			continue
 		}
 
-		for e2ID := range cc.ColliderComponent.Contacts {
-			cEntity := s.world.GetEntityByID(e2ID)
-			if cEntity.Type() == types.EntityTypeLootbox {
-				cc.InventoryComponent.Add(&components.Item{ID: 69})
-				event := &events.UnregisterEntityEvent{
-					GlobalCommandFrame: s.world.CommandFrame(),
-					EntityID:           cEntity.GetID(),
-				}
-				s.world.GetEventBroker().Broadcast(event)
-			}
+		entity.GetComponentContainer().InventoryComponent.Add(&components.Item{ID: 69})
+		pickedUp[lootbox.GetID()] = true
+		event := &events.UnregisterEntityEvent{
+			GlobalCommandFrame: s.world.CommandFrame(),
+			EntityID:           lootbox.GetID(),
 		}
+		s.world.GetEventBroker().Broadcast(event)
 	}
 }
 
@@ -1,22 +1,24 @@
 package networkdispatch
 
 import (
-	"fmt"
 	"time"
 
 	"github.com/kkevinchou/kito/kito/commandframe"
+	"github.com/kkevinchou/kito/kito/components"
 	"github.com/kkevinchou/kito/kito/entities"
 	"github.com/kkevinchou/kito/kito/knetwork"
 	"github.com/kkevinchou/kito/kito/netsync"
 	"github.com/kkevinchou/kito/kito/settings"
+	"github.com/kkevinchou/kito/lib/logger"
 	"github.com/kkevinchou/kito/lib/network"
+	"github.com/kkevinchou/kito/lib/profiler"
 )
 
-func clientMessageHandler(world World, message *network.Message) {
+func clientMessageHandler(world World, message *network.Message, timer profiler.Timer) {
 	metricsRegistry := world.MetricsRegistry()
 	if message.MessageType == knetwork.MessageTypeGameStateUpdate {
 		var gameStateUpdate knetwork.GameStateUpdateMessage
-		err := network.DeserializeBody(message, &gameStateUpdate)
+		err := deserializeBody(message, &gameStateUpdate, timer)
 		if err != nil {
 			panic(err)
 		}
@@ -29,25 +31,65 @@ func clientMessageHandler(world World, message *network.Message) {
 		metricsRegistry.Inc("update_message_count", 1)
 
 		singleton := world.GetSingleton()
-		validateClientPrediction(&gameStateUpdate, world)
+		if gameStateUpdate.LastInputGlobalCommandFrame > 0 {
+			singleton.ServerCommandFrameOffset = gameStateUpdate.LastInputGlobalCommandFrame - gameStateUpdate.LastInputCommandFrame
+		}
+		validateClientPrediction(&gameStateUpdate, world, timer)
+		reconcilePredictedSpawns(&gameStateUpdate, world)
 		singleton.StateBuffer.PushEntityUpdate(world.CommandFrame(), &gameStateUpdate)
 	} else if message.MessageType == knetwork.MessageTypeAckCreatePlayer {
-		fmt.Println("this should be handled in the client code and not handled here")
+		log.Warn("create player ack should be handled by the client before the game starts")
 		// panic("this should be handled in the client code and not handled here")
 	} else if message.MessageType == knetwork.MessageTypeAckPing {
 		var ackPingMessage knetwork.AckPingMessage
-		err := network.DeserializeBody(message, &ackPingMessage)
+		err := deserializeBody(message, &ackPingMessage, timer)
 		if err != nil {
-			fmt.Printf("error deserializing ackping message %s\n", err)
+			log.Error("failed to deserialize ack ping message", logger.Err(err))
 		}
 
 		metricsRegistry.Inc("ping", float64(time.Since(ackPingMessage.PingSendTime).Milliseconds()))
+	} else if message.MessageType == knetwork.MessageTypeTimeControl {
+		var timeControlMessage knetwork.TimeControlMessage
+		err := deserializeBody(message, &timeControlMessage, timer)
+		if err != nil {
+			log.Error("failed to deserialize time control message", logger.Err(err))
+			return
+		}
+
+		world.SetTimeControl(timeControlMessage)
+	} else if message.MessageType == knetwork.MessageTypeComponentEdit {
+		var componentEditMessage knetwork.ComponentEditMessage
+		err := deserializeBody(message, &componentEditMessage, timer)
+		if err != nil {
+			log.Error("failed to deserialize component edit message", logger.Err(err))
+			return
+		}
+
+		handleComponentEdit(world, componentEditMessage)
 	} else {
-		fmt.Println("unknown message type:", message.MessageType, string(message.Body))
+		log.Warn("unknown message type", logger.F("type", message.MessageType), logger.F("body", string(message.Body)))
+	}
+}
+
+// handleComponentEdit applies an inspector edit once the server has acknowledged it.
+// Rejected edits were never applied so there's nothing to undo
+func handleComponentEdit(world World, edit knetwork.ComponentEditMessage) {
+	field := edit.Component + "." + edit.Field
+	if edit.Error != "" {
+		log.Warn("component edit rejected by the server", logger.EntityID(edit.EntityID), logger.F("field", field), logger.F("error", edit.Error))
+		return
+	}
+
+	entity := world.GetEntityByID(edit.EntityID)
+	if entity == nil {
+		return
+	}
+	if err := entity.GetComponentContainer().SetField(edit.Component, edit.Field, edit.Value); err != nil {
+		log.Warn("failed to apply acknowledged component edit", logger.EntityID(edit.EntityID), logger.F("field", field), logger.Err(err))
 	}
 }
 
-func validateClientPrediction(gameStateUpdate *knetwork.GameStateUpdateMessage, world World) {
+func validateClientPrediction(gameStateUpdate *knetwork.GameStateUpdateMessage, world World, timer profiler.Timer) {
 	metricsRegistry := world.MetricsRegistry()
 
 	// We use a gcf adjusted command frame lookup because even though an input may happen on only one command
@@ -117,7 +159,7 @@ func validateClientPrediction(gameStateUpdate *knetwork.GameStateUpdateMessage,
 			cc.TransformComponent.Orientation = entitySnapshot.Orientation
 			cc.ThirdPersonControllerComponent.BaseVelocity = entitySnapshot.Velocity
 
-			replayInputs(playerEntity, world, lookupCommandFrame, cfHistory)
+			replayInputs(playerEntity, world, lookupCommandFrame, cfHistory, timer)
 
 			_ = originalPosition
 			_ = originalOrientation
@@ -146,6 +188,7 @@ func replayInputs(
 	world World,
 	startFrame int,
 	cfHistory *commandframe.CommandFrameHistory,
+	timer profiler.Timer,
 ) {
 	frameIndex := startFrame + 1
 	cf := cfHistory.GetCommandFrame(frameIndex)
@@ -164,10 +207,81 @@ func replayInputs(
 	// TODO(kevin): this should ideally rewind all other entities as well,
 	// not just the player
 
+	// kinematic bodies are moved back to where they were on each replayed frame. the
+	// kinematic system moves them on to the current frame afterwards
+	// replayed frames record overlaps on the player and on the triggers it passes
+	// through. both sides are put back afterwards so that only the current frame's
+	// overlaps are turned into trigger events
+	overlaps := saveOverlaps(world)
+	defer restoreOverlaps(world, overlaps)
+
+	serverCommandFrameOffset := world.GetSingleton().ServerCommandFrameOffset
 	for i, cf := range cfs {
-		netsync.UpdateCharacterController(time.Duration(settings.MSPerCommandFrame)*time.Millisecond, playerEntity, world.GetCamera(), cf.FrameInput)
-		netsync.ResolveCollisionsForPlayer(playerEntity, world)
+		netsync.UpdateKinematicBodies(startFrame+i+1+serverCommandFrameOffset, world)
+		world.Broadphase().Sync()
+		netsync.UpdateCharacterController(time.Duration(settings.MSPerCommandFrame)*time.Millisecond, playerEntity, world.GetCamera(), cf.FrameInput, world)
+		netsync.ResolveCollisionsForPlayer(playerEntity, world, timer)
 		netsync.CollisionBookKeeping(playerEntity)
 		cfHistory.AddCommandFrame(startFrame+i+1, cf.FrameInput, playerEntity)
 	}
 }
+
+// saveOverlaps copies what overlaps each collider
+func saveOverlaps(world World) map[int]map[int]bool {
+	overlaps := map[int]map[int]bool{}
+	for _, entity := range world.QueryEntity(components.ComponentFlagCollider) {
+		entityOverlaps := map[int]bool{}
+		for id := range entity.GetComponentContainer().ColliderComponent.Overlaps {
+			entityOverlaps[id] = true
+		}
+		overlaps[entity.GetID()] = entityOverlaps
+	}
+	return overlaps
+}
+
+// restoreOverlaps puts back what overlapped each collider when the overlaps were saved
+func restoreOverlaps(world World, overlaps map[int]map[int]bool) {
+	for _, entity := range world.QueryEntity(components.ComponentFlagCollider) {
+		entityOverlaps, ok := overlaps[entity.GetID()]
+		if !ok {
+			entityOverlaps = map[int]bool{}
+		}
+		entity.GetComponentContainer().ColliderComponent.Overlaps = entityOverlaps
+	}
+}
+
+// reconcilePredictedSpawns matches the entities the client predicted with the entities
+// the server spawned for the same inputs. Once the server has processed the input a
+// spawn was predicted on, the server's entity should be in the update. If it isn't, the
+// server rejected the spawn and the predicted entity is destroyed. Confirmed spawns are
+// handed off to the server's entity once it's interpolated in, see clientstate
+func reconcilePredictedSpawns(gameStateUpdate *knetwork.GameStateUpdateMessage, world World) {
+	singleton := world.GetSingleton()
+	if len(singleton.PredictedSpawns) == 0 {
+		return
+	}
+
+	spawnedEntities := map[int]int{}
+	for _, snapshot := range gameStateUpdate.Entities {
+		if snapshot.SpawnKey != 0 && snapshot.OwnerPlayerID == singleton.PlayerID {
+			spawnedEntities[snapshot.SpawnKey] = snapshot.ID
+		}
+	}
+
+	metricsRegistry := world.MetricsRegistry()
+	for spawnKey, predictedSpawn := range singleton.PredictedSpawns {
+		if predictedSpawn.AuthoritativeEntityID != 0 || predictedSpawn.CommandFrame > gameStateUpdate.LastInputCommandFrame {
+			continue
+		}
+
+		if entityID, ok := spawnedEntities[spawnKey]; ok {
+			predictedSpawn.AuthoritativeEntityID = entityID
+			metricsRegistry.Inc("predictedSpawnConfirmed", 1)
+			continue
+		}
+
+		world.UnregisterEntityByID(predictedSpawn.EntityID)
+		delete(singleton.PredictedSpawns, spawnKey)
+		metricsRegistry.Inc("predictedSpawnRejected", 1)
+	}
+}
@@ -3,21 +3,25 @@ package networkdispatch
 import (
 	"time"
 
+	"github.com/kkevinchou/kito/kito/broadphase"
 	"github.com/kkevinchou/kito/kito/commandframe"
 	"github.com/kkevinchou/kito/kito/entities"
 	"github.com/kkevinchou/kito/kito/knetwork"
 	"github.com/kkevinchou/kito/kito/managers/eventbroker"
 	"github.com/kkevinchou/kito/kito/managers/player"
 	"github.com/kkevinchou/kito/kito/singleton"
-	"github.com/kkevinchou/kito/kito/spatialpartition"
 	"github.com/kkevinchou/kito/kito/systems/base"
 	"github.com/kkevinchou/kito/kito/utils"
+	"github.com/kkevinchou/kito/lib/logger"
 	"github.com/kkevinchou/kito/lib/metrics"
 	"github.com/kkevinchou/kito/lib/network"
+	"github.com/kkevinchou/kito/lib/profiler"
 )
 
+var log = logger.New("networkdispatch")
+
 type MessageFetcher func(world World) []*network.Message
-type MessageHandler func(world World, message *network.Message)
+type MessageHandler func(world World, message *network.Message, timer profiler.Timer)
 
 type World interface {
 	RegisterEntities([]entities.Entity)
@@ -30,10 +34,13 @@ type World interface {
 	MetricsRegistry() *metrics.MetricsRegistry
 	GetPlayer() *player.Player
 	GetPlayerByID(id int) *player.Player
-	QueryEntity(componentFlags int) []entities.Entity
+	QueryEntity(componentFlags ...int) []entities.Entity
 	GetEntityByID(id int) entities.Entity
-	SpatialPartition() *spatialpartition.SpatialPartition
+	UnregisterEntityByID(id int)
+	Broadphase() *broadphase.Broadphase
 	SetServerStats(serverStats map[string]string)
+	TimeControl() knetwork.TimeControlMessage
+	SetTimeControl(timeControl knetwork.TimeControlMessage)
 }
 
 type NetworkDispatchSystem struct {
@@ -61,6 +68,10 @@ func NewNetworkDispatchSystem(world World) *NetworkDispatchSystem {
 }
 
 func (s *NetworkDispatchSystem) Update(delta time.Duration) {
+	s.UpdateTimed(delta, profiler.Timer{})
+}
+
+func (s *NetworkDispatchSystem) UpdateTimed(delta time.Duration, timer profiler.Timer) {
 	var latestGameStateUpdate *network.Message
 	messages := s.messageFetcher(s.world)
 	for _, message := range messages {
@@ -85,7 +96,7 @@ func (s *NetworkDispatchSystem) Update(delta time.Duration) {
 				sawInputMessage = true
 			}
 		}
-		s.messageHandler(s.world, message)
+		s.messageHandler(s.world, message, timer)
 	}
 	_ = sawInputMessage
 	// if utils.IsServer() && !sawInputMessage {
@@ -96,3 +107,9 @@ func (s *NetworkDispatchSystem) Update(delta time.Duration) {
 func (s *NetworkDispatchSystem) Name() string {
 	return "NetworkDispatchSystem"
 }
+
+// deserializeBody decodes the body of the message, profiled inside of the timer's scope
+func deserializeBody(message *network.Message, messageBody any, timer profiler.Timer) error {
+	defer timer.Begin("network.decode").End()
+	return network.DeserializeBody(message, messageBody)
+}
@@ -1,7 +1,6 @@
 package networkdispatch
 
 import (
-	"fmt"
 	"time"
 
 	"github.com/go-gl/mathgl/mgl64"
@@ -11,22 +10,29 @@ import (
 	"github.com/kkevinchou/kito/kito/knetwork"
 	"github.com/kkevinchou/kito/kito/managers/player"
 	"github.com/kkevinchou/kito/kito/utils/entityutils"
+	"github.com/kkevinchou/kito/lib/logger"
+	"github.com/kkevinchou/kito/lib/metrics"
 	"github.com/kkevinchou/kito/lib/network"
+	"github.com/kkevinchou/kito/lib/profiler"
 )
 
-func serverMessageHandler(world World, message *network.Message) {
+func serverMessageHandler(world World, message *network.Message, timer profiler.Timer) {
 	player := world.GetPlayerByID(message.SenderID)
 	singleton := world.GetSingleton()
 	if player == nil {
-		fmt.Println(fmt.Errorf("failed to find player with id %d", message.SenderID))
+		log.Warn("message from unknown player", logger.PlayerID(message.SenderID))
 		return
 	}
 
+	metricsRegistry := world.MetricsRegistry()
+	metricsRegistry.Counter("kito_messages_received_total", metrics.L("type", message.MessageType)).Inc()
+	metricsRegistry.Histogram("kito_message_size_bytes", metrics.L("direction", "received"), metrics.L("type", message.MessageType)).Observe(float64(len(message.Body)))
+
 	if message.MessageType == knetwork.MessageTypeCreatePlayer {
 		handleCreatePlayer(player, message, world)
 	} else if message.MessageType == knetwork.MessageTypeInput {
 		inputMessage := knetwork.InputMessage{}
-		err := network.DeserializeBody(message, &inputMessage)
+		err := deserializeBody(message, &inputMessage, timer)
 		if err != nil {
 			panic(err)
 		}
@@ -34,24 +40,38 @@ func serverMessageHandler(world World, message *network.Message) {
 		singleton.InputBuffer.PushInput(world.CommandFrame(), message.CommandFrame, message.SenderID, time.Now(), &inputMessage)
 	} else if message.MessageType == knetwork.MessageTypePing {
 		var pingMessage knetwork.PingMessage
-		err := network.DeserializeBody(message, &pingMessage)
+		err := deserializeBody(message, &pingMessage, timer)
 		if err != nil {
-			fmt.Printf("error deserializing ping body %s\n", err)
+			log.Error("failed to deserialize ping message", logger.PlayerID(player.ID), logger.Err(err))
 		}
+		recordPlayerHealth(metricsRegistry, player.ID, pingMessage)
+
 		msg := knetwork.AckPingMessage{PingSendTime: pingMessage.SendTime}
 		err = player.Client.SendMessage(knetwork.MessageTypeAckPing, msg)
 		if err != nil {
-			fmt.Printf("error sending ackping message %s\n", err)
+			log.Error("failed to send ack ping message", logger.PlayerID(player.ID), logger.Err(err))
 		}
 	} else if message.MessageType == knetwork.MessageTypeRPC {
 		var rpcMessage knetwork.RPCMessage
-		err := network.DeserializeBody(message, &rpcMessage)
+		err := deserializeBody(message, &rpcMessage, timer)
 		if err != nil {
-			fmt.Printf("error deserializing ping body %s\n", err)
+			log.Error("failed to deserialize rpc message", logger.PlayerID(player.ID), logger.Err(err))
 		}
 		world.GetEventBroker().Broadcast(&events.RPCEvent{PlayerID: message.SenderID, Command: rpcMessage.Command})
 	} else {
-		fmt.Println("unknown message type:", message.MessageType, string(message.Body))
+		log.Warn("unknown message type", logger.PlayerID(player.ID), logger.F("type", message.MessageType), logger.F("body", string(message.Body)))
+	}
+}
+
+// recordPlayerHealth exports the network health reported by the player
+func recordPlayerHealth(metricsRegistry *metrics.MetricsRegistry, playerID int, pingMessage knetwork.PingMessage) {
+	playerLabel := metrics.L("player", playerID)
+	metricsRegistry.Gauge("kito_player_rtt_ms", playerLabel).Set(pingMessage.RTTMilliseconds)
+
+	predictions := pingMessage.PredictionHits + pingMessage.PredictionMisses
+	if predictions > 0 {
+		hitRatio := float64(pingMessage.PredictionHits) / float64(predictions)
+		metricsRegistry.Gauge("kito_player_prediction_hit_ratio", playerLabel).Set(hitRatio)
 	}
 }
 
@@ -59,19 +79,21 @@ func serverMessageHandler(world World, message *network.Message) {
 func handleCreatePlayer(player *player.Player, message *network.Message, world World) {
 	playerID := message.SenderID
 
+	// entities are assigned their IDs on registration, bob needs one before the camera
+	// can be pointed at it
 	bob := entities.NewBob()
+	world.RegisterEntities([]entities.Entity{bob})
 	player.EntityID = bob.ID
 
 	cc := bob.ComponentContainer
 
 	camera := entities.NewThirdPersonCamera(mgl64.Vec3{}, mgl64.Vec2{0, 0}, player.ID, player.EntityID)
 	cameraComponentContainer := camera.GetComponentContainer()
-	fmt.Println("Server camera initialized at position", cameraComponentContainer.TransformComponent.Position)
+	log.Debug("camera initialized", logger.PlayerID(player.ID), logger.F("position", cameraComponentContainer.TransformComponent.Position))
 
+	world.RegisterEntities([]entities.Entity{camera})
 	cc.ThirdPersonControllerComponent.CameraID = camera.GetID()
-
-	world.RegisterEntities([]entities.Entity{bob, camera})
-	fmt.Println("Created and registered a new bob with id", bob.ID)
+	log.Info("created player entity", logger.PlayerID(player.ID), logger.EntityID(bob.ID))
 
 	snapshots := map[int]knetwork.EntitySnapshot{}
 	for _, entity := range world.QueryEntity(components.ComponentFlagNetwork) {
@@ -84,12 +106,12 @@ func handleCreatePlayer(player *player.Player, message *network.Message, world W
 	ack := &knetwork.AckCreatePlayerMessage{
 		PlayerID:    playerID,
 		EntityID:    bob.ID,
-		CameraID:    camera.ID,
 		Position:    cc.TransformComponent.Position,
 		Orientation: cc.TransformComponent.Orientation,
 		Entities:    snapshots,
+		TimeControl: world.TimeControl(),
 	}
 
 	player.Client.SendMessage(network.MessageTypeAckCreatePlayer, ack)
-	fmt.Println("Sent entity ack creation message")
+	log.Debug("sent create player ack", logger.PlayerID(player.ID))
 }
@@ -13,6 +13,7 @@ import (
 	"github.com/kkevinchou/kito/kito/systems/base"
 	"github.com/kkevinchou/kito/kito/types"
 	"github.com/kkevinchou/kito/lib/metrics"
+	"github.com/kkevinchou/kito/lib/profiler"
 	"google.golang.org/protobuf/proto"
 )
 
@@ -28,49 +29,34 @@ type World interface {
 
 type NetworkInputSystem struct {
 	*base.BaseSystem
-	world    World
-	entities []entities.Entity
-	events   []events.Event
+	world         World
+	entities      []entities.Entity
+	commandEvents *eventbroker.Queue[*events.PlayerCommandEvent]
 }
 
 func NewNetworkInputSystem(world World) *NetworkInputSystem {
-	s := &NetworkInputSystem{
-		BaseSystem: &base.BaseSystem{},
-		world:      world,
+	return &NetworkInputSystem{
+		BaseSystem:    &base.BaseSystem{},
+		world:         world,
+		commandEvents: eventbroker.NewQueue[*events.PlayerCommandEvent](world.GetEventBroker(), 0),
 	}
-
-	eventBroker := world.GetEventBroker()
-	eventBroker.AddObserver(s, []events.EventType{
-		events.EventTypePlayerCommand,
-	})
-
-	return s
 }
 
-func (s *NetworkInputSystem) Observe(event events.Event) {
-	if event.Type() == events.EventTypePlayerCommand {
-		s.events = append(s.events, event)
-	}
-}
-
-func (s *NetworkInputSystem) clearEvents() {
-	s.events = nil
+func (s *NetworkInputSystem) Update(delta time.Duration) {
+	s.UpdateTimed(delta, profiler.Timer{})
 }
 
-func (s *NetworkInputSystem) Update(delta time.Duration) {
-	defer s.clearEvents()
+func (s *NetworkInputSystem) UpdateTimed(delta time.Duration, timer profiler.Timer) {
 	singleton := s.world.GetSingleton()
 
 	player := s.world.GetPlayer()
 	playerInput := singleton.PlayerInput[player.ID]
 
 	commandList := playercommand.PlayerCommandList{Commands: []*playercommand.Wrapper{}}
-	for _, e := range s.events {
-		if cmdEvent, ok := e.(*events.PlayerCommandEvent); ok {
-			commandList.Commands = append(commandList.Commands,
-				cmdEvent.Command,
-			)
-		}
+	for _, cmdEvent := range s.commandEvents.Drain() {
+		commandList.Commands = append(commandList.Commands,
+			cmdEvent.Command,
+		)
 	}
 
 	commandListBytes, err := proto.Marshal(&commandList)
@@ -82,10 +68,14 @@ func (s *NetworkInputSystem) Update(delta time.Duration) {
 		PlayerCommands: commandListBytes,
 		CommandFrame:   singleton.CommandFrame,
 		Input:          playerInput,
+		SpawnKey:       singleton.OutgoingSpawnKey,
 	}
+	singleton.OutgoingSpawnKey = 0
 
 	s.world.MetricsRegistry().Inc("newinput", 1)
+	encode := timer.Begin("network.encode")
 	player.Client.SendMessage(knetwork.MessageTypeInput, inputMessage)
+	encode.End()
 }
 
 func (s *NetworkInputSystem) Name() string {
@@ -7,7 +7,6 @@ import (
 	"github.com/kkevinchou/kito/kito/components"
 	"github.com/kkevinchou/kito/kito/directory"
 	"github.com/kkevinchou/kito/kito/entities"
-	"github.com/kkevinchou/kito/kito/events"
 	"github.com/kkevinchou/kito/kito/knetwork"
 	"github.com/kkevinchou/kito/kito/managers/eventbroker"
 	"github.com/kkevinchou/kito/kito/settings"
@@ -15,15 +14,19 @@ import (
 	"github.com/kkevinchou/kito/kito/systems/base"
 	"github.com/kkevinchou/kito/kito/types"
 	"github.com/kkevinchou/kito/kito/utils/entityutils"
+	"github.com/kkevinchou/kito/lib/logger"
 	"github.com/kkevinchou/kito/lib/metrics"
+	"github.com/kkevinchou/kito/lib/profiler"
 )
 
+var log = logger.New("networkupdate")
+
 type World interface {
 	RegisterEntities([]entities.Entity)
 	GetEventBroker() eventbroker.EventBroker
 	GetSingleton() *singleton.Singleton
 	CommandFrame() int
-	QueryEntity(componentFlags int) []entities.Entity
+	QueryEntity(componentFlags ...int) []entities.Entity
 	MetricsRegistry() *metrics.MetricsRegistry
 }
 
@@ -31,30 +34,20 @@ type NetworkUpdateSystem struct {
 	*base.BaseSystem
 	world         World
 	elapsedFrames int
-	events        []events.Event
 }
 
 func NewNetworkUpdateSystem(world World) *NetworkUpdateSystem {
-	networkUpdateSystem := &NetworkUpdateSystem{
+	return &NetworkUpdateSystem{
 		BaseSystem: &base.BaseSystem{},
 		world:      world,
 	}
-
-	eventBroker := world.GetEventBroker()
-	eventBroker.AddObserver(networkUpdateSystem, []events.EventType{
-		events.EventTypeUnregisterEntity,
-	})
-
-	return networkUpdateSystem
 }
 
-func (s *NetworkUpdateSystem) Observe(event events.Event) {
-	if event.Type() == events.EventTypeUnregisterEntity {
-		s.events = append(s.events, event)
-	}
+func (s *NetworkUpdateSystem) Update(delta time.Duration) {
+	s.UpdateTimed(delta, profiler.Timer{})
 }
 
-func (s *NetworkUpdateSystem) Update(delta time.Duration) {
+func (s *NetworkUpdateSystem) UpdateTimed(delta time.Duration, timer profiler.Timer) {
 	s.elapsedFrames++
 	if s.elapsedFrames < settings.CommandFramesPerServerUpdate {
 		return
@@ -62,32 +55,36 @@ func (s *NetworkUpdateSystem) Update(delta time.Duration) {
 
 	s.elapsedFrames %= settings.CommandFramesPerServerUpdate
 
+	metricsRegistry := s.world.MetricsRegistry()
+	frameTimes := metricsRegistry.Histogram("kito_frame_time_ms").Percentiles(0.5, 0.95, 0.99)
 	serverStats := map[string]string{
-		"fps":       fmt.Sprintf("%d", int(s.world.MetricsRegistry().GetOneSecondSum("fps"))),
-		"frametime": fmt.Sprintf("%d", int(s.world.MetricsRegistry().GetOneSecondAverage("frametime"))),
+		"fps":           fmt.Sprintf("%d", int(metricsRegistry.GetOneSecondSum("fps"))),
+		"frametime":     fmt.Sprintf("%d", int(metricsRegistry.GetOneSecondAverage("frametime"))),
+		"frametime p50": fmt.Sprintf("%.2f", frameTimes[0]),
+		"frametime p95": fmt.Sprintf("%.2f", frameTimes[1]),
+		"frametime p99": fmt.Sprintf("%.2f", frameTimes[2]),
 	}
+	metricsRegistry.Gauge("kito_entities").Set(float64(len(s.world.QueryEntity())))
 
 	gameStateUpdate := &knetwork.GameStateUpdateMessage{
 		Entities:    map[int]knetwork.EntitySnapshot{},
 		ServerStats: serverStats,
 	}
 
-	for _, entity := range s.world.QueryEntity(components.ComponentFlagTransform | components.ComponentFlagNetwork) {
+	for _, entity := range s.world.QueryEntity(components.ComponentFlagTransform, components.ComponentFlagNetwork) {
 		if entity.Type() == types.EntityTypeCamera {
 			continue
 		}
 		gameStateUpdate.Entities[entity.GetID()] = entityutils.ConstructEntitySnapshot(entity)
 	}
 
-	defer s.clearEvents()
-	for _, event := range s.events {
-		bytes, err := knetwork.Serialize(event)
+	// events flagged as replicated in the event registry are collected by the event broker
+	for _, event := range s.world.GetEventBroker().DrainReplicated() {
+		networkEvent, err := knetwork.SerializeEvent(event)
 		if err != nil {
-			fmt.Println("failed to serialize event", err)
+			log.Error("failed to serialize event", logger.Err(err))
 			continue
 		}
-
-		networkEvent := knetwork.Event{Type: event.Type(), Bytes: bytes}
 		gameStateUpdate.Events = append(gameStateUpdate.Events, networkEvent)
 	}
 
@@ -98,14 +95,12 @@ func (s *NetworkUpdateSystem) Update(delta time.Duration) {
 		gameStateUpdate.LastInputCommandFrame = player.LastInputLocalCommandFrame
 		gameStateUpdate.LastInputGlobalCommandFrame = player.LastInputGlobalCommandFrame
 		gameStateUpdate.CurrentGlobalCommandFrame = s.world.CommandFrame()
+		encode := timer.Begin("network.encode")
 		player.Client.SendMessage(knetwork.MessageTypeGameStateUpdate, gameStateUpdate)
+		encode.End()
 	}
 }
 
-func (s *NetworkUpdateSystem) clearEvents() {
-	s.events = nil
-}
-
 func (s *NetworkUpdateSystem) Name() string {
 	return "NetworkUpdateSystem"
 }
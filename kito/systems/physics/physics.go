@@ -3,6 +3,7 @@ package physics
 import (
 	"time"
 
+	"github.com/kkevinchou/kito/kito/broadphase"
 	"github.com/kkevinchou/kito/kito/components"
 	"github.com/kkevinchou/kito/kito/netsync"
 	"github.com/kkevinchou/kito/kito/singleton"
@@ -15,7 +16,9 @@ import (
 type World interface {
 	GetSingleton() *singleton.Singleton
 	GetPlayerEntity() entities.Entity
-	QueryEntity(componentFlags int) []entities.Entity
+	GetEntityByID(id int) entities.Entity
+	QueryEntity(componentFlags ...int) []entities.Entity
+	Broadphase() *broadphase.Broadphase
 }
 
 type PhysicsSystem struct {
@@ -31,13 +34,16 @@ func NewPhysicsSystem(world World) *PhysicsSystem {
 }
 
 func (s *PhysicsSystem) Update(delta time.Duration) {
-	// physics simulation is done on the server and the results are synchronized to the client
-	if utils.IsClient() {
-		return
-	}
-
-	for _, entity := range s.world.QueryEntity(components.ComponentFlagPhysics | components.ComponentFlagTransform) {
-		netsync.PhysicsStep(delta, entity)
+	// physics simulation is done on the server and the results are synchronized to the
+	// client. the exception is predicted entities which the server doesn't know about yet
+	for _, entity := range s.world.QueryEntity(components.ComponentFlagPhysics, components.ComponentFlagTransform) {
+		if utils.IsClient() {
+			networkComponent := entity.GetComponentContainer().NetworkComponent
+			if networkComponent == nil || !networkComponent.Predicted {
+				continue
+			}
+		}
+		netsync.PhysicsStep(delta, entity, s.world)
 	}
 }
 
@@ -1,16 +1,20 @@
 package ping
 
 import (
-	"fmt"
 	"time"
 
 	"github.com/kkevinchou/kito/kito/knetwork"
 	"github.com/kkevinchou/kito/kito/managers/player"
 	"github.com/kkevinchou/kito/kito/systems/base"
+	"github.com/kkevinchou/kito/lib/logger"
+	"github.com/kkevinchou/kito/lib/metrics"
 )
 
+var log = logger.New("ping")
+
 type World interface {
 	GetPlayer() *player.Player
+	MetricsRegistry() *metrics.MetricsRegistry
 }
 
 type PingSystem struct {
@@ -33,14 +37,19 @@ func (s *PingSystem) Update(delta time.Duration) {
 	}
 
 	player := s.world.GetPlayer()
+	metricsRegistry := s.world.MetricsRegistry()
 
 	pingMessage := &knetwork.PingMessage{
 		SendTime: time.Now(),
+
+		RTTMilliseconds:  metricsRegistry.GetOneSecondAverage("ping"),
+		PredictionHits:   int(metricsRegistry.GetOneSecondSum("predictionHit")),
+		PredictionMisses: int(metricsRegistry.GetOneSecondSum("predictionMiss")),
 	}
 
 	err := player.Client.SendMessage(knetwork.MessageTypePing, pingMessage)
 	if err != nil {
-		fmt.Printf("error sending ping message: %s\nshutting down ping system\n", err)
+		log.Error("failed to send ping message, shutting down the ping system", logger.Err(err))
 		s.enabled = false
 	}
 }
@@ -1,7 +1,6 @@
 package playerinput
 
 import (
-	"fmt"
 	"time"
 
 	"github.com/kkevinchou/kito/kito/directory"
@@ -9,8 +8,11 @@ import (
 	"github.com/kkevinchou/kito/kito/managers/player"
 	"github.com/kkevinchou/kito/kito/singleton"
 	"github.com/kkevinchou/kito/kito/systems/base"
+	"github.com/kkevinchou/kito/lib/logger"
 )
 
+var log = logger.New("playerinput")
+
 type World interface {
 	CommandFrame() int
 	GetSingleton() *singleton.Singleton
@@ -51,8 +53,9 @@ func handlePlayerInput(player *player.Player, commandFrame int, bufferedInput *i
 		singleton := world.GetSingleton()
 		singleton.PlayerInput[player.ID] = bufferedInput.Input
 		singleton.PlayerCommands[player.ID] = bufferedInput.PlayerCommands
+		singleton.PlayerSpawnKeys[player.ID] = bufferedInput.SpawnKey
 	} else {
-		fmt.Printf("received input out of order, last saw %d but got %d\n", player.LastInputLocalCommandFrame, commandFrame)
+		log.Warn("received input out of order", logger.PlayerID(player.ID), logger.F("last", player.LastInputLocalCommandFrame), logger.F("received", commandFrame))
 	}
 }
 
@@ -1,18 +1,22 @@
 package playerregistration
 
 import (
-	"fmt"
 	"time"
 
 	"github.com/kkevinchou/kito/kito/directory"
 	"github.com/kkevinchou/kito/kito/settings"
 	"github.com/kkevinchou/kito/kito/systems/base"
 	"github.com/kkevinchou/kito/kito/types"
+	"github.com/kkevinchou/kito/lib/logger"
+	"github.com/kkevinchou/kito/lib/metrics"
 	"github.com/kkevinchou/kito/lib/network"
 )
 
+var log = logger.New("playerregistration")
+
 type World interface {
 	CommandFrame() int
+	MetricsRegistry() *metrics.MetricsRegistry
 }
 
 type PlayerRegistrationSystem struct {
@@ -39,14 +43,21 @@ func (s *PlayerRegistrationSystem) Update(delta time.Duration) {
 
 	incomingConnections := s.nserver.PullIncomingConnections()
 	for _, incomingConnection := range incomingConnections {
-		fmt.Println("New player connected with id", incomingConnection.ID)
+		log.Info("player connected", logger.PlayerID(incomingConnection.ID))
 
 		client := network.NewClient(settings.ServerID, incomingConnection.Connection)
 		client.SetCommandFrameFunction(s.world.CommandFrame)
+		client.SetSendObserver(func(messageType int, size int) {
+			s.world.MetricsRegistry().Histogram("kito_message_size_bytes", metrics.L("direction", "sent"), metrics.L("type", messageType)).Observe(float64(size))
+		})
 
 		var playerClient types.NetworkClient = client
 		playerManager.RegisterPlayer(incomingConnection.ID, playerClient)
 	}
+
+	if len(incomingConnections) > 0 {
+		s.world.MetricsRegistry().Gauge("kito_players").Set(float64(len(playerManager.GetPlayers())))
+	}
 }
 
 func (s *PlayerRegistrationSystem) Name() string {
@@ -3,16 +3,16 @@ package preframe
 import (
 	"time"
 
+	"github.com/kkevinchou/kito/kito/broadphase"
 	"github.com/kkevinchou/kito/kito/entities"
 	"github.com/kkevinchou/kito/kito/singleton"
-	"github.com/kkevinchou/kito/kito/spatialpartition"
 	"github.com/kkevinchou/kito/kito/systems/base"
 )
 
 type World interface {
 	GetSingleton() *singleton.Singleton
-	QueryEntity(componentFlags int) []entities.Entity
-	SpatialPartition() *spatialpartition.SpatialPartition
+	QueryEntity(componentFlags ...int) []entities.Entity
+	Broadphase() *broadphase.Broadphase
 	GetPlayerEntity() entities.Entity
 	GetEntityByID(id int) entities.Entity
 }
@@ -30,8 +30,7 @@ func NewPreFrameSystem(world World) *PreFrameSystem {
 }
 
 func (s *PreFrameSystem) Update(delta time.Duration) {
-	s.world.SpatialPartition().FrameSetup(s.world)
-
+	s.world.Broadphase().Sync()
 }
 
 func (s *PreFrameSystem) Name() string {
@@ -0,0 +1,125 @@
+package render
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/inkyblackness/imgui-go/v4"
+	"github.com/kkevinchou/kito/kito/components"
+	"github.com/kkevinchou/kito/kito/entities"
+	"github.com/kkevinchou/kito/kito/events"
+	"github.com/kkevinchou/kito/kito/types"
+)
+
+// inspectorWindow lists every entity and shows the fields of the selected entity's
+// components as described by the component schemas. Edits are sent to the server, which
+// validates them and applies them to its own entity. They're only applied locally once
+// the server acknowledges them so that a rejected edit can't leave the client diverged
+func (s *RenderSystem) inspectorWindow() {
+	imgui.SetNextWindowBgAlpha(0.8)
+	imgui.BeginV("Inspector", nil, imgui.WindowFlagsNoFocusOnAppearing)
+
+	entityList := s.world.QueryEntity()
+	sort.Slice(entityList, func(i, j int) bool {
+		return entityList[i].GetID() < entityList[j].GetID()
+	})
+
+	imgui.BeginChildV("entities", imgui.Vec2{X: 200, Y: 0}, true, 0)
+	for _, entity := range entityList {
+		label := fmt.Sprintf("%d %s", entity.GetID(), entity.GetName())
+		if imgui.SelectableV(label, entity.GetID() == s.inspectedEntityID, 0, imgui.Vec2{}) {
+			s.inspectedEntityID = entity.GetID()
+			s.inspectorEdits = map[string]string{}
+		}
+	}
+	imgui.EndChild()
+
+	imgui.SameLine()
+
+	imgui.BeginChildV("components", imgui.Vec2{}, false, 0)
+	if entity := s.world.GetEntityByID(s.inspectedEntityID); entity != nil {
+		s.inspectEntity(entity)
+	}
+	imgui.EndChild()
+
+	if imgui.IsWindowFocused() {
+		s.world.SetFocusedWindow(types.WindowInspector)
+	}
+	imgui.End()
+}
+
+func (s *RenderSystem) inspectEntity(entity entities.Entity) {
+	cc := entity.GetComponentContainer()
+	for _, component := range cc.Components() {
+		schema, ok := components.SchemaByFlag(component.ComponentFlag())
+		if !ok {
+			continue
+		}
+
+		label := schema.Name
+		if schema.Synchronized {
+			label += " (synchronized)"
+		}
+		if !imgui.CollapsingHeaderV(label, imgui.TreeNodeFlagsCollapsingHeader|imgui.TreeNodeFlagsDefaultOpen) {
+			continue
+		}
+
+		imgui.BeginTableV(schema.Name, 2, imgui.TableFlagsBorders, imgui.Vec2{}, 0)
+		for _, field := range schema.Fields {
+			imgui.TableNextRow()
+			imgui.TableSetColumnIndex(0)
+			imgui.Text(field.Name)
+			imgui.TableSetColumnIndex(1)
+			s.inspectField(entity, schema, field, component)
+		}
+		imgui.EndTable()
+	}
+}
+
+func (s *RenderSystem) inspectField(entity entities.Entity, schema *components.ComponentSchema, field components.FieldSchema, component components.Component) {
+	current := field.Format(component)
+	if field.ReadOnly {
+		imgui.Text(current)
+		return
+	}
+
+	id := fmt.Sprintf("##%d_%s_%s", entity.GetID(), schema.Name, field.Name)
+
+	if field.Kind == components.FieldKindBool {
+		value, _ := strconv.ParseBool(current)
+		if imgui.Checkbox(id, &value) {
+			s.submitFieldEdit(entity, schema, field, strconv.FormatBool(value))
+		}
+		return
+	}
+
+	// the text being typed is kept across frames until it's submitted, otherwise it would
+	// be overwritten by the live value every frame
+	text, editing := s.inspectorEdits[id]
+	if !editing {
+		text = current
+	}
+
+	imgui.PushItemWidth(-1)
+	submitted := imgui.InputTextV(id, &text, imgui.InputTextFlagsEnterReturnsTrue, nil)
+	imgui.PopItemWidth()
+
+	if submitted {
+		delete(s.inspectorEdits, id)
+		s.submitFieldEdit(entity, schema, field, text)
+	} else if imgui.IsItemActive() {
+		s.inspectorEdits[id] = text
+	} else {
+		delete(s.inspectorEdits, id)
+	}
+
+	if field.HasRange && imgui.IsItemHovered() {
+		imgui.SetTooltipf("[%g, %g]", field.Min, field.Max)
+	}
+}
+
+func (s *RenderSystem) submitFieldEdit(entity entities.Entity, schema *components.ComponentSchema, field components.FieldSchema, value string) {
+	command := fmt.Sprintf("component set %d %s %s %s", entity.GetID(), schema.Name, field.Name, value)
+	s.world.GetEventBroker().Broadcast(&events.RPCEvent{Command: command})
+}
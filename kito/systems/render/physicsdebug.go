@@ -0,0 +1,208 @@
+package render
+
+import (
+	"fmt"
+
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/inkyblackness/imgui-go/v4"
+	"github.com/kkevinchou/kito/kito/events"
+	"github.com/kkevinchou/kito/kito/settings"
+	"github.com/kkevinchou/kito/kito/utils"
+	"github.com/kkevinchou/kito/lib/collision/collider"
+	"github.com/kkevinchou/kito/lib/physicsdebug"
+	"github.com/kkevinchou/kito/lib/shaders"
+)
+
+const (
+	physicsDebugNormalLength float64 = 10
+	physicsDebugPointSize    float64 = 1
+)
+
+var (
+	physicsDebugPairColor      = mgl64.Vec3{0.8, 0, 0.8}
+	physicsDebugContactColor   = mgl64.Vec3{1, 0, 0}
+	physicsDebugNormalColor    = mgl64.Vec3{0, 0, 1}
+	physicsDebugSeparatorColor = mgl64.Vec3{1, 0.5, 0}
+	physicsDebugSweepColor     = mgl64.Vec3{1, 1, 1}
+	physicsDebugTriangleColor  = mgl64.Vec3{1, 1, 0}
+)
+
+// physicsDebugUIComponent shows what the physics debugger recorded for the selected
+// frame. Pausing and stepping the simulation from here keeps the frame being inspected
+// from being pushed out by new ones
+func (s *RenderSystem) physicsDebugUIComponent() {
+	if !imgui.CollapsingHeaderV("Physics", imgui.TreeNodeFlagsCollapsingHeader) {
+		return
+	}
+
+	enabled := physicsdebug.GlobalRecorder.Enabled()
+	if imgui.Checkbox("Record##physics", &enabled) {
+		physicsdebug.GlobalRecorder.SetEnabled(enabled)
+	}
+	imgui.SameLine()
+	imgui.Checkbox("Draw##physics", &settings.DebugRenderPhysics)
+	imgui.SameLine()
+	imgui.Checkbox("Follow##physics", &s.physicsDebugFollow)
+
+	if s.world.TimeControl().Paused {
+		if imgui.Button("Resume") {
+			s.world.GetEventBroker().Broadcast(&events.RPCEvent{Command: "resume"})
+		}
+		imgui.SameLine()
+		if imgui.Button("Step") {
+			s.world.GetEventBroker().Broadcast(&events.RPCEvent{Command: "step"})
+			s.physicsDebugFollow = true
+		}
+	} else if imgui.Button("Pause") {
+		s.world.GetEventBroker().Broadcast(&events.RPCEvent{Command: "pause"})
+	}
+
+	frames := physicsdebug.GlobalRecorder.Frames()
+	if len(frames) == 0 {
+		return
+	}
+	s.selectPhysicsDebugFrame(frames)
+
+	selected := int32(s.physicsDebugSelectedFrame)
+	if imgui.SliderInt("Frame##physics", &selected, 0, int32(len(frames)-1)) {
+		s.physicsDebugSelectedFrame = int(selected)
+		s.physicsDebugFollow = false
+	}
+
+	frame := frames[s.physicsDebugSelectedFrame]
+	imgui.Text(fmt.Sprintf("CF %d, %d pairs, %d contacts, %d sweeps", frame.CommandFrame, len(frame.Pairs), len(frame.Contacts), len(frame.Sweeps)))
+
+	if len(frame.Resolutions) > 0 && imgui.TreeNode("Resolutions") {
+		imgui.BeginTableV("resolutions", 3, imgui.TableFlagsBorders, imgui.Vec2{}, 0)
+		uiTableHeaders("Entity", "Count", "Maxed")
+		for _, resolution := range frame.Resolutions {
+			uiTableColumns(resolution.EntityID, resolution.Count, resolution.Maxed)
+		}
+		imgui.EndTable()
+		imgui.TreePop()
+	}
+
+	if len(frame.Contacts) > 0 && imgui.TreeNode("Contacts") {
+		imgui.BeginTableV("contacts", 5, imgui.TableFlagsBorders, imgui.Vec2{}, 0)
+		uiTableHeaders("Entity", "Source", "Iteration", "Separation", "Normal")
+		for _, contact := range frame.Contacts {
+			uiTableColumns(contact.EntityID, contact.SourceEntityID, contact.Iteration, fmt.Sprintf("%.4f", contact.SeparatingDistance), utils.PPrintVec(contact.Normal))
+		}
+		imgui.EndTable()
+		imgui.TreePop()
+	}
+
+	if len(frame.Sweeps) > 0 && imgui.TreeNode("Sweeps") {
+		imgui.BeginTableV("sweeps", 4, imgui.TableFlagsBorders, imgui.Vec2{}, 0)
+		uiTableHeaders("Entity", "Hit", "Time of Impact", "Normal")
+		for _, sweep := range frame.Sweeps {
+			uiTableColumns(sweep.EntityID, sweep.HitEntityID, fmt.Sprintf("%.4f", sweep.TimeOfImpact), utils.PPrintVec(sweep.Normal))
+		}
+		imgui.EndTable()
+		imgui.TreePop()
+	}
+}
+
+// selectPhysicsDebugFrame keeps the selected frame within the recorded frames. The newest
+// frame may still be being recorded so the one before it is selected when following
+func (s *RenderSystem) selectPhysicsDebugFrame(frames []physicsdebug.Frame) {
+	if s.physicsDebugFollow || s.physicsDebugSelectedFrame >= len(frames) {
+		s.physicsDebugSelectedFrame = len(frames) - 1
+		if len(frames) > 1 && !s.world.TimeControl().Paused {
+			s.physicsDebugSelectedFrame--
+		}
+	}
+}
+
+// drawPhysicsDebug draws the broadphase pairs, contacts, sweeps and triangles that were
+// hit on the selected frame
+func (s *RenderSystem) drawPhysicsDebug(viewerContext ViewerContext, shader *shaders.ShaderProgram) {
+	// drawing needs something to draw so the recorder is turned on with it
+	if !physicsdebug.GlobalRecorder.Enabled() {
+		physicsdebug.GlobalRecorder.SetEnabled(true)
+	}
+
+	frames := physicsdebug.GlobalRecorder.Frames()
+	if len(frames) == 0 {
+		return
+	}
+	s.selectPhysicsDebugFrame(frames)
+	frame := frames[s.physicsDebugSelectedFrame]
+
+	var pairLines [][]mgl64.Vec3
+	for _, pair := range frame.Pairs {
+		e1, e2 := s.world.GetEntityByID(pair[0]), s.world.GetEntityByID(pair[1])
+		if e1 == nil || e2 == nil {
+			continue
+		}
+		pairLines = append(pairLines, []mgl64.Vec3{
+			e1.GetComponentContainer().TransformComponent.Position,
+			e2.GetComponentContainer().TransformComponent.Position,
+		})
+	}
+
+	var pointLines, normalLines, separatorLines, sweepLines, triangleLines [][]mgl64.Vec3
+	for _, contact := range frame.Contacts {
+		pointLines = append(pointLines, pointMarkerLines(contact.Point)...)
+		normalLines = append(normalLines, []mgl64.Vec3{contact.Point, contact.Point.Add(contact.Normal.Mul(physicsDebugNormalLength))})
+		if contact.SeparatingVector.LenSqr() > 0 {
+			separatorLines = append(separatorLines, []mgl64.Vec3{contact.Point, contact.Point.Add(contact.SeparatingVector)})
+		}
+		triangleLines = append(triangleLines, triangleEdgeLines(contact.Triangle)...)
+	}
+
+	for _, sweep := range frame.Sweeps {
+		if end := sweep.Start.Add(sweep.Displacement.Mul(sweep.TimeOfImpact)); end.Sub(sweep.Start).LenSqr() > 0 {
+			sweepLines = append(sweepLines, []mgl64.Vec3{sweep.Start, end})
+		}
+		pointLines = append(pointLines, pointMarkerLines(sweep.Point)...)
+		normalLines = append(normalLines, []mgl64.Vec3{sweep.Point, sweep.Point.Add(sweep.Normal.Mul(physicsDebugNormalLength))})
+		triangleLines = append(triangleLines, triangleEdgeLines(sweep.Triangle)...)
+	}
+
+	thickness := settings.DefaultLineThickness
+	drawLines(viewerContext, shader, pairLines, thickness, physicsDebugPairColor)
+	drawLines(viewerContext, shader, triangleLines, thickness, physicsDebugTriangleColor)
+	drawLines(viewerContext, shader, sweepLines, thickness, physicsDebugSweepColor)
+	drawLines(viewerContext, shader, separatorLines, thickness, physicsDebugSeparatorColor)
+	drawLines(viewerContext, shader, normalLines, thickness, physicsDebugNormalColor)
+	drawLines(viewerContext, shader, pointLines, thickness, physicsDebugContactColor)
+}
+
+// pointMarkerLines returns a small cross at the point along each axis
+func pointMarkerLines(point mgl64.Vec3) [][]mgl64.Vec3 {
+	var lines [][]mgl64.Vec3
+	for axis := 0; axis < 3; axis++ {
+		var offset mgl64.Vec3
+		offset[axis] = physicsDebugPointSize
+		lines = append(lines, []mgl64.Vec3{point.Sub(offset), point.Add(offset)})
+	}
+	return lines
+}
+
+func triangleEdgeLines(triangle *collider.Triangle) [][]mgl64.Vec3 {
+	if triangle == nil {
+		return nil
+	}
+
+	var lines [][]mgl64.Vec3
+	for i := range triangle.Points {
+		lines = append(lines, []mgl64.Vec3{triangle.Points[i], triangle.Points[(i+1)%len(triangle.Points)]})
+	}
+	return lines
+}
+
+func uiTableHeaders(labels ...string) {
+	for _, label := range labels {
+		imgui.TableSetupColumn(label)
+	}
+	imgui.TableHeadersRow()
+}
+
+func uiTableColumns(values ...any) {
+	imgui.TableNextRow()
+	for i, value := range values {
+		imgui.TableSetColumnIndex(i)
+		imgui.Text(fmt.Sprintf("%v", value))
+	}
+}
@@ -0,0 +1,147 @@
+package render
+
+import (
+	"fmt"
+	"hash/fnv"
+	"time"
+
+	"github.com/inkyblackness/imgui-go/v4"
+	"github.com/kkevinchou/kito/lib/logger"
+	"github.com/kkevinchou/kito/lib/profiler"
+)
+
+const (
+	profilerRowHeight   float32 = 18
+	profilerGraphHeight float32 = 60
+)
+
+// profilerUIComponent graphs the duration of recently recorded frames and draws the
+// scopes of the selected frame as a timeline, one row per nesting depth of each track
+func (s *RenderSystem) profilerUIComponent() {
+	if !imgui.CollapsingHeaderV("Profiler", imgui.TreeNodeFlagsCollapsingHeader) {
+		return
+	}
+
+	enabled := profiler.GlobalProfiler.Enabled()
+	if imgui.Checkbox("Record", &enabled) {
+		profiler.GlobalProfiler.SetEnabled(enabled)
+	}
+	imgui.SameLine()
+	imgui.Checkbox("Follow", &s.profilerFollow)
+	imgui.SameLine()
+	if imgui.Button("Export") {
+		path := profiler.DefaultExportPath()
+		if err := profiler.GlobalProfiler.ExportChromeTrace(path); err != nil {
+			log.Error("failed to export profile", logger.Err(err))
+		} else {
+			log.Info("exported profile", logger.F("path", path))
+		}
+	}
+
+	frames := profiler.GlobalProfiler.Frames()
+	if len(frames) == 0 {
+		return
+	}
+
+	// the newest frame is still being recorded so the one before it is shown when following
+	if s.profilerFollow || s.profilerSelectedFrame >= len(frames) {
+		s.profilerSelectedFrame = len(frames) - 1
+		if len(frames) > 1 {
+			s.profilerSelectedFrame--
+		}
+	}
+
+	durations := make([]float32, len(frames))
+	var longest float32
+	for i, frame := range frames {
+		durations[i] = float32(frame.Duration()) / float32(time.Millisecond)
+		if durations[i] > longest {
+			longest = durations[i]
+		}
+	}
+	overlay := fmt.Sprintf("max %.2fms", longest)
+	imgui.PlotHistogramV("##frames", durations, 0, overlay, 0, longest, imgui.Vec2{X: imgui.ContentRegionAvail().X, Y: profilerGraphHeight})
+
+	selected := int32(s.profilerSelectedFrame)
+	if imgui.SliderInt("Frame", &selected, 0, int32(len(frames)-1)) {
+		s.profilerSelectedFrame = int(selected)
+		s.profilerFollow = false
+	}
+
+	frame := frames[s.profilerSelectedFrame]
+	imgui.Text(fmt.Sprintf("CF %d, %.3fms", frame.CommandFrame, durations[s.profilerSelectedFrame]))
+	drawProfilerTimeline(frame)
+}
+
+func drawProfilerTimeline(frame profiler.Frame) {
+	duration := frame.Duration()
+	if duration <= 0 {
+		return
+	}
+
+	// rows are laid out track by track, each track taking as many rows as it nests
+	rowOffsets := map[int]int{}
+	rows := 0
+	tracks := map[int]int{}
+	for _, scope := range frame.Scopes {
+		if scope.Depth+1 > tracks[scope.Track] {
+			tracks[scope.Track] = scope.Depth + 1
+		}
+	}
+	for track := 0; len(rowOffsets) < len(tracks); track++ {
+		if depth, ok := tracks[track]; ok {
+			rowOffsets[track] = rows
+			rows += depth
+		}
+	}
+
+	origin := imgui.CursorScreenPos()
+	width := imgui.ContentRegionAvail().X
+	scale := width / float32(duration)
+
+	drawList := imgui.WindowDrawList()
+	mouse := imgui.MousePos()
+	hovered := ""
+	for _, scope := range frame.Scopes {
+		min := imgui.Vec2{
+			X: origin.X + float32(scope.Start)*scale,
+			Y: origin.Y + float32(rowOffsets[scope.Track]+scope.Depth)*profilerRowHeight,
+		}
+		max := imgui.Vec2{
+			X: origin.X + float32(scope.End)*scale,
+			Y: min.Y + profilerRowHeight - 1,
+		}
+		if max.X-min.X < 1 {
+			max.X = min.X + 1
+		}
+
+		drawList.AddRectFilled(min, max, scopeColor(scope.Name))
+		if textSize := imgui.CalcTextSize(scope.Name, false, 0); textSize.X < max.X-min.X-4 {
+			drawList.AddText(imgui.Vec2{X: min.X + 2, Y: min.Y + 2}, imgui.PackedColorFromVec4(imgui.Vec4{X: 0, Y: 0, Z: 0, W: 1}), scope.Name)
+		}
+
+		if mouse.X >= min.X && mouse.X <= max.X && mouse.Y >= min.Y && mouse.Y <= max.Y {
+			hovered = fmt.Sprintf("%s\n%.1fus", scope.Name, float64(scope.Duration())/float64(time.Microsecond))
+		}
+	}
+
+	imgui.Dummy(imgui.Vec2{X: width, Y: float32(rows) * profilerRowHeight})
+	if hovered != "" && imgui.IsItemHovered() {
+		imgui.SetTooltip(hovered)
+	}
+}
+
+// scopeColor picks a stable color for a scope name so that the same scope is easy to
+// follow between frames
+func scopeColor(name string) imgui.PackedColor {
+	hash := fnv.New32a()
+	hash.Write([]byte(name))
+	h := hash.Sum32()
+
+	return imgui.PackedColorFromVec4(imgui.Vec4{
+		X: 0.4 + float32(h&0xff)/255*0.6,
+		Y: 0.4 + float32((h>>8)&0xff)/255*0.6,
+		Z: 0.4 + float32((h>>16)&0xff)/255*0.6,
+		W: 1,
+	})
+}
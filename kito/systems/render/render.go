@@ -9,22 +9,27 @@ import (
 	"github.com/go-gl/gl/v4.1-core/gl"
 	"github.com/go-gl/mathgl/mgl64"
 	"github.com/inkyblackness/imgui-go/v4"
+	"github.com/kkevinchou/kito/kito/broadphase"
 	"github.com/kkevinchou/kito/kito/components"
 	"github.com/kkevinchou/kito/kito/directory"
 	"github.com/kkevinchou/kito/kito/entities"
 	"github.com/kkevinchou/kito/kito/events"
+	"github.com/kkevinchou/kito/kito/knetwork"
 	"github.com/kkevinchou/kito/kito/managers/eventbroker"
 	"github.com/kkevinchou/kito/kito/settings"
 	"github.com/kkevinchou/kito/kito/singleton"
-	"github.com/kkevinchou/kito/kito/spatialpartition"
 	"github.com/kkevinchou/kito/kito/systems/base"
 	"github.com/kkevinchou/kito/kito/types"
 	"github.com/kkevinchou/kito/lib/libutils"
+	"github.com/kkevinchou/kito/lib/logger"
 	"github.com/kkevinchou/kito/lib/metrics"
+	"github.com/kkevinchou/kito/lib/profiler"
 	"github.com/veandco/go-sdl2/sdl"
 	"github.com/veandco/go-sdl2/ttf"
 )
 
+var log = logger.New("render")
+
 const (
 	fovx float64 = 105
 	near float64 = 1
@@ -40,14 +45,15 @@ type World interface {
 	GetEntityByID(id int) entities.Entity
 	GetPlayerEntity() entities.Entity
 	MetricsRegistry() *metrics.MetricsRegistry
-	QueryEntity(componentFlags int) []entities.Entity
+	QueryEntity(componentFlags ...int) []entities.Entity
 	CommandFrame() int
 	SetFocusedWindow(focusedWindow types.Window)
 	GetFocusedWindow() types.Window
 	GetWindowVisibility(types.Window) bool
 	GetEventBroker() eventbroker.EventBroker
-	SpatialPartition() *spatialpartition.SpatialPartition
+	Broadphase() *broadphase.Broadphase
 	ServerStats() map[string]string
+	TimeControl() knetwork.TimeControlMessage
 }
 
 type Platform interface {
@@ -72,8 +78,17 @@ type RenderSystem struct {
 	imguiRenderer *ImguiOpenGL4Renderer
 	platform      Platform
 
-	entities []entities.Entity
-	events   []events.Event
+	entities             []entities.Entity
+	consoleEnabledEvents *eventbroker.Queue[*events.ConsoleEnabledEvent]
+
+	inspectedEntityID int
+	inspectorEdits    map[string]string
+
+	profilerFollow        bool
+	profilerSelectedFrame int
+
+	physicsDebugFollow        bool
+	physicsDebugSelectedFrame int
 
 	timeSoFar time.Duration
 }
@@ -127,24 +142,14 @@ func NewRenderSystem(world World, window *sdl.Window, platform Platform, imguiIO
 
 		platform:      platform,
 		imguiRenderer: imguiRenderer,
-	}
-
-	eventBroker := world.GetEventBroker()
-	eventBroker.AddObserver(&renderSystem, []events.EventType{
-		events.EventTypeConsoleEnabled,
-	})
 
-	return &renderSystem
-}
-
-func (s *RenderSystem) Observe(event events.Event) {
-	if event.Type() == events.EventTypeConsoleEnabled {
-		s.events = append(s.events, event)
+		consoleEnabledEvents: eventbroker.NewQueue[*events.ConsoleEnabledEvent](world.GetEventBroker(), 0),
+		inspectorEdits:       map[string]string{},
+		profilerFollow:       true,
+		physicsDebugFollow:   true,
 	}
-}
 
-func (s *RenderSystem) clearEvents() {
-	s.events = nil
+	return &renderSystem
 }
 
 func (s *RenderSystem) GetCameraTransform() *components.TransformComponent {
@@ -154,7 +159,7 @@ func (s *RenderSystem) GetCameraTransform() *components.TransformComponent {
 	}
 	camera := s.world.GetEntityByID(singleton.CameraID)
 	if camera == nil {
-		fmt.Printf("render syste could not find camera with entity id %d\n", singleton.CameraID)
+		log.Warn("could not find camera", logger.EntityID(singleton.CameraID))
 		return nil
 	}
 	componentContainer := camera.GetComponentContainer()
@@ -163,7 +168,8 @@ func (s *RenderSystem) GetCameraTransform() *components.TransformComponent {
 
 func (s *RenderSystem) Render(delta time.Duration) {
 	s.timeSoFar += delta
-	defer s.clearEvents()
+	// events that weren't consumed by a window this frame are stale
+	defer s.consoleEnabledEvents.Drain()
 
 	transformComponent := s.GetCameraTransform()
 	if transformComponent == nil {
@@ -206,11 +212,21 @@ func (s *RenderSystem) Render(delta time.Duration) {
 		LightSpaceMatrix: lightProjectionMatrix.Mul4(lightViewMatrix),
 	}
 
+	timer := profiler.Begin("render.shadow")
 	s.renderToDepthMap(lightViewerContext, lightContext)
+	timer.End()
+
+	timer = profiler.Begin("render.scene")
 	s.renderToDisplay(cameraViewerContext, lightContext)
+	timer.End()
+
+	timer = profiler.Begin("render.imgui")
 	s.renderImgui()
+	timer.End()
 
+	timer = profiler.Begin("render.swap")
 	s.window.GLSwap()
+	timer.End()
 }
 
 func (s *RenderSystem) renderToDepthMap(viewerContext ViewerContext, lightContext LightContext) {
@@ -249,6 +265,9 @@ func (s *RenderSystem) renderImgui() {
 	if s.world.GetWindowVisibility(types.WindowInventory) {
 		s.inventoryWindow()
 	}
+	if s.world.GetWindowVisibility(types.WindowInspector) {
+		s.inspectorWindow()
+	}
 
 	imgui.Render()
 	s.imguiRenderer.Render(s.platform.DisplaySize(), s.platform.FramebufferSize(), imgui.RenderedDrawData())
@@ -317,7 +336,7 @@ func (s *RenderSystem) renderScene(viewerContext ViewerContext, lightContext Lig
 				continue
 			}
 
-			if settings.DebugRenderSpatialPartition {
+			if settings.DebugRenderBroadphase {
 				if componentContainer.ColliderComponent.BoundingBoxCollider != nil {
 					bb := componentContainer.ColliderComponent.BoundingBoxCollider.Transform(componentContainer.TransformComponent.Position)
 					drawAABB(
@@ -360,6 +379,14 @@ func (s *RenderSystem) renderScene(viewerContext ViewerContext, lightContext Lig
 						mgl64.Vec3{0.5, 1, 0},
 						componentContainer.ColliderComponent.TransformedTriMeshCollider,
 					)
+				} else if shape := componentContainer.ColliderComponent.TransformedConvexCollider(); shape != nil {
+					drawConvexCollider(
+						viewerContext,
+						shaderManager.GetShaderProgram("flat"),
+						mgl64.Vec3{0.5, 1, 0},
+						shape,
+						settings.DefaultLineThickness,
+					)
 				}
 			}
 		}
@@ -382,16 +409,20 @@ func (s *RenderSystem) renderScene(viewerContext ViewerContext, lightContext Lig
 		assetManager.GetTexture("back"),
 	)
 
-	if settings.DebugRenderSpatialPartition {
-		drawSpatialPartition(
+	if settings.DebugRenderBroadphase {
+		drawBroadphase(
 			viewerContext,
 			shaderManager.GetShaderProgram("flat"),
 			mgl64.Vec3{0.5, 1, 0},
-			s.world.SpatialPartition(),
+			s.world.Broadphase(),
 			settings.DefaultLineThickness,
 		)
 	}
 
+	if settings.DebugRenderPhysics {
+		s.drawPhysicsDebug(viewerContext, shaderManager.GetShaderProgram("flat"))
+	}
+
 	// var renderText string
 	// drawText(shaderManager.GetShaderProgram("quadtex"), assetManager.GetFont("robotomono-regular"), renderText, 0.8, 0)
 }
@@ -21,6 +21,8 @@ func (s *RenderSystem) debugWindow() {
 	s.networkInfoUIComponent()
 	s.entityInfoUIComponent()
 	s.serverStatsInfoComponent()
+	s.profilerUIComponent()
+	s.physicsDebugUIComponent()
 	if imgui.IsWindowFocused() {
 		s.world.SetFocusedWindow(types.WindowDebug)
 	}
@@ -28,12 +30,16 @@ func (s *RenderSystem) debugWindow() {
 }
 
 func (s *RenderSystem) consoleWindow() {
+	console.GlobalConsole.FlushLogs()
 	imgui.BeginV("Console", nil, imgui.WindowFlagsNoTitleBar)
 
 	imgui.PushItemWidth(-1)
 	imgui.PushStyleColor(imgui.StyleColorFrameBg, imgui.Vec4{X: 0.5, Y: 0.5, Z: 0.5, W: 1})
 	for _, consoleItem := range console.GlobalConsole.ConsoleHistory {
 		imgui.Textf("%s", consoleItem.Command)
+		if consoleItem.Output != "" {
+			imgui.Textf("%s", consoleItem.Output)
+		}
 	}
 	imgui.PopStyleColor()
 	imgui.Separator()
@@ -58,11 +64,8 @@ func (s *RenderSystem) consoleWindow() {
 		s.world.SetFocusedWindow(types.WindowConsole)
 	}
 
-	for _, e := range s.events {
-		if e.Type() == events.EventTypeConsoleEnabled {
-			imgui.SetKeyboardFocusHereV(-1)
-			break
-		}
+	if len(s.consoleEnabledEvents.Drain()) > 0 {
+		imgui.SetKeyboardFocusHereV(-1)
 	}
 
 	imgui.End()
@@ -181,6 +184,9 @@ func (s *RenderSystem) generalInfoComponent() {
 		uiTableRow("rendertime", fmt.Sprintf("%.3f", s.world.MetricsRegistry().GetOneSecondAverage("rendertime")))
 		// uiTableRow("Frame Catchup", frameCatchup)
 		uiTableRow("CF", s.world.CommandFrame())
+		timeControl := s.world.TimeControl()
+		uiTableRow("Time Scale", fmt.Sprintf("%.2f", timeControl.TimeScale))
+		uiTableRow("Paused", timeControl.Paused)
 		imgui.EndTable()
 	}
 }
@@ -2,14 +2,15 @@ package render
 
 import (
 	"fmt"
+	"math"
 
 	"github.com/go-gl/gl/v4.1-core/gl"
 	"github.com/go-gl/mathgl/mgl32"
 	"github.com/go-gl/mathgl/mgl64"
+	"github.com/kkevinchou/kito/kito/broadphase"
 	"github.com/kkevinchou/kito/kito/components"
 	"github.com/kkevinchou/kito/kito/directory"
 	"github.com/kkevinchou/kito/kito/settings"
-	"github.com/kkevinchou/kito/kito/spatialpartition"
 	"github.com/kkevinchou/kito/lib/collision/collider"
 	"github.com/kkevinchou/kito/lib/font"
 	"github.com/kkevinchou/kito/lib/libutils"
@@ -448,61 +449,75 @@ func drawTris(viewerContext ViewerContext, shader *shaders.ShaderProgram, points
 	gl.DrawArrays(gl.TRIANGLES, 0, int32(len(vertices)))
 }
 
-func drawSpatialPartition(viewerContext ViewerContext, shader *shaders.ShaderProgram, color mgl64.Vec3, spatialPartition *spatialpartition.SpatialPartition, thickness float64) {
+// drawBroadphase draws the fattened bounds of every node of the broadphase's tree
+func drawBroadphase(viewerContext ViewerContext, shader *shaders.ShaderProgram, color mgl64.Vec3, broadphase *broadphase.Broadphase, thickness float64) {
 	var allLines [][]mgl64.Vec3
+	broadphase.Tree().Walk(func(bounds collider.BoundingBox, depth int, leaf bool) {
+		allLines = append(allLines, aabbLines(&bounds)...)
+	})
 
-	d := spatialPartition.PartitionDimension * spatialPartition.PartitionCount
-
-	var baseHorizontalLines [][]mgl64.Vec3
-
-	// lines along z axis
-	for i := 0; i < spatialPartition.PartitionCount+1; i++ {
-		baseHorizontalLines = append(baseHorizontalLines,
-			[]mgl64.Vec3{{float64(-d/2 + i*spatialPartition.PartitionDimension), float64(-d / 2), float64(-d / 2)}, {float64(-d/2 + i*spatialPartition.PartitionDimension), float64(-d / 2), float64(d / 2)}},
-		)
-	}
-
-	// // lines along x axis
-	for i := 0; i < spatialPartition.PartitionCount+1; i++ {
-		baseHorizontalLines = append(baseHorizontalLines,
-			[]mgl64.Vec3{{float64(-d / 2), float64(-d / 2), float64(-d/2 + i*spatialPartition.PartitionDimension)}, {float64(d / 2), float64(-d / 2), float64(-d/2 + i*spatialPartition.PartitionDimension)}},
-		)
-	}
+	drawLines(
+		viewerContext,
+		shader,
+		allLines,
+		thickness,
+		color,
+	)
+}
 
-	for i := 0; i < spatialPartition.PartitionCount+1; i++ {
-		for _, b := range baseHorizontalLines {
-			allLines = append(allLines,
-				[]mgl64.Vec3{b[0].Add(mgl64.Vec3{0, float64(i * spatialPartition.PartitionDimension), 0}), b[1].Add(mgl64.Vec3{0, float64(i * spatialPartition.PartitionDimension), 0})},
-			)
+// drawConvexCollider draws the edges of boxes and convex hulls, and three rings around
+// spheres
+func drawConvexCollider(viewerContext ViewerContext, shader *shaders.ShaderProgram, color mgl64.Vec3, shape collider.Convex, thickness float64) {
+	var lines [][]mgl64.Vec3
+	switch s := shape.(type) {
+	case collider.Box:
+		vertices := s.Vertices()
+		for i := range vertices {
+			for j := 0; j < 3; j++ {
+				if i&(1<<j) == 0 {
+					lines = append(lines, []mgl64.Vec3{vertices[i], vertices[i|(1<<j)]})
+				}
+			}
+		}
+	case collider.ConvexHull:
+		for _, edge := range s.Edges() {
+			lines = append(lines, []mgl64.Vec3{s.Vertices[edge[0]], s.Vertices[edge[1]]})
+		}
+	case collider.Sphere:
+		segments := 24
+		for axis := 0; axis < 3; axis++ {
+			for i := 0; i < segments; i++ {
+				lines = append(lines, []mgl64.Vec3{
+					ringPoint(s, axis, 2*math.Pi*float64(i)/float64(segments)),
+					ringPoint(s, axis, 2*math.Pi*float64(i+1)/float64(segments)),
+				})
+			}
 		}
 	}
 
-	var baseVerticalLines [][]mgl64.Vec3
-
-	for i := 0; i < spatialPartition.PartitionCount+1; i++ {
-		baseVerticalLines = append(baseVerticalLines,
-			[]mgl64.Vec3{{float64(-d/2 + i*spatialPartition.PartitionDimension), float64(-d / 2), float64(-d / 2)}, {float64(-d/2 + i*spatialPartition.PartitionDimension), float64(d / 2), float64(-d / 2)}},
-		)
-	}
+	drawLines(viewerContext, shader, lines, thickness, color)
+}
 
-	for i := 0; i < spatialPartition.PartitionCount+1; i++ {
-		for _, b := range baseVerticalLines {
-			allLines = append(allLines,
-				[]mgl64.Vec3{b[0].Add(mgl64.Vec3{0, 0, float64(i * spatialPartition.PartitionDimension)}), b[1].Add(mgl64.Vec3{0, 0, float64(i * spatialPartition.PartitionDimension)})},
-			)
-		}
-	}
+// ringPoint returns the point at the angle around the sphere's ring perpendicular to the axis
+func ringPoint(sphere collider.Sphere, axis int, angle float64) mgl64.Vec3 {
+	var offset mgl64.Vec3
+	offset[(axis+1)%3] = math.Cos(angle) * sphere.Radius
+	offset[(axis+2)%3] = math.Sin(angle) * sphere.Radius
+	return sphere.Center.Add(offset)
+}
 
+func drawAABB(viewerContext ViewerContext, shader *shaders.ShaderProgram, color mgl64.Vec3, aabb *collider.BoundingBox, thickness float64) {
 	drawLines(
 		viewerContext,
 		shader,
-		allLines,
+		aabbLines(aabb),
 		thickness,
 		color,
 	)
 }
 
-func drawAABB(viewerContext ViewerContext, shader *shaders.ShaderProgram, color mgl64.Vec3, aabb *collider.BoundingBox, thickness float64) {
+// aabbLines returns the 12 edges of the box
+func aabbLines(aabb *collider.BoundingBox) [][]mgl64.Vec3 {
 	var allLines [][]mgl64.Vec3
 
 	d := aabb.MaxVertex.Sub(aabb.MinVertex)
@@ -542,13 +557,7 @@ func drawAABB(viewerContext ViewerContext, shader *shaders.ShaderProgram, color
 		}
 	}
 
-	drawLines(
-		viewerContext,
-		shader,
-		allLines,
-		thickness,
-		color,
-	)
+	return allLines
 }
 
 func createModelMatrix(scaleMatrix, rotationMatrix, translationMatrix mgl64.Mat4) mgl64.Mat4 {
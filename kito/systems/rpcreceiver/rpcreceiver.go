@@ -10,46 +10,43 @@ import (
 	"github.com/kkevinchou/kito/kito/directory"
 	"github.com/kkevinchou/kito/kito/entities"
 	"github.com/kkevinchou/kito/kito/events"
+	"github.com/kkevinchou/kito/kito/knetwork"
 	"github.com/kkevinchou/kito/kito/managers/eventbroker"
+	"github.com/kkevinchou/kito/kito/managers/player"
+	"github.com/kkevinchou/kito/kito/scheduler"
 	"github.com/kkevinchou/kito/kito/singleton"
 	"github.com/kkevinchou/kito/kito/systems/base"
+	"github.com/kkevinchou/kito/lib/logger"
+	"github.com/kkevinchou/kito/lib/profiler"
 )
 
+var log = logger.New("rpcreceiver")
+
 type World interface {
 	GetEventBroker() eventbroker.EventBroker
 	GetEntityByID(id int) entities.Entity
 	GetPlayerEntityByID(id int) entities.Entity
+	GetPlayerByID(id int) *player.Player
 	GetSingleton() *singleton.Singleton
+	Scheduler() *scheduler.Scheduler
+	TimeControl() knetwork.TimeControlMessage
+	SetTimeControl(timeControl knetwork.TimeControlMessage)
 }
 
 type RPCReceiverSystem struct {
 	*base.BaseSystem
-	world  World
-	events []events.Event
+	world     World
+	rpcEvents *eventbroker.Queue[*events.RPCEvent]
 }
 
 func NewRPCReceiverSystem(world World) *RPCReceiverSystem {
-	rpcSystem := &RPCReceiverSystem{
+	return &RPCReceiverSystem{
 		BaseSystem: &base.BaseSystem{},
 		world:      world,
-	}
-
-	eventBroker := world.GetEventBroker()
-	eventBroker.AddObserver(rpcSystem, []events.EventType{
-		events.EventTypeRPC,
-	})
-	return rpcSystem
-}
-
-func (s *RPCReceiverSystem) Observe(event events.Event) {
-	if event.Type() == events.EventTypeRPC {
-		s.events = append(s.events, event)
+		rpcEvents:  eventbroker.NewQueue[*events.RPCEvent](world.GetEventBroker(), 0),
 	}
 }
 
-func (s *RPCReceiverSystem) clearEvents() {
-	s.events = nil
-}
 func (s *RPCReceiverSystem) Update(delta time.Duration) {
 	s.handlePlayerCommands()
 	s.handleRPCs()
@@ -83,64 +80,215 @@ func (s *RPCReceiverSystem) handlePlayerCommands() {
 }
 
 func (s *RPCReceiverSystem) handleRPCs() {
-	defer s.clearEvents()
+	for _, e := range s.rpcEvents.Drain() {
+		tokens := strings.Split(e.Command, " ")
+		if len(tokens) == 0 {
+			continue
+		}
 
-	for _, event := range s.events {
-		if e, ok := event.(*events.RPCEvent); ok {
-			tokens := strings.Split(e.Command, " ")
-			if len(tokens) == 0 {
+		if timeControlCommands[tokens[0]] {
+			if err := s.handleTimeControl(tokens); err != nil {
+				log.Warn("failed to execute rpc", logger.PlayerID(e.PlayerID), logger.F("command", e.Command), logger.Err(err))
 				continue
 			}
+			log.Info("executed rpc", logger.PlayerID(e.PlayerID), logger.F("command", e.Command))
+			continue
+		}
 
-			if len(tokens) != 3 {
+		if tokens[0] == "component" {
+			edit, err := s.handleComponentEdit(tokens)
+			if err != nil {
+				log.Warn("failed to execute rpc", logger.PlayerID(e.PlayerID), logger.F("command", e.Command), logger.Err(err))
+				edit.Error = err.Error()
+			} else {
+				log.Info("executed rpc", logger.PlayerID(e.PlayerID), logger.F("command", e.Command))
+			}
+			s.sendComponentEdit(e.PlayerID, edit)
+			continue
+		}
+
+		if tokens[0] == "server-system" {
+			output, err := s.world.Scheduler().HandleCommand(tokens[1:])
+			if err != nil {
+				log.Warn("failed to execute rpc", logger.PlayerID(e.PlayerID), logger.F("command", e.Command), logger.Err(err))
 				continue
 			}
+			log.Info(output, logger.PlayerID(e.PlayerID))
+			continue
+		}
 
-			command := tokens[0]
-			if command == "position" {
-				var entity entities.Entity
-				if tokens[1] == "self" || tokens[1] == "me" {
-					entity = s.world.GetPlayerEntityByID(e.PlayerID)
-				} else {
-					entityID, err := strconv.Atoi(tokens[1])
-					if err != nil {
-						continue
-					}
-					entity = s.world.GetEntityByID(entityID)
-				}
+		if tokens[0] == "server-profile" {
+			output, err := profiler.HandleCommand(tokens[1:])
+			if err != nil {
+				log.Warn("failed to execute rpc", logger.PlayerID(e.PlayerID), logger.F("command", e.Command), logger.Err(err))
+				continue
+			}
+			log.Info(output, logger.PlayerID(e.PlayerID))
+			continue
+		}
 
-				if entity == nil {
-					continue
-				}
+		if tokens[0] == "server-log" {
+			output, err := logger.HandleCommand(tokens[1:])
+			if err != nil {
+				log.Warn("failed to execute rpc", logger.PlayerID(e.PlayerID), logger.F("command", e.Command), logger.Err(err))
+				continue
+			}
+			log.Info(output, logger.PlayerID(e.PlayerID))
+			continue
+		}
 
-				vec := strings.Split(tokens[2], ",")
-				x, err := strconv.Atoi(vec[0])
-				if err != nil {
-					continue
-				}
-				y, err := strconv.Atoi(vec[1])
-				if err != nil {
-					continue
-				}
-				z, err := strconv.Atoi(vec[2])
+		if len(tokens) != 3 {
+			continue
+		}
+
+		command := tokens[0]
+		if command == "position" {
+			var entity entities.Entity
+			if tokens[1] == "self" || tokens[1] == "me" {
+				entity = s.world.GetPlayerEntityByID(e.PlayerID)
+			} else {
+				entityID, err := strconv.Atoi(tokens[1])
 				if err != nil {
 					continue
 				}
+				entity = s.world.GetEntityByID(entityID)
+			}
+
+			if entity == nil {
+				continue
+			}
 
-				cc := entity.GetComponentContainer()
+			vec := strings.Split(tokens[2], ",")
+			x, err := strconv.Atoi(vec[0])
+			if err != nil {
+				continue
+			}
+			y, err := strconv.Atoi(vec[1])
+			if err != nil {
+				continue
+			}
+			z, err := strconv.Atoi(vec[2])
+			if err != nil {
+				continue
+			}
 
-				positionVec := mgl64.Vec3{float64(x), float64(y), float64(z)}
-				cc.TransformComponent.Position = positionVec
-				if cc.ThirdPersonControllerComponent != nil {
-					cc.ThirdPersonControllerComponent.BaseVelocity = mgl64.Vec3{}
-				}
+			cc := entity.GetComponentContainer()
 
-				fmt.Println("executed rpc", e.Command)
+			positionVec := mgl64.Vec3{float64(x), float64(y), float64(z)}
+			cc.TransformComponent.Position = positionVec
+			if cc.ThirdPersonControllerComponent != nil {
+				cc.ThirdPersonControllerComponent.BaseVelocity = mgl64.Vec3{}
+			}
+
+			log.Info("executed rpc", logger.PlayerID(e.PlayerID), logger.F("command", e.Command))
+		}
+	}
+}
+
+var timeControlCommands = map[string]bool{
+	"pause":     true,
+	"resume":    true,
+	"step":      true,
+	"timescale": true,
+	"catchup":   true,
+}
+
+func (s *RPCReceiverSystem) handleTimeControl(tokens []string) error {
+	timeControl := s.world.TimeControl()
+	// any pending steps have been consumed or are superseded by this command
+	timeControl.StepFrames = 0
+
+	switch tokens[0] {
+	case "pause":
+		timeControl.Paused = true
+	case "resume":
+		timeControl.Paused = false
+	case "step":
+		if !timeControl.Paused {
+			return fmt.Errorf("the simulation must be paused before stepping")
+		}
+		timeControl.StepFrames = 1
+		if len(tokens) > 1 {
+			frames, err := strconv.Atoi(tokens[1])
+			if err != nil {
+				return err
 			}
+			if frames < 1 {
+				return fmt.Errorf("step count must be positive, got %d", frames)
+			}
+			timeControl.StepFrames = frames
+		}
+	case "timescale":
+		if len(tokens) != 2 {
+			return fmt.Errorf("expected a time scale")
+		}
+		timeScale, err := strconv.ParseFloat(tokens[1], 64)
+		if err != nil {
+			return err
+		}
+		// a time scale of zero would stop the game loop from polling input, use pause instead
+		if timeScale <= 0 {
+			return fmt.Errorf("time scale must be positive, got %f", timeScale)
+		}
+		timeControl.TimeScale = timeScale
+	case "catchup":
+		if len(tokens) != 2 {
+			return fmt.Errorf("expected a command frame count")
 		}
+		frames, err := strconv.Atoi(tokens[1])
+		if err != nil {
+			return err
+		}
+		if frames < 1 {
+			return fmt.Errorf("catch up must allow at least one command frame, got %d", frames)
+		}
+		timeControl.MaxCommandFramesPerTick = frames
+	}
+
+	s.world.SetTimeControl(timeControl)
+	return nil
+}
+
+// handleComponentEdit applies a field edit from the entity inspector of the form
+//
+//	component set <entity id> <component> <field> <value>
+//
+// the edit is validated against the component schema before it's applied. The returned
+// message describes the edit so that it can be acknowledged to the client that sent it
+func (s *RPCReceiverSystem) handleComponentEdit(tokens []string) (knetwork.ComponentEditMessage, error) {
+	var edit knetwork.ComponentEditMessage
+	if len(tokens) < 6 || tokens[1] != "set" {
+		return edit, fmt.Errorf("expected component set <entity id> <component> <field> <value>")
+	}
+
+	entityID, err := strconv.Atoi(tokens[2])
+	if err != nil {
+		return edit, err
+	}
+	edit.EntityID = entityID
+	edit.Component = tokens[3]
+	edit.Field = tokens[4]
+	edit.Value = strings.Join(tokens[5:], " ")
+
+	entity := s.world.GetEntityByID(entityID)
+	if entity == nil {
+		return edit, fmt.Errorf("unknown entity %d", entityID)
+	}
+	return edit, entity.GetComponentContainer().SetField(edit.Component, edit.Field, edit.Value)
+}
+
+// sendComponentEdit tells the player whether their edit was applied. Clients only apply
+// edits that the server acknowledged
+func (s *RPCReceiverSystem) sendComponentEdit(playerID int, edit knetwork.ComponentEditMessage) {
+	player := s.world.GetPlayerByID(playerID)
+	if player == nil {
+		return
+	}
+	if err := player.Client.SendMessage(knetwork.MessageTypeComponentEdit, edit); err != nil {
+		log.Error("failed to send component edit", logger.PlayerID(playerID), logger.Err(err))
 	}
 }
 
 func (s *RPCReceiverSystem) Name() string {
-	return "RPCSystem"
+	return "RPCReceiverSystem"
 }
@@ -8,60 +8,74 @@ import (
 	"github.com/kkevinchou/kito/kito/knetwork"
 	"github.com/kkevinchou/kito/kito/managers/eventbroker"
 	"github.com/kkevinchou/kito/kito/managers/player"
+	"github.com/kkevinchou/kito/kito/scheduler"
 	"github.com/kkevinchou/kito/kito/settings"
 	"github.com/kkevinchou/kito/kito/systems/base"
+	"github.com/kkevinchou/kito/lib/console"
+	"github.com/kkevinchou/kito/lib/logger"
+	"github.com/kkevinchou/kito/lib/profiler"
 )
 
 type World interface {
 	GetEventBroker() eventbroker.EventBroker
 	GetPlayer() *player.Player
+	Scheduler() *scheduler.Scheduler
 }
 
 type RPCSenderSystem struct {
 	*base.BaseSystem
-	world  World
-	events []events.Event
+	world     World
+	rpcEvents *eventbroker.Queue[*events.RPCEvent]
 }
 
 func NewRPCSenderSystem(world World) *RPCSenderSystem {
-	rpcSystem := &RPCSenderSystem{
+	return &RPCSenderSystem{
 		BaseSystem: &base.BaseSystem{},
 		world:      world,
+		rpcEvents:  eventbroker.NewQueue[*events.RPCEvent](world.GetEventBroker(), 0),
 	}
-	eventBroker := world.GetEventBroker()
-	eventBroker.AddObserver(rpcSystem, []events.EventType{
-		events.EventTypeRPC,
-	})
-	return rpcSystem
 }
 
-func (s *RPCSenderSystem) Observe(event events.Event) {
-	if event.Type() == events.EventTypeRPC {
-		s.events = append(s.events, event)
+func (s *RPCSenderSystem) Update(delta time.Duration) {
+	for _, e := range s.rpcEvents.Drain() {
+		if s.handleLocalCommand(e) {
+			continue
+		}
+		player := s.world.GetPlayer()
+		rpcMessage := knetwork.RPCMessage{Command: e.Command}
+		player.Client.SendMessage(knetwork.MessageTypeRPC, rpcMessage)
 	}
 }
 
-func (s *RPCSenderSystem) clearEvents() {
-	s.events = nil
-}
+func (s *RPCSenderSystem) handleLocalCommand(e *events.RPCEvent) bool {
+	commandSplit := strings.Split(e.Command, " ")
+	if len(commandSplit) > 0 && commandSplit[0] == "system" {
+		output, err := s.world.Scheduler().HandleCommand(commandSplit[1:])
+		if err != nil {
+			output = err.Error()
+		}
+		console.GlobalConsole.AppendOutput(output)
+		return true
+	}
 
-func (s *RPCSenderSystem) Update(delta time.Duration) {
-	defer s.clearEvents()
+	if len(commandSplit) > 0 && commandSplit[0] == "profile" {
+		output, err := profiler.HandleCommand(commandSplit[1:])
+		if err != nil {
+			output = err.Error()
+		}
+		console.GlobalConsole.AppendOutput(output)
+		return true
+	}
 
-	for _, event := range s.events {
-		if e, ok := event.(*events.RPCEvent); ok {
-			if s.handleLocalCommand(e) {
-				continue
-			}
-			player := s.world.GetPlayer()
-			rpcMessage := knetwork.RPCMessage{Command: e.Command}
-			player.Client.SendMessage(knetwork.MessageTypeRPC, rpcMessage)
+	if len(commandSplit) > 0 && commandSplit[0] == "log" {
+		output, err := logger.HandleCommand(commandSplit[1:])
+		if err != nil {
+			output = err.Error()
 		}
+		console.GlobalConsole.AppendOutput(output)
+		return true
 	}
-}
 
-func (s *RPCSenderSystem) handleLocalCommand(e *events.RPCEvent) bool {
-	commandSplit := strings.Split(e.Command, " ")
 	if len(commandSplit) == 2 {
 		if commandSplit[0] == "collision-render" {
 			if commandSplit[1] == "true" {
@@ -72,9 +86,16 @@ func (s *RPCSenderSystem) handleLocalCommand(e *events.RPCEvent) bool {
 			return true
 		} else if commandSplit[0] == "boundingbox-render" {
 			if commandSplit[1] == "true" {
-				settings.DebugRenderSpatialPartition = true
+				settings.DebugRenderBroadphase = true
+			} else if commandSplit[1] == "false" {
+				settings.DebugRenderBroadphase = false
+			}
+			return true
+		} else if commandSplit[0] == "physics-render" {
+			if commandSplit[1] == "true" {
+				settings.DebugRenderPhysics = true
 			} else if commandSplit[1] == "false" {
-				settings.DebugRenderSpatialPartition = false
+				settings.DebugRenderPhysics = false
 			}
 			return true
 		}
@@ -84,5 +105,5 @@ func (s *RPCSenderSystem) handleLocalCommand(e *events.RPCEvent) bool {
 }
 
 func (s *RPCSenderSystem) Name() string {
-	return "RPCSystem"
+	return "RPCSenderSystem"
 }
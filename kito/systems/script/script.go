@@ -0,0 +1,127 @@
+package script
+
+import (
+	"sort"
+	"time"
+
+	"github.com/kkevinchou/kito/kito/components"
+	"github.com/kkevinchou/kito/kito/scripting"
+	"github.com/kkevinchou/kito/kito/systems/base"
+	"github.com/kkevinchou/kito/kito/utils"
+	"github.com/kkevinchou/kito/lib/logger"
+	lua "github.com/yuin/gopher-lua"
+)
+
+var log = logger.New("script")
+
+const (
+	// reloadInterval is how often, in command frames, the scripts directory is checked
+	// for modified scripts
+	reloadInterval = 60
+
+	// the seed for the scripts' random number generator. the server is the only one
+	// that runs scripts so a fixed seed keeps a replayed session identical
+	scriptSeed = 0
+)
+
+type World interface {
+	scripting.World
+}
+
+// ScriptSystem runs the hooks of system scripts and of the scripts attached to
+// entities through their ScriptComponent. Scripts are run on the server only.
+//
+// System scripts may define:
+//
+//	update(dt)
+//
+// Entity scripts may define:
+//
+//	on_update(self, dt)
+//	on_contact(self, other), called for every entity collided with this frame
+type ScriptSystem struct {
+	*base.BaseSystem
+
+	world         World
+	engine        *scripting.Engine
+	systemScripts []string
+
+	framesSinceReload int
+
+	// missing tracks scripts that were attached but aren't loaded so that they're
+	// only reported once
+	missing map[string]bool
+}
+
+func NewScriptSystem(world World, scriptDirectory string, systemScripts ...string) *ScriptSystem {
+	engine := scripting.NewEngine(world, scriptDirectory, scriptSeed)
+	if err := engine.LoadAll(); err != nil {
+		log.Error("failed to load scripts", logger.Err(err))
+	}
+
+	return &ScriptSystem{
+		world:         world,
+		engine:        engine,
+		systemScripts: systemScripts,
+		missing:       map[string]bool{},
+	}
+}
+
+func (s *ScriptSystem) Update(delta time.Duration) {
+	if utils.IsClient() {
+		return
+	}
+
+	s.framesSinceReload++
+	if s.framesSinceReload >= reloadInterval {
+		s.framesSinceReload = 0
+		for _, name := range s.engine.Reload() {
+			log.Info("reloaded script", logger.F("script", name))
+		}
+	}
+
+	dt := lua.LNumber(delta.Seconds())
+	for _, name := range s.systemScripts {
+		s.call(name, "update", dt)
+	}
+
+	for _, entity := range s.world.QueryEntity(components.ComponentFlagScript) {
+		cc := entity.GetComponentContainer()
+		self := lua.LNumber(entity.GetID())
+
+		// contacts are visited in ID order to keep scripts deterministic
+		var contacts []int
+		if cc.ColliderComponent != nil {
+			for id := range cc.ColliderComponent.Contacts {
+				contacts = append(contacts, id)
+			}
+			sort.Ints(contacts)
+		}
+
+		for _, name := range cc.ScriptComponent.Scripts {
+			s.call(name, "on_update", self, dt)
+			for _, other := range contacts {
+				s.call(name, "on_contact", self, lua.LNumber(other))
+			}
+		}
+	}
+}
+
+func (s *ScriptSystem) call(name string, hook string, args ...lua.LValue) {
+	if !s.engine.Loaded(name) {
+		if !s.missing[name] {
+			log.Warn("script is not loaded", logger.F("script", name))
+			s.missing[name] = true
+		}
+		return
+	}
+	delete(s.missing, name)
+
+	if err := s.engine.Call(name, hook, args...); err != nil {
+		log.Error("script failed", logger.F("script", name), logger.F("hook", hook), logger.Err(err))
+	}
+}
+
+func (s *ScriptSystem) Name() string {
+	return "ScriptSystem"
+}
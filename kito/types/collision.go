@@ -0,0 +1,15 @@
+package types
+
+// CollisionLayer is a bit set of the layers a collider is on or interacts with
+type CollisionLayer uint32
+
+const (
+	CollisionLayerDefault CollisionLayer = 1 << iota
+	CollisionLayerTerrain
+	CollisionLayerCharacter
+	CollisionLayerProjectile
+	CollisionLayerLoot
+	CollisionLayerTrigger
+
+	CollisionLayerAll = ^CollisionLayer(0)
+)
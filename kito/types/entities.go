@@ -14,4 +14,5 @@ const (
 	EntityTypeProjectile
 	EntityTypeEnemy
 	EntityTypeLootbox
+	EntityTypePlatform
 )
@@ -28,4 +28,5 @@ const (
 	WindowGame      Window = "GAME"
 	WindowDebug     Window = "DEBUG"
 	WindowInventory Window = "INVENTORY"
+	WindowInspector Window = "INSPECTOR"
 )
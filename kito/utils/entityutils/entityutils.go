@@ -1,14 +1,15 @@
 package entityutils
 
 import (
-	"fmt"
-
 	"github.com/go-gl/mathgl/mgl64"
 	"github.com/kkevinchou/kito/kito/entities"
 	"github.com/kkevinchou/kito/kito/knetwork"
 	"github.com/kkevinchou/kito/kito/types"
+	"github.com/kkevinchou/kito/lib/logger"
 )
 
+var log = logger.New("entityutils")
+
 func Spawn(entityType types.EntityType, position mgl64.Vec3, orientation mgl64.Quat) *entities.EntityImpl {
 	var newEntity *entities.EntityImpl
 
@@ -28,8 +29,10 @@ func Spawn(entityType types.EntityType, position mgl64.Vec3, orientation mgl64.Q
 		newEntity = entities.NewEnemy()
 	} else if types.EntityType(entityType) == types.EntityTypeLootbox {
 		newEntity = entities.NewLootbox()
+	} else if types.EntityType(entityType) == types.EntityTypePlatform {
+		newEntity = entities.NewPlatform()
 	} else {
-		fmt.Printf("unrecognized entity with type %v to spawn\n", entityType)
+		log.Warn("unrecognized entity type to spawn", logger.F("type", entityType))
 		return nil
 	}
 
@@ -62,6 +65,11 @@ func ConstructEntitySnapshot(entity entities.Entity) knetwork.EntitySnapshot {
 		snapshot.Velocity = tpcComponent.BaseVelocity
 	}
 
+	if networkComponent := cc.NetworkComponent; networkComponent != nil {
+		snapshot.SpawnKey = networkComponent.SpawnKey
+		snapshot.OwnerPlayerID = networkComponent.OwnerPlayerID
+	}
+
 	animationComponent := cc.AnimationComponent
 	if animationComponent != nil {
 		snapshot.Animation = animationComponent.Player.CurrentAnimation()
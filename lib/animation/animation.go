@@ -6,10 +6,13 @@ import (
 
 	"github.com/go-gl/mathgl/mgl32"
 	"github.com/kkevinchou/kito/lib/libutils"
+	"github.com/kkevinchou/kito/lib/logger"
 	"github.com/kkevinchou/kito/lib/model"
 	"github.com/kkevinchou/kito/lib/modelspec"
 )
 
+var log = logger.New("animation")
+
 type AnimationPlayer struct {
 	elapsedTime         time.Duration
 	animationTransforms map[int]mgl32.Mat4
@@ -38,6 +41,30 @@ func NewAnimationPlayer(m *model.Model) *AnimationPlayer {
 	}
 }
 
+// SetModel swaps the animations the player plays from, e.g. when the model is reloaded.
+// Playback carries over for animations that still exist in the new model and is
+// stopped otherwise
+func (player *AnimationPlayer) SetModel(m *model.Model) {
+	player.animations = m.Animations()
+	player.rootJoint = m.RootJoint()
+
+	if player.currentAnimation != nil {
+		player.currentAnimation = player.animations[player.currentAnimation.Name]
+		if player.currentAnimation == nil {
+			player.elapsedTime = 0
+		}
+	}
+	if player.blendAnimation != nil {
+		player.blendAnimation = player.animations[player.blendAnimation.Name]
+	}
+	if player.secondaryAnimation != nil {
+		if _, ok := player.animations[*player.secondaryAnimation]; !ok {
+			player.secondaryAnimation = nil
+			player.loop = true
+		}
+	}
+}
+
 func (player *AnimationPlayer) CurrentAnimation() string {
 	if player.currentAnimation == nil {
 		return ""
@@ -71,7 +98,7 @@ func (player *AnimationPlayer) PlayAnimation(animationName string) {
 
 func (player *AnimationPlayer) PlayAndBlendAnimation(animationName string, blendDuration time.Duration) {
 	if player.currentAnimation == nil {
-		fmt.Println("NO ANIMATION TO BLEND FROM")
+		log.Warn("no animation to blend from")
 		return
 	}
 
@@ -3,13 +3,17 @@ package assets
 import (
 	"fmt"
 
+	"github.com/go-gl/gl/v4.1-core/gl"
 	"github.com/kkevinchou/kito/lib/assets/loaders"
+	"github.com/kkevinchou/kito/lib/assets/loaders/gltextures"
 	"github.com/kkevinchou/kito/lib/font"
 	"github.com/kkevinchou/kito/lib/modelspec"
 	"github.com/kkevinchou/kito/lib/textures"
 )
 
 type AssetManager struct {
+	loadVisualAssets bool
+
 	textures       map[string]*textures.Texture
 	animatedModels map[string]*modelspec.ModelSpecification
 	fonts          map[string]font.Font
@@ -24,9 +28,10 @@ func NewAssetManager(directory string, loadVisualAssets bool) *AssetManager {
 	}
 
 	assetManager := AssetManager{
-		textures:       loadedTextures,
-		animatedModels: loaders.LoadModels(directory),
-		fonts:          loadedFonts,
+		loadVisualAssets: loadVisualAssets,
+		textures:         loadedTextures,
+		animatedModels:   loaders.LoadModels(directory),
+		fonts:            loadedFonts,
 	}
 
 	return &assetManager
@@ -52,3 +57,39 @@ func (a *AssetManager) GetFont(name string) font.Font {
 	}
 	return a.fonts[name]
 }
+
+// ReloadModel parses the model at the path and replaces the loaded model of the same
+// name, returning the previously loaded model (if any) and the new one. The previously
+// loaded model is kept if parsing fails
+func (a *AssetManager) ReloadModel(name string, path string) (*modelspec.ModelSpecification, *modelspec.ModelSpecification, error) {
+	modelSpec, err := loaders.LoadModel(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to reload model %s: %w", name, err)
+	}
+	previous := a.animatedModels[name]
+	a.animatedModels[name] = modelSpec
+	return previous, modelSpec, nil
+}
+
+// ReloadTexture loads the texture at the path into the loaded texture of the same name.
+// The texture is updated in place so that anything holding on to it, e.g. mesh chunks,
+// picks up the new texture
+func (a *AssetManager) ReloadTexture(name string, path string) error {
+	if !a.loadVisualAssets {
+		return nil
+	}
+
+	textureID, err := gltextures.LoadTexture(path)
+	if err != nil {
+		return fmt.Errorf("failed to reload texture %s: %w", name, err)
+	}
+
+	texture, ok := a.textures[name]
+	if !ok {
+		a.textures[name] = &textures.Texture{ID: textureID}
+		return nil
+	}
+	gl.DeleteTextures(1, &texture.ID)
+	texture.ID = textureID
+	return nil
+}
@@ -1,6 +1,7 @@
 package gltextures
 
 import (
+	"fmt"
 	"image"
 	"image/draw"
 	"log"
@@ -13,13 +14,25 @@ import (
 )
 
 func NewTexture(file string) uint32 {
+	texture, err := LoadTexture(file)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return texture
+}
+
+// LoadTexture creates a texture from an image on disk, returning an error rather than
+// exiting if the image can't be loaded
+func LoadTexture(file string) (uint32, error) {
 	imgFile, err := os.Open(file)
 	if err != nil {
-		log.Fatalf("texture %q not found on disk: %v\n", file, err)
+		return 0, fmt.Errorf("texture %q not found on disk: %w", file, err)
 	}
+	defer imgFile.Close()
+
 	img, _, err := image.Decode(imgFile)
 	if err != nil {
-		panic(err)
+		return 0, fmt.Errorf("failed to decode texture %q: %w", file, err)
 	}
 
 	// is vertically flipped if directly read into opengl texture
@@ -27,7 +40,7 @@ func NewTexture(file string) uint32 {
 
 	rgba := image.NewRGBA(img.Bounds())
 	if rgba.Stride != rgba.Rect.Size().X*4 {
-		panic("unsupported stride")
+		return 0, fmt.Errorf("texture %q has an unsupported stride", file)
 	}
 
 	draw.Draw(rgba, rgba.Bounds(), nrgba, image.Point{0, 0}, draw.Src)
@@ -50,7 +63,7 @@ func NewTexture(file string) uint32 {
 		gl.UNSIGNED_BYTE,
 		gl.Ptr(rgba.Pix))
 
-	return texture
+	return texture, nil
 }
 
 func NewFontTexture(pixels []byte, width, height int32) uint32 {
@@ -6,11 +6,14 @@ import (
 	"time"
 
 	"github.com/go-gl/mathgl/mgl32"
+	"github.com/kkevinchou/kito/lib/logger"
 	"github.com/kkevinchou/kito/lib/modelspec"
 	"github.com/qmuntal/gltf"
 	"github.com/qmuntal/gltf/modeler"
 )
 
+var log = logger.New("gltf")
+
 type jointMeta struct {
 	inverseBindMatrix mgl32.Mat4
 }
@@ -59,7 +62,7 @@ func ParseGLTF(documentPath string, config *ParseConfig) (*modelspec.ModelSpecif
 	for _, texture := range document.Textures {
 		img := document.Images[int(*texture.Source)]
 		if img.MimeType != "image/png" {
-			panic(fmt.Sprintf("image %s has mimetype %s which is not supported for textures", img.Name, img.MimeType))
+			return nil, fmt.Errorf("image %s has mimetype %s which is not supported for textures", img.Name, img.MimeType)
 		}
 		modelSpec.Textures = append(modelSpec.Textures, img.Name)
 	}
@@ -68,7 +71,6 @@ func ParseGLTF(documentPath string, config *ParseConfig) (*modelspec.ModelSpecif
 		mat := mgl32.QuatRotate(mgl32.DegToRad(180), mgl32.Vec3{0, 0, -1}).Mat4()
 		meshSpec, err := parseMesh(document, mesh, mat, modelSpec.Textures, config)
 		if err != nil {
-			fmt.Println(err)
 			return nil, err
 		}
 
@@ -152,7 +154,7 @@ func parseAnimation(document *gltf.Document, animation *gltf.Animation, parsedJo
 
 		input, err := modeler.ReadAccessor(document, inputAccessor, nil)
 		if err != nil {
-			panic("WHA")
+			return nil, fmt.Errorf("failed to read animation input accessor %d: %w", inputAccessorIndex, err)
 		}
 
 		timestamps := input.([]float32)
@@ -182,7 +184,7 @@ func parseAnimation(document *gltf.Document, animation *gltf.Animation, parsedJo
 			}
 			output, err := modeler.ReadAccessor(document, outputAccessor, nil)
 			if err != nil {
-				panic("WHA")
+				return nil, fmt.Errorf("failed to read animation output accessor %d: %w", outputAccessorIndex, err)
 			}
 			f32OutputValues := output.([][3]float32)
 			for i, timestamp := range timestamps {
@@ -198,7 +200,7 @@ func parseAnimation(document *gltf.Document, animation *gltf.Animation, parsedJo
 			}
 			output, err := modeler.ReadAccessor(document, outputAccessor, nil)
 			if err != nil {
-				panic("WHA")
+				return nil, fmt.Errorf("failed to read animation output accessor %d: %w", outputAccessorIndex, err)
 			}
 			f32OutputValues := output.([][4]float32)
 			for i, timestamp := range timestamps {
@@ -214,7 +216,7 @@ func parseAnimation(document *gltf.Document, animation *gltf.Animation, parsedJo
 			}
 			output, err := modeler.ReadAccessor(document, outputAccessor, nil)
 			if err != nil {
-				panic("WHA")
+				return nil, fmt.Errorf("failed to read animation output accessor %d: %w", outputAccessorIndex, err)
 			}
 			f32OutputValues := output.([][3]float32)
 			for i, timestamp := range timestamps {
@@ -385,7 +387,7 @@ func parseMesh(document *gltf.Document, mesh *gltf.Mesh, parentTransforms mgl32.
 				}
 
 				if len(positions) != len(meshChunkSpec.UniqueVertices) {
-					fmt.Println("dafuq")
+					log.Warn("position count does not match the vertex count", logger.F("mesh", mesh.Name))
 				}
 
 				for i, position := range positions {
@@ -429,7 +431,7 @@ func parseMesh(document *gltf.Document, mesh *gltf.Mesh, parentTransforms mgl32.
 					meshChunkSpec.UniqueVertices[i].JointWeights = jointWeights
 				}
 			} else {
-				fmt.Printf("[%s] unhandled attribute %s\n", mesh.Name, attribute)
+				log.Debug("unhandled attribute", logger.F("mesh", mesh.Name), logger.F("attribute", attribute))
 			}
 		}
 
@@ -2,6 +2,8 @@ package gltf_test
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/kkevinchou/kito/lib/assets/loaders/gltf"
@@ -49,3 +51,20 @@ func jointCount(joint *modelspec.JointSpec) int {
 	}
 	return count
 }
+
+func TestUnsupportedTextureMimeType(t *testing.T) {
+	document := `{
+		"asset": {"version": "2.0"},
+		"images": [{"name": "skin", "mimeType": "image/jpeg", "uri": "skin.jpg"}],
+		"textures": [{"source": 0}]
+	}`
+
+	path := filepath.Join(t.TempDir(), "jpeg.gltf")
+	if err := os.WriteFile(path, []byte(document), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := gltf.ParseGLTF(path, &gltf.ParseConfig{TextureCoordStyle: gltf.TextureCoordStyleOpenGL}); err == nil {
+		t.Error("expected an error for a texture that isn't a png")
+	}
+}
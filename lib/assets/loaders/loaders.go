@@ -9,10 +9,13 @@ import (
 	"github.com/kkevinchou/kito/lib/assets/loaders/gltf"
 	"github.com/kkevinchou/kito/lib/font"
 	utils "github.com/kkevinchou/kito/lib/libutils"
+	"github.com/kkevinchou/kito/lib/logger"
 	"github.com/kkevinchou/kito/lib/modelspec"
 	"github.com/kkevinchou/kito/lib/textures"
 )
 
+var log = logger.New("loaders")
+
 func LoadTextures(directory string) map[string]*textures.Texture {
 	var subDirectories []string = []string{"images", "icons", "gltf"}
 
@@ -53,9 +56,9 @@ func LoadModels(directory string) map[string]*modelspec.ModelSpecification {
 		}
 
 		if metaData.Extension == ".gltf" {
-			modelSpec, err = gltf.ParseGLTF(metaData.Path, &gltf.ParseConfig{TextureCoordStyle: gltf.TextureCoordStyleOpenGL})
+			modelSpec, err = LoadModel(metaData.Path)
 			if err != nil {
-				fmt.Println("failed to parse gltf for", metaData.Path, ", error:", err)
+				log.Error("failed to parse gltf", logger.F("path", metaData.Path), logger.Err(err))
 				continue
 			}
 		} else {
@@ -69,6 +72,10 @@ func LoadModels(directory string) map[string]*modelspec.ModelSpecification {
 	return animationMap
 }
 
+func LoadModel(path string) (*modelspec.ModelSpecification, error) {
+	return gltf.ParseGLTF(path, &gltf.ParseConfig{TextureCoordStyle: gltf.TextureCoordStyleOpenGL})
+}
+
 func LoadFonts(directory string) map[string]font.Font {
 	var subDirectories []string = []string{"fonts"}
 
@@ -0,0 +1,519 @@
+package aabbtree
+
+import (
+	"sort"
+
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/kkevinchou/kito/lib/collision/checks"
+	"github.com/kkevinchou/kito/lib/collision/collider"
+)
+
+const null = -1
+
+type node struct {
+	// bounds of a leaf are its proxy's bounds expanded by the tree's margin
+	bounds collider.BoundingBox
+
+	parent int
+	left   int
+	right  int
+	// height is 0 for leaves, free nodes have a height of -1
+	height int
+
+	id int
+}
+
+func (n *node) leaf() bool {
+	return n.left == null
+}
+
+// Pair is two proxies whose bounds overlap, A is always less than B
+type Pair struct {
+	A int
+	B int
+}
+
+// Tree is a dynamic bounding volume tree over proxies identified by ints. Each proxy is
+// stored with bounds that are fattened by a margin so that small movements don't
+// require the tree to be changed. The tree is kept balanced with rotations as proxies
+// are inserted and removed, and it has no bounds on the space it covers.
+//
+// Pairs of proxies whose fattened bounds overlap are cached and only recomputed for
+// proxies that were inserted or moved since the pairs were last updated
+type Tree struct {
+	nodes    []node
+	root     int
+	freeList int
+	margin   float64
+
+	leaves map[int]int
+	moved  map[int]bool
+	pairs  map[int]map[int]bool
+}
+
+func New(margin float64) *Tree {
+	return &Tree{
+		root:     null,
+		freeList: null,
+		margin:   margin,
+		leaves:   map[int]int{},
+		moved:    map[int]bool{},
+		pairs:    map[int]map[int]bool{},
+	}
+}
+
+// Insert adds a proxy to the tree. A proxy that's already in the tree is updated
+func (t *Tree) Insert(id int, bounds collider.BoundingBox) {
+	if _, ok := t.leaves[id]; ok {
+		t.Update(id, bounds)
+		return
+	}
+
+	leaf := t.allocate()
+	t.nodes[leaf].bounds = bounds.Expand(t.margin)
+	t.nodes[leaf].id = id
+	t.nodes[leaf].height = 0
+	t.insertLeaf(leaf)
+
+	t.leaves[id] = leaf
+	t.moved[id] = true
+}
+
+// Update moves a proxy to new bounds. The tree is only changed when the bounds have left
+// the proxy's fattened bounds, in which case true is returned
+func (t *Tree) Update(id int, bounds collider.BoundingBox) bool {
+	leaf, ok := t.leaves[id]
+	if !ok {
+		t.Insert(id, bounds)
+		return true
+	}
+
+	if t.nodes[leaf].bounds.Contains(&bounds) {
+		return false
+	}
+
+	t.removeLeaf(leaf)
+	t.nodes[leaf].bounds = bounds.Expand(t.margin)
+	t.insertLeaf(leaf)
+	t.moved[id] = true
+	return true
+}
+
+// Remove removes a proxy and every cached pair that it was part of
+func (t *Tree) Remove(id int) {
+	leaf, ok := t.leaves[id]
+	if !ok {
+		return
+	}
+
+	t.removeLeaf(leaf)
+	t.free(leaf)
+	delete(t.leaves, id)
+	delete(t.moved, id)
+
+	for other := range t.pairs[id] {
+		delete(t.pairs[other], id)
+	}
+	delete(t.pairs, id)
+}
+
+func (t *Tree) Contains(id int) bool {
+	_, ok := t.leaves[id]
+	return ok
+}
+
+// IDs returns the ids of every proxy in ascending order
+func (t *Tree) IDs() []int {
+	ids := make([]int, 0, len(t.leaves))
+	for id := range t.leaves {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+// FatBounds returns the fattened bounds that a proxy is stored with
+func (t *Tree) FatBounds(id int) (collider.BoundingBox, bool) {
+	leaf, ok := t.leaves[id]
+	if !ok {
+		return collider.BoundingBox{}, false
+	}
+	return t.nodes[leaf].bounds, true
+}
+
+// Height is the number of levels below the root, 0 for an empty tree or a single proxy
+func (t *Tree) Height() int {
+	if t.root == null {
+		return 0
+	}
+	return t.nodes[t.root].height
+}
+
+// UpdatePairs refreshes the cached pairs of every proxy that was inserted or moved
+// since the last update
+func (t *Tree) UpdatePairs() {
+	if len(t.moved) == 0 {
+		return
+	}
+
+	moved := make([]int, 0, len(t.moved))
+	for id := range t.moved {
+		moved = append(moved, id)
+	}
+	sort.Ints(moved)
+
+	for _, id := range moved {
+		bounds := t.nodes[t.leaves[id]].bounds
+
+		// drop pairs that the move separated
+		for other := range t.pairs[id] {
+			otherBounds := t.nodes[t.leaves[other]].bounds
+			if !bounds.Overlaps(&otherBounds) {
+				delete(t.pairs[id], other)
+				delete(t.pairs[other], id)
+			}
+		}
+
+		t.QueryBoundingBox(bounds, func(other int) bool {
+			if other != id {
+				t.addPair(id, other)
+			}
+			return true
+		})
+	}
+
+	t.moved = map[int]bool{}
+}
+
+// Pairs returns the cached pairs sorted by A then B
+func (t *Tree) Pairs() []Pair {
+	var pairs []Pair
+	for a, others := range t.pairs {
+		for b := range others {
+			if a < b {
+				pairs = append(pairs, Pair{A: a, B: b})
+			}
+		}
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i].A != pairs[j].A {
+			return pairs[i].A < pairs[j].A
+		}
+		return pairs[i].B < pairs[j].B
+	})
+	return pairs
+}
+
+func (t *Tree) addPair(a, b int) {
+	if t.pairs[a] == nil {
+		t.pairs[a] = map[int]bool{}
+	}
+	if t.pairs[b] == nil {
+		t.pairs[b] = map[int]bool{}
+	}
+	t.pairs[a][b] = true
+	t.pairs[b][a] = true
+}
+
+// QueryBoundingBox calls visit with every proxy whose fattened bounds overlap the box.
+// Returning false from visit stops the query
+func (t *Tree) QueryBoundingBox(bounds collider.BoundingBox, visit func(id int) bool) {
+	t.query(
+		func(nodeBounds *collider.BoundingBox) bool { return nodeBounds.Overlaps(&bounds) },
+		func(n *node) bool { return visit(n.id) },
+	)
+}
+
+// QueryRay calls visit with every proxy whose fattened bounds the ray enters before
+// maxT, along with the parameter along the ray where it enters. Returning false from
+// visit stops the query
+func (t *Tree) QueryRay(ray collider.Ray, maxT float64, visit func(id int, t float64) bool) {
+	var hitT float64
+	t.query(
+		func(nodeBounds *collider.BoundingBox) bool {
+			var ok bool
+			hitT, ok = checks.IntersectRayAABB(ray, nodeBounds)
+			return ok && hitT <= maxT
+		},
+		func(n *node) bool { return visit(n.id, hitT) },
+	)
+}
+
+// QuerySweep calls visit with every proxy whose fattened bounds the box touches while
+// moving by the displacement, along with the fraction of the displacement at which it
+// first touches. Returning false from visit stops the query
+func (t *Tree) QuerySweep(bounds collider.BoundingBox, displacement mgl64.Vec3, visit func(id int, t float64) bool) {
+	// sweeping a box against a box is the same as casting a ray from the box's center
+	// against the other box grown by the swept box's extents
+	halfExtents := bounds.MaxVertex.Sub(bounds.MinVertex).Mul(0.5)
+	ray := collider.Ray{Origin: bounds.Center(), Direction: displacement}
+
+	var hitT float64
+	t.query(
+		func(nodeBounds *collider.BoundingBox) bool {
+			grown := collider.BoundingBox{MinVertex: nodeBounds.MinVertex.Sub(halfExtents), MaxVertex: nodeBounds.MaxVertex.Add(halfExtents)}
+			var ok bool
+			hitT, ok = checks.IntersectRayAABB(ray, &grown)
+			return ok && hitT <= 1
+		},
+		func(n *node) bool { return visit(n.id, hitT) },
+	)
+}
+
+// Walk calls visit with the bounds of every node and its depth, starting from the root
+func (t *Tree) Walk(visit func(bounds collider.BoundingBox, depth int, leaf bool)) {
+	if t.root == null {
+		return
+	}
+
+	type entry struct {
+		index int
+		depth int
+	}
+	stack := []entry{{index: t.root}}
+	for len(stack) > 0 {
+		e := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		n := &t.nodes[e.index]
+		visit(n.bounds, e.depth, n.leaf())
+		if !n.leaf() {
+			stack = append(stack, entry{index: n.right, depth: e.depth + 1}, entry{index: n.left, depth: e.depth + 1})
+		}
+	}
+}
+
+// query walks the nodes that pass the test and calls visit with the leaves that are
+// reached. test is called on a leaf right before visit
+func (t *Tree) query(test func(bounds *collider.BoundingBox) bool, visit func(n *node) bool) {
+	if t.root == null {
+		return
+	}
+
+	stack := make([]int, 1, 64)
+	stack[0] = t.root
+	for len(stack) > 0 {
+		n := &t.nodes[stack[len(stack)-1]]
+		stack = stack[:len(stack)-1]
+
+		if !test(&n.bounds) {
+			continue
+		}
+
+		if n.leaf() {
+			if !visit(n) {
+				return
+			}
+			continue
+		}
+		stack = append(stack, n.right, n.left)
+	}
+}
+
+func (t *Tree) allocate() int {
+	if t.freeList == null {
+		t.nodes = append(t.nodes, node{})
+		t.freeList = len(t.nodes) - 1
+		t.nodes[t.freeList].left = null
+		t.nodes[t.freeList].parent = null
+	}
+
+	index := t.freeList
+	t.freeList = t.nodes[index].parent
+	t.nodes[index] = node{parent: null, left: null, right: null}
+	return index
+}
+
+// free adds a node to the free list, which is threaded through the parent indices
+func (t *Tree) free(index int) {
+	t.nodes[index] = node{parent: t.freeList, left: null, right: null, height: -1}
+	t.freeList = index
+}
+
+// insertLeaf finds the best sibling for the leaf by the surface area heuristic, see
+// Erin Catto's "Dynamic Bounding Volume Hierarchies"
+func (t *Tree) insertLeaf(leaf int) {
+	if t.root == null {
+		t.root = leaf
+		t.nodes[leaf].parent = null
+		return
+	}
+
+	bounds := t.nodes[leaf].bounds
+	index := t.root
+	for !t.nodes[index].leaf() {
+		n := &t.nodes[index]
+		area := n.bounds.SurfaceArea()
+		combined := n.bounds.Union(&bounds)
+		combinedArea := combined.SurfaceArea()
+
+		// cost of creating a new parent for this node and the leaf
+		cost := 2 * combinedArea
+		// minimum cost of pushing the leaf further down the tree
+		inheritanceCost := 2 * (combinedArea - area)
+
+		leftCost := t.descendCost(n.left, bounds) + inheritanceCost
+		rightCost := t.descendCost(n.right, bounds) + inheritanceCost
+		if cost < leftCost && cost < rightCost {
+			break
+		}
+
+		if leftCost < rightCost {
+			index = n.left
+		} else {
+			index = n.right
+		}
+	}
+
+	sibling := index
+	oldParent := t.nodes[sibling].parent
+	newParent := t.allocate()
+	t.nodes[newParent].parent = oldParent
+	t.nodes[newParent].bounds = bounds.Union(&t.nodes[sibling].bounds)
+	t.nodes[newParent].height = t.nodes[sibling].height + 1
+	t.nodes[newParent].left = sibling
+	t.nodes[newParent].right = leaf
+	t.nodes[sibling].parent = newParent
+	t.nodes[leaf].parent = newParent
+
+	if oldParent == null {
+		t.root = newParent
+	} else if t.nodes[oldParent].left == sibling {
+		t.nodes[oldParent].left = newParent
+	} else {
+		t.nodes[oldParent].right = newParent
+	}
+
+	t.refit(t.nodes[leaf].parent)
+}
+
+func (t *Tree) descendCost(index int, bounds collider.BoundingBox) float64 {
+	n := &t.nodes[index]
+	combined := bounds.Union(&n.bounds)
+	if n.leaf() {
+		return combined.SurfaceArea()
+	}
+	return combined.SurfaceArea() - n.bounds.SurfaceArea()
+}
+
+func (t *Tree) removeLeaf(leaf int) {
+	if leaf == t.root {
+		t.root = null
+		return
+	}
+
+	parent := t.nodes[leaf].parent
+	grandParent := t.nodes[parent].parent
+	sibling := t.nodes[parent].left
+	if sibling == leaf {
+		sibling = t.nodes[parent].right
+	}
+
+	t.free(parent)
+	if grandParent == null {
+		t.root = sibling
+		t.nodes[sibling].parent = null
+		return
+	}
+
+	if t.nodes[grandParent].left == parent {
+		t.nodes[grandParent].left = sibling
+	} else {
+		t.nodes[grandParent].right = sibling
+	}
+	t.nodes[sibling].parent = grandParent
+	t.refit(grandParent)
+}
+
+// refit rebalances and recomputes the bounds and heights of the node and its ancestors
+func (t *Tree) refit(index int) {
+	for index != null {
+		index = t.balance(index)
+
+		n := &t.nodes[index]
+		left := &t.nodes[n.left]
+		right := &t.nodes[n.right]
+		n.height = 1 + max(left.height, right.height)
+		n.bounds = left.bounds.Union(&right.bounds)
+
+		index = n.parent
+	}
+}
+
+// balance rotates the node's taller child up if the node is imbalanced and returns the
+// index of the node that took its place
+func (t *Tree) balance(iA int) int {
+	a := &t.nodes[iA]
+	if a.leaf() || a.height < 2 {
+		return iA
+	}
+
+	iB := a.left
+	iC := a.right
+	b := &t.nodes[iB]
+	c := &t.nodes[iC]
+
+	balance := c.height - b.height
+	if balance > 1 {
+		t.rotateUp(iA, iC, false)
+		return iC
+	}
+	if balance < -1 {
+		t.rotateUp(iA, iB, true)
+		return iB
+	}
+	return iA
+}
+
+// rotateUp swaps node A with its child X, which becomes the parent of A. X's shorter
+// child is given to A in place of X
+func (t *Tree) rotateUp(iA, iX int, xIsLeft bool) {
+	a := &t.nodes[iA]
+	x := &t.nodes[iX]
+
+	iF := x.left
+	iG := x.right
+	f := &t.nodes[iF]
+	g := &t.nodes[iG]
+
+	x.left = iA
+	x.parent = a.parent
+	a.parent = iX
+
+	if x.parent == null {
+		t.root = iX
+	} else if t.nodes[x.parent].left == iA {
+		t.nodes[x.parent].left = iX
+	} else {
+		t.nodes[x.parent].right = iX
+	}
+
+	// X keeps its taller child and A takes the shorter one
+	iKeep, iGive := iF, iG
+	if f.height <= g.height {
+		iKeep, iGive = iG, iF
+	}
+	x.right = iKeep
+	if xIsLeft {
+		a.left = iGive
+	} else {
+		a.right = iGive
+	}
+	t.nodes[iGive].parent = iA
+
+	aLeft := &t.nodes[a.left]
+	aRight := &t.nodes[a.right]
+	a.bounds = aLeft.bounds.Union(&aRight.bounds)
+	a.height = 1 + max(aLeft.height, aRight.height)
+
+	keep := &t.nodes[iKeep]
+	x.bounds = a.bounds.Union(&keep.bounds)
+	x.height = 1 + max(a.height, keep.height)
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
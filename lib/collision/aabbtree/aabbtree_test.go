@@ -0,0 +1,186 @@
+package aabbtree_test
+
+import (
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/kkevinchou/kito/lib/collision/aabbtree"
+	"github.com/kkevinchou/kito/lib/collision/collider"
+)
+
+func box(center mgl64.Vec3, halfSize float64) collider.BoundingBox {
+	half := mgl64.Vec3{halfSize, halfSize, halfSize}
+	return collider.BoundingBox{MinVertex: center.Sub(half), MaxVertex: center.Add(half)}
+}
+
+func randomPosition(random *rand.Rand, spread float64) mgl64.Vec3 {
+	return mgl64.Vec3{(random.Float64() - 0.5) * spread, (random.Float64() - 0.5) * spread, (random.Float64() - 0.5) * spread}
+}
+
+// bruteForcePairs finds every pair of overlapping fat bounds
+func bruteForcePairs(tree *aabbtree.Tree) []aabbtree.Pair {
+	var pairs []aabbtree.Pair
+	ids := tree.IDs()
+	for i, a := range ids {
+		aBounds, _ := tree.FatBounds(a)
+		for _, b := range ids[i+1:] {
+			bBounds, _ := tree.FatBounds(b)
+			if aBounds.Overlaps(&bBounds) {
+				pairs = append(pairs, aabbtree.Pair{A: a, B: b})
+			}
+		}
+	}
+	return pairs
+}
+
+func TestTreeMatchesBruteForce(t *testing.T) {
+	random := rand.New(rand.NewSource(0))
+	tree := aabbtree.New(1)
+
+	positions := map[int]mgl64.Vec3{}
+	// far apart positions to check that the tree isn't bounded
+	for id := 0; id < 500; id++ {
+		positions[id] = randomPosition(random, 100000)
+		tree.Insert(id, box(positions[id], 5))
+	}
+	for id := 500; id < 1000; id++ {
+		positions[id] = randomPosition(random, 200)
+		tree.Insert(id, box(positions[id], 5))
+	}
+
+	for frame := 0; frame < 20; frame++ {
+		for id := range positions {
+			if random.Float64() < 0.3 {
+				positions[id] = positions[id].Add(randomPosition(random, 4))
+				tree.Update(id, box(positions[id], 5))
+			}
+		}
+		for i := 0; i < 10; i++ {
+			id := random.Intn(1000)
+			if tree.Contains(id) {
+				tree.Remove(id)
+				delete(positions, id)
+			}
+		}
+		tree.UpdatePairs()
+
+		if !reflect.DeepEqual(tree.Pairs(), bruteForcePairs(tree)) {
+			t.Fatalf("expected the cached pairs to match brute force on frame %d", frame)
+		}
+	}
+
+	// a perfectly balanced tree of ~1000 leaves has a height of 10
+	if tree.Height() > 20 {
+		t.Fatalf("expected the tree to stay balanced but its height is %d", tree.Height())
+	}
+
+	query := box(mgl64.Vec3{0, 0, 0}, 30)
+	var actual []int
+	tree.QueryBoundingBox(query, func(id int) bool {
+		actual = append(actual, id)
+		return true
+	})
+	sort.Ints(actual)
+
+	var expected []int
+	for _, id := range tree.IDs() {
+		bounds, _ := tree.FatBounds(id)
+		if bounds.Overlaps(&query) {
+			expected = append(expected, id)
+		}
+	}
+	if len(expected) == 0 || !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("expected query results %v but got %v", expected, actual)
+	}
+}
+
+func TestUpdateWithinMargin(t *testing.T) {
+	tree := aabbtree.New(1)
+	tree.Insert(1, box(mgl64.Vec3{0, 0, 0}, 1))
+	tree.Insert(2, box(mgl64.Vec3{10, 0, 0}, 1))
+	tree.UpdatePairs()
+
+	if tree.Update(1, box(mgl64.Vec3{0.5, 0, 0}, 1)) {
+		t.Fatal("expected a move within the margin to not change the tree")
+	}
+	if !tree.Update(1, box(mgl64.Vec3{8, 0, 0}, 1)) {
+		t.Fatal("expected a move outside the margin to change the tree")
+	}
+
+	tree.UpdatePairs()
+	if !reflect.DeepEqual(tree.Pairs(), []aabbtree.Pair{{A: 1, B: 2}}) {
+		t.Fatalf("expected the moved proxies to be paired but got %v", tree.Pairs())
+	}
+
+	tree.Update(2, box(mgl64.Vec3{100, 0, 0}, 1))
+	tree.UpdatePairs()
+	if len(tree.Pairs()) != 0 {
+		t.Fatalf("expected the pair to be dropped after moving apart but got %v", tree.Pairs())
+	}
+
+	tree.Update(2, box(mgl64.Vec3{8, 0, 0}, 1))
+	tree.UpdatePairs()
+	tree.Remove(1)
+	if len(tree.Pairs()) != 0 || tree.Contains(1) {
+		t.Fatal("expected removing a proxy to remove its pairs")
+	}
+}
+
+func TestRayAndSweep(t *testing.T) {
+	tree := aabbtree.New(0)
+	for i := 0; i < 10; i++ {
+		tree.Insert(i, box(mgl64.Vec3{float64(i) * 10, 0, 0}, 1))
+	}
+
+	hits := map[int]float64{}
+	tree.QueryRay(collider.Ray{Origin: mgl64.Vec3{-5, 0, 0}, Direction: mgl64.Vec3{1, 0, 0}}, 30, func(id int, t float64) bool {
+		hits[id] = t
+		return true
+	})
+	if !reflect.DeepEqual(hits, map[int]float64{0: 4, 1: 14, 2: 24}) {
+		t.Fatalf("unexpected ray hits %v", hits)
+	}
+
+	hits = map[int]float64{}
+	tree.QueryRay(collider.Ray{Origin: mgl64.Vec3{-5, 5, 0}, Direction: mgl64.Vec3{1, 0, 0}}, 100, func(id int, t float64) bool {
+		hits[id] = t
+		return true
+	})
+	if len(hits) != 0 {
+		t.Fatalf("expected a ray above the boxes to miss but got %v", hits)
+	}
+
+	// a box sweeping above the row only touches once it has moved down into it
+	hits = map[int]float64{}
+	tree.QuerySweep(box(mgl64.Vec3{20, 5, 0}, 1), mgl64.Vec3{0, -4, 0}, func(id int, t float64) bool {
+		hits[id] = t
+		return true
+	})
+	if !reflect.DeepEqual(hits, map[int]float64{2: 0.75}) {
+		t.Fatalf("unexpected sweep hits %v", hits)
+	}
+}
+
+func BenchmarkUpdatePairs(b *testing.B) {
+	random := rand.New(rand.NewSource(0))
+	tree := aabbtree.New(2)
+
+	positions := make([]mgl64.Vec3, 2000)
+	for id := range positions {
+		positions[id] = randomPosition(random, 5000)
+		tree.Insert(id, box(positions[id], 5))
+	}
+	tree.UpdatePairs()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for id := range positions {
+			positions[id] = positions[id].Add(randomPosition(random, 1))
+			tree.Update(id, box(positions[id], 5))
+		}
+		tree.UpdatePairs()
+	}
+}
@@ -1,6 +1,8 @@
 package checks
 
 import (
+	"math"
+
 	"github.com/go-gl/mathgl/mgl64"
 	"github.com/kkevinchou/kito/lib/collision/collider"
 )
@@ -46,30 +48,87 @@ func IntersectRayTriangle(ray collider.Ray, triangle collider.Triangle) *mgl64.V
 	return nil
 }
 
+// IntersectRayTriMesh returns the closest point where the ray hits the mesh, walking
+// only the nodes of the mesh's BVH that the ray passes through
 func IntersectRayTriMesh(ray collider.Ray, triMesh collider.TriMesh) *mgl64.Vec3 {
+	point, _ := IntersectRayTriMeshTriangle(ray, triMesh)
+	return point
+}
+
+// IntersectRayTriMeshTriangle returns the closest point where the ray hits the mesh
+// along with the index of the triangle that was hit
+func IntersectRayTriMeshTriangle(ray collider.Ray, triMesh collider.TriMesh) (*mgl64.Vec3, int) {
 	var minDist *float64
 	var minPoint *mgl64.Vec3
+	minIndex := -1
 
-	for _, t := range triMesh.Triangles {
-		point := IntersectRayTriangle(ray, t)
+	visit := func(i int) bool {
+		point := IntersectRayTriangle(ray, triMesh.Triangles[i])
 		if point == nil {
-			continue
+			return true
 		}
 
-		if minDist == nil {
-			dst := ray.Origin.Sub(*point).Len()
+		dst := ray.Origin.Sub(*point).Len()
+		if minDist == nil || dst < *minDist {
 			minDist = &dst
 			minPoint = point
-		} else {
-			dst := ray.Origin.Sub(*point).Len()
-			if dst < *minDist {
-				minDist = &dst
-				minPoint = point
+			minIndex = i
+		}
+		return true
+	}
+
+	if triMesh.BVH == nil {
+		for i := range triMesh.Triangles {
+			visit(i)
+		}
+		return minPoint, minIndex
+	}
+
+	directionLength := ray.Direction.Len()
+	triMesh.BVH.Query(
+		func(bounds *collider.BoundingBox) bool {
+			t, ok := IntersectRayAABB(ray, bounds)
+			if !ok {
+				return false
+			}
+			// skip nodes that start further away than the closest hit so far
+			return minDist == nil || t*directionLength <= *minDist
+		},
+		visit,
+	)
+
+	return minPoint, minIndex
+}
+
+// IntersectRayAABB returns the parameter along the ray at which it enters the box, or 0
+// if the ray starts inside of it. Real Time Collision Detection - page 180
+func IntersectRayAABB(ray collider.Ray, boundingBox *collider.BoundingBox) (float64, bool) {
+	tMin := 0.0
+	tMax := math.MaxFloat64
+
+	for i := 0; i < 3; i++ {
+		if math.Abs(ray.Direction[i]) < epsilon {
+			// the ray is parallel to the slab, it misses unless it starts within it
+			if ray.Origin[i] < boundingBox.MinVertex[i] || ray.Origin[i] > boundingBox.MaxVertex[i] {
+				return 0, false
 			}
+			continue
+		}
+
+		ood := 1 / ray.Direction[i]
+		t1 := (boundingBox.MinVertex[i] - ray.Origin[i]) * ood
+		t2 := (boundingBox.MaxVertex[i] - ray.Origin[i]) * ood
+		if t1 > t2 {
+			t1, t2 = t2, t1
+		}
+		tMin = math.Max(tMin, t1)
+		tMax = math.Min(tMax, t2)
+		if tMin > tMax {
+			return 0, false
 		}
 	}
 
-	return minPoint
+	return tMin, true
 }
 
 // ClosestPointOnLineToPoint returns the point on line segment AB that is closest
@@ -197,6 +256,67 @@ func ClosestPointsLineVSLine(line1 collider.Line, line2 collider.Line) ([]mgl64.
 	return []mgl64.Vec3{c1, c2}, c1.Sub(c2).Len()
 }
 
+// ClosestPointOnTriangleToPoint returns the point on the triangle that is closest to
+// point P. Real Time Collision Detection - page 141
+func ClosestPointOnTriangleToPoint(triangle collider.Triangle, p mgl64.Vec3) mgl64.Vec3 {
+	a := triangle.Points[0]
+	b := triangle.Points[1]
+	c := triangle.Points[2]
+
+	// check if P is in the vertex region outside A
+	ab := b.Sub(a)
+	ac := c.Sub(a)
+	ap := p.Sub(a)
+	d1 := ab.Dot(ap)
+	d2 := ac.Dot(ap)
+	if d1 <= 0 && d2 <= 0 {
+		return a
+	}
+
+	// check if P is in the vertex region outside B
+	bp := p.Sub(b)
+	d3 := ab.Dot(bp)
+	d4 := ac.Dot(bp)
+	if d3 >= 0 && d4 <= d3 {
+		return b
+	}
+
+	// check if P is in the edge region of AB
+	vc := d1*d4 - d3*d2
+	if vc <= 0 && d1 >= 0 && d3 <= 0 {
+		v := d1 / (d1 - d3)
+		return a.Add(ab.Mul(v))
+	}
+
+	// check if P is in the vertex region outside C
+	cp := p.Sub(c)
+	d5 := ab.Dot(cp)
+	d6 := ac.Dot(cp)
+	if d6 >= 0 && d5 <= d6 {
+		return c
+	}
+
+	// check if P is in the edge region of AC
+	vb := d5*d2 - d1*d6
+	if vb <= 0 && d2 >= 0 && d6 <= 0 {
+		w := d2 / (d2 - d6)
+		return a.Add(ac.Mul(w))
+	}
+
+	// check if P is in the edge region of BC
+	va := d3*d6 - d5*d4
+	if va <= 0 && (d4-d3) >= 0 && (d5-d6) >= 0 {
+		w := (d4 - d3) / ((d4 - d3) + (d5 - d6))
+		return b.Add(c.Sub(b).Mul(w))
+	}
+
+	// P is inside the face region
+	denom := 1 / (va + vb + vc)
+	v := vb * denom
+	w := vc * denom
+	return a.Add(ab.Mul(v)).Add(ac.Mul(w))
+}
+
 func ProjectPointOnTriangle(point mgl64.Vec3, triangle collider.Triangle) (mgl64.Vec3, bool) {
 	ray := collider.Ray{
 		Origin:    point,
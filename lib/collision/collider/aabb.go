@@ -1,6 +1,8 @@
 package collider
 
 import (
+	"math"
+
 	"github.com/go-gl/mathgl/mgl64"
 	"github.com/kkevinchou/kito/lib/libutils"
 	"github.com/kkevinchou/kito/lib/model"
@@ -15,6 +17,62 @@ func (c *BoundingBox) Transform(position mgl64.Vec3) *BoundingBox {
 	return &BoundingBox{MinVertex: c.MinVertex.Add(position), MaxVertex: c.MaxVertex.Add(position)}
 }
 
+// Rotate returns the smallest box that contains the box rotated about the origin
+func (c *BoundingBox) Rotate(orientation mgl64.Quat) *BoundingBox {
+	var corners []mgl64.Vec3
+	for i := 0; i < 8; i++ {
+		corner := c.MinVertex
+		for axis := 0; axis < 3; axis++ {
+			if i&(1<<axis) != 0 {
+				corner[axis] = c.MaxVertex[axis]
+			}
+		}
+		corners = append(corners, orientation.Rotate(corner))
+	}
+	return BoundingBoxFromVertices(corners)
+}
+
+// Union returns the smallest box that contains both boxes
+func (c *BoundingBox) Union(other *BoundingBox) BoundingBox {
+	return BoundingBox{
+		MinVertex: mgl64.Vec3{math.Min(c.MinVertex[0], other.MinVertex[0]), math.Min(c.MinVertex[1], other.MinVertex[1]), math.Min(c.MinVertex[2], other.MinVertex[2])},
+		MaxVertex: mgl64.Vec3{math.Max(c.MaxVertex[0], other.MaxVertex[0]), math.Max(c.MaxVertex[1], other.MaxVertex[1]), math.Max(c.MaxVertex[2], other.MaxVertex[2])},
+	}
+}
+
+// Expand returns the box grown by the amount in every direction
+func (c *BoundingBox) Expand(amount float64) BoundingBox {
+	delta := mgl64.Vec3{amount, amount, amount}
+	return BoundingBox{MinVertex: c.MinVertex.Sub(delta), MaxVertex: c.MaxVertex.Add(delta)}
+}
+
+func (c *BoundingBox) Overlaps(other *BoundingBox) bool {
+	for i := 0; i < 3; i++ {
+		if c.MaxVertex[i] < other.MinVertex[i] || c.MinVertex[i] > other.MaxVertex[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *BoundingBox) Contains(other *BoundingBox) bool {
+	for i := 0; i < 3; i++ {
+		if other.MinVertex[i] < c.MinVertex[i] || other.MaxVertex[i] > c.MaxVertex[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *BoundingBox) Center() mgl64.Vec3 {
+	return c.MinVertex.Add(c.MaxVertex).Mul(0.5)
+}
+
+func (c *BoundingBox) SurfaceArea() float64 {
+	d := c.MaxVertex.Sub(c.MinVertex)
+	return 2 * (d[0]*d[1] + d[1]*d[2] + d[2]*d[0])
+}
+
 func BoundingBoxFromVertices(vertices []mgl64.Vec3) *BoundingBox {
 	var minX, minY, minZ, maxX, maxY, maxZ float64
 
@@ -52,9 +110,11 @@ func BoundingBoxFromVertices(vertices []mgl64.Vec3) *BoundingBox {
 }
 
 func BoundingBoxFromCapsule(capsule Capsule) *BoundingBox {
+	radius := mgl64.Vec3{capsule.Radius, capsule.Radius, capsule.Radius}
+	ends := BoundingBoxFromVertices([]mgl64.Vec3{capsule.Top, capsule.Bottom})
 	return &BoundingBox{
-		MinVertex: capsule.Bottom.Add(mgl64.Vec3{-capsule.Radius, -capsule.Radius, -capsule.Radius}),
-		MaxVertex: capsule.Top.Add(mgl64.Vec3{capsule.Radius, capsule.Radius, capsule.Radius}),
+		MinVertex: ends.MinVertex.Sub(radius),
+		MaxVertex: ends.MaxVertex.Add(radius),
 	}
 }
 
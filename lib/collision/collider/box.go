@@ -0,0 +1,76 @@
+package collider
+
+import "github.com/go-gl/mathgl/mgl64"
+
+// Box is a box that can be rotated away from the world axes
+type Box struct {
+	Center      mgl64.Vec3
+	HalfExtents mgl64.Vec3
+	Orientation mgl64.Quat
+}
+
+func NewBox(center, halfExtents mgl64.Vec3, orientation mgl64.Quat) Box {
+	return Box{
+		Center:      center,
+		HalfExtents: halfExtents,
+		Orientation: orientation,
+	}
+}
+
+// NewBoxFromBoundingBox creates an axis aligned box that fills the bounding box
+func NewBoxFromBoundingBox(boundingBox BoundingBox) Box {
+	return NewBox(
+		boundingBox.MinVertex.Add(boundingBox.MaxVertex).Mul(0.5),
+		boundingBox.MaxVertex.Sub(boundingBox.MinVertex).Mul(0.5),
+		mgl64.QuatIdent(),
+	)
+}
+
+func (b Box) Transform(position mgl64.Vec3) Box {
+	return NewBox(b.Center.Add(position), b.HalfExtents, b.Orientation)
+}
+
+// Rotate rotates the box around the origin
+func (b Box) Rotate(orientation mgl64.Quat) Box {
+	return NewBox(orientation.Rotate(b.Center), b.HalfExtents, orientation.Mul(b.Orientation).Normalize())
+}
+
+// Axes returns the directions of the box's local x, y and z axes
+func (b Box) Axes() [3]mgl64.Vec3 {
+	return [3]mgl64.Vec3{
+		b.Orientation.Rotate(mgl64.Vec3{1, 0, 0}),
+		b.Orientation.Rotate(mgl64.Vec3{0, 1, 0}),
+		b.Orientation.Rotate(mgl64.Vec3{0, 0, 1}),
+	}
+}
+
+// Vertices returns the 8 corners of the box. Corner i is on the positive side of
+// axis j when bit j of i is set
+func (b Box) Vertices() []mgl64.Vec3 {
+	axes := b.Axes()
+	vertices := make([]mgl64.Vec3, 8)
+	for i := range vertices {
+		vertex := b.Center
+		for j, axis := range axes {
+			if i&(1<<j) != 0 {
+				vertex = vertex.Add(axis.Mul(b.HalfExtents[j]))
+			} else {
+				vertex = vertex.Sub(axis.Mul(b.HalfExtents[j]))
+			}
+		}
+		vertices[i] = vertex
+	}
+	return vertices
+}
+
+func (b Box) Support(direction mgl64.Vec3) mgl64.Vec3 {
+	result := b.Center
+	for i, axis := range b.Axes() {
+		if direction.Dot(axis) >= 0 {
+			result = result.Add(axis.Mul(b.HalfExtents[i]))
+		} else {
+			result = result.Sub(axis.Mul(b.HalfExtents[i]))
+		}
+	}
+	return result
+}
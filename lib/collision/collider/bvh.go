@@ -0,0 +1,278 @@
+package collider
+
+import (
+	"math"
+	"sort"
+
+	"github.com/go-gl/mathgl/mgl64"
+)
+
+const (
+	// bvhLeafSize is the number of triangles below which a node is never split
+	bvhLeafSize = 4
+	// bvhMaxLeafSize is the number of triangles above which a node is always split,
+	// even when the surface area heuristic prefers a leaf
+	bvhMaxLeafSize = 16
+	// bvhBinCount is the number of buckets centroids are sorted into when evaluating
+	// the surface area heuristic
+	bvhBinCount = 12
+)
+
+// BVHNode is a node of a bounding volume hierarchy. Leaf nodes reference a range of
+// BVH.Triangles, interior nodes reference their two children
+type BVHNode struct {
+	Bounds BoundingBox
+
+	Left  int
+	Right int
+
+	First int
+	Count int
+}
+
+func (n *BVHNode) Leaf() bool {
+	return n.Count > 0
+}
+
+// BVH is a bounding volume hierarchy over the triangles of a TriMesh, built with the
+// surface area heuristic. Nodes are stored depth first so that children always come
+// after their parent, the root is the first node
+type BVH struct {
+	Nodes []BVHNode
+	// Triangles are indices into the mesh's triangles, ordered so that the triangles
+	// of each leaf are contiguous
+	Triangles []int
+}
+
+type bvhBin struct {
+	bounds BoundingBox
+	count  int
+}
+
+// NewBVH builds a hierarchy over the triangles
+func NewBVH(triangles []Triangle) *BVH {
+	bvh := &BVH{
+		Nodes:     make([]BVHNode, 0, 2*len(triangles)),
+		Triangles: make([]int, len(triangles)),
+	}
+	if len(triangles) == 0 {
+		return bvh
+	}
+
+	bounds := make([]BoundingBox, len(triangles))
+	centroids := make([]mgl64.Vec3, len(triangles))
+	for i, triangle := range triangles {
+		bvh.Triangles[i] = i
+		bounds[i] = triangleBounds(triangle)
+		centroids[i] = bounds[i].MinVertex.Add(bounds[i].MaxVertex).Mul(0.5)
+	}
+
+	bvh.build(0, len(triangles), bounds, centroids)
+	return bvh
+}
+
+func (b *BVH) build(first, count int, bounds []BoundingBox, centroids []mgl64.Vec3) int {
+	index := len(b.Nodes)
+	b.Nodes = append(b.Nodes, BVHNode{First: first, Count: count})
+
+	nodeBounds := bounds[b.Triangles[first]]
+	centroidBounds := BoundingBox{MinVertex: centroids[b.Triangles[first]], MaxVertex: centroids[b.Triangles[first]]}
+	for _, triangle := range b.Triangles[first : first+count] {
+		nodeBounds = union(nodeBounds, bounds[triangle])
+		centroidBounds = union(centroidBounds, BoundingBox{MinVertex: centroids[triangle], MaxVertex: centroids[triangle]})
+	}
+	b.Nodes[index].Bounds = nodeBounds
+
+	if count <= bvhLeafSize {
+		return index
+	}
+
+	// split along the axis where the centroids are most spread out
+	extent := centroidBounds.MaxVertex.Sub(centroidBounds.MinVertex)
+	axis := 0
+	if extent[1] > extent[axis] {
+		axis = 1
+	}
+	if extent[2] > extent[axis] {
+		axis = 2
+	}
+	if extent[axis] <= 0 {
+		// every centroid is in the same place, there's no split that separates them
+		return index
+	}
+
+	mid, ok := b.splitSAH(first, count, axis, centroidBounds, nodeBounds, bounds, centroids)
+	if !ok {
+		if count <= bvhMaxLeafSize {
+			return index
+		}
+		mid = b.splitMedian(first, count, axis, centroids)
+	}
+
+	left := b.build(first, mid-first, bounds, centroids)
+	right := b.build(mid, first+count-mid, bounds, centroids)
+	b.Nodes[index] = BVHNode{Bounds: nodeBounds, Left: left, Right: right}
+	return index
+}
+
+// splitSAH partitions the node's triangles at the binned split with the lowest surface
+// area heuristic cost and returns the start of the right half. ok is false when no
+// split is cheaper than keeping the triangles in a leaf
+func (b *BVH) splitSAH(first, count, axis int, centroidBounds, nodeBounds BoundingBox, bounds []BoundingBox, centroids []mgl64.Vec3) (int, bool) {
+	min := centroidBounds.MinVertex[axis]
+	scale := bvhBinCount / (centroidBounds.MaxVertex[axis] - min)
+	binOf := func(triangle int) int {
+		bin := int((centroids[triangle][axis] - min) * scale)
+		if bin >= bvhBinCount {
+			bin = bvhBinCount - 1
+		}
+		return bin
+	}
+
+	var bins [bvhBinCount]bvhBin
+	for _, triangle := range b.Triangles[first : first+count] {
+		bin := &bins[binOf(triangle)]
+		if bin.count == 0 {
+			bin.bounds = bounds[triangle]
+		} else {
+			bin.bounds = union(bin.bounds, bounds[triangle])
+		}
+		bin.count++
+	}
+
+	// sweep from the right to find the area and count of everything right of each split
+	var rightAreas [bvhBinCount]float64
+	var rightCounts [bvhBinCount]int
+	var accumulated bvhBin
+	for i := bvhBinCount - 1; i > 0; i-- {
+		accumulated = mergeBin(accumulated, bins[i])
+		rightAreas[i] = surfaceArea(accumulated.bounds)
+		rightCounts[i] = accumulated.count
+	}
+
+	bestCost := math.Inf(1)
+	bestSplit := -1
+	accumulated = bvhBin{}
+	for i := 1; i < bvhBinCount; i++ {
+		accumulated = mergeBin(accumulated, bins[i-1])
+		if accumulated.count == 0 || rightCounts[i] == 0 {
+			continue
+		}
+		cost := surfaceArea(accumulated.bounds)*float64(accumulated.count) + rightAreas[i]*float64(rightCounts[i])
+		if cost < bestCost {
+			bestCost = cost
+			bestSplit = i
+		}
+	}
+
+	// the traversal cost is folded into the leaf cost, which is relative to the area of
+	// the node
+	leafCost := surfaceArea(nodeBounds) * float64(count)
+	if bestSplit < 0 || bestCost >= leafCost {
+		return 0, false
+	}
+
+	triangles := b.Triangles[first : first+count]
+	mid := 0
+	for i := range triangles {
+		if binOf(triangles[i]) < bestSplit {
+			triangles[i], triangles[mid] = triangles[mid], triangles[i]
+			mid++
+		}
+	}
+	return first + mid, true
+}
+
+// splitMedian partitions the node's triangles in half along the axis
+func (b *BVH) splitMedian(first, count, axis int, centroids []mgl64.Vec3) int {
+	triangles := b.Triangles[first : first+count]
+	sort.Slice(triangles, func(i, j int) bool {
+		return centroids[triangles[i]][axis] < centroids[triangles[j]][axis]
+	})
+	return first + count/2
+}
+
+// Refit recomputes the bounds of every node for the triangles, which must be the same
+// triangles the hierarchy was built from after being moved. The structure of the tree is
+// kept, so queries stay correct though they get slower the more the triangles are
+// deformed relative to each other
+func (b *BVH) Refit(triangles []Triangle) {
+	for i := len(b.Nodes) - 1; i >= 0; i-- {
+		node := &b.Nodes[i]
+		if node.Leaf() {
+			node.Bounds = triangleBounds(triangles[b.Triangles[node.First]])
+			for _, triangle := range b.Triangles[node.First+1 : node.First+node.Count] {
+				node.Bounds = union(node.Bounds, triangleBounds(triangles[triangle]))
+			}
+			continue
+		}
+		node.Bounds = union(b.Nodes[node.Left].Bounds, b.Nodes[node.Right].Bounds)
+	}
+}
+
+// Copy returns a hierarchy with its own node bounds that can be refit independently
+func (b *BVH) Copy() *BVH {
+	return &BVH{
+		Nodes:     append([]BVHNode{}, b.Nodes...),
+		Triangles: b.Triangles,
+	}
+}
+
+// Query walks the nodes whose bounds pass the test and calls visit with the index of
+// every triangle in the leaves that are reached. Returning false from visit stops the
+// query
+func (b *BVH) Query(test func(bounds *BoundingBox) bool, visit func(triangle int) bool) {
+	if len(b.Nodes) == 0 {
+		return
+	}
+
+	stack := make([]int, 1, 64)
+	for len(stack) > 0 {
+		node := &b.Nodes[stack[len(stack)-1]]
+		stack = stack[:len(stack)-1]
+
+		if !test(&node.Bounds) {
+			continue
+		}
+
+		if node.Leaf() {
+			for _, triangle := range b.Triangles[node.First : node.First+node.Count] {
+				if !visit(triangle) {
+					return
+				}
+			}
+			continue
+		}
+		stack = append(stack, node.Right, node.Left)
+	}
+}
+
+func triangleBounds(triangle Triangle) BoundingBox {
+	bounds := BoundingBox{MinVertex: triangle.Points[0], MaxVertex: triangle.Points[0]}
+	for _, point := range triangle.Points[1:] {
+		bounds = union(bounds, BoundingBox{MinVertex: point, MaxVertex: point})
+	}
+	return bounds
+}
+
+func union(a, b BoundingBox) BoundingBox {
+	return a.Union(&b)
+}
+
+func overlaps(a, b BoundingBox) bool {
+	return a.Overlaps(&b)
+}
+
+func surfaceArea(box BoundingBox) float64 {
+	return box.SurfaceArea()
+}
+
+func mergeBin(a, b bvhBin) bvhBin {
+	if a.count == 0 {
+		return b
+	}
+	if b.count == 0 {
+		return a
+	}
+	return bvhBin{bounds: union(a.bounds, b.bounds), count: a.count + b.count}
+}
@@ -0,0 +1,90 @@
+package collider_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/kkevinchou/kito/lib/collision/collider"
+)
+
+// a grid of unit squares on the xz plane, two triangles per square
+func gridTriangles(size int) []collider.Triangle {
+	var triangles []collider.Triangle
+	for x := 0; x < size; x++ {
+		for z := 0; z < size; z++ {
+			p := mgl64.Vec3{float64(x), 0, float64(z)}
+			triangles = append(triangles,
+				collider.NewTriangle([]mgl64.Vec3{p, p.Add(mgl64.Vec3{0, 0, 1}), p.Add(mgl64.Vec3{1, 0, 0})}),
+				collider.NewTriangle([]mgl64.Vec3{p.Add(mgl64.Vec3{1, 0, 0}), p.Add(mgl64.Vec3{0, 0, 1}), p.Add(mgl64.Vec3{1, 0, 1})}),
+			)
+		}
+	}
+	return triangles
+}
+
+func TestBVHStructure(t *testing.T) {
+	triangles := gridTriangles(16)
+	bvh := collider.NewBVH(triangles)
+
+	seen := map[int]bool{}
+	for i, node := range bvh.Nodes {
+		if !node.Leaf() {
+			if node.Left <= i || node.Right <= i {
+				t.Fatalf("expected the children of node %d to come after it", i)
+			}
+			continue
+		}
+		if node.Count > 16 {
+			t.Fatalf("expected leaves to be split down to at most 16 triangles but got %d", node.Count)
+		}
+		for _, triangle := range bvh.Triangles[node.First : node.First+node.Count] {
+			seen[triangle] = true
+		}
+	}
+	if len(seen) != len(triangles) {
+		t.Fatalf("expected every triangle in a leaf but found %d of %d", len(seen), len(triangles))
+	}
+
+	root := bvh.Nodes[0].Bounds
+	if !root.MinVertex.ApproxEqual(mgl64.Vec3{0, 0, 0}) || !root.MaxVertex.ApproxEqual(mgl64.Vec3{16, 0, 16}) {
+		t.Fatalf("unexpected root bounds %v", root)
+	}
+}
+
+func TestQueryBoundingBox(t *testing.T) {
+	triMesh := collider.NewTriMeshFromTriangles(gridTriangles(16))
+
+	box := collider.BoundingBox{MinVertex: mgl64.Vec3{2.5, -1, 2.5}, MaxVertex: mgl64.Vec3{3.5, 1, 3.5}}
+	expected := collider.TriMesh{Triangles: triMesh.Triangles}.QueryBoundingBox(box)
+	actual := triMesh.QueryBoundingBox(box)
+	if !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("expected triangles %v but got %v", expected, actual)
+	}
+	if len(actual) != 8 {
+		t.Fatalf("expected the 4 squares around (3, 3) to overlap but got %d triangles", len(actual))
+	}
+
+	box = collider.BoundingBox{MinVertex: mgl64.Vec3{2.5, 1, 2.5}, MaxVertex: mgl64.Vec3{3.5, 2, 3.5}}
+	if result := triMesh.QueryBoundingBox(box); len(result) != 0 {
+		t.Fatalf("expected no triangles above the grid but got %v", result)
+	}
+}
+
+func TestRefit(t *testing.T) {
+	triMesh := collider.NewTriMeshFromTriangles(gridTriangles(8))
+	moved := triMesh.Transform(mgl64.Translate3D(100, 5, 0))
+
+	root := moved.BVH.Nodes[0].Bounds
+	if !root.MinVertex.ApproxEqual(mgl64.Vec3{100, 5, 0}) || !root.MaxVertex.ApproxEqual(mgl64.Vec3{108, 5, 8}) {
+		t.Fatalf("unexpected refit root bounds %v", root)
+	}
+	if !triMesh.BVH.Nodes[0].Bounds.MaxVertex.ApproxEqual(mgl64.Vec3{8, 0, 8}) {
+		t.Fatal("expected the original hierarchy to be left untouched")
+	}
+
+	box := collider.BoundingBox{MinVertex: mgl64.Vec3{100.5, 4, 0.5}, MaxVertex: mgl64.Vec3{100.6, 6, 0.6}}
+	if result := moved.QueryBoundingBox(box); len(result) != 2 {
+		t.Fatalf("expected both triangles of the first square but got %v", result)
+	}
+}
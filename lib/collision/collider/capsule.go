@@ -28,6 +28,21 @@ func (c Capsule) Transform(position mgl64.Vec3) Capsule {
 	return NewCapsule(newTop, newBottom, c.Radius)
 }
 
+// Rotate returns the capsule rotated about the origin
+func (c Capsule) Rotate(orientation mgl64.Quat) Capsule {
+	return NewCapsule(orientation.Rotate(c.Top), orientation.Rotate(c.Bottom), c.Radius)
+}
+
+// Support returns the point of the capsule that is furthest along the direction. The
+// capsule can be oriented in any direction
+func (c Capsule) Support(direction mgl64.Vec3) mgl64.Vec3 {
+	end := c.Bottom
+	if direction.Dot(c.Top.Sub(c.Bottom)) >= 0 {
+		end = c.Top
+	}
+	return end.Add(supportDirection(direction).Mul(c.Radius))
+}
+
 func NewCapsuleFromModel(model *model.Model) Capsule {
 	var vertices []mgl64.Vec3
 	for _, vertex := range model.Vertices() {
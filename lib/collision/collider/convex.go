@@ -0,0 +1,31 @@
+package collider
+
+import "github.com/go-gl/mathgl/mgl64"
+
+// Convex is implemented by the convex shapes that can be tested against each other
+// with GJK
+type Convex interface {
+	// Support returns the point of the shape that is furthest along the direction
+	Support(direction mgl64.Vec3) mgl64.Vec3
+}
+
+// BoundingBoxFromConvex creates the tightest bounding box around a convex shape
+func BoundingBoxFromConvex(shape Convex) *BoundingBox {
+	var minVertex, maxVertex mgl64.Vec3
+	for i := 0; i < 3; i++ {
+		var axis mgl64.Vec3
+		axis[i] = 1
+		minVertex[i] = shape.Support(axis.Mul(-1))[i]
+		maxVertex[i] = shape.Support(axis)[i]
+	}
+	return &BoundingBox{MinVertex: minVertex, MaxVertex: maxVertex}
+}
+
+// supportDirection normalizes the direction, picking an arbitrary one when it's zero
+func supportDirection(direction mgl64.Vec3) mgl64.Vec3 {
+	length := direction.Len()
+	if length == 0 {
+		return mgl64.Vec3{1, 0, 0}
+	}
+	return direction.Mul(1 / length)
+}
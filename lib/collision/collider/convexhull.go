@@ -0,0 +1,221 @@
+package collider
+
+import (
+	"math"
+
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/kkevinchou/kito/lib/libutils"
+	"github.com/kkevinchou/kito/lib/model"
+)
+
+// hullEpsilon is how far, relative to the size of the point cloud, a point must be in
+// front of a face to be considered outside of the hull
+const hullEpsilon float64 = 1e-9
+
+type ConvexHull struct {
+	Vertices []mgl64.Vec3
+
+	// Faces index into Vertices and are wound counter clockwise when viewed from
+	// outside of the hull. Point clouds that are flat have no faces
+	Faces [][3]int
+}
+
+// NewConvexHull creates the convex hull of the points, dropping any points that are
+// inside of it. The hull is built incrementally by adding each point outside of the
+// current hull and replacing the faces it can see
+func NewConvexHull(points []mgl64.Vec3) ConvexHull {
+	points = uniquePoints(points)
+	if len(points) < 4 {
+		return ConvexHull{Vertices: points}
+	}
+
+	bounds := BoundingBoxFromVertices(points)
+	epsilon := hullEpsilon * math.Max(bounds.MaxVertex.Sub(bounds.MinVertex).Len(), 1)
+
+	initial, ok := initialTetrahedron(points, epsilon)
+	if !ok {
+		return ConvexHull{Vertices: points}
+	}
+
+	centroid := mgl64.Vec3{}
+	for _, i := range initial {
+		centroid = centroid.Add(points[i].Mul(0.25))
+	}
+
+	var faces [][3]int
+	for _, face := range [][3]int{
+		{initial[0], initial[1], initial[2]},
+		{initial[0], initial[3], initial[1]},
+		{initial[0], initial[2], initial[3]},
+		{initial[1], initial[3], initial[2]},
+	} {
+		if faceNormal(points, face).Dot(centroid.Sub(points[face[0]])) > 0 {
+			face[1], face[2] = face[2], face[1]
+		}
+		faces = append(faces, face)
+	}
+
+	for i, point := range points {
+		var visible []bool
+		anyVisible := false
+		for _, face := range faces {
+			v := faceNormal(points, face).Normalize().Dot(point.Sub(points[face[0]])) > epsilon
+			visible = append(visible, v)
+			anyVisible = anyVisible || v
+		}
+		if !anyVisible {
+			continue
+		}
+
+		// the horizon is made of the edges of visible faces that aren't shared with
+		// another visible face
+		visibleEdges := map[[2]int]bool{}
+		for j, face := range faces {
+			if visible[j] {
+				for k := 0; k < 3; k++ {
+					visibleEdges[[2]int{face[k], face[(k+1)%3]}] = true
+				}
+			}
+		}
+
+		var newFaces [][3]int
+		for j, face := range faces {
+			if !visible[j] {
+				newFaces = append(newFaces, face)
+			}
+		}
+		for j, face := range faces {
+			if !visible[j] {
+				continue
+			}
+			for k := 0; k < 3; k++ {
+				a, b := face[k], face[(k+1)%3]
+				if !visibleEdges[[2]int{b, a}] {
+					newFaces = append(newFaces, [3]int{a, b, i})
+				}
+			}
+		}
+		faces = newFaces
+	}
+
+	// only keep the points that ended up on the hull
+	hull := ConvexHull{}
+	remap := map[int]int{}
+	for _, face := range faces {
+		var newFace [3]int
+		for k, index := range face {
+			if _, ok := remap[index]; !ok {
+				remap[index] = len(hull.Vertices)
+				hull.Vertices = append(hull.Vertices, points[index])
+			}
+			newFace[k] = remap[index]
+		}
+		hull.Faces = append(hull.Faces, newFace)
+	}
+
+	return hull
+}
+
+// NewConvexHullFromModel creates the convex hull of a model's vertices
+func NewConvexHullFromModel(m *model.Model) ConvexHull {
+	return NewConvexHull(libutils.ModelSpecVertsToVec3(m.Vertices()))
+}
+
+func (c ConvexHull) Transform(position mgl64.Vec3) ConvexHull {
+	vertices := make([]mgl64.Vec3, len(c.Vertices))
+	for i, vertex := range c.Vertices {
+		vertices[i] = vertex.Add(position)
+	}
+	return ConvexHull{Vertices: vertices, Faces: c.Faces}
+}
+
+// Rotate rotates the hull around the origin
+func (c ConvexHull) Rotate(orientation mgl64.Quat) ConvexHull {
+	vertices := make([]mgl64.Vec3, len(c.Vertices))
+	for i, vertex := range c.Vertices {
+		vertices[i] = orientation.Rotate(vertex)
+	}
+	return ConvexHull{Vertices: vertices, Faces: c.Faces}
+}
+
+func (c ConvexHull) Support(direction mgl64.Vec3) mgl64.Vec3 {
+	result := c.Vertices[0]
+	maxDot := result.Dot(direction)
+	for _, vertex := range c.Vertices[1:] {
+		if dot := vertex.Dot(direction); dot > maxDot {
+			result = vertex
+			maxDot = dot
+		}
+	}
+	return result
+}
+
+// Edges returns each edge of the hull's faces once
+func (c ConvexHull) Edges() [][2]int {
+	var edges [][2]int
+	for _, face := range c.Faces {
+		for k := 0; k < 3; k++ {
+			// each edge is shared by two faces that wind it in opposite directions
+			if a, b := face[k], face[(k+1)%3]; a < b {
+				edges = append(edges, [2]int{a, b})
+			}
+		}
+	}
+	return edges
+}
+
+func uniquePoints(points []mgl64.Vec3) []mgl64.Vec3 {
+	var result []mgl64.Vec3
+	seen := map[mgl64.Vec3]bool{}
+	for _, point := range points {
+		if !seen[point] {
+			seen[point] = true
+			result = append(result, point)
+		}
+	}
+	return result
+}
+
+// initialTetrahedron picks four points that span as much of the point cloud as it can
+// find, failing if the points are all on a plane
+func initialTetrahedron(points []mgl64.Vec3, epsilon float64) ([4]int, bool) {
+	var result [4]int
+
+	furthest := func(distance func(p mgl64.Vec3) float64) (int, float64) {
+		index, maxDistance := 0, -1.0
+		for i, point := range points {
+			if d := distance(point); d > maxDistance {
+				index, maxDistance = i, d
+			}
+		}
+		return index, maxDistance
+	}
+
+	a := points[0]
+	result[1], _ = furthest(func(p mgl64.Vec3) float64 { return p.Sub(a).Len() })
+	b := points[result[1]]
+	if b.Sub(a).Len() <= epsilon {
+		return result, false
+	}
+
+	var lineDistance float64
+	result[2], lineDistance = furthest(func(p mgl64.Vec3) float64 { return p.Sub(a).Cross(b.Sub(a)).Len() / b.Sub(a).Len() })
+	if lineDistance <= epsilon {
+		return result, false
+	}
+	c := points[result[2]]
+
+	normal := b.Sub(a).Cross(c.Sub(a)).Normalize()
+	var planeDistance float64
+	result[3], planeDistance = furthest(func(p mgl64.Vec3) float64 { return math.Abs(normal.Dot(p.Sub(a))) })
+	if planeDistance <= epsilon {
+		return result, false
+	}
+
+	return result, true
+}
+
+func faceNormal(points []mgl64.Vec3, face [3]int) mgl64.Vec3 {
+	a := points[face[0]]
+	return points[face[1]].Sub(a).Cross(points[face[2]].Sub(a))
+}
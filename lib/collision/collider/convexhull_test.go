@@ -0,0 +1,57 @@
+package collider_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/kkevinchou/kito/lib/collision/collider"
+)
+
+func TestConvexHull(t *testing.T) {
+	random := rand.New(rand.NewSource(0))
+
+	// the corners of a cube with points scattered inside and on its faces
+	points := []mgl64.Vec3{
+		{-1, -1, -1}, {1, -1, -1}, {1, 1, -1}, {-1, 1, -1},
+		{-1, -1, 1}, {1, -1, 1}, {1, 1, 1}, {-1, 1, 1},
+	}
+	for i := 0; i < 200; i++ {
+		points = append(points, mgl64.Vec3{random.Float64()*2 - 1, random.Float64()*2 - 1, random.Float64()*2 - 1})
+	}
+	points = append(points, mgl64.Vec3{0, 1, 0}, mgl64.Vec3{0.5, -1, 0.5}, mgl64.Vec3{1, 1, 1})
+
+	hull := collider.NewConvexHull(points)
+	if len(hull.Vertices) != 8 {
+		t.Fatalf("expected only the 8 corners to be kept but got %d vertices", len(hull.Vertices))
+	}
+	if len(hull.Faces) != 12 || len(hull.Edges()) != 18 {
+		t.Fatalf("expected a closed hull with 12 faces and 18 edges but got %d and %d", len(hull.Faces), len(hull.Edges()))
+	}
+
+	for _, face := range hull.Faces {
+		a, b, c := hull.Vertices[face[0]], hull.Vertices[face[1]], hull.Vertices[face[2]]
+		normal := b.Sub(a).Cross(c.Sub(a))
+		for _, vertex := range hull.Vertices {
+			if normal.Dot(vertex.Sub(a)) > 1e-9 {
+				t.Fatalf("expected every vertex to be behind face %v", face)
+			}
+		}
+	}
+
+	if support := hull.Support(mgl64.Vec3{1, 2, -3}); !support.ApproxEqual(mgl64.Vec3{1, 1, -1}) {
+		t.Fatalf("expected the support point to be {1, 1, -1} but got %v", support)
+	}
+
+	bounds := collider.BoundingBoxFromConvex(hull.Transform(mgl64.Vec3{5, 0, 0}))
+	if !bounds.MinVertex.ApproxEqual(mgl64.Vec3{4, -1, -1}) || !bounds.MaxVertex.ApproxEqual(mgl64.Vec3{6, 1, 1}) {
+		t.Fatalf("unexpected bounds %v", bounds)
+	}
+}
+
+func TestFlatConvexHull(t *testing.T) {
+	hull := collider.NewConvexHull([]mgl64.Vec3{{0, 0, 0}, {1, 0, 0}, {0, 0, 1}, {1, 0, 1}, {1, 0, 1}})
+	if len(hull.Vertices) != 4 || len(hull.Faces) != 0 {
+		t.Fatalf("expected a flat hull to keep its unique points and have no faces but got %d vertices and %d faces", len(hull.Vertices), len(hull.Faces))
+	}
+}
@@ -15,3 +15,16 @@ func NewSphere(center mgl64.Vec3, radius float64) Sphere {
 		RadiusSquared: radius * radius,
 	}
 }
+
+func (c Sphere) Transform(position mgl64.Vec3) Sphere {
+	return NewSphere(c.Center.Add(position), c.Radius)
+}
+
+// Rotate rotates the sphere around the origin
+func (c Sphere) Rotate(orientation mgl64.Quat) Sphere {
+	return NewSphere(orientation.Rotate(c.Center), c.Radius)
+}
+
+func (c Sphere) Support(direction mgl64.Vec3) mgl64.Vec3 {
+	return c.Center.Add(supportDirection(direction).Mul(c.Radius))
+}
@@ -1,6 +1,8 @@
 package collider
 
 import (
+	"sort"
+
 	"github.com/go-gl/mathgl/mgl64"
 	"github.com/kkevinchou/kito/lib/libutils"
 	"github.com/kkevinchou/kito/lib/model"
@@ -23,6 +25,16 @@ func (t Triangle) Transform(transform mgl64.Mat4) Triangle {
 	})
 }
 
+func (t Triangle) Support(direction mgl64.Vec3) mgl64.Vec3 {
+	result := t.Points[0]
+	for _, point := range t.Points[1:] {
+		if point.Dot(direction) > result.Dot(direction) {
+			result = point
+		}
+	}
+	return result
+}
+
 func NewTriangle(points []mgl64.Vec3) Triangle {
 	seg1 := points[1].Sub(points[0])
 	seg2 := points[2].Sub(points[0])
@@ -35,18 +47,61 @@ func NewTriangle(points []mgl64.Vec3) Triangle {
 
 type TriMesh struct {
 	Triangles []Triangle
+	// BVH accelerates queries against the triangles. Meshes without one fall back to
+	// testing every triangle
+	BVH *BVH
 }
 
+// Transform returns the mesh with every triangle transformed. The hierarchy is refit
+// rather than rebuilt
 func (t TriMesh) Transform(transform mgl64.Mat4) TriMesh {
 	newTriMesh := TriMesh{Triangles: make([]Triangle, len(t.Triangles))}
 	for i, tri := range t.Triangles {
 		newTriMesh.Triangles[i] = tri.Transform(transform)
 	}
+	if t.BVH != nil {
+		newTriMesh.BVH = t.BVH.Copy()
+		newTriMesh.BVH.Refit(newTriMesh.Triangles)
+	}
 	return newTriMesh
 }
 
+// QueryBoundingBox returns the indices of the triangles whose bounds overlap the box in
+// ascending order
+func (t TriMesh) QueryBoundingBox(box BoundingBox) []int {
+	var result []int
+	if t.BVH == nil {
+		for i, triangle := range t.Triangles {
+			if overlaps(triangleBounds(triangle), box) {
+				result = append(result, i)
+			}
+		}
+		return result
+	}
+
+	t.BVH.Query(
+		func(bounds *BoundingBox) bool { return overlaps(*bounds, box) },
+		func(i int) bool {
+			if overlaps(triangleBounds(t.Triangles[i]), box) {
+				result = append(result, i)
+			}
+			return true
+		},
+	)
+	sort.Ints(result)
+	return result
+}
+
+// NewTriMeshFromTriangles creates a mesh and builds its BVH
+func NewTriMeshFromTriangles(triangles []Triangle) TriMesh {
+	return TriMesh{
+		Triangles: triangles,
+		BVH:       NewBVH(triangles),
+	}
+}
+
 func NewTriMesh(model *model.Model) TriMesh {
-	triMesh := TriMesh{}
+	var triangles []Triangle
 	for _, mesh := range model.Meshes() {
 		for _, meshChunk := range mesh.MeshChunks() {
 			vertices := meshChunk.Vertices()
@@ -56,11 +111,11 @@ func NewTriMesh(model *model.Model) TriMesh {
 					libutils.Vec3F32ToF64(vertices[i+1].Position),
 					libutils.Vec3F32ToF64(vertices[i+2].Position),
 				}
-				triMesh.Triangles = append(triMesh.Triangles, NewTriangle(points))
+				triangles = append(triangles, NewTriangle(points))
 			}
 		}
 	}
-	return triMesh
+	return NewTriMeshFromTriangles(triangles)
 }
 
 func NewBoxTriMesh(w, l, h float64) TriMesh {
@@ -109,5 +164,6 @@ func NewBoxTriMesh(w, l, h float64) TriMesh {
 	triMesh.Triangles = append(triMesh.Triangles, NewTriangle(
 		[]mgl64.Vec3{{halfW, h, -halfL}, {-halfW, h, -halfL}, {-halfW, h, halfL}},
 	))
+	triMesh.BVH = NewBVH(triMesh.Triangles)
 	return triMesh
 }
@@ -2,6 +2,7 @@ package collision
 
 import (
 	"fmt"
+	"math"
 
 	"github.com/go-gl/mathgl/mgl64"
 	"github.com/kkevinchou/kito/kito/utils"
@@ -25,6 +26,9 @@ type ContactType string
 
 var ContactTypeCapsuleTriMesh ContactType = "TRIMESH"
 var ContactTypeCapsuleCapsule ContactType = "CAPSULE"
+var ContactTypeSphereTriMesh ContactType = "SPHERE_TRIMESH"
+var ContactTypeConvexConvex ContactType = "CONVEX"
+var ContactTypeConvexTriMesh ContactType = "CONVEX_TRIMESH"
 
 type Contact struct {
 	EntityID       *int
@@ -45,10 +49,39 @@ func (c *Contact) String() string {
 	return result
 }
 
+// CheckCollisionCapsuleTriMesh tests the capsule against the triangles of the mesh whose
+// bounds overlap the capsule's
 func CheckCollisionCapsuleTriMesh(capsule collider.Capsule, triangulatedMesh collider.TriMesh) []*Contact {
+	radius := mgl64.Vec3{capsule.Radius, capsule.Radius, capsule.Radius}
+	bounds := collider.BoundingBox{
+		MinVertex: minVec3(capsule.Top, capsule.Bottom).Sub(radius),
+		MaxVertex: maxVec3(capsule.Top, capsule.Bottom).Add(radius),
+	}
+
+	var contacts []*Contact
+	for _, i := range triangulatedMesh.QueryBoundingBox(bounds) {
+		if triContact := CheckCollisionCapsuleTriangle(capsule, triangulatedMesh.Triangles[i]); triContact != nil {
+			index := i
+			triContact.TriIndex = &index
+			contacts = append(contacts, triContact)
+		}
+	}
+
+	return contacts
+}
+
+// CheckCollisionSphereTriMesh tests the sphere against the triangles of the mesh whose
+// bounds overlap the sphere's
+func CheckCollisionSphereTriMesh(sphere collider.Sphere, triangulatedMesh collider.TriMesh) []*Contact {
+	radius := mgl64.Vec3{sphere.Radius, sphere.Radius, sphere.Radius}
+	bounds := collider.BoundingBox{
+		MinVertex: sphere.Center.Sub(radius),
+		MaxVertex: sphere.Center.Add(radius),
+	}
+
 	var contacts []*Contact
-	for i, tri := range triangulatedMesh.Triangles {
-		if triContact := CheckCollisionCapsuleTriangle(capsule, tri); triContact != nil {
+	for _, i := range triangulatedMesh.QueryBoundingBox(bounds) {
+		if triContact := CheckCollisionSphereTriangle(sphere, triangulatedMesh.Triangles[i]); triContact != nil {
 			index := i
 			triContact.TriIndex = &index
 			contacts = append(contacts, triContact)
@@ -58,6 +91,29 @@ func CheckCollisionCapsuleTriMesh(capsule collider.Capsule, triangulatedMesh col
 	return contacts
 }
 
+func CheckCollisionSphereTriangle(sphere collider.Sphere, triangle collider.Triangle) *Contact {
+	closestPoint := checks.ClosestPointOnTriangleToPoint(triangle, sphere.Center)
+	toCenter := sphere.Center.Sub(closestPoint)
+	distanceSquared := toCenter.LenSqr()
+	if distanceSquared >= sphere.RadiusSquared {
+		return nil
+	}
+
+	// a sphere centered on the triangle is pushed out along the triangle's normal
+	direction := triangle.Normal
+	if distanceSquared > 0 {
+		direction = toCenter.Normalize()
+	}
+	separatingDistance := sphere.Radius - math.Sqrt(distanceSquared)
+	return &Contact{
+		Point:              closestPoint,
+		Normal:             triangle.Normal,
+		SeparatingVector:   direction.Mul(separatingDistance),
+		SeparatingDistance: separatingDistance,
+		Type:               ContactTypeSphereTriMesh,
+	}
+}
+
 // func CheckCollisionLineTriangle(line collider.Line, triangle collider.Triangle) *Contact {
 // 	dir1 := line.P1.Sub(line.P2)
 // 	dir2 := line.P2.Sub(line.P1)
@@ -94,7 +150,8 @@ func CheckCollisionCapsuleTriangle(capsule collider.Capsule, triangle collider.T
 	return nil
 }
 
-// for now assumes vertical capsules only
+// CheckCollisionCapsuleCapsule tests two capsules by the closest points of their
+// segments, which works for capsules in any orientation
 func CheckCollisionCapsuleCapsule(capsule1 collider.Capsule, capsule2 collider.Capsule) *Contact {
 	closestPoints, closestPointsDistance := checks.ClosestPointsLineVSLine(
 		collider.Line{P1: capsule1.Top, P2: capsule1.Bottom},
@@ -106,7 +163,8 @@ func CheckCollisionCapsuleCapsule(capsule1 collider.Capsule, capsule2 collider.C
 		capsule2To1 := closestPoints[0].Sub(closestPoints[1]).Normalize()
 		separatingVec := capsule2To1.Mul(separatingDistance)
 		return &Contact{
-			Point:              capsule2To1.Mul(closestPointsDistance),
+			Point:              closestPoints[1].Add(capsule2To1.Mul(capsule2.Radius)),
+			Normal:             capsule2To1,
 			SeparatingVector:   separatingVec,
 			SeparatingDistance: separatingDistance,
 			Type:               ContactTypeCapsuleCapsule,
@@ -147,3 +205,11 @@ func CheckOverlapAABBAABB(aabb1 *collider.BoundingBox, aabb2 *collider.BoundingB
 
 // 	return nil
 // }
+
+func minVec3(a, b mgl64.Vec3) mgl64.Vec3 {
+	return mgl64.Vec3{math.Min(a.X(), b.X()), math.Min(a.Y(), b.Y()), math.Min(a.Z(), b.Z())}
+}
+
+func maxVec3(a, b mgl64.Vec3) mgl64.Vec3 {
+	return mgl64.Vec3{math.Max(a.X(), b.X()), math.Max(a.Y(), b.Y()), math.Max(a.Z(), b.Z())}
+}
@@ -0,0 +1,166 @@
+package collision_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/kkevinchou/kito/lib/assets/loaders/gltf"
+	"github.com/kkevinchou/kito/lib/collision"
+	"github.com/kkevinchou/kito/lib/collision/checks"
+	"github.com/kkevinchou/kito/lib/collision/collider"
+	"github.com/kkevinchou/kito/lib/libutils"
+)
+
+var sceneFile string = "../../_assets/gltf/scene.gltf"
+
+func loadScene(t testing.TB) collider.TriMesh {
+	spec, err := gltf.ParseGLTF(sceneFile, &gltf.ParseConfig{TextureCoordStyle: gltf.TextureCoordStyleOpenGL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var triangles []collider.Triangle
+	for _, mesh := range spec.Meshes {
+		for _, meshChunk := range mesh.MeshChunks {
+			vertices := meshChunk.Vertices
+			for i := 0; i < len(vertices); i += 3 {
+				triangles = append(triangles, collider.NewTriangle([]mgl64.Vec3{
+					libutils.Vec3F32ToF64(vertices[i].Position),
+					libutils.Vec3F32ToF64(vertices[i+1].Position),
+					libutils.Vec3F32ToF64(vertices[i+2].Position),
+				}))
+			}
+		}
+	}
+	return collider.NewTriMeshFromTriangles(triangles)
+}
+
+// bruteForce drops the BVH so that queries test every triangle
+func bruteForce(triMesh collider.TriMesh) collider.TriMesh {
+	return collider.TriMesh{Triangles: triMesh.Triangles}
+}
+
+// capsules placed around the scene, the same ones are used by every test and benchmark
+func sceneCapsules(triMesh collider.TriMesh, count int) []collider.Capsule {
+	bounds := collider.BoundingBoxFromVertices([]mgl64.Vec3{triMesh.BVH.Nodes[0].Bounds.MinVertex, triMesh.BVH.Nodes[0].Bounds.MaxVertex})
+	random := rand.New(rand.NewSource(0))
+
+	capsules := make([]collider.Capsule, count)
+	for i := range capsules {
+		position := mgl64.Vec3{
+			bounds.MinVertex.X() + random.Float64()*(bounds.MaxVertex.X()-bounds.MinVertex.X()),
+			bounds.MinVertex.Y() + random.Float64()*(bounds.MaxVertex.Y()-bounds.MinVertex.Y()),
+			bounds.MinVertex.Z() + random.Float64()*(bounds.MaxVertex.Z()-bounds.MinVertex.Z()),
+		}
+		capsules[i] = collider.NewCapsule(mgl64.Vec3{0, 40, 0}, mgl64.Vec3{0, 10, 0}, 10).Transform(position)
+	}
+	return capsules
+}
+
+func TestBVHMatchesBruteForce(t *testing.T) {
+	triMesh := loadScene(t)
+	capsules := sceneCapsules(triMesh, 200)
+
+	hits := 0
+	for _, capsule := range capsules {
+		expected := collision.CheckCollisionCapsuleTriMesh(capsule, bruteForce(triMesh))
+		actual := collision.CheckCollisionCapsuleTriMesh(capsule, triMesh)
+		if len(expected) != len(actual) {
+			t.Fatalf("expected %d capsule contacts but got %d", len(expected), len(actual))
+		}
+		for i := range expected {
+			if *expected[i].TriIndex != *actual[i].TriIndex {
+				t.Fatalf("expected contact with triangle %d but got %d", *expected[i].TriIndex, *actual[i].TriIndex)
+			}
+		}
+		hits += len(actual)
+
+		sphere := collider.NewSphere(capsule.Bottom, capsule.Radius)
+		if e, a := len(collision.CheckCollisionSphereTriMesh(sphere, bruteForce(triMesh))), len(collision.CheckCollisionSphereTriMesh(sphere, triMesh)); e != a {
+			t.Fatalf("expected %d sphere contacts but got %d", e, a)
+		}
+
+		ray := collider.Ray{Origin: capsule.Top, Direction: mgl64.Vec3{0.3, -1, 0.2}}
+		expectedPoint := checks.IntersectRayTriMesh(ray, bruteForce(triMesh))
+		actualPoint := checks.IntersectRayTriMesh(ray, triMesh)
+		if (expectedPoint == nil) != (actualPoint == nil) || (expectedPoint != nil && !expectedPoint.ApproxEqual(*actualPoint)) {
+			t.Fatalf("expected ray hit %v but got %v", expectedPoint, actualPoint)
+		}
+	}
+
+	if hits == 0 {
+		t.Fatal("expected some capsules to collide with the scene")
+	}
+}
+
+func TestCheckCollisionSphereTriangle(t *testing.T) {
+	triangle := collider.NewTriangle([]mgl64.Vec3{{-1, 0, 1}, {1, 0, 1}, {0, 0, -1}})
+
+	contact := collision.CheckCollisionSphereTriangle(collider.NewSphere(mgl64.Vec3{0, 0.5, 0}, 1), triangle)
+	if contact == nil {
+		t.Fatal("expected the sphere to collide with the triangle")
+	}
+	if !contact.SeparatingVector.ApproxEqual(mgl64.Vec3{0, 0.5, 0}) {
+		t.Fatalf("expected the sphere to be pushed up by 0.5 but got %v", contact.SeparatingVector)
+	}
+
+	// closest to the edge rather than the face
+	contact = collision.CheckCollisionSphereTriangle(collider.NewSphere(mgl64.Vec3{0, 0, 1.5}, 1), triangle)
+	if contact == nil || !contact.Point.ApproxEqual(mgl64.Vec3{0, 0, 1}) {
+		t.Fatalf("expected a contact on the edge of the triangle but got %v", contact)
+	}
+
+	if collision.CheckCollisionSphereTriangle(collider.NewSphere(mgl64.Vec3{0, 2, 0}, 1), triangle) != nil {
+		t.Fatal("expected the sphere to not collide with the triangle")
+	}
+}
+
+func BenchmarkCapsuleTriMesh(b *testing.B) {
+	triMesh := loadScene(b)
+	capsules := sceneCapsules(triMesh, 100)
+
+	b.Run("bvh", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			collision.CheckCollisionCapsuleTriMesh(capsules[i%len(capsules)], triMesh)
+		}
+	})
+	b.Run("brute force", func(b *testing.B) {
+		bruteForceMesh := bruteForce(triMesh)
+		for i := 0; i < b.N; i++ {
+			collision.CheckCollisionCapsuleTriMesh(capsules[i%len(capsules)], bruteForceMesh)
+		}
+	})
+}
+
+func BenchmarkRayTriMesh(b *testing.B) {
+	triMesh := loadScene(b)
+	capsules := sceneCapsules(triMesh, 100)
+
+	b.Run("bvh", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			checks.IntersectRayTriMesh(collider.Ray{Origin: capsules[i%len(capsules)].Top, Direction: mgl64.Vec3{0.3, -1, 0.2}}, triMesh)
+		}
+	})
+	b.Run("brute force", func(b *testing.B) {
+		bruteForceMesh := bruteForce(triMesh)
+		for i := 0; i < b.N; i++ {
+			checks.IntersectRayTriMesh(collider.Ray{Origin: capsules[i%len(capsules)].Top, Direction: mgl64.Vec3{0.3, -1, 0.2}}, bruteForceMesh)
+		}
+	})
+}
+
+func BenchmarkBuildBVH(b *testing.B) {
+	triMesh := loadScene(b)
+	b.Run("build", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			collider.NewBVH(triMesh.Triangles)
+		}
+	})
+	b.Run("refit", func(b *testing.B) {
+		bvh := triMesh.BVH.Copy()
+		for i := 0; i < b.N; i++ {
+			bvh.Refit(triMesh.Triangles)
+		}
+	})
+}
@@ -0,0 +1,176 @@
+package collision
+
+import (
+	"math"
+
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/kkevinchou/kito/lib/collision/collider"
+)
+
+const (
+	// GJK stops refining the distance once a new support point improves it by less than
+	// this fraction
+	gjkDistanceTolerance float64 = 1e-8
+)
+
+// ClosestPointsConvexConvex finds the closest points between two convex shapes with
+// GJK. The points on shape1 and shape2 are returned along with the distance between
+// them. Shapes that intersect return false
+func ClosestPointsConvexConvex(shape1 collider.Convex, shape2 collider.Convex) (mgl64.Vec3, mgl64.Vec3, float64, bool) {
+	simplex := []supportPoint{support(shape1, shape2, mgl64.Vec3{1, 0, 0})}
+	weights := []float64{1}
+	closest := simplex[0].point
+
+	for i := 0; i < maxGJKIterations; i++ {
+		if closest.LenSqr() < gjkEpsilon {
+			return mgl64.Vec3{}, mgl64.Vec3{}, 0, false
+		}
+
+		next := support(shape1, shape2, closest.Mul(-1))
+
+		// stop once the new point can't get any closer to the origin than we already are
+		if closest.LenSqr()-closest.Dot(next.point) <= gjkDistanceTolerance*closest.LenSqr() {
+			break
+		}
+
+		var enclosed bool
+		simplex, weights, enclosed = closestOnSimplex(append(simplex, next))
+		if enclosed {
+			return mgl64.Vec3{}, mgl64.Vec3{}, 0, false
+		}
+
+		closest = mgl64.Vec3{}
+		for j, p := range simplex {
+			closest = closest.Add(p.point.Mul(weights[j]))
+		}
+	}
+
+	var pointA, pointB mgl64.Vec3
+	for j, p := range simplex {
+		pointA = pointA.Add(p.a.Mul(weights[j]))
+		pointB = pointB.Add(p.b.Mul(weights[j]))
+	}
+	return pointA, pointB, closest.Len(), true
+}
+
+// closestOnSimplex reduces the simplex to the feature closest to the origin, returning
+// the barycentric weights of the closest point on it. A tetrahedron that encloses the
+// origin means the shapes intersect
+func closestOnSimplex(simplex []supportPoint) ([]supportPoint, []float64, bool) {
+	switch len(simplex) {
+	case 1:
+		return simplex, []float64{1}, false
+	case 2:
+		s, w := closestOnSegment(simplex[0], simplex[1])
+		return s, w, false
+	case 3:
+		s, w := closestOnTriangle(simplex[0], simplex[1], simplex[2])
+		return s, w, false
+	default:
+		return closestOnTetrahedron(simplex)
+	}
+}
+
+func closestOnSegment(a, b supportPoint) ([]supportPoint, []float64) {
+	ab := b.point.Sub(a.point)
+	lengthSqr := ab.LenSqr()
+	if lengthSqr < gjkEpsilon {
+		return []supportPoint{a}, []float64{1}
+	}
+
+	t := -a.point.Dot(ab) / lengthSqr
+	if t <= 0 {
+		return []supportPoint{a}, []float64{1}
+	} else if t >= 1 {
+		return []supportPoint{b}, []float64{1}
+	}
+	return []supportPoint{a, b}, []float64{1 - t, t}
+}
+
+// closestOnTriangle finds the region of the triangle closest to the origin. Real Time
+// Collision Detection - page 141
+func closestOnTriangle(a, b, c supportPoint) ([]supportPoint, []float64) {
+	ab := b.point.Sub(a.point)
+	ac := c.point.Sub(a.point)
+
+	ap := a.point.Mul(-1)
+	d1 := ab.Dot(ap)
+	d2 := ac.Dot(ap)
+	if d1 <= 0 && d2 <= 0 {
+		return []supportPoint{a}, []float64{1}
+	}
+
+	bp := b.point.Mul(-1)
+	d3 := ab.Dot(bp)
+	d4 := ac.Dot(bp)
+	if d3 >= 0 && d4 <= d3 {
+		return []supportPoint{b}, []float64{1}
+	}
+
+	vc := d1*d4 - d3*d2
+	if vc <= 0 && d1 >= 0 && d3 <= 0 {
+		v := d1 / (d1 - d3)
+		return []supportPoint{a, b}, []float64{1 - v, v}
+	}
+
+	cp := c.point.Mul(-1)
+	d5 := ab.Dot(cp)
+	d6 := ac.Dot(cp)
+	if d6 >= 0 && d5 <= d6 {
+		return []supportPoint{c}, []float64{1}
+	}
+
+	vb := d5*d2 - d1*d6
+	if vb <= 0 && d2 >= 0 && d6 <= 0 {
+		w := d2 / (d2 - d6)
+		return []supportPoint{a, c}, []float64{1 - w, w}
+	}
+
+	va := d3*d6 - d5*d4
+	if va <= 0 && (d4-d3) >= 0 && (d5-d6) >= 0 {
+		w := (d4 - d3) / ((d4 - d3) + (d5 - d6))
+		return []supportPoint{b, c}, []float64{1 - w, w}
+	}
+
+	denominator := va + vb + vc
+	if math.Abs(denominator) < gjkEpsilon {
+		// the triangle is degenerate, fall back to its longest edge
+		return closestOnSegment(a, b)
+	}
+	v := vb / denominator
+	w := vc / denominator
+	return []supportPoint{a, b, c}, []float64{1 - v - w, v, w}
+}
+
+// closestOnTetrahedron checks the faces the origin is in front of, if there are none the
+// origin is inside. Real Time Collision Detection - page 143
+func closestOnTetrahedron(simplex []supportPoint) ([]supportPoint, []float64, bool) {
+	faces := [4][4]int{{0, 1, 2, 3}, {0, 2, 3, 1}, {0, 3, 1, 2}, {1, 3, 2, 0}}
+
+	var bestSimplex []supportPoint
+	var bestWeights []float64
+	bestDistance := math.MaxFloat64
+	for _, face := range faces {
+		a, b, c, d := simplex[face[0]], simplex[face[1]], simplex[face[2]], simplex[face[3]]
+		normal := b.point.Sub(a.point).Cross(c.point.Sub(a.point))
+		originSide := normal.Dot(a.point.Mul(-1))
+		oppositeSide := normal.Dot(d.point.Sub(a.point))
+		if originSide*oppositeSide > 0 {
+			continue
+		}
+
+		s, w := closestOnTriangle(a, b, c)
+		var point mgl64.Vec3
+		for j, p := range s {
+			point = point.Add(p.point.Mul(w[j]))
+		}
+		if distance := point.LenSqr(); distance < bestDistance {
+			bestSimplex, bestWeights, bestDistance = s, w, distance
+		}
+	}
+
+	if bestSimplex == nil {
+		return simplex, nil, true
+	}
+	return bestSimplex, bestWeights, false
+}
@@ -0,0 +1,51 @@
+package collision_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/kkevinchou/kito/lib/collision"
+	"github.com/kkevinchou/kito/lib/collision/collider"
+)
+
+func TestClosestPointsConvexConvex(t *testing.T) {
+	box := collider.NewBox(mgl64.Vec3{}, mgl64.Vec3{1, 1, 1}, mgl64.QuatIdent())
+
+	testCases := []struct {
+		name     string
+		shape    collider.Convex
+		pointA   mgl64.Vec3
+		pointB   mgl64.Vec3
+		distance float64
+	}{
+		{"box face", collider.NewBox(mgl64.Vec3{0.5, 4, 0.2}, mgl64.Vec3{1, 1, 1}, mgl64.QuatIdent()), mgl64.Vec3{}, mgl64.Vec3{}, 2},
+		{"box corner", collider.NewBox(mgl64.Vec3{3, 3, 3}, mgl64.Vec3{1, 1, 1}, mgl64.QuatIdent()), mgl64.Vec3{2, 2, 2}, mgl64.Vec3{1, 1, 1}, math.Sqrt(3)},
+		{"sphere", collider.NewSphere(mgl64.Vec3{4, 0, 0}, 1), mgl64.Vec3{3, 0, 0}, mgl64.Vec3{1, 0, 0}, 2},
+		{"point over an edge", collider.NewSphere(mgl64.Vec3{2, 2, 0}, 0), mgl64.Vec3{2, 2, 0}, mgl64.Vec3{1, 1, 0}, math.Sqrt2},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			pointA, pointB, distance, separated := collision.ClosestPointsConvexConvex(testCase.shape, box)
+			if !separated {
+				t.Fatal("expected the shapes to be separated")
+			}
+			if math.Abs(distance-testCase.distance) > 1e-6 {
+				t.Fatalf("expected a distance of %f but got %f", testCase.distance, distance)
+			}
+			// faces are closest along an area so only the other cases have exact points.
+			// GJK only approximates curved shapes so the points are checked loosely
+			if testCase.pointA != (mgl64.Vec3{}) && (pointA.Sub(testCase.pointA).Len() > 1e-4 || pointB.Sub(testCase.pointB).Len() > 1e-4) {
+				t.Fatalf("expected closest points %v and %v but got %v and %v", testCase.pointA, testCase.pointB, pointA, pointB)
+			}
+			if math.Abs(pointA.Sub(pointB).Len()-distance) > 1e-6 {
+				t.Fatalf("expected the closest points to be %f apart but they're %f apart", distance, pointA.Sub(pointB).Len())
+			}
+		})
+	}
+
+	if _, _, _, separated := collision.ClosestPointsConvexConvex(collider.NewSphere(mgl64.Vec3{1.5, 0, 0}, 1), box); separated {
+		t.Fatal("expected overlapping shapes to not be separated")
+	}
+}
@@ -0,0 +1,332 @@
+package collision
+
+import (
+	"math"
+
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/kkevinchou/kito/lib/collision/collider"
+)
+
+const (
+	maxGJKIterations = 64
+	maxEPAIterations = 64
+
+	// EPA stops expanding once the polytope grows by less than epaTolerance
+	epaTolerance float64 = 1e-4
+
+	gjkEpsilon float64 = 1e-12
+)
+
+// supportPoint is a point on the minkowski difference of two shapes along with the
+// points on each shape it came from
+type supportPoint struct {
+	point mgl64.Vec3
+	a     mgl64.Vec3
+	b     mgl64.Vec3
+}
+
+func support(a, b collider.Convex, direction mgl64.Vec3) supportPoint {
+	pointA := a.Support(direction)
+	pointB := b.Support(direction.Mul(-1))
+	return supportPoint{point: pointA.Sub(pointB), a: pointA, b: pointB}
+}
+
+// gjk determines whether two convex shapes intersect by searching for a tetrahedron
+// of points on their minkowski difference that encloses the origin. The tetrahedron is
+// returned so that EPA can find the penetration. Shapes that only touch are treated as
+// not intersecting
+func gjk(a, b collider.Convex) ([]supportPoint, bool) {
+	first := support(a, b, mgl64.Vec3{1, 0, 0})
+	simplex := []supportPoint{first}
+	direction := first.point.Mul(-1)
+
+	for i := 0; i < maxGJKIterations; i++ {
+		if direction.LenSqr() < gjkEpsilon {
+			return nil, false
+		}
+
+		next := support(a, b, direction)
+		if next.point.Dot(direction) <= 0 {
+			return nil, false
+		}
+
+		// the newest point is always first
+		simplex = append([]supportPoint{next}, simplex...)
+
+		var enclosed bool
+		simplex, direction, enclosed = nextSimplex(simplex)
+		if enclosed {
+			return simplex, true
+		}
+	}
+
+	return nil, false
+}
+
+// nextSimplex reduces the simplex to the feature closest to the origin and returns the
+// direction towards the origin from that feature
+func nextSimplex(simplex []supportPoint) ([]supportPoint, mgl64.Vec3, bool) {
+	switch len(simplex) {
+	case 2:
+		simplex, direction := lineSimplex(simplex)
+		return simplex, direction, false
+	case 3:
+		simplex, direction := triangleSimplex(simplex)
+		return simplex, direction, false
+	default:
+		return tetrahedronSimplex(simplex)
+	}
+}
+
+func lineSimplex(simplex []supportPoint) ([]supportPoint, mgl64.Vec3) {
+	a, b := simplex[0], simplex[1]
+	ab := b.point.Sub(a.point)
+	ao := a.point.Mul(-1)
+
+	if ab.Dot(ao) > 0 {
+		direction := ab.Cross(ao).Cross(ab)
+		if direction.LenSqr() < gjkEpsilon {
+			// the origin is on the line, any direction perpendicular to it will do
+			direction = perpendicular(ab)
+		}
+		return simplex, direction
+	}
+	return []supportPoint{a}, ao
+}
+
+func perpendicular(v mgl64.Vec3) mgl64.Vec3 {
+	if math.Abs(v.X()) < math.Abs(v.Y()) {
+		return v.Cross(mgl64.Vec3{1, 0, 0})
+	}
+	return v.Cross(mgl64.Vec3{0, 1, 0})
+}
+
+func triangleSimplex(simplex []supportPoint) ([]supportPoint, mgl64.Vec3) {
+	a, b, c := simplex[0], simplex[1], simplex[2]
+	ab := b.point.Sub(a.point)
+	ac := c.point.Sub(a.point)
+	ao := a.point.Mul(-1)
+	abc := ab.Cross(ac)
+
+	if abc.Cross(ac).Dot(ao) > 0 {
+		if ac.Dot(ao) > 0 {
+			return []supportPoint{a, c}, ac.Cross(ao).Cross(ac)
+		}
+		return lineSimplex([]supportPoint{a, b})
+	}
+
+	if ab.Cross(abc).Dot(ao) > 0 {
+		return lineSimplex([]supportPoint{a, b})
+	}
+
+	if abc.Dot(ao) > 0 {
+		return simplex, abc
+	}
+	return []supportPoint{a, c, b}, abc.Mul(-1)
+}
+
+func tetrahedronSimplex(simplex []supportPoint) ([]supportPoint, mgl64.Vec3, bool) {
+	a, b, c, d := simplex[0], simplex[1], simplex[2], simplex[3]
+	ab := b.point.Sub(a.point)
+	ac := c.point.Sub(a.point)
+	ad := d.point.Sub(a.point)
+	ao := a.point.Mul(-1)
+
+	if ab.Cross(ac).Dot(ao) > 0 {
+		simplex, direction := triangleSimplex([]supportPoint{a, b, c})
+		return simplex, direction, false
+	}
+	if ac.Cross(ad).Dot(ao) > 0 {
+		simplex, direction := triangleSimplex([]supportPoint{a, c, d})
+		return simplex, direction, false
+	}
+	if ad.Cross(ab).Dot(ao) > 0 {
+		simplex, direction := triangleSimplex([]supportPoint{a, d, b})
+		return simplex, direction, false
+	}
+
+	return simplex, mgl64.Vec3{}, true
+}
+
+type epaFace struct {
+	indices  [3]int
+	normal   mgl64.Vec3
+	distance float64
+}
+
+func newEPAFace(polytope []supportPoint, indices [3]int) epaFace {
+	a := polytope[indices[0]].point
+	normal := polytope[indices[1]].point.Sub(a).Cross(polytope[indices[2]].point.Sub(a))
+	if length := normal.Len(); length > 0 {
+		normal = normal.Mul(1 / length)
+	}
+
+	distance := normal.Dot(a)
+	if distance < 0 {
+		// the origin is inside the polytope so every face should face away from it
+		indices[1], indices[2] = indices[2], indices[1]
+		normal = normal.Mul(-1)
+		distance = -distance
+	}
+	return epaFace{indices: indices, normal: normal, distance: distance}
+}
+
+// epa expands the tetrahedron found by GJK towards the surface of the minkowski
+// difference until it finds the face closest to the origin. That face's normal and
+// distance are the direction and depth of the penetration. The returned normal points
+// from b towards a and the points are the deepest points of each shape
+func epa(a, b collider.Convex, simplex []supportPoint) (mgl64.Vec3, float64, mgl64.Vec3, mgl64.Vec3) {
+	polytope := append([]supportPoint{}, simplex...)
+	faces := []epaFace{
+		newEPAFace(polytope, [3]int{0, 1, 2}),
+		newEPAFace(polytope, [3]int{0, 3, 1}),
+		newEPAFace(polytope, [3]int{0, 2, 3}),
+		newEPAFace(polytope, [3]int{1, 3, 2}),
+	}
+
+	closest := closestFace(faces)
+	for i := 0; i < maxEPAIterations; i++ {
+		next := support(a, b, closest.normal)
+		if next.point.Dot(closest.normal)-closest.distance < epaTolerance {
+			break
+		}
+
+		// replace the faces the new point can see with faces connecting it to the
+		// horizon of the hole they leave behind
+		polytope = append(polytope, next)
+		index := len(polytope) - 1
+
+		visibleEdges := map[[2]int]bool{}
+		var visible []bool
+		for _, face := range faces {
+			v := face.normal.Dot(next.point.Sub(polytope[face.indices[0]].point)) > 0
+			visible = append(visible, v)
+			if v {
+				for k := 0; k < 3; k++ {
+					visibleEdges[[2]int{face.indices[k], face.indices[(k+1)%3]}] = true
+				}
+			}
+		}
+
+		var newFaces []epaFace
+		for j, face := range faces {
+			if !visible[j] {
+				newFaces = append(newFaces, face)
+			}
+		}
+		for j, face := range faces {
+			if !visible[j] {
+				continue
+			}
+			for k := 0; k < 3; k++ {
+				e1, e2 := face.indices[k], face.indices[(k+1)%3]
+				if !visibleEdges[[2]int{e2, e1}] {
+					newFaces = append(newFaces, newEPAFace(polytope, [3]int{e1, e2, index}))
+				}
+			}
+		}
+
+		if len(newFaces) == 0 {
+			break
+		}
+		faces = newFaces
+		closest = closestFace(faces)
+	}
+
+	// the deepest points are found from where the origin projects onto the closest face
+	p1 := polytope[closest.indices[0]]
+	p2 := polytope[closest.indices[1]]
+	p3 := polytope[closest.indices[2]]
+	u, v, w := barycentric(closest.normal.Mul(closest.distance), p1.point, p2.point, p3.point)
+	pointA := p1.a.Mul(u).Add(p2.a.Mul(v)).Add(p3.a.Mul(w))
+	pointB := p1.b.Mul(u).Add(p2.b.Mul(v)).Add(p3.b.Mul(w))
+
+	return closest.normal.Mul(-1), closest.distance, pointA, pointB
+}
+
+func closestFace(faces []epaFace) epaFace {
+	closest := faces[0]
+	for _, face := range faces[1:] {
+		if face.distance < closest.distance {
+			closest = face
+		}
+	}
+	return closest
+}
+
+// barycentric returns the barycentric coordinates of p with respect to the triangle abc
+func barycentric(p, a, b, c mgl64.Vec3) (float64, float64, float64) {
+	v0 := b.Sub(a)
+	v1 := c.Sub(a)
+	v2 := p.Sub(a)
+	d00 := v0.Dot(v0)
+	d01 := v0.Dot(v1)
+	d11 := v1.Dot(v1)
+	d20 := v2.Dot(v0)
+	d21 := v2.Dot(v1)
+
+	denominator := d00*d11 - d01*d01
+	if math.Abs(denominator) < gjkEpsilon {
+		return 1, 0, 0
+	}
+	v := (d11*d20 - d01*d21) / denominator
+	w := (d00*d21 - d01*d20) / denominator
+	return 1 - v - w, v, w
+}
+
+// CheckCollisionConvexConvex tests two convex shapes with GJK and finds how far they
+// penetrate with EPA. The separating vector moves shape1 out of shape2
+func CheckCollisionConvexConvex(shape1 collider.Convex, shape2 collider.Convex) *Contact {
+	simplex, intersecting := gjk(shape1, shape2)
+	if !intersecting {
+		return nil
+	}
+
+	normal, depth, _, point := epa(shape1, shape2, simplex)
+	if depth <= 0 {
+		return nil
+	}
+
+	return &Contact{
+		Point:              point,
+		Normal:             normal,
+		SeparatingVector:   normal.Mul(depth),
+		SeparatingDistance: depth,
+		Type:               ContactTypeConvexConvex,
+	}
+}
+
+// CheckCollisionConvexTriangle tests a convex shape against a triangle. Triangles only
+// push shapes out of their front side
+func CheckCollisionConvexTriangle(shape collider.Convex, triangle collider.Triangle) *Contact {
+	contact := CheckCollisionConvexConvex(shape, triangle)
+	if contact == nil {
+		return nil
+	}
+
+	contact.Type = ContactTypeConvexTriMesh
+	if contact.Normal.Dot(triangle.Normal) < 0 {
+		// the shape is closer to leaving out of the back of the triangle. push it out
+		// of the front instead, far enough that its deepest point clears the triangle
+		depth := triangle.Normal.Dot(triangle.Points[0].Sub(shape.Support(triangle.Normal.Mul(-1))))
+		contact.Normal = triangle.Normal
+		contact.SeparatingVector = triangle.Normal.Mul(depth)
+		contact.SeparatingDistance = depth
+	}
+	return contact
+}
+
+// CheckCollisionConvexTriMesh tests the shape against the triangles of the mesh whose
+// bounds overlap the shape's
+func CheckCollisionConvexTriMesh(shape collider.Convex, triangulatedMesh collider.TriMesh) []*Contact {
+	var contacts []*Contact
+	for _, i := range triangulatedMesh.QueryBoundingBox(*collider.BoundingBoxFromConvex(shape)) {
+		if triContact := CheckCollisionConvexTriangle(shape, triangulatedMesh.Triangles[i]); triContact != nil {
+			index := i
+			triContact.TriIndex = &index
+			contacts = append(contacts, triContact)
+		}
+	}
+
+	return contacts
+}
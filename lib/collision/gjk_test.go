@@ -0,0 +1,142 @@
+package collision_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/kkevinchou/kito/lib/collision"
+	"github.com/kkevinchou/kito/lib/collision/collider"
+)
+
+func TestConvexConvex(t *testing.T) {
+	unitBox := collider.NewBox(mgl64.Vec3{}, mgl64.Vec3{1, 1, 1}, mgl64.QuatIdent())
+	// a box rotated 45 degrees about y reaches sqrt(2) along x
+	rotatedBox := collider.NewBox(mgl64.Vec3{2.2, 0, 0}, mgl64.Vec3{1, 1, 1}, mgl64.QuatRotate(math.Pi/4, mgl64.Vec3{0, 1, 0}))
+	tiltedCapsule := collider.NewCapsule(mgl64.Vec3{-0.5, 3.2, 0}, mgl64.Vec3{0.5, 2.2, 0}, 1)
+	cube := collider.NewConvexHull([]mgl64.Vec3{
+		{-1, -1, -1}, {1, -1, -1}, {1, 1, -1}, {-1, 1, -1},
+		{-1, -1, 1}, {1, -1, 1}, {1, 1, 1}, {-1, 1, 1},
+	})
+
+	testCases := []struct {
+		name   string
+		shape1 collider.Convex
+		shape2 collider.Convex
+		// the separating vector that moves shape1 out of shape2, nil if they don't collide.
+		// EPA only approximates curved shapes so they get a looser threshold
+		expected  *mgl64.Vec3
+		threshold float64
+	}{
+		{"spheres", collider.NewSphere(mgl64.Vec3{1.5, 0, 0}, 1), collider.NewSphere(mgl64.Vec3{}, 1), &mgl64.Vec3{0.5, 0, 0}, 1e-2},
+		{"separated spheres", collider.NewSphere(mgl64.Vec3{2.5, 0, 0}, 1), collider.NewSphere(mgl64.Vec3{}, 1), nil, 0},
+		{"boxes", unitBox.Transform(mgl64.Vec3{0, 1.75, 0.1}), unitBox, &mgl64.Vec3{0, 0.25, 0}, 1e-6},
+		{"rotated box", rotatedBox, unitBox, &mgl64.Vec3{1 + math.Sqrt2 - 2.2, 0, 0}, 1e-6},
+		{"separated rotated box", rotatedBox.Transform(mgl64.Vec3{0.3, 0, 0}), unitBox, nil, 0},
+		{"tilted capsule", tiltedCapsule, unitBox, nil, 0},
+		{"sinking tilted capsule", tiltedCapsule.Transform(mgl64.Vec3{0, -0.4, 0}), unitBox, &mgl64.Vec3{0, 0.2, 0}, 1e-2},
+		{"hull and sphere", collider.NewSphere(mgl64.Vec3{0, 0, 1.8}, 1), cube, &mgl64.Vec3{0, 0, 0.2}, 1e-2},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			contact := collision.CheckCollisionConvexConvex(testCase.shape1, testCase.shape2)
+			if testCase.expected == nil {
+				if contact != nil {
+					t.Fatalf("expected no collision but got %v", contact.SeparatingVector)
+				}
+				return
+			}
+			if contact == nil {
+				t.Fatal("expected a collision")
+			}
+			if contact.SeparatingVector.Sub(*testCase.expected).Len() > testCase.threshold {
+				t.Fatalf("expected separating vector %v but got %v", *testCase.expected, contact.SeparatingVector)
+			}
+
+			// moving shape1 by the separating vector should leave them touching
+			if contact := collision.CheckCollisionConvexConvex(translate(testCase.shape1, contact.SeparatingVector.Mul(1.01)), testCase.shape2); contact != nil {
+				t.Fatalf("expected the shapes to be separated but they're still penetrating by %f", contact.SeparatingDistance)
+			}
+		})
+	}
+}
+
+func TestConvexTriMesh(t *testing.T) {
+	floor := collider.NewTriMeshFromTriangles([]collider.Triangle{
+		collider.NewTriangle([]mgl64.Vec3{{-10, 0, -10}, {-10, 0, 10}, {10, 0, 10}}),
+		collider.NewTriangle([]mgl64.Vec3{{-10, 0, -10}, {10, 0, 10}, {10, 0, -10}}),
+	})
+
+	box := collider.NewBox(mgl64.Vec3{0, 0.8, 0}, mgl64.Vec3{1, 1, 1}, mgl64.QuatIdent())
+	contacts := collision.CheckCollisionConvexTriMesh(box, floor)
+	if len(contacts) == 0 {
+		t.Fatal("expected the box to collide with the floor")
+	}
+	for _, contact := range contacts {
+		if !contact.SeparatingVector.ApproxEqualThreshold(mgl64.Vec3{0, 0.2, 0}, 1e-6) {
+			t.Fatalf("expected the box to be pushed up by 0.2 but got %v", contact.SeparatingVector)
+		}
+	}
+
+	// a box that has sunk most of the way through the floor is still pushed up
+	contacts = collision.CheckCollisionConvexTriMesh(box.Transform(mgl64.Vec3{0, -1.5, 0}), floor)
+	if len(contacts) == 0 || !contacts[0].SeparatingVector.ApproxEqualThreshold(mgl64.Vec3{0, 1.7, 0}, 1e-6) {
+		t.Fatalf("expected the box to be pushed back up through the floor but got %v", contacts)
+	}
+
+	if contacts := collision.CheckCollisionConvexTriMesh(box.Transform(mgl64.Vec3{0, 1, 0}), floor); len(contacts) != 0 {
+		t.Fatalf("expected a box above the floor to not collide but got %d contacts", len(contacts))
+	}
+}
+
+func TestContactPoints(t *testing.T) {
+	floor := collider.NewBox(mgl64.Vec3{0, -1, 0}, mgl64.Vec3{10, 1, 10}, mgl64.QuatIdent())
+
+	// a box lying flat touches at its 4 bottom corners
+	box := collider.NewBox(mgl64.Vec3{0, 0.9, 0}, mgl64.Vec3{1, 1, 1}, mgl64.QuatIdent())
+	contact := collision.CheckCollisionConvexConvex(box, floor)
+	if contact == nil {
+		t.Fatal("expected the box to collide with the floor")
+	}
+	points := collision.ContactPoints(box, contact, 0.01)
+	if len(points) != 4 {
+		t.Fatalf("expected 4 contact points but got %v", points)
+	}
+	for _, point := range points {
+		if math.Abs(point.Y()) > 1e-6 {
+			t.Fatalf("expected the contact points to be on the floor but got %v", point)
+		}
+	}
+
+	// a box balanced on its edge touches along the edge
+	box = collider.NewBox(mgl64.Vec3{0, math.Sqrt2 - 0.1, 0}, mgl64.Vec3{1, 1, 1}, mgl64.QuatRotate(math.Pi/4, mgl64.Vec3{0, 0, 1}))
+	if contact = collision.CheckCollisionConvexConvex(box, floor); contact == nil {
+		t.Fatal("expected the box to collide with the floor")
+	}
+	if points := collision.ContactPoints(box, contact, 0.01); len(points) != 2 {
+		t.Fatalf("expected 2 contact points but got %v", points)
+	}
+
+	sphere := collider.NewSphere(mgl64.Vec3{0, 0.9, 0}, 1)
+	if contact = collision.CheckCollisionConvexConvex(sphere, floor); contact == nil {
+		t.Fatal("expected the sphere to collide with the floor")
+	}
+	if points := collision.ContactPoints(sphere, contact, 0.01); len(points) != 1 || !points[0].ApproxEqual(contact.Point) {
+		t.Fatalf("expected a sphere to touch at the contact point but got %v", points)
+	}
+}
+
+func translate(shape collider.Convex, offset mgl64.Vec3) collider.Convex {
+	switch s := shape.(type) {
+	case collider.Sphere:
+		return s.Transform(offset)
+	case collider.Box:
+		return s.Transform(offset)
+	case collider.Capsule:
+		return s.Transform(offset)
+	case collider.ConvexHull:
+		return s.Transform(offset)
+	}
+	panic("unexpected shape")
+}
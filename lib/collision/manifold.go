@@ -0,0 +1,45 @@
+package collision
+
+import (
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/kkevinchou/kito/lib/collision/collider"
+)
+
+// ContactPoints returns the points where the shape touches the other shape of a contact
+// the shape is the first shape of. GJK and EPA only find the single deepest point, which
+// isn't enough to keep e.g. a box lying flat from rocking. The corners of boxes and
+// hulls and the ends of capsules that are within tolerance of the deepest point along
+// the contact normal are projected onto the contact plane. Shapes without corners touch
+// at the contact point alone
+func ContactPoints(shape collider.Convex, contact *Contact, tolerance float64) []mgl64.Vec3 {
+	normal := contact.Normal
+
+	var candidates []mgl64.Vec3
+	switch s := shape.(type) {
+	case collider.Box:
+		candidates = s.Vertices()
+	case collider.ConvexHull:
+		candidates = s.Vertices
+	case collider.Capsule:
+		candidates = []mgl64.Vec3{s.Top.Sub(normal.Mul(s.Radius)), s.Bottom.Sub(normal.Mul(s.Radius))}
+	}
+
+	if len(candidates) == 0 {
+		return []mgl64.Vec3{contact.Point}
+	}
+
+	deepest := candidates[0].Dot(normal)
+	for _, candidate := range candidates[1:] {
+		if d := candidate.Dot(normal); d < deepest {
+			deepest = d
+		}
+	}
+
+	var points []mgl64.Vec3
+	for _, candidate := range candidates {
+		if candidate.Dot(normal) <= deepest+tolerance {
+			points = append(points, candidate.Sub(normal.Mul(normal.Dot(candidate.Sub(contact.Point)))))
+		}
+	}
+	return points
+}
@@ -0,0 +1,209 @@
+package collision
+
+import (
+	"math"
+
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/kkevinchou/kito/lib/collision/checks"
+	"github.com/kkevinchou/kito/lib/collision/collider"
+)
+
+const (
+	// sweeps report an impact once the shapes are within sweepTolerance of each other
+	sweepTolerance float64 = 1e-4
+
+	// the maximum number of advancement steps a sweep takes before giving up and
+	// reporting an impact where it stopped
+	maxSweepIterations = 32
+)
+
+// SweepHit describes the first time a moving shape touches another
+type SweepHit struct {
+	// TimeOfImpact is the fraction of the displacement, in [0, 1], the shape can move
+	// before touching
+	TimeOfImpact float64
+	TriIndex     *int
+
+	// Point is the point on the other shape that is touched
+	Point mgl64.Vec3
+
+	// Normal points from the other shape towards the moving shape at the time of impact
+	Normal mgl64.Vec3
+}
+
+// distanceFunc returns the distance between the surfaces of two shapes after the
+// moving shape has been offset, the closest point on the other shape and the direction
+// from that point towards the moving shape
+type distanceFunc func(offset mgl64.Vec3) (float64, mgl64.Vec3, mgl64.Vec3)
+
+// timeOfImpact finds when a shape moving by the displacement first touches another by
+// conservative advancement. The distance between two convex shapes is convex in the
+// time of the sweep, so stepping to where its tangent reaches zero never steps past the
+// impact and once the distance stops shrinking it never shrinks again. Shapes that
+// start out penetrating are left to the discrete checks to separate
+func timeOfImpact(displacement mgl64.Vec3, distance distanceFunc) *SweepHit {
+	var t float64
+	for i := 0; i < maxSweepIterations; i++ {
+		separation, point, normal := distance(displacement.Mul(t))
+		if i == 0 && separation < -sweepTolerance {
+			return nil
+		}
+
+		closingSpeed := -displacement.Dot(normal)
+		if closingSpeed <= 0 {
+			return nil
+		}
+
+		if separation <= sweepTolerance {
+			return &SweepHit{TimeOfImpact: t, Point: point, Normal: normal}
+		}
+
+		t += separation / closingSpeed
+		if t > 1 {
+			return nil
+		}
+	}
+
+	_, point, normal := distance(displacement.Mul(t))
+	return &SweepHit{TimeOfImpact: t, Point: point, Normal: normal}
+}
+
+// SweepCapsuleTriangle returns when the capsule moving by the displacement first
+// touches the triangle, or nil if it doesn't
+func SweepCapsuleTriangle(capsule collider.Capsule, displacement mgl64.Vec3, triangle collider.Triangle) *SweepHit {
+	return timeOfImpact(displacement, func(offset mgl64.Vec3) (float64, mgl64.Vec3, mgl64.Vec3) {
+		closestPoints, closestPointsDistance := checks.ClosestPointsLineVSTriangle(
+			collider.Line{P1: capsule.Top.Add(offset), P2: capsule.Bottom.Add(offset)},
+			triangle,
+		)
+		normal := triangle.Normal
+		if closestPointsDistance > 0 {
+			normal = closestPoints[0].Sub(closestPoints[1]).Mul(1 / closestPointsDistance)
+		}
+		return closestPointsDistance - capsule.Radius, closestPoints[1], normal
+	})
+}
+
+// SweepSphereTriangle returns when the sphere moving by the displacement first touches
+// the triangle, or nil if it doesn't
+func SweepSphereTriangle(sphere collider.Sphere, displacement mgl64.Vec3, triangle collider.Triangle) *SweepHit {
+	return timeOfImpact(displacement, func(offset mgl64.Vec3) (float64, mgl64.Vec3, mgl64.Vec3) {
+		center := sphere.Center.Add(offset)
+		closestPoint := checks.ClosestPointOnTriangleToPoint(triangle, center)
+		toCenter := center.Sub(closestPoint)
+		distance := toCenter.Len()
+
+		normal := triangle.Normal
+		if distance > 0 {
+			normal = toCenter.Mul(1 / distance)
+		}
+		return distance - sphere.Radius, closestPoint, normal
+	})
+}
+
+// SweepCapsuleTriMesh returns the earliest impact of the capsule moving by the
+// displacement against the triangles of the mesh, or nil if it doesn't touch any
+func SweepCapsuleTriMesh(capsule collider.Capsule, displacement mgl64.Vec3, triangulatedMesh collider.TriMesh) *SweepHit {
+	radius := mgl64.Vec3{capsule.Radius, capsule.Radius, capsule.Radius}
+	bounds := collider.BoundingBox{
+		MinVertex: minVec3(capsule.Top, capsule.Bottom).Sub(radius),
+		MaxVertex: maxVec3(capsule.Top, capsule.Bottom).Add(radius),
+	}
+
+	return sweepTriMesh(bounds, displacement, triangulatedMesh, func(triangle collider.Triangle) *SweepHit {
+		return SweepCapsuleTriangle(capsule, displacement, triangle)
+	})
+}
+
+// SweepSphereTriMesh returns the earliest impact of the sphere moving by the
+// displacement against the triangles of the mesh, or nil if it doesn't touch any
+func SweepSphereTriMesh(sphere collider.Sphere, displacement mgl64.Vec3, triangulatedMesh collider.TriMesh) *SweepHit {
+	radius := mgl64.Vec3{sphere.Radius, sphere.Radius, sphere.Radius}
+	bounds := collider.BoundingBox{
+		MinVertex: sphere.Center.Sub(radius),
+		MaxVertex: sphere.Center.Add(radius),
+	}
+
+	return sweepTriMesh(bounds, displacement, triangulatedMesh, func(triangle collider.Triangle) *SweepHit {
+		return SweepSphereTriangle(sphere, displacement, triangle)
+	})
+}
+
+// sweepTriMesh sweeps against the triangles whose bounds overlap the bounds of the
+// shape across the whole displacement, keeping the earliest impact
+func sweepTriMesh(bounds collider.BoundingBox, displacement mgl64.Vec3, triangulatedMesh collider.TriMesh, sweep func(triangle collider.Triangle) *SweepHit) *SweepHit {
+	movedBounds := collider.BoundingBox{
+		MinVertex: bounds.MinVertex.Add(displacement),
+		MaxVertex: bounds.MaxVertex.Add(displacement),
+	}
+	sweptBounds := bounds.Union(&movedBounds)
+
+	var earliest *SweepHit
+	for _, i := range triangulatedMesh.QueryBoundingBox(sweptBounds) {
+		hit := sweep(triangulatedMesh.Triangles[i])
+		if hit == nil || (earliest != nil && hit.TimeOfImpact >= earliest.TimeOfImpact) {
+			continue
+		}
+		index := i
+		hit.TriIndex = &index
+		earliest = hit
+	}
+
+	return earliest
+}
+
+// SweepCapsuleCapsule returns when capsule1 moving by the displacement first touches
+// capsule2, or nil if it doesn't. When both capsules are moving, pass the displacement
+// of capsule1 relative to capsule2
+func SweepCapsuleCapsule(capsule1 collider.Capsule, displacement mgl64.Vec3, capsule2 collider.Capsule) *SweepHit {
+	line2 := collider.Line{P1: capsule2.Top, P2: capsule2.Bottom}
+	return timeOfImpact(displacement, func(offset mgl64.Vec3) (float64, mgl64.Vec3, mgl64.Vec3) {
+		closestPoints, closestPointsDistance := checks.ClosestPointsLineVSLine(
+			collider.Line{P1: capsule1.Top.Add(offset), P2: capsule1.Bottom.Add(offset)},
+			line2,
+		)
+
+		// capsules with intersecting segments are already penetrating and never
+		// make it past the first step
+		var normal mgl64.Vec3
+		if closestPointsDistance > 0 {
+			normal = closestPoints[0].Sub(closestPoints[1]).Mul(1 / closestPointsDistance)
+		}
+		return closestPointsDistance - capsule1.Radius - capsule2.Radius, closestPoints[1].Add(normal.Mul(capsule2.Radius)), normal
+	})
+}
+
+// SweepConvexConvex returns when shape1 moving by the displacement first touches
+// shape2, or nil if it doesn't. A ray can be swept as a sphere with no radius
+func SweepConvexConvex(shape1 collider.Convex, displacement mgl64.Vec3, shape2 collider.Convex) *SweepHit {
+	// GJK can't tell which way shapes that are just touching face each other, so they
+	// keep the normal from the last step
+	normal := displacement.Normalize().Mul(-1)
+	var point mgl64.Vec3
+
+	return timeOfImpact(displacement, func(offset mgl64.Vec3) (float64, mgl64.Vec3, mgl64.Vec3) {
+		pointA, pointB, distance, separated := ClosestPointsConvexConvex(translatedConvex{shape: shape1, offset: offset}, shape2)
+		if !separated {
+			if offset.LenSqr() == 0 {
+				return -math.MaxFloat64, point, normal
+			}
+			return 0, point, normal
+		}
+
+		point = pointB
+		if distance > 0 {
+			normal = pointA.Sub(pointB).Mul(1 / distance)
+		}
+		return distance, point, normal
+	})
+}
+
+// translatedConvex moves a shape without having to know what kind of shape it is
+type translatedConvex struct {
+	shape  collider.Convex
+	offset mgl64.Vec3
+}
+
+func (t translatedConvex) Support(direction mgl64.Vec3) mgl64.Vec3 {
+	return t.shape.Support(direction).Add(t.offset)
+}
@@ -0,0 +1,132 @@
+package collision_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/kkevinchou/kito/lib/collision"
+	"github.com/kkevinchou/kito/lib/collision/collider"
+)
+
+// thinWall is a wall with no thickness in the plane x = 0 facing -x
+func thinWall() collider.TriMesh {
+	return collider.NewTriMeshFromTriangles([]collider.Triangle{
+		collider.NewTriangle([]mgl64.Vec3{{0, -10, -10}, {0, -10, 10}, {0, 10, 10}}),
+		collider.NewTriangle([]mgl64.Vec3{{0, -10, -10}, {0, 10, 10}, {0, 10, -10}}),
+	})
+}
+
+func TestSweepSphereThroughThinWall(t *testing.T) {
+	wall := thinWall()
+	sphere := collider.NewSphere(mgl64.Vec3{-5, 0, 0}, 1)
+	displacement := mgl64.Vec3{10, 0, 0}
+
+	// the sphere ends up on the other side of the wall without ever overlapping it
+	// at the start or end of the frame
+	if len(collision.CheckCollisionSphereTriMesh(collider.NewSphere(sphere.Center.Add(displacement), 1), wall)) != 0 {
+		t.Fatal("expected the discrete check to miss the wall")
+	}
+
+	hit := collision.SweepSphereTriMesh(sphere, displacement, wall)
+	if hit == nil {
+		t.Fatal("expected the sweep to hit the wall")
+	}
+	if math.Abs(hit.TimeOfImpact-0.4) > 1e-3 {
+		t.Fatalf("expected a time of impact of 0.4 but got %f", hit.TimeOfImpact)
+	}
+	if !hit.Normal.ApproxEqualThreshold(mgl64.Vec3{-1, 0, 0}, 1e-6) || !hit.Point.ApproxEqualThreshold(mgl64.Vec3{0, 0, 0}, 1e-3) {
+		t.Fatalf("expected to hit the wall at the origin facing -x but got %v %v", hit.Point, hit.Normal)
+	}
+	if hit.TriIndex == nil {
+		t.Fatal("expected the hit to record the triangle")
+	}
+
+	if collision.SweepSphereTriMesh(sphere, displacement.Mul(-1), wall) != nil {
+		t.Fatal("expected a sphere moving away from the wall to not hit it")
+	}
+}
+
+func TestSweepCapsuleTriMesh(t *testing.T) {
+	wall := thinWall()
+	capsule := collider.NewCapsule(mgl64.Vec3{-5, 3, 0}, mgl64.Vec3{-5, -3, 0}, 2)
+
+	hit := collision.SweepCapsuleTriMesh(capsule, mgl64.Vec3{20, 0, 0}, wall)
+	if hit == nil || math.Abs(hit.TimeOfImpact-0.15) > 1e-3 {
+		t.Fatalf("expected a time of impact of 0.15 but got %v", hit)
+	}
+
+	// a capsule touching the wall and sliding along it doesn't stop
+	touching := collider.NewCapsule(mgl64.Vec3{-2, 3, 0}, mgl64.Vec3{-2, -3, 0}, 2)
+	if hit := collision.SweepCapsuleTriMesh(touching, mgl64.Vec3{0, 0, 5}, wall); hit != nil {
+		t.Fatalf("expected a capsule sliding along the wall to not hit it but got %v", hit)
+	}
+
+	// moving past the edge of the wall
+	if hit := collision.SweepCapsuleTriMesh(capsule, mgl64.Vec3{20, 0, 100}, wall); hit != nil {
+		t.Fatalf("expected a capsule moving past the wall to not hit it but got %v", hit)
+	}
+}
+
+func TestSweepCapsuleCapsule(t *testing.T) {
+	capsule1 := collider.NewCapsule(mgl64.Vec3{0, 3, 0}, mgl64.Vec3{0, 0, 0}, 1)
+	capsule2 := collider.NewCapsule(mgl64.Vec3{10, 3, 0}, mgl64.Vec3{10, 0, 0}, 1)
+
+	hit := collision.SweepCapsuleCapsule(capsule1, mgl64.Vec3{20, 0, 0}, capsule2)
+	if hit == nil || math.Abs(hit.TimeOfImpact-0.4) > 1e-3 {
+		t.Fatalf("expected a time of impact of 0.4 but got %v", hit)
+	}
+	if !hit.Normal.ApproxEqualThreshold(mgl64.Vec3{-1, 0, 0}, 1e-6) || math.Abs(hit.Point.X()-9) > 1e-3 {
+		t.Fatalf("expected to touch the near side of the capsule but got %v %v", hit.Point, hit.Normal)
+	}
+
+	if hit := collision.SweepCapsuleCapsule(capsule1, mgl64.Vec3{20, 0, 5}, capsule2); hit != nil {
+		t.Fatalf("expected the capsules to pass each other but got %v", hit)
+	}
+}
+
+func TestSweepConvexConvex(t *testing.T) {
+	box := collider.NewBox(mgl64.Vec3{}, mgl64.Vec3{1, 1, 1}, mgl64.QuatIdent())
+
+	testCases := []struct {
+		name         string
+		shape        collider.Convex
+		displacement mgl64.Vec3
+		// the time of impact and normal, nil if the shape misses
+		timeOfImpact *float64
+		normal       mgl64.Vec3
+	}{
+		{"ray", collider.NewSphere(mgl64.Vec3{-5, 0.5, 0}, 0), mgl64.Vec3{10, 0, 0}, floatPointer(0.4), mgl64.Vec3{-1, 0, 0}},
+		{"sphere", collider.NewSphere(mgl64.Vec3{0, 6, 0}, 1), mgl64.Vec3{0, -8, 0}, floatPointer(0.5), mgl64.Vec3{0, 1, 0}},
+		{"sphere onto an edge", collider.NewSphere(mgl64.Vec3{1 + math.Sqrt2/2, 5, 0}, 1), mgl64.Vec3{0, -10, 0}, floatPointer((4 - math.Sqrt2/2) / 10), mgl64.Vec3{math.Sqrt2 / 2, math.Sqrt2 / 2, 0}},
+		{"capsule", collider.NewCapsule(mgl64.Vec3{-4, 3, 0}, mgl64.Vec3{-4, 1, 0}, 0.5), mgl64.Vec3{5, 0, 0}, floatPointer(0.5), mgl64.Vec3{-1, 0, 0}},
+		{"miss", collider.NewSphere(mgl64.Vec3{-5, 3, 0}, 1), mgl64.Vec3{10, 0, 0}, nil, mgl64.Vec3{}},
+		{"too short", collider.NewSphere(mgl64.Vec3{-5, 0, 0}, 1), mgl64.Vec3{2, 0, 0}, nil, mgl64.Vec3{}},
+		{"moving away", collider.NewSphere(mgl64.Vec3{-5, 0, 0}, 1), mgl64.Vec3{-10, 0, 0}, nil, mgl64.Vec3{}},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			hit := collision.SweepConvexConvex(testCase.shape, testCase.displacement, box)
+			if testCase.timeOfImpact == nil {
+				if hit != nil {
+					t.Fatalf("expected a miss but hit at %f", hit.TimeOfImpact)
+				}
+				return
+			}
+			if hit == nil {
+				t.Fatal("expected a hit")
+			}
+			if math.Abs(hit.TimeOfImpact-*testCase.timeOfImpact) > 1e-3 {
+				t.Fatalf("expected a time of impact of %f but got %f", *testCase.timeOfImpact, hit.TimeOfImpact)
+			}
+			if hit.Normal.Sub(testCase.normal).Len() > 1e-3 {
+				t.Fatalf("expected a normal of %v but got %v", testCase.normal, hit.Normal)
+			}
+		})
+	}
+}
+
+func floatPointer(f float64) *float64 {
+	return &f
+}
@@ -2,6 +2,12 @@ package console
 
 import (
 	"github.com/inkyblackness/imgui-go/v4"
+	"github.com/kkevinchou/kito/lib/logger"
+)
+
+const (
+	// maxBufferedLogs caps the log entries held between console renders
+	maxBufferedLogs = 200
 )
 
 var GlobalConsole *Console = &Console{}
@@ -17,6 +23,26 @@ type Console struct {
 	HistoryPointer int
 
 	ScrollToBottom bool
+
+	logs *logger.BufferSink
+}
+
+// LogSink returns a sink that shows log entries at or above the level in the console.
+// Entries are buffered until FlushLogs is called since the console is only safe to
+// modify while rendering
+func (c *Console) LogSink(level logger.Level) logger.Sink {
+	c.logs = logger.NewBufferSink(level, maxBufferedLogs)
+	return c.logs
+}
+
+// FlushLogs appends the buffered log entries to the console output
+func (c *Console) FlushLogs() {
+	if c.logs == nil {
+		return
+	}
+	for _, entry := range c.logs.Drain() {
+		c.AppendOutput(entry.String())
+	}
 }
 
 func (c *Console) Send() string {
@@ -27,6 +53,20 @@ func (c *Console) Send() string {
 	return command
 }
 
+// AppendOutput attaches output to the most recently sent command
+func (c *Console) AppendOutput(output string) {
+	if len(c.ConsoleHistory) == 0 {
+		c.ConsoleHistory = append(c.ConsoleHistory, &ConsoleItem{})
+	}
+
+	item := c.ConsoleHistory[len(c.ConsoleHistory)-1]
+	if item.Output != "" {
+		item.Output += "\n"
+	}
+	item.Output += output
+	c.ScrollToBottom = true
+}
+
 func (c *Console) AdvanceHistoryCursor(delta int) string {
 	c.HistoryPointer += delta
 	if c.HistoryPointer > 0 {
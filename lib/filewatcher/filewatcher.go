@@ -0,0 +1,127 @@
+package filewatcher
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+type Op int
+
+const (
+	OpModified Op = iota
+	OpCreated
+	OpRemoved
+)
+
+func (o Op) String() string {
+	switch o {
+	case OpCreated:
+		return "created"
+	case OpRemoved:
+		return "removed"
+	}
+	return "modified"
+}
+
+type Event struct {
+	Op   Op
+	Path string
+
+	// Name is the file name without the extension, which is what assets are looked up by
+	Name      string
+	Extension string
+}
+
+// Watcher polls a set of files and directories for changes. Polling is used rather than
+// OS notifications so that it behaves the same on every platform and so that changes
+// are picked up at a well defined point in the frame
+type Watcher struct {
+	paths      []string
+	extensions map[string]bool
+	modTimes   map[string]time.Time
+}
+
+// New creates a watcher over the given files and directories. Directories are watched
+// recursively, only for files with one of the extensions. The current state of the
+// watched files is the baseline that later changes are reported against
+func New(extensions []string, paths ...string) *Watcher {
+	w := &Watcher{
+		paths:      paths,
+		extensions: map[string]bool{},
+	}
+	for _, extension := range extensions {
+		w.extensions[extension] = true
+	}
+	w.modTimes = w.scan()
+	return w
+}
+
+// Poll returns the files that were created, modified or removed since the last poll,
+// ordered by path
+func (w *Watcher) Poll() []Event {
+	modTimes := w.scan()
+
+	var events []Event
+	for path, modTime := range modTimes {
+		previous, ok := w.modTimes[path]
+		if !ok {
+			events = append(events, newEvent(OpCreated, path))
+		} else if !modTime.Equal(previous) {
+			events = append(events, newEvent(OpModified, path))
+		}
+	}
+	for path := range w.modTimes {
+		if _, ok := modTimes[path]; !ok {
+			events = append(events, newEvent(OpRemoved, path))
+		}
+	}
+
+	w.modTimes = modTimes
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].Path < events[j].Path
+	})
+	return events
+}
+
+func newEvent(op Op, path string) Event {
+	extension := filepath.Ext(path)
+	return Event{
+		Op:        op,
+		Path:      path,
+		Name:      strings.TrimSuffix(filepath.Base(path), extension),
+		Extension: extension,
+	}
+}
+
+func (w *Watcher) scan() map[string]time.Time {
+	modTimes := map[string]time.Time{}
+	for _, root := range w.paths {
+		info, err := os.Stat(root)
+		if err != nil {
+			// missing paths are watched for being created
+			continue
+		}
+
+		if !info.IsDir() {
+			modTimes[root] = info.ModTime()
+			continue
+		}
+
+		filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() || !w.extensions[filepath.Ext(path)] {
+				return nil
+			}
+			info, err := d.Info()
+			if err != nil {
+				return nil
+			}
+			modTimes[path] = info.ModTime()
+			return nil
+		})
+	}
+	return modTimes
+}
@@ -0,0 +1,75 @@
+package filewatcher_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kkevinchou/kito/lib/filewatcher"
+)
+
+func writeFile(t *testing.T, path string, modTime time.Time) {
+	if err := os.WriteFile(path, []byte(path), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPoll(t *testing.T) {
+	directory := t.TempDir()
+	if err := os.Mkdir(filepath.Join(directory, "gltf"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	config := filepath.Join(t.TempDir(), "config.json")
+
+	start := time.Now()
+	model := filepath.Join(directory, "gltf", "bob.gltf")
+	writeFile(t, model, start)
+	writeFile(t, filepath.Join(directory, "notes.txt"), start)
+	writeFile(t, config, start)
+
+	w := filewatcher.New([]string{".gltf", ".png"}, directory, config)
+	if events := w.Poll(); len(events) != 0 {
+		t.Fatalf("expected no events before any changes but got %v", events)
+	}
+
+	texture := filepath.Join(directory, "gltf", "bob.png")
+	writeFile(t, model, start.Add(time.Second))
+	writeFile(t, texture, start)
+	writeFile(t, filepath.Join(directory, "notes.txt"), start.Add(time.Second))
+	writeFile(t, config, start.Add(time.Second))
+
+	events := w.Poll()
+	expected := []filewatcher.Event{
+		{Op: filewatcher.OpModified, Path: model, Name: "bob", Extension: ".gltf"},
+		{Op: filewatcher.OpCreated, Path: texture, Name: "bob", Extension: ".png"},
+		{Op: filewatcher.OpModified, Path: config, Name: "config", Extension: ".json"},
+	}
+	if len(events) != len(expected) {
+		t.Fatalf("expected %v but got %v", expected, events)
+	}
+	for _, e := range expected {
+		found := false
+		for _, event := range events {
+			if event == e {
+				found = true
+			}
+		}
+		if !found {
+			t.Fatalf("expected %v in %v", e, events)
+		}
+	}
+
+	os.Remove(texture)
+	events = w.Poll()
+	if len(events) != 1 || events[0].Op != filewatcher.OpRemoved || events[0].Path != texture {
+		t.Fatalf("expected %s to be removed but got %v", texture, events)
+	}
+
+	if events := w.Poll(); len(events) != 0 {
+		t.Fatalf("expected no events without changes but got %v", events)
+	}
+}
@@ -1,14 +1,17 @@
 package libutils
 
 import (
-	"fmt"
 	"os"
 	"path"
 	"path/filepath"
 
 	_ "image/png"
+
+	"github.com/kkevinchou/kito/lib/logger"
 )
 
+var log = logger.New("libutils")
+
 type FileMetaData struct {
 	Name      string
 	Path      string
@@ -29,7 +32,7 @@ func GetFileMetaData(directory string, subDirectories []string, extensions map[s
 	for _, subDir := range subPaths {
 		files, err := os.ReadDir(subDir)
 		if err != nil {
-			fmt.Println(err)
+			log.Error("failed to read directory", logger.F("path", subDir), logger.Err(err))
 			return nil
 		}
 
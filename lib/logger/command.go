@@ -0,0 +1,80 @@
+package logger
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// HandleCommand executes a console command against the log levels and returns its
+// output. Supported commands are:
+//
+//	list
+//	<level>
+//	<subsystem> <level|reset>
+func HandleCommand(args []string) (string, error) {
+	if len(args) == 0 {
+		return "", fmt.Errorf("expected list, a level, or a subsystem and a level")
+	}
+
+	if len(args) == 1 {
+		if args[0] == "list" {
+			return listLevels(), nil
+		}
+
+		level, err := ParseLevel(args[0])
+		if err != nil {
+			return "", err
+		}
+		SetLevel("", level)
+		return fmt.Sprintf("default: %s", level), nil
+	}
+
+	if len(args) != 2 {
+		return "", fmt.Errorf("expected a subsystem and a level")
+	}
+
+	subsystem := args[0]
+	if args[1] == "reset" {
+		ResetLevel(subsystem)
+		return fmt.Sprintf("%s: default", subsystem), nil
+	}
+
+	level, err := ParseLevel(args[1])
+	if err != nil {
+		return "", err
+	}
+	SetLevel(subsystem, level)
+	return fmt.Sprintf("%s: %s", subsystem, level), nil
+}
+
+func listLevels() string {
+	defaultLevel, levels := Levels()
+
+	subsystems := Subsystems()
+	for subsystem := range levels {
+		if !contains(subsystems, subsystem) {
+			subsystems = append(subsystems, subsystem)
+		}
+	}
+	sort.Strings(subsystems)
+
+	lines := []string{fmt.Sprintf("default: %s", defaultLevel)}
+	for _, subsystem := range subsystems {
+		if level, ok := levels[subsystem]; ok {
+			lines = append(lines, fmt.Sprintf("%s: %s", subsystem, level))
+		} else {
+			lines = append(lines, fmt.Sprintf("%s: default", subsystem))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -2,20 +2,292 @@ package logger
 
 import (
 	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 )
 
-var (
-	LoggingLevel int = 1
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+	// LevelOff disables logging for a subsystem
+	LevelOff
 )
 
-func Debug(message string) {
-	if LoggingLevel <= 0 {
-		fmt.Println(message)
+var levelNames = map[Level]string{
+	LevelDebug: "debug",
+	LevelInfo:  "info",
+	LevelWarn:  "warn",
+	LevelError: "error",
+	LevelOff:   "off",
+}
+
+func (l Level) String() string {
+	if name, ok := levelNames[l]; ok {
+		return name
+	}
+	return fmt.Sprintf("level(%d)", int(l))
+}
+
+func ParseLevel(name string) (Level, error) {
+	for level, levelName := range levelNames {
+		if strings.EqualFold(name, levelName) {
+			return level, nil
+		}
+	}
+	return LevelOff, fmt.Errorf("unknown log level %q, expected one of debug, info, warn, error or off", name)
+}
+
+// Field is a key value pair attached to a log entry
+type Field struct {
+	Key   string
+	Value any
+}
+
+func F(key string, value any) Field {
+	return Field{Key: key, Value: value}
+}
+
+func PlayerID(id int) Field {
+	return F("player", id)
+}
+
+func EntityID(id int) Field {
+	return F("entity", id)
+}
+
+func Err(err error) Field {
+	return F("err", err)
+}
+
+type Entry struct {
+	Time      time.Time
+	Level     Level
+	Subsystem string
+	Message   string
+	Fields    []Field
+}
+
+// String formats the entry as a single line, e.g.
+//
+//	12:04:05.123 WARN  [network] message queue full cf=1024 player=70001
+func (e Entry) String() string {
+	var b strings.Builder
+	b.WriteString(e.Time.Format("15:04:05.000"))
+	fmt.Fprintf(&b, " %-5s [%s] %s", strings.ToUpper(e.Level.String()), e.Subsystem, e.Message)
+	for _, field := range e.Fields {
+		value := fmt.Sprint(field.Value)
+		if value == "" || strings.ContainsAny(value, " \t\n\"=") {
+			value = fmt.Sprintf("%q", value)
+		}
+		fmt.Fprintf(&b, " %s=%s", field.Key, value)
+	}
+	return b.String()
+}
+
+// registry holds the log levels and sinks shared by every logger
+type registry struct {
+	mu           sync.RWMutex
+	defaultLevel Level
+	levels       map[string]Level
+	sinks        []Sink
+	commandFrame func() int
+	subsystems   map[string]bool
+}
+
+var std = &registry{
+	defaultLevel: LevelInfo,
+	levels:       map[string]Level{},
+	sinks:        []Sink{NewWriterSink(os.Stdout)},
+	subsystems:   map[string]bool{},
+}
+
+// Logger logs entries tagged with its subsystem. Loggers are cheap to create and are
+// typically stored in a package level variable named after the package
+type Logger struct {
+	subsystem string
+	fields    []Field
+}
+
+func New(subsystem string) *Logger {
+	std.mu.Lock()
+	std.subsystems[subsystem] = true
+	std.mu.Unlock()
+	return &Logger{subsystem: subsystem}
+}
+
+// With returns a logger that attaches the fields to every entry it logs
+func (l *Logger) With(fields ...Field) *Logger {
+	return &Logger{
+		subsystem: l.subsystem,
+		fields:    append(append([]Field{}, l.fields...), fields...),
+	}
+}
+
+func (l *Logger) Enabled(level Level) bool {
+	std.mu.RLock()
+	defer std.mu.RUnlock()
+	return level >= std.levelFor(l.subsystem)
+}
+
+func (l *Logger) Debug(message string, fields ...Field) {
+	l.log(LevelDebug, message, fields)
+}
+
+func (l *Logger) Info(message string, fields ...Field) {
+	l.log(LevelInfo, message, fields)
+}
+
+func (l *Logger) Warn(message string, fields ...Field) {
+	l.log(LevelWarn, message, fields)
+}
+
+func (l *Logger) Error(message string, fields ...Field) {
+	l.log(LevelError, message, fields)
+}
+
+func (l *Logger) log(level Level, message string, fields []Field) {
+	std.mu.RLock()
+	if level >= LevelOff || level < std.levelFor(l.subsystem) {
+		std.mu.RUnlock()
+		return
+	}
+	sinks := std.sinks
+	commandFrame := std.commandFrame
+	std.mu.RUnlock()
+
+	entry := Entry{
+		Time:      time.Now(),
+		Level:     level,
+		Subsystem: l.subsystem,
+		Message:   message,
+		Fields:    make([]Field, 0, len(l.fields)+len(fields)+1),
+	}
+	if commandFrame != nil {
+		entry.Fields = append(entry.Fields, F("cf", commandFrame()))
+	}
+	entry.Fields = append(entry.Fields, l.fields...)
+	entry.Fields = append(entry.Fields, fields...)
+
+	for _, sink := range sinks {
+		sink.Write(entry)
 	}
 }
 
-func Debug1(message string) {
-	if LoggingLevel <= -1 {
-		fmt.Println(message)
+func (r *registry) levelFor(subsystem string) Level {
+	if level, ok := r.levels[subsystem]; ok {
+		return level
 	}
+	return r.defaultLevel
+}
+
+// SetLevel sets the level of a subsystem. An empty subsystem sets the level of every
+// subsystem without its own level
+func SetLevel(subsystem string, level Level) {
+	std.mu.Lock()
+	defer std.mu.Unlock()
+	if subsystem == "" {
+		std.defaultLevel = level
+	} else {
+		std.levels[subsystem] = level
+	}
+}
+
+// ResetLevel makes the subsystem use the default level again
+func ResetLevel(subsystem string) {
+	std.mu.Lock()
+	defer std.mu.Unlock()
+	delete(std.levels, subsystem)
+}
+
+// SetLevels replaces every level with the levels in the spec, see ParseLevels
+func SetLevels(spec string) error {
+	defaultLevel, levels, err := ParseLevels(spec)
+	if err != nil {
+		return err
+	}
+	std.mu.Lock()
+	defer std.mu.Unlock()
+	std.defaultLevel = defaultLevel
+	std.levels = levels
+	return nil
+}
+
+// ParseLevels parses a comma separated list of levels. A bare level sets the default
+// level and subsystem=level sets the level of a subsystem, e.g. "warn,network=debug".
+// The default level is info if the spec doesn't set one
+func ParseLevels(spec string) (Level, map[string]Level, error) {
+	defaultLevel := LevelInfo
+	levels := map[string]Level{}
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		subsystem, name, ok := strings.Cut(part, "=")
+		if !ok {
+			name = subsystem
+		}
+		level, err := ParseLevel(strings.TrimSpace(name))
+		if err != nil {
+			return defaultLevel, nil, err
+		}
+		if ok {
+			levels[strings.TrimSpace(subsystem)] = level
+		} else {
+			defaultLevel = level
+		}
+	}
+	return defaultLevel, levels, nil
+}
+
+// Levels returns the default level and the levels of subsystems that override it
+func Levels() (Level, map[string]Level) {
+	std.mu.RLock()
+	defer std.mu.RUnlock()
+	levels := map[string]Level{}
+	for subsystem, level := range std.levels {
+		levels[subsystem] = level
+	}
+	return std.defaultLevel, levels
+}
+
+// Subsystems returns the subsystems that loggers have been created for, sorted by name
+func Subsystems() []string {
+	std.mu.RLock()
+	defer std.mu.RUnlock()
+	var subsystems []string
+	for subsystem := range std.subsystems {
+		subsystems = append(subsystems, subsystem)
+	}
+	sort.Strings(subsystems)
+	return subsystems
+}
+
+// SetSinks replaces the sinks that entries are written to
+func SetSinks(sinks ...Sink) {
+	std.mu.Lock()
+	defer std.mu.Unlock()
+	std.sinks = sinks
+}
+
+func AddSink(sink Sink) {
+	std.mu.Lock()
+	defer std.mu.Unlock()
+	std.sinks = append(append([]Sink{}, std.sinks...), sink)
+}
+
+// SetCommandFrameFunc sets the function used to tag every entry with the current
+// command frame
+func SetCommandFrameFunc(fn func() int) {
+	std.mu.Lock()
+	defer std.mu.Unlock()
+	std.commandFrame = fn
 }
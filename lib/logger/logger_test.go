@@ -0,0 +1,146 @@
+package logger_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kkevinchou/kito/lib/logger"
+)
+
+// capture routes every entry into a buffer sink for the duration of the test
+func capture(t *testing.T) *logger.BufferSink {
+	sink := logger.NewBufferSink(logger.LevelDebug, 100)
+	logger.SetSinks(sink)
+	t.Cleanup(func() {
+		logger.SetSinks(logger.NewWriterSink(os.Stdout))
+		logger.SetLevels("")
+		logger.SetCommandFrameFunc(nil)
+	})
+	return sink
+}
+
+func TestSubsystemLevels(t *testing.T) {
+	sink := capture(t)
+	if err := logger.SetLevels("warn,network=debug"); err != nil {
+		t.Fatal(err)
+	}
+
+	network := logger.New("network")
+	render := logger.New("render")
+
+	network.Debug("network debug")
+	render.Info("render info")
+	render.Warn("render warn")
+
+	entries := sink.Drain()
+	if len(entries) != 2 || entries[0].Message != "network debug" || entries[1].Message != "render warn" {
+		t.Fatalf("expected the network debug and render warn entries but got %v", entries)
+	}
+
+	logger.SetLevel("network", logger.LevelOff)
+	network.Error("network error")
+	if entries := sink.Drain(); len(entries) != 0 {
+		t.Fatalf("expected the network subsystem to be off but got %v", entries)
+	}
+}
+
+func TestFields(t *testing.T) {
+	sink := capture(t)
+	logger.SetCommandFrameFunc(func() int { return 42 })
+
+	log := logger.New("network").With(logger.PlayerID(70001))
+	log.Warn("message queue full", logger.F("type", 3), logger.Err(errors.New("queue has 1024 messages")))
+
+	entries := sink.Drain()
+	if len(entries) != 1 {
+		t.Fatalf("expected one entry but got %v", entries)
+	}
+
+	line := entries[0].String()
+	expected := `WARN  [network] message queue full cf=42 player=70001 type=3 err="queue has 1024 messages"`
+	if !strings.HasSuffix(line, expected) {
+		t.Fatalf("expected %q to end with %q", line, expected)
+	}
+}
+
+func TestParseLevels(t *testing.T) {
+	defaultLevel, levels, err := logger.ParseLevels(" error , script = debug,")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if defaultLevel != logger.LevelError || len(levels) != 1 || levels["script"] != logger.LevelDebug {
+		t.Fatalf("unexpected levels %s %v", defaultLevel, levels)
+	}
+
+	if defaultLevel, _, _ := logger.ParseLevels(""); defaultLevel != logger.LevelInfo {
+		t.Fatalf("expected the default level to be info but got %s", defaultLevel)
+	}
+	for _, spec := range []string{"loud", "network=loud"} {
+		if _, _, err := logger.ParseLevels(spec); err == nil {
+			t.Errorf("expected %q to be invalid", spec)
+		}
+	}
+}
+
+func TestHandleCommand(t *testing.T) {
+	capture(t)
+	logger.New("physics")
+
+	if _, err := logger.HandleCommand([]string{"physics", "debug"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := logger.HandleCommand([]string{"warn"}); err != nil {
+		t.Fatal(err)
+	}
+
+	output, err := logger.HandleCommand([]string{"list"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(output, "default: warn") || !strings.Contains(output, "physics: debug") {
+		t.Fatalf("unexpected levels listed:\n%s", output)
+	}
+
+	if _, err := logger.HandleCommand([]string{"physics", "reset"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, levels := logger.Levels(); len(levels) != 0 {
+		t.Fatalf("expected physics to use the default level but got %v", levels)
+	}
+
+	if _, err := logger.HandleCommand([]string{"physics", "loud"}); err == nil {
+		t.Fatal("expected an unknown level to fail")
+	}
+}
+
+func TestRotatingFileSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "kito.log")
+	sink, err := logger.NewRotatingFileSink(path, 100, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger.SetSinks(sink)
+	t.Cleanup(func() { logger.SetSinks(logger.NewWriterSink(os.Stdout)) })
+
+	log := logger.New("test")
+	for i := 0; i < 10; i++ {
+		log.Info("a message long enough to fill the log file quickly")
+	}
+	sink.Close()
+
+	for _, p := range []string{path, path + ".1", path + ".2"} {
+		info, err := os.Stat(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Size() > 100 {
+			t.Errorf("expected %s to be rotated before exceeding 100 bytes but it was %d", p, info.Size())
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Error("expected only two rotated files to be kept")
+	}
+}
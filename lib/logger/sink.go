@@ -0,0 +1,156 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// Sink receives every entry that passes the level of its subsystem. Sinks may be written
+// to from multiple goroutines
+type Sink interface {
+	Write(entry Entry)
+}
+
+// WriterSink writes each entry as a line to a writer, e.g. os.Stdout
+type WriterSink struct {
+	mu     sync.Mutex
+	writer io.Writer
+}
+
+func NewWriterSink(writer io.Writer) *WriterSink {
+	return &WriterSink{writer: writer}
+}
+
+func (s *WriterSink) Write(entry Entry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fmt.Fprintln(s.writer, entry.String())
+}
+
+// RotatingFileSink writes entries to a file. When the file would grow past its max size
+// it's renamed with a .1 suffix, shifting older files up to maxFiles after which the
+// oldest file is removed
+type RotatingFileSink struct {
+	mu       sync.Mutex
+	path     string
+	maxBytes int64
+	maxFiles int
+
+	file *os.File
+	size int64
+}
+
+func NewRotatingFileSink(path string, maxBytes int64, maxFiles int) (*RotatingFileSink, error) {
+	s := &RotatingFileSink{path: path, maxBytes: maxBytes, maxFiles: maxFiles}
+	if err := s.open(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *RotatingFileSink) open() error {
+	file, err := os.OpenFile(s.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	s.file = file
+	s.size = info.Size()
+	return nil
+}
+
+func (s *RotatingFileSink) Write(entry Entry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file == nil {
+		return
+	}
+
+	line := entry.String() + "\n"
+	if s.size > 0 && s.size+int64(len(line)) > s.maxBytes {
+		if err := s.rotate(); err != nil {
+			// the log file can't be written to so fall back to stderr rather than
+			// losing the entry
+			fmt.Fprintf(os.Stderr, "failed to rotate %s: %s\n%s", s.path, err, line)
+			return
+		}
+	}
+
+	n, _ := s.file.WriteString(line)
+	s.size += int64(n)
+}
+
+func (s *RotatingFileSink) rotate() error {
+	s.file.Close()
+	s.file = nil
+
+	os.Remove(rotatedPath(s.path, s.maxFiles))
+	for i := s.maxFiles - 1; i >= 1; i-- {
+		os.Rename(rotatedPath(s.path, i), rotatedPath(s.path, i+1))
+	}
+	if s.maxFiles > 0 {
+		if err := os.Rename(s.path, rotatedPath(s.path, 1)); err != nil {
+			return err
+		}
+	} else if err := os.Remove(s.path); err != nil {
+		return err
+	}
+	return s.open()
+}
+
+func (s *RotatingFileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Close()
+	s.file = nil
+	return err
+}
+
+func rotatedPath(path string, index int) string {
+	return fmt.Sprintf("%s.%d", path, index)
+}
+
+// BufferSink holds entries at or above its level until they're drained. It's used for
+// sinks that have to be written to from a specific goroutine, like the in-game console
+// which is only safe to modify while rendering. The oldest entries are dropped once the
+// buffer holds capacity entries
+type BufferSink struct {
+	mu       sync.Mutex
+	level    Level
+	capacity int
+	entries  []Entry
+}
+
+func NewBufferSink(level Level, capacity int) *BufferSink {
+	return &BufferSink{level: level, capacity: capacity}
+}
+
+func (s *BufferSink) Write(entry Entry) {
+	if entry.Level < s.level {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.entries) >= s.capacity {
+		s.entries = s.entries[1:]
+	}
+	s.entries = append(s.entries, entry)
+}
+
+func (s *BufferSink) Drain() []Entry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entries := s.entries
+	s.entries = nil
+	return entries
+}
@@ -1,6 +1,7 @@
 package metrics
 
 import (
+	"sync"
 	"time"
 )
 
@@ -20,13 +21,22 @@ type Metric struct {
 	data            [bucketSize]DataPoint
 }
 
+// MetricsRegistry holds two kinds of metrics. Metrics recorded with Inc keep the last
+// second of data points for the debug UI. Counters, gauges and histograms are typed,
+// labeled and safe to read from other goroutines, which is what's exported to Prometheus
 type MetricsRegistry struct {
 	metrics map[string]*Metric
+
+	mu     sync.Mutex
+	series map[string]*series
+	help   map[string]string
 }
 
 func New() *MetricsRegistry {
 	return &MetricsRegistry{
 		metrics: map[string]*Metric{},
+		series:  map[string]*series{},
+		help:    map[string]string{},
 	}
 }
 
@@ -105,3 +115,9 @@ func (m *MetricsRegistry) advanceMetricCursors(name string, duration time.Durati
 		start = metric.data[metric.oneSecondCursor]
 	}
 }
+
+// Milliseconds converts a duration to fractional milliseconds, which is the unit that
+// timings are recorded in
+func Milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
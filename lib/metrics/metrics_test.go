@@ -0,0 +1,104 @@
+package metrics_test
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kkevinchou/kito/lib/metrics"
+)
+
+func TestHistogramPercentiles(t *testing.T) {
+	registry := metrics.New()
+	histogram := registry.Histogram("frame_time_ms")
+
+	if p := histogram.Percentile(0.5); p != 0 {
+		t.Fatalf("expected an empty histogram to report 0 but got %f", p)
+	}
+
+	for i := 1; i <= 100; i++ {
+		histogram.Observe(float64(i))
+	}
+	percentiles := histogram.Percentiles(0.5, 0.95, 0.99, 1)
+	expected := []float64{50, 95, 99, 100}
+	for i := range expected {
+		if percentiles[i] != expected[i] {
+			t.Fatalf("expected percentiles %v but got %v", expected, percentiles)
+		}
+	}
+
+	// percentiles only cover the recent window while the count and sum cover everything
+	for i := 0; i < 1000; i++ {
+		histogram.Observe(1000)
+	}
+	if p := histogram.Percentile(0.01); p != 1000 {
+		t.Fatalf("expected old observations to leave the window but got p1 %f", p)
+	}
+	if histogram.Count() != 1100 || histogram.Sum() != 5050+1000*1000 {
+		t.Fatalf("unexpected count %d and sum %f", histogram.Count(), histogram.Sum())
+	}
+}
+
+func TestLabels(t *testing.T) {
+	registry := metrics.New()
+
+	registry.Gauge("rtt_ms", metrics.L("player", 1), metrics.L("region", "us")).Set(30)
+	if registry.Gauge("rtt_ms", metrics.L("region", "us"), metrics.L("player", 1)).Value() != 30 {
+		t.Fatal("expected label order to not matter")
+	}
+	if registry.Gauge("rtt_ms", metrics.L("player", 2)).Value() != 0 {
+		t.Fatal("expected different label values to be different series")
+	}
+
+	registry.Counter("messages_total").Add(-5)
+	if registry.Counter("messages_total").Value() != 0 {
+		t.Fatal("expected counters to never decrease")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected reusing a counter name as a gauge to panic")
+		}
+	}()
+	registry.Gauge("messages_total")
+}
+
+func TestPrometheus(t *testing.T) {
+	registry := metrics.New()
+	registry.SetHelp("messages_total", "Messages received")
+	registry.Counter("messages_total", metrics.L("type", 2)).Add(3)
+	registry.Counter("messages_total", metrics.L("type", 1)).Inc()
+	registry.Gauge("players").Set(2)
+	registry.Gauge("rtt_ms", metrics.L("player", `a "quoted" name`)).Set(12.5)
+	registry.Histogram("frame_time_ms").Observe(4)
+
+	// the legacy metrics only feed the debug UI and aren't exported
+	registry.Inc("fps", 1)
+
+	recorder := httptest.NewRecorder()
+	registry.Handler().ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	body, _ := io.ReadAll(recorder.Body)
+
+	expected := `# TYPE frame_time_ms summary
+frame_time_ms{quantile="0.5"} 4
+frame_time_ms{quantile="0.95"} 4
+frame_time_ms{quantile="0.99"} 4
+frame_time_ms_sum 4
+frame_time_ms_count 1
+# HELP messages_total Messages received
+# TYPE messages_total counter
+messages_total{type="1"} 1
+messages_total{type="2"} 3
+# TYPE players gauge
+players 2
+# TYPE rtt_ms gauge
+rtt_ms{player="a \"quoted\" name"} 12.5
+`
+	if string(body) != expected {
+		t.Fatalf("expected\n%s\nbut got\n%s", expected, body)
+	}
+	if !strings.HasPrefix(recorder.Header().Get("Content-Type"), "text/plain") {
+		t.Fatalf("unexpected content type %s", recorder.Header().Get("Content-Type"))
+	}
+}
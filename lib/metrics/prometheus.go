@@ -0,0 +1,79 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// exported percentiles of histograms
+var quantiles = []float64{0.5, 0.95, 0.99}
+
+// WritePrometheus writes the counters, gauges and histograms in the Prometheus text
+// exposition format. Histograms are written as summaries of their recent percentiles
+func (m *MetricsRegistry) WritePrometheus(w io.Writer) error {
+	all, help := m.sortedSeries()
+
+	bw := bufio.NewWriter(w)
+	previous := ""
+	for _, s := range all {
+		if s.name != previous {
+			if text, ok := help[s.name]; ok {
+				fmt.Fprintf(bw, "# HELP %s %s\n", s.name, escapeHelp(text))
+			}
+			fmt.Fprintf(bw, "# TYPE %s %s\n", s.name, s.kind)
+			previous = s.name
+		}
+
+		switch s.kind {
+		case KindCounter:
+			writeSample(bw, s.name, s.labels, s.counter.Value())
+		case KindGauge:
+			writeSample(bw, s.name, s.labels, s.gauge.Value())
+		case KindHistogram:
+			values := s.histogram.Percentiles(quantiles...)
+			for i, q := range quantiles {
+				labels := append(append([]Label{}, s.labels...), L("quantile", formatValue(q)))
+				writeSample(bw, s.name, labels, values[i])
+			}
+			writeSample(bw, s.name+"_sum", s.labels, s.histogram.Sum())
+			writeSample(bw, s.name+"_count", s.labels, float64(s.histogram.Count()))
+		}
+	}
+	return bw.Flush()
+}
+
+// Handler serves the metrics in the Prometheus text format
+func (m *MetricsRegistry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		m.WritePrometheus(w)
+	})
+}
+
+func writeSample(w io.Writer, name string, labels []Label, value float64) {
+	io.WriteString(w, name)
+	if len(labels) > 0 {
+		parts := make([]string, len(labels))
+		for i, label := range labels {
+			parts[i] = fmt.Sprintf("%s=\"%s\"", label.Name, escapeLabel(label.Value))
+		}
+		fmt.Fprintf(w, "{%s}", strings.Join(parts, ","))
+	}
+	fmt.Fprintf(w, " %s\n", formatValue(value))
+}
+
+func formatValue(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+func escapeLabel(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
+
+func escapeHelp(text string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(text)
+}
@@ -0,0 +1,121 @@
+package metrics
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+type Label struct {
+	Name  string
+	Value string
+}
+
+func L(name string, value any) Label {
+	return Label{Name: name, Value: fmt.Sprint(value)}
+}
+
+// series is one metric with a specific set of label values
+type series struct {
+	name   string
+	labels []Label
+	kind   Kind
+
+	counter   *Counter
+	gauge     *Gauge
+	histogram *Histogram
+}
+
+// Counter returns the counter with the name and labels, creating it if needed
+func (m *MetricsRegistry) Counter(name string, labels ...Label) *Counter {
+	return m.getOrCreate(name, labels, KindCounter).counter
+}
+
+// Gauge returns the gauge with the name and labels, creating it if needed
+func (m *MetricsRegistry) Gauge(name string, labels ...Label) *Gauge {
+	return m.getOrCreate(name, labels, KindGauge).gauge
+}
+
+// Histogram returns the histogram with the name and labels, creating it if needed
+func (m *MetricsRegistry) Histogram(name string, labels ...Label) *Histogram {
+	return m.getOrCreate(name, labels, KindHistogram).histogram
+}
+
+// SetHelp sets the description exported with every metric with the name
+func (m *MetricsRegistry) SetHelp(name string, help string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.help[name] = help
+}
+
+func (m *MetricsRegistry) getOrCreate(name string, labels []Label, kind Kind) *series {
+	labels = sortLabels(labels)
+	key := seriesKey(name, labels)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if s, ok := m.series[key]; ok {
+		if s.kind != kind {
+			panic(fmt.Sprintf("metric %s is a %s and can't be used as a %s", key, s.kind, kind))
+		}
+		return s
+	}
+	for _, s := range m.series {
+		if s.name == name && s.kind != kind {
+			panic(fmt.Sprintf("metric %s is a %s and can't be used as a %s", name, s.kind, kind))
+		}
+	}
+
+	s := &series{name: name, labels: labels, kind: kind}
+	switch kind {
+	case KindCounter:
+		s.counter = &Counter{}
+	case KindGauge:
+		s.gauge = &Gauge{}
+	case KindHistogram:
+		s.histogram = &Histogram{}
+	}
+	m.series[key] = s
+	return s
+}
+
+// sortedSeries returns a snapshot of every series ordered by name and then labels
+func (m *MetricsRegistry) sortedSeries() ([]*series, map[string]string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	all := make([]*series, 0, len(m.series))
+	for _, s := range m.series {
+		all = append(all, s)
+	}
+	sort.Slice(all, func(i, j int) bool {
+		if all[i].name != all[j].name {
+			return all[i].name < all[j].name
+		}
+		return seriesKey("", all[i].labels) < seriesKey("", all[j].labels)
+	})
+
+	help := map[string]string{}
+	for name, text := range m.help {
+		help[name] = text
+	}
+	return all, help
+}
+
+func sortLabels(labels []Label) []Label {
+	sorted := append([]Label{}, labels...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted
+}
+
+func seriesKey(name string, labels []Label) string {
+	var b strings.Builder
+	b.WriteString(name)
+	for _, label := range labels {
+		fmt.Fprintf(&b, ",%s=%q", label.Name, label.Value)
+	}
+	return b.String()
+}
@@ -0,0 +1,149 @@
+package metrics
+
+import (
+	"math"
+	"sort"
+	"sync"
+)
+
+const (
+	// histogramWindow is how many of the most recent observations percentiles are
+	// computed over
+	histogramWindow = 1000
+)
+
+type Kind int
+
+const (
+	KindCounter Kind = iota
+	KindGauge
+	KindHistogram
+)
+
+func (k Kind) String() string {
+	switch k {
+	case KindGauge:
+		return "gauge"
+	case KindHistogram:
+		return "summary"
+	}
+	return "counter"
+}
+
+// Counter is a value that only goes up, e.g. the number of messages received
+type Counter struct {
+	mu    sync.Mutex
+	value float64
+}
+
+func (c *Counter) Inc() {
+	c.Add(1)
+}
+
+// Add increases the counter. Negative values are ignored since counters never decrease
+func (c *Counter) Add(value float64) {
+	if value < 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.value += value
+}
+
+func (c *Counter) Value() float64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.value
+}
+
+// Gauge is a value that can go up and down, e.g. the number of connected players
+type Gauge struct {
+	mu    sync.Mutex
+	value float64
+}
+
+func (g *Gauge) Set(value float64) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.value = value
+}
+
+func (g *Gauge) Add(value float64) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.value += value
+}
+
+func (g *Gauge) Value() float64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.value
+}
+
+// Histogram tracks the distribution of observed values, e.g. frame times. Percentiles
+// are computed over the most recent observations so that they reflect current health
+// during long sessions, while the count and sum cover every observation
+type Histogram struct {
+	mu     sync.Mutex
+	window [histogramWindow]float64
+	cursor int
+	count  int
+	sum    float64
+}
+
+func (h *Histogram) Observe(value float64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.window[h.cursor] = value
+	h.cursor = (h.cursor + 1) % histogramWindow
+	h.count++
+	h.sum += value
+}
+
+// Percentile returns the value below which p percent of the recent observations fall,
+// with p in [0, 1]. Zero is returned if nothing has been observed
+func (h *Histogram) Percentile(p float64) float64 {
+	return h.Percentiles(p)[0]
+}
+
+// Percentiles returns multiple percentiles while only sorting the observations once
+func (h *Histogram) Percentiles(ps ...float64) []float64 {
+	h.mu.Lock()
+	n := h.count
+	if n > histogramWindow {
+		n = histogramWindow
+	}
+	values := make([]float64, n)
+	copy(values, h.window[:n])
+	h.mu.Unlock()
+
+	results := make([]float64, len(ps))
+	if n == 0 {
+		return results
+	}
+
+	sort.Float64s(values)
+	for i, p := range ps {
+		// nearest rank
+		rank := int(math.Ceil(p*float64(n))) - 1
+		if rank < 0 {
+			rank = 0
+		} else if rank >= n {
+			rank = n - 1
+		}
+		results[i] = values[rank]
+	}
+	return results
+}
+
+func (h *Histogram) Count() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.count
+}
+
+func (h *Histogram) Sum() float64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.sum
+}
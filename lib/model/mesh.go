@@ -10,6 +10,7 @@ import (
 
 type MeshChunk struct {
 	vao       uint32
+	buffers   []uint32
 	textureID *uint32
 	spec      *modelspec.MeshChunkSpecification
 }
@@ -93,6 +94,7 @@ func (m *MeshChunk) initializeOpenGLObjects() {
 	// lay out the position, normal, texture coords in a VBO
 	var vbo uint32
 	gl.GenBuffers(1, &vbo)
+	m.buffers = append(m.buffers, vbo)
 	gl.BindBuffer(gl.ARRAY_BUFFER, vbo)
 	gl.BufferData(gl.ARRAY_BUFFER, len(vertexAttributes)*4, gl.Ptr(vertexAttributes), gl.STATIC_DRAW)
 
@@ -108,6 +110,7 @@ func (m *MeshChunk) initializeOpenGLObjects() {
 	// lay out the joint IDs in a VBO
 	var vboJointIDs uint32
 	gl.GenBuffers(1, &vboJointIDs)
+	m.buffers = append(m.buffers, vboJointIDs)
 	gl.BindBuffer(gl.ARRAY_BUFFER, vboJointIDs)
 	gl.BufferData(gl.ARRAY_BUFFER, len(jointIDsAttribute)*4, gl.Ptr(jointIDsAttribute), gl.STATIC_DRAW)
 	gl.VertexAttribIPointer(3, int32(settings.AnimationMaxJointWeights), gl.INT, int32(settings.AnimationMaxJointWeights)*4, nil)
@@ -116,6 +119,7 @@ func (m *MeshChunk) initializeOpenGLObjects() {
 	// lay out the joint weights in a VBO
 	var vboJointWeights uint32
 	gl.GenBuffers(1, &vboJointWeights)
+	m.buffers = append(m.buffers, vboJointWeights)
 	gl.BindBuffer(gl.ARRAY_BUFFER, vboJointWeights)
 	gl.BufferData(gl.ARRAY_BUFFER, len(jointWeightsAttribute)*4, gl.Ptr(jointWeightsAttribute), gl.STATIC_DRAW)
 	gl.VertexAttribPointer(4, int32(settings.AnimationMaxJointWeights), gl.FLOAT, false, int32(settings.AnimationMaxJointWeights)*4, nil)
@@ -125,10 +129,23 @@ func (m *MeshChunk) initializeOpenGLObjects() {
 	// that form a triangle.
 	var ebo uint32
 	gl.GenBuffers(1, &ebo)
+	m.buffers = append(m.buffers, ebo)
 	gl.BindBuffer(gl.ELEMENT_ARRAY_BUFFER, ebo)
 	gl.BufferData(gl.ELEMENT_ARRAY_BUFFER, len(m.spec.VertexIndices)*4, gl.Ptr(m.spec.VertexIndices), gl.STATIC_DRAW)
 }
 
+// Delete frees the OpenGL objects backing the mesh chunk, after which it can no longer
+// be drawn
+func (m *MeshChunk) Delete() {
+	if m.vao == 0 {
+		return
+	}
+	gl.DeleteBuffers(int32(len(m.buffers)), &m.buffers[0])
+	gl.DeleteVertexArrays(1, &m.vao)
+	m.vao = 0
+	m.buffers = nil
+}
+
 func NewMesh(spec *modelspec.MeshSpecification) *Mesh {
 	var meshChunks []*MeshChunk
 	for _, mc := range spec.MeshChunks {
@@ -140,6 +157,13 @@ func NewMesh(spec *modelspec.MeshSpecification) *Mesh {
 	}
 }
 
+// Delete frees the OpenGL objects of every mesh chunk in the mesh
+func (m *Mesh) Delete() {
+	for _, meshChunk := range m.meshChunks {
+		meshChunk.Delete()
+	}
+}
+
 func (m *Mesh) MeshChunks() []*MeshChunk {
 	return m.meshChunks
 }
@@ -11,17 +11,29 @@ type Model struct {
 }
 
 func NewModel(spec *modelspec.ModelSpecification) *Model {
+	m := &Model{}
+	m.SetSpec(spec)
+	return m
+}
+
+// SetSpec rebuilds the model's meshes from the spec, e.g. when the model is reloaded.
+// The meshes built from the previous spec are deleted
+func (m *Model) SetSpec(spec *modelspec.ModelSpecification) {
 	var meshes []*Mesh
 	for _, ms := range spec.Meshes {
 		meshes = append(meshes, NewMesh(ms))
 	}
 
-	m := &Model{
-		modelSpec: spec,
-		meshes:    meshes,
+	for _, mesh := range m.meshes {
+		mesh.Delete()
 	}
 
-	return m
+	m.modelSpec = spec
+	m.meshes = meshes
+}
+
+func (m *Model) Spec() *modelspec.ModelSpecification {
+	return m.modelSpec
 }
 
 func (m *Model) RootJoint() *modelspec.JointSpec {
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/kkevinchou/kito/kito/settings"
+	"github.com/kkevinchou/kito/lib/logger"
 )
 
 type commandFrameFunc func() int
@@ -21,6 +22,9 @@ type Client struct {
 	messageQueue chan *Message
 
 	commandFrameFunc commandFrameFunc
+
+	// sendObserver is called with the body size of every message sent
+	sendObserver func(messageType int, size int)
 }
 
 func baseClient() *Client {
@@ -43,9 +47,13 @@ func (c *Client) SetCommandFrameFunction(f commandFrameFunc) {
 	c.commandFrameFunc = f
 }
 
+func (c *Client) SetSendObserver(f func(messageType int, size int)) {
+	c.sendObserver = f
+}
+
 func Connect(host, port, connectionType string) (*Client, int, error) {
 	address := fmt.Sprintf("%s:%s", host, port)
-	fmt.Println("connecting to " + address + " via " + connectionType)
+	log.Info("connecting", logger.F("address", address), logger.F("network", connectionType))
 
 	dialFunc := net.Dial
 	if settings.LatencyInjection > 0 {
@@ -104,6 +112,10 @@ func (c *Client) SendMessage(messageType int, messageBody any) error {
 		}
 	}
 
+	if c.sendObserver != nil {
+		c.sendObserver(messageType, len(bodyBytes))
+	}
+
 	msg := &Message{
 		SenderID:     c.id,
 		CommandFrame: c.commandFrameFunc(),
@@ -2,12 +2,15 @@ package network
 
 import (
 	"encoding/json"
-	"fmt"
 	"io"
 	"net"
 	"time"
+
+	"github.com/kkevinchou/kito/lib/logger"
 )
 
+var log = logger.New("network")
+
 const (
 	messageQueueBufferSize        = 1024
 	incomingConnectionsBufferSize = 1024
@@ -32,8 +35,7 @@ func queueIncomingMessages(conn net.Conn, messageQueue chan *Message) {
 				continue
 			}
 
-			fmt.Println("error reading incoming message:", err.Error())
-			fmt.Println("closing connection")
+			log.Info("closing connection after failing to read a message", logger.Err(err))
 			return
 		}
 
@@ -42,7 +44,7 @@ func queueIncomingMessages(conn net.Conn, messageQueue chan *Message) {
 		select {
 		case messageQueue <- &message:
 		default:
-			fmt.Println("message queue full")
+			log.Warn("message queue full, dropping message", logger.F("type", message.MessageType))
 		}
 	}
 }
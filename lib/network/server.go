@@ -2,11 +2,11 @@ package network
 
 import (
 	"encoding/json"
-	"fmt"
 	"net"
 	"sync"
 
 	"github.com/kkevinchou/kito/kito/settings"
+	"github.com/kkevinchou/kito/lib/logger"
 )
 
 type Server struct {
@@ -37,7 +37,7 @@ func (s *Server) Start() error {
 	if err != nil {
 		return err
 	}
-	fmt.Println("listening on " + s.host + ":" + s.port)
+	log.Info("listening", logger.F("address", s.host+":"+s.port))
 
 	if settings.LatencyInjection > 0 {
 		listener = WrapListener(listener, settings.LatencyInjection)
@@ -48,7 +48,7 @@ func (s *Server) Start() error {
 		for {
 			conn, err := listener.Accept()
 			if err != nil {
-				fmt.Println("error accepting a connection on the listener:", err.Error())
+				log.Error("failed to accept a connection", logger.Err(err))
 				continue
 			}
 
@@ -56,13 +56,13 @@ func (s *Server) Start() error {
 
 			message, err := s.createAcceptMessage(id)
 			if err != nil {
-				fmt.Println(err)
+				log.Error("failed to create accept message", logger.PlayerID(id), logger.Err(err))
 				continue
 			}
 
 			sendMessage(conn, message)
 			if err != nil {
-				fmt.Println("error sending accept message:", err.Error())
+				log.Error("failed to send accept message", logger.PlayerID(id), logger.Err(err))
 				continue
 			}
 
@@ -117,7 +117,6 @@ func (s *Server) createAcceptMessage(id int) (*Message, error) {
 	}
 	bodyBytes, err := json.Marshal(acceptMessage)
 	if err != nil {
-		fmt.Println("error marshaling accept message:", err.Error())
 		return nil, err
 	}
 	return &Message{
@@ -0,0 +1,84 @@
+package physics
+
+import (
+	"math"
+	"time"
+
+	"github.com/go-gl/mathgl/mgl64"
+)
+
+// Motion is how a kinematic body moves over time. Where a body is only depends on how
+// much time has passed so that anything simulating the same time puts it in the same place
+type Motion interface {
+	PositionAt(elapsed time.Duration) mgl64.Vec3
+	OrientationAt(elapsed time.Duration) mgl64.Quat
+}
+
+// Path moves through its waypoints at a constant speed. Once it reaches the last waypoint
+// it heads back through the waypoints in reverse, or straight back to the first waypoint
+// when it loops
+type Path struct {
+	Waypoints []mgl64.Vec3
+	Speed     float64
+	Loop      bool
+}
+
+func (p Path) PositionAt(elapsed time.Duration) mgl64.Vec3 {
+	if len(p.Waypoints) == 0 {
+		return mgl64.Vec3{}
+	}
+
+	waypoints := p.Waypoints
+	if p.Loop {
+		waypoints = append(append([]mgl64.Vec3{}, waypoints...), waypoints[0])
+	} else {
+		for i := len(p.Waypoints) - 2; i >= 0; i-- {
+			waypoints = append(waypoints, p.Waypoints[i])
+		}
+	}
+
+	var length float64
+	for i := 1; i < len(waypoints); i++ {
+		length += waypoints[i].Sub(waypoints[i-1]).Len()
+	}
+	if length == 0 {
+		return waypoints[0]
+	}
+
+	distance := math.Mod(p.Speed*elapsed.Seconds(), length)
+	if distance < 0 {
+		distance += length
+	}
+
+	for i := 1; i < len(waypoints); i++ {
+		segment := waypoints[i].Sub(waypoints[i-1])
+		segmentLength := segment.Len()
+		if distance <= segmentLength && segmentLength > 0 {
+			return waypoints[i-1].Add(segment.Mul(distance / segmentLength))
+		}
+		distance -= segmentLength
+	}
+	return waypoints[len(waypoints)-1]
+}
+
+// OrientationAt is always the identity, paths don't turn bodies that follow them
+func (p Path) OrientationAt(elapsed time.Duration) mgl64.Quat {
+	return mgl64.QuatIdent()
+}
+
+// Spin turns a body about an axis at a constant speed in radians per second while the
+// motion it wraps moves it
+type Spin struct {
+	Motion Motion
+	Axis   mgl64.Vec3
+	Speed  float64
+}
+
+func (s Spin) PositionAt(elapsed time.Duration) mgl64.Vec3 {
+	return s.Motion.PositionAt(elapsed)
+}
+
+func (s Spin) OrientationAt(elapsed time.Duration) mgl64.Quat {
+	rotation := mgl64.QuatRotate(s.Speed*elapsed.Seconds(), s.Axis.Normalize())
+	return rotation.Mul(s.Motion.OrientationAt(elapsed))
+}
@@ -1,24 +1,180 @@
 package physics
 
-import "github.com/go-gl/mathgl/mgl64"
+import (
+	"time"
 
-type ColliderType string
+	"github.com/go-gl/mathgl/mgl64"
+)
 
 const (
-	ColliderTypeCapsule ColliderType = "CAPSULE"
-	ColliderTypeBox     ColliderType = "BOX"
-	ColliderTypeSphere  ColliderType = "SPHERE"
-	// RigidBodyTypeCapsule RigidBodyType = "CAPSULE"
+	// damping is applied per second to keep bodies from moving forever from numerical
+	// error and to help them settle enough to fall asleep
+	linearDamping  float64 = 0.05
+	angularDamping float64 = 0.5
 )
 
-type Capsule struct {
-	Position mgl64.Vec3
+// Config holds the scale dependent parameters of the simulation
+type Config struct {
+	// Iterations is how many times the contacts are solved. More iterations are more
+	// accurate for stacks and bodies touching several things at once
+	Iterations int
+
+	// bodies that hit each other slower than RestitutionThreshold don't bounce, which
+	// stops bodies resting on each other from jittering
+	RestitutionThreshold float64
+
+	// bodies that move slower than the sleep speeds for TimeToSleep fall asleep
+	SleepSpeed        float64
+	SleepAngularSpeed float64
+	TimeToSleep       time.Duration
+}
+
+// Body is a rigid body. Bodies with an inverse mass of zero can't be moved by contacts,
+// e.g. the scene or characters whose movement is controlled directly
+type Body struct {
+	Position    mgl64.Vec3
+	Orientation mgl64.Quat
+
+	// CenterOfMass is the offset from the body's position to its center of mass in its
+	// local space. Bodies rotate around their center of mass
+	CenterOfMass mgl64.Vec3
+
+	Velocity        mgl64.Vec3
+	AngularVelocity mgl64.Vec3
+
+	InverseMass float64
+
+	// InverseInertia is the inverse of the diagonal of the body's inertia tensor in its
+	// local space
+	InverseInertia mgl64.Vec3
+
+	// Restitution is how much speed the body keeps when it bounces, from 0 to 1
+	Restitution float64
+	Friction    float64
+
+	// sleeping bodies aren't integrated and act as if they're immovable until they're
+	// woken up by something hitting them
+	Sleeping bool
+	IdleTime time.Duration
+}
+
+// NewBody creates a body with the mass and the diagonal of its inertia tensor
+func NewBody(mass float64, inertia mgl64.Vec3, restitution, friction float64) *Body {
+	return &Body{
+		Orientation:    mgl64.QuatIdent(),
+		InverseMass:    inverse(mass),
+		InverseInertia: mgl64.Vec3{inverse(inertia[0]), inverse(inertia[1]), inverse(inertia[2])},
+		Restitution:    restitution,
+		Friction:       friction,
+	}
+}
+
+// NewKinematicBody creates an immovable body that is moving with the velocity, used to
+// represent the things rigid bodies hit that aren't rigid bodies themselves
+func NewKinematicBody(position, velocity mgl64.Vec3, friction float64) *Body {
+	return &Body{
+		Position:    position,
+		Orientation: mgl64.QuatIdent(),
+		Velocity:    velocity,
+		Friction:    friction,
+	}
+}
+
+// BoxInertia returns the diagonal of the inertia tensor of a solid box
+func BoxInertia(mass float64, halfExtents mgl64.Vec3) mgl64.Vec3 {
+	x2 := 4 * halfExtents[0] * halfExtents[0]
+	y2 := 4 * halfExtents[1] * halfExtents[1]
+	z2 := 4 * halfExtents[2] * halfExtents[2]
+	return mgl64.Vec3{mass * (y2 + z2) / 12, mass * (x2 + z2) / 12, mass * (x2 + y2) / 12}
+}
+
+// SphereInertia returns the diagonal of the inertia tensor of a solid sphere
+func SphereInertia(mass float64, radius float64) mgl64.Vec3 {
+	i := 2 * mass * radius * radius / 5
+	return mgl64.Vec3{i, i, i}
+}
+
+func (b *Body) Static() bool {
+	return b.InverseMass == 0
 }
 
-type Sphere struct {
-	Position mgl64.Vec3
-	Radius   float64
+// WorldCenterOfMass returns the body's center of mass in world space
+func (b *Body) WorldCenterOfMass() mgl64.Vec3 {
+	return b.Position.Add(b.Orientation.Rotate(b.CenterOfMass))
+}
+
+// inverseInertiaWorld returns the inverse inertia tensor rotated into world space
+func (b *Body) inverseInertiaWorld() mgl64.Mat3 {
+	rotation := b.Orientation.Mat4().Mat3()
+	return rotation.Mul3(mgl64.Diag3(b.InverseInertia)).Mul3(rotation.Transpose())
+}
+
+// VelocityAt returns the velocity of the point of the body at the world position
+func (b *Body) VelocityAt(point mgl64.Vec3) mgl64.Vec3 {
+	return b.Velocity.Add(b.AngularVelocity.Cross(point.Sub(b.WorldCenterOfMass())))
+}
+
+// ApplyImpulse applies an impulse at the world position, waking the body up
+func (b *Body) ApplyImpulse(impulse, point mgl64.Vec3) {
+	if b.Static() {
+		return
+	}
+	b.Wake()
+	b.applyImpulse(impulse, point.Sub(b.WorldCenterOfMass()))
+}
+
+func (b *Body) applyImpulse(impulse, r mgl64.Vec3) {
+	b.Velocity = b.Velocity.Add(impulse.Mul(b.InverseMass))
+	b.AngularVelocity = b.AngularVelocity.Add(b.inverseInertiaWorld().Mul3x1(r.Cross(impulse)))
+}
+
+func (b *Body) Wake() {
+	b.Sleeping = false
+	b.IdleTime = 0
+}
+
+// Integrate moves and rotates the body by its velocities after accelerating it
+func (b *Body) Integrate(delta time.Duration, acceleration mgl64.Vec3) {
+	if b.Static() || b.Sleeping {
+		return
+	}
+
+	dt := delta.Seconds()
+	b.Velocity = b.Velocity.Add(acceleration.Mul(dt)).Mul(1 / (1 + dt*linearDamping))
+	b.AngularVelocity = b.AngularVelocity.Mul(1 / (1 + dt*angularDamping))
+
+	centerOfMass := b.WorldCenterOfMass().Add(b.Velocity.Mul(dt))
+
+	// dq/dt = 0.5 * w * q
+	spin := mgl64.Quat{W: 0, V: b.AngularVelocity}.Mul(b.Orientation).Scale(0.5 * dt)
+	b.Orientation = b.Orientation.Add(spin).Normalize()
+
+	// the body rotated around its center of mass, which moves its position
+	b.Position = centerOfMass.Sub(b.Orientation.Rotate(b.CenterOfMass))
+}
+
+// UpdateSleep puts the body to sleep once it has been moving slowly for long enough
+func (b *Body) UpdateSleep(delta time.Duration, config Config) {
+	if b.Static() || b.Sleeping {
+		return
+	}
+
+	if b.Velocity.Len() > config.SleepSpeed || b.AngularVelocity.Len() > config.SleepAngularSpeed {
+		b.IdleTime = 0
+		return
+	}
+
+	b.IdleTime += delta
+	if b.IdleTime >= config.TimeToSleep {
+		b.Sleeping = true
+		b.Velocity = mgl64.Vec3{}
+		b.AngularVelocity = mgl64.Vec3{}
+	}
 }
 
-type Box struct {
+func inverse(value float64) float64 {
+	if value == 0 {
+		return 0
+	}
+	return 1 / value
 }
@@ -0,0 +1,187 @@
+package physics_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/kkevinchou/kito/lib/physics"
+)
+
+const delta = 16 * time.Millisecond
+
+var (
+	gravity = mgl64.Vec3{0, -10, 0}
+	config  = physics.Config{
+		Iterations:           10,
+		RestitutionThreshold: 1,
+		SleepSpeed:           0.05,
+		SleepAngularSpeed:    0.05,
+		TimeToSleep:          500 * time.Millisecond,
+	}
+	halfExtents = mgl64.Vec3{1, 1, 1}
+)
+
+// step simulates a box over the ground at y = 0, treating the corners of the box that
+// are below the ground as contacts
+func step(box *physics.Body, ground *physics.Body) {
+	box.UpdateSleep(delta, config)
+	box.Integrate(delta, gravity)
+
+	var contacts []*physics.Contact
+	var depth float64
+	for i := 0; i < 8; i++ {
+		corner := mgl64.Vec3{-halfExtents[0], -halfExtents[1], -halfExtents[2]}
+		for j := 0; j < 3; j++ {
+			if i&(1<<j) != 0 {
+				corner[j] = halfExtents[j]
+			}
+		}
+
+		point := box.Position.Add(box.Orientation.Rotate(corner))
+		if point.Y() < 0 {
+			contacts = append(contacts, &physics.Contact{A: box, B: ground, Point: point, Normal: mgl64.Vec3{0, 1, 0}})
+			depth = math.Max(depth, -point.Y())
+		}
+	}
+
+	physics.Solve(contacts, config)
+	box.Position[1] += depth
+}
+
+func TestRestingBoxSleeps(t *testing.T) {
+	box := physics.NewBody(1, physics.BoxInertia(1, halfExtents), 0.2, 0.5)
+	box.Position = mgl64.Vec3{0, 3, 0}
+	box.Orientation = mgl64.QuatRotate(0.1, mgl64.Vec3{0, 0, 1})
+	ground := physics.NewKinematicBody(mgl64.Vec3{}, mgl64.Vec3{}, 0.5)
+
+	for i := 0; i < 500; i++ {
+		step(box, ground)
+	}
+
+	if !box.Sleeping {
+		t.Fatalf("expected the box to fall asleep but it's moving at %v", box.Velocity)
+	}
+	if math.Abs(box.Position.Y()-1) > 0.05 {
+		t.Fatalf("expected the box to rest on the ground but it's at %v", box.Position)
+	}
+	if up := box.Orientation.Rotate(mgl64.Vec3{0, 1, 0}); up.Y() < 0.999 {
+		t.Fatalf("expected the box to settle onto a face but its up is %v", up)
+	}
+}
+
+func TestRestitution(t *testing.T) {
+	testCases := []struct {
+		name     string
+		speed    float64
+		expected float64
+	}{
+		{"bounce", 10, 5},
+		{"below the restitution threshold", 0.5, 0},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ball := physics.NewBody(1, physics.SphereInertia(1, 1), 0.5, 0)
+			ball.Position = mgl64.Vec3{0, 1, 0}
+			ball.Velocity = mgl64.Vec3{0, -testCase.speed, 0}
+			ground := physics.NewKinematicBody(mgl64.Vec3{}, mgl64.Vec3{}, 0)
+
+			physics.Solve([]*physics.Contact{{A: ball, B: ground, Point: mgl64.Vec3{}, Normal: mgl64.Vec3{0, 1, 0}}}, config)
+			if math.Abs(ball.Velocity.Y()-testCase.expected) > 1e-6 {
+				t.Fatalf("expected the ball to leave at %f but got %v", testCase.expected, ball.Velocity)
+			}
+			if ball.AngularVelocity.Len() > 1e-6 {
+				t.Fatalf("expected a head on bounce to not spin the ball but got %v", ball.AngularVelocity)
+			}
+		})
+	}
+}
+
+func TestFriction(t *testing.T) {
+	for _, friction := range []float64{0, 0.5} {
+		box := physics.NewBody(1, physics.BoxInertia(1, halfExtents), 0, friction)
+		box.Position = mgl64.Vec3{0, 1, 0}
+		box.Velocity = mgl64.Vec3{5, 0, 0}
+		ground := physics.NewKinematicBody(mgl64.Vec3{}, mgl64.Vec3{}, friction)
+
+		for i := 0; i < 100; i++ {
+			step(box, ground)
+		}
+
+		if friction == 0 && box.Velocity.X() < 4.5 {
+			t.Fatalf("expected a frictionless box to keep sliding but it slowed to %v", box.Velocity)
+		} else if friction > 0 && math.Abs(box.Velocity.X()) > 0.05 {
+			t.Fatalf("expected friction to stop the box but it's moving at %v", box.Velocity)
+		}
+	}
+}
+
+func TestApplyImpulse(t *testing.T) {
+	box := physics.NewBody(2, physics.BoxInertia(2, halfExtents), 0, 0)
+	box.ApplyImpulse(mgl64.Vec3{2, 0, 0}, mgl64.Vec3{})
+	if !box.Velocity.ApproxEqual(mgl64.Vec3{1, 0, 0}) || box.AngularVelocity.Len() > 1e-9 {
+		t.Fatalf("expected an impulse through the center of mass to only move the box but got %v and %v", box.Velocity, box.AngularVelocity)
+	}
+
+	// pushing the top of the box along x spins it around -z
+	box = physics.NewBody(2, physics.BoxInertia(2, halfExtents), 0, 0)
+	box.ApplyImpulse(mgl64.Vec3{2, 0, 0}, mgl64.Vec3{0, 1, 0})
+	if !box.Velocity.ApproxEqual(mgl64.Vec3{1, 0, 0}) || box.AngularVelocity.Z() >= 0 {
+		t.Fatalf("expected an off center impulse to spin the box around -z but got %v", box.AngularVelocity)
+	}
+
+	// the box spins around its center of mass rather than its position
+	box = physics.NewBody(2, physics.BoxInertia(2, halfExtents), 0, 0)
+	box.CenterOfMass = mgl64.Vec3{0, 1, 0}
+	box.ApplyImpulse(mgl64.Vec3{2, 0, 0}, mgl64.Vec3{0, 1, 0})
+	if box.AngularVelocity.Len() > 1e-9 {
+		t.Fatalf("expected an impulse through the offset center of mass to not spin the box but got %v", box.AngularVelocity)
+	}
+
+	box.AngularVelocity = mgl64.Vec3{0, 0, 1}
+	box.Integrate(delta, mgl64.Vec3{})
+	if centerOfMass := box.WorldCenterOfMass(); !centerOfMass.ApproxEqualThreshold(mgl64.Vec3{delta.Seconds(), 1, 0}, 1e-3) {
+		t.Fatalf("expected the center of mass to only move by the velocity but it's at %v", centerOfMass)
+	}
+}
+
+func TestPath(t *testing.T) {
+	waypoints := []mgl64.Vec3{{0, 0, 0}, {10, 0, 0}, {10, 10, 0}}
+
+	testCases := []struct {
+		name     string
+		path     physics.Path
+		elapsed  time.Duration
+		expected mgl64.Vec3
+	}{
+		{"start", physics.Path{Waypoints: waypoints, Speed: 5}, 0, mgl64.Vec3{0, 0, 0}},
+		{"along the first segment", physics.Path{Waypoints: waypoints, Speed: 5}, time.Second, mgl64.Vec3{5, 0, 0}},
+		{"along the second segment", physics.Path{Waypoints: waypoints, Speed: 5}, 3 * time.Second, mgl64.Vec3{10, 5, 0}},
+		{"heads back from the last waypoint", physics.Path{Waypoints: waypoints, Speed: 5}, 5 * time.Second, mgl64.Vec3{10, 5, 0}},
+		{"repeats", physics.Path{Waypoints: waypoints, Speed: 5}, 9 * time.Second, mgl64.Vec3{5, 0, 0}},
+		{"loops back to the first waypoint", physics.Path{Waypoints: waypoints, Speed: 5, Loop: true}, 5 * time.Second, mgl64.Vec3{10 - 5/math.Sqrt2, 10 - 5/math.Sqrt2, 0}},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			if position := testCase.path.PositionAt(testCase.elapsed); !position.ApproxEqualThreshold(testCase.expected, 1e-9) {
+				t.Fatalf("expected the path to be at %v but got %v", testCase.expected, position)
+			}
+		})
+	}
+}
+
+func TestSpin(t *testing.T) {
+	path := physics.Path{Waypoints: []mgl64.Vec3{{0, 0, 0}, {10, 0, 0}}, Speed: 5}
+	spin := physics.Spin{Motion: path, Axis: mgl64.Vec3{0, 2, 0}, Speed: math.Pi / 2}
+
+	if position := spin.PositionAt(time.Second); position != path.PositionAt(time.Second) {
+		t.Fatalf("expected the spin to follow the path but it's at %v", position)
+	}
+	forward := spin.OrientationAt(time.Second).Rotate(mgl64.Vec3{1, 0, 0})
+	if !forward.ApproxEqualThreshold(mgl64.Vec3{0, 0, -1}, 1e-6) {
+		t.Fatalf("expected a quarter turn about y after a second but forward is %v", forward)
+	}
+}
@@ -0,0 +1,159 @@
+package physics
+
+import (
+	"math"
+
+	"github.com/go-gl/mathgl/mgl64"
+)
+
+// Contact is a point where two bodies touch
+type Contact struct {
+	A *Body
+	B *Body
+
+	Point mgl64.Vec3
+
+	// Normal points from B towards A
+	Normal mgl64.Vec3
+
+	rA, rB   mgl64.Vec3
+	tangents [2]mgl64.Vec3
+
+	normalMass  float64
+	tangentMass [2]float64
+	bounce      float64
+	friction    float64
+
+	// impulses accumulated over the iterations, clamped so that contacts only ever
+	// push bodies apart and friction never exceeds what the normal impulse allows
+	normalImpulse  float64
+	tangentImpulse [2]float64
+}
+
+// Solve applies impulses to the bodies of the contacts so that they stop moving into
+// each other, bounce according to their restitution and slide according to their
+// friction. Contacts are solved one at a time over several iterations so that the
+// impulses of contacts that affect each other converge. Only velocities are changed,
+// separating the bodies is left to the caller
+func Solve(contacts []*Contact, config Config) {
+	wake(contacts, config)
+
+	for _, contact := range contacts {
+		contact.prepare(config)
+	}
+
+	for i := 0; i < config.Iterations; i++ {
+		for _, contact := range contacts {
+			contact.solve()
+		}
+	}
+}
+
+// wake wakes sleeping bodies that are hit by something moving faster than a body can
+// while asleep. Bodies woken up can wake up the sleeping bodies they touch in turn
+func wake(contacts []*Contact, config Config) {
+	for changed := true; changed; {
+		changed = false
+		for _, contact := range contacts {
+			a, b := contact.A, contact.B
+			if a.Sleeping == b.Sleeping {
+				continue
+			}
+
+			sleeping, other := a, b
+			if b.Sleeping {
+				sleeping, other = b, a
+			}
+
+			if other.VelocityAt(contact.Point).Len() <= config.SleepSpeed {
+				continue
+			}
+			sleeping.Wake()
+			changed = true
+		}
+	}
+}
+
+func (c *Contact) inverseMass(body *Body) float64 {
+	if body.Sleeping {
+		return 0
+	}
+	return body.InverseMass
+}
+
+// effectiveMass returns the mass the contact has to push against along the direction
+func (c *Contact) effectiveMass(direction mgl64.Vec3) float64 {
+	k := c.inverseMass(c.A) + c.inverseMass(c.B)
+	if !c.A.Sleeping {
+		rnA := c.rA.Cross(direction)
+		k += c.A.inverseInertiaWorld().Mul3x1(rnA).Cross(c.rA).Dot(direction)
+	}
+	if !c.B.Sleeping {
+		rnB := c.rB.Cross(direction)
+		k += c.B.inverseInertiaWorld().Mul3x1(rnB).Cross(c.rB).Dot(direction)
+	}
+	return inverse(k)
+}
+
+func (c *Contact) prepare(config Config) {
+	c.rA = c.Point.Sub(c.A.WorldCenterOfMass())
+	c.rB = c.Point.Sub(c.B.WorldCenterOfMass())
+	c.tangents = tangents(c.Normal)
+
+	c.normalMass = c.effectiveMass(c.Normal)
+	c.tangentMass[0] = c.effectiveMass(c.tangents[0])
+	c.tangentMass[1] = c.effectiveMass(c.tangents[1])
+
+	c.friction = math.Sqrt(c.A.Friction * c.B.Friction)
+
+	c.bounce = 0
+	approachSpeed := -c.relativeVelocity().Dot(c.Normal)
+	if approachSpeed > config.RestitutionThreshold {
+		c.bounce = math.Max(c.A.Restitution, c.B.Restitution) * approachSpeed
+	}
+
+	c.normalImpulse = 0
+	c.tangentImpulse = [2]float64{}
+}
+
+func (c *Contact) relativeVelocity() mgl64.Vec3 {
+	return c.A.VelocityAt(c.Point).Sub(c.B.VelocityAt(c.Point))
+}
+
+func (c *Contact) solve() {
+	// friction is solved first since the normal impulse matters more
+	for i, tangent := range c.tangents {
+		lambda := -c.relativeVelocity().Dot(tangent) * c.tangentMass[i]
+
+		maxFriction := c.friction * c.normalImpulse
+		previous := c.tangentImpulse[i]
+		c.tangentImpulse[i] = mgl64.Clamp(previous+lambda, -maxFriction, maxFriction)
+		c.apply(tangent.Mul(c.tangentImpulse[i] - previous))
+	}
+
+	lambda := (-c.relativeVelocity().Dot(c.Normal) + c.bounce) * c.normalMass
+	previous := c.normalImpulse
+	c.normalImpulse = math.Max(previous+lambda, 0)
+	c.apply(c.Normal.Mul(c.normalImpulse - previous))
+}
+
+// apply pushes A along the impulse and B against it
+func (c *Contact) apply(impulse mgl64.Vec3) {
+	if !c.A.Sleeping {
+		c.A.applyImpulse(impulse, c.rA)
+	}
+	if !c.B.Sleeping {
+		c.B.applyImpulse(impulse.Mul(-1), c.rB)
+	}
+}
+
+// tangents returns two directions perpendicular to the normal and each other
+func tangents(normal mgl64.Vec3) [2]mgl64.Vec3 {
+	var t1 mgl64.Vec3
+	if math.Abs(normal.X()) < 0.57735 {
+		t1 = normal.Cross(mgl64.Vec3{1, 0, 0}).Normalize()
+	} else {
+		t1 = normal.Cross(mgl64.Vec3{0, 1, 0}).Normalize()
+	}
+	return [2]mgl64.Vec3{t1, normal.Cross(t1)}
+}
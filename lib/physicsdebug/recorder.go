@@ -0,0 +1,229 @@
+// Package physicsdebug records what collision detection and resolution did each command
+// frame so that it can be drawn and inspected after the fact
+package physicsdebug
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/kkevinchou/kito/lib/collision/collider"
+)
+
+const (
+	// defaultFrameCount is how many frames the global recorder keeps, about two seconds
+	// of command frames
+	defaultFrameCount = 120
+)
+
+// GlobalRecorder records the physics of the game. It starts disabled since every contact
+// of every frame is kept while recording
+var GlobalRecorder = New(defaultFrameCount)
+
+// Contact is a collision found while resolving collisions. The separating vector moves
+// EntityID out of SourceEntityID
+type Contact struct {
+	EntityID       int
+	SourceEntityID int
+
+	// Iteration is the pass of collision resolution that found the contact, later passes
+	// find the collisions that resolving earlier ones caused
+	Iteration int
+
+	Point              mgl64.Vec3
+	Normal             mgl64.Vec3
+	SeparatingVector   mgl64.Vec3
+	SeparatingDistance float64
+
+	// Triangle is the triangle that was hit in world space when SourceEntityID has a
+	// trimesh collider
+	Triangle *collider.Triangle
+}
+
+// Sweep is a shape that was swept through the world and stopped on what it hit
+type Sweep struct {
+	EntityID    int
+	HitEntityID int
+
+	Start        mgl64.Vec3
+	Displacement mgl64.Vec3
+	TimeOfImpact float64
+
+	Point  mgl64.Vec3
+	Normal mgl64.Vec3
+
+	// Triangle is the triangle that was hit in world space when HitEntityID has a
+	// trimesh collider
+	Triangle *collider.Triangle
+}
+
+// Resolution is how many times an entity had its collisions resolved. Entities that hit
+// the max are left alone for the rest of the frame
+type Resolution struct {
+	EntityID int
+	Count    int
+	Maxed    bool
+}
+
+// Frame holds everything recorded during a command frame
+type Frame struct {
+	CommandFrame int
+
+	// Pairs are the pairs of entities the broadphase found could be colliding
+	Pairs       [][2]int
+	Contacts    []Contact
+	Sweeps      []Sweep
+	Resolutions []Resolution
+}
+
+// Recorder records physics into a ring buffer of frames. Recording is safe from multiple
+// goroutines
+type Recorder struct {
+	mu      sync.Mutex
+	enabled bool
+
+	frames  []Frame
+	next    int
+	count   int
+	current int
+}
+
+func New(frameCount int) *Recorder {
+	return &Recorder{
+		frames:  make([]Frame, frameCount),
+		current: -1,
+	}
+}
+
+func (r *Recorder) SetEnabled(enabled bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.enabled = enabled
+	if !enabled {
+		r.current = -1
+	}
+}
+
+func (r *Recorder) Enabled() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.enabled
+}
+
+// BeginFrame starts recording a new frame, replacing the oldest frame once the buffer
+// is full
+func (r *Recorder) BeginFrame(commandFrame int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.enabled {
+		return
+	}
+
+	r.frames[r.next] = Frame{CommandFrame: commandFrame}
+	r.current = r.next
+	r.next = (r.next + 1) % len(r.frames)
+	if r.count < len(r.frames) {
+		r.count++
+	}
+}
+
+// record adds to the current frame, if there is one
+func (r *Recorder) record(add func(frame *Frame)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.enabled || r.current < 0 {
+		return
+	}
+	add(&r.frames[r.current])
+}
+
+func (r *Recorder) RecordPair(entityID int, otherEntityID int) {
+	r.record(func(frame *Frame) {
+		frame.Pairs = append(frame.Pairs, [2]int{entityID, otherEntityID})
+	})
+}
+
+func (r *Recorder) RecordContact(contact Contact) {
+	r.record(func(frame *Frame) {
+		frame.Contacts = append(frame.Contacts, contact)
+	})
+}
+
+func (r *Recorder) RecordSweep(sweep Sweep) {
+	r.record(func(frame *Frame) {
+		frame.Sweeps = append(frame.Sweeps, sweep)
+	})
+}
+
+// RecordResolutions records how many times each entity had its collisions resolved, and
+// which of them hit the max
+func (r *Recorder) RecordResolutions(counts map[int]int, maxed map[int]bool) {
+	ids := make([]int, 0, len(counts))
+	for id := range counts {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	r.record(func(frame *Frame) {
+		for _, id := range ids {
+			frame.Resolutions = append(frame.Resolutions, Resolution{EntityID: id, Count: counts[id], Maxed: maxed[id]})
+		}
+	})
+}
+
+// Frames returns copies of the recorded frames from oldest to newest
+func (r *Recorder) Frames() []Frame {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	frames := make([]Frame, 0, r.count)
+	start := (r.next - r.count + len(r.frames)) % len(r.frames)
+	for i := 0; i < r.count; i++ {
+		frame := r.frames[(start+i)%len(r.frames)]
+		frame.Pairs = append([][2]int{}, frame.Pairs...)
+		frame.Contacts = append([]Contact{}, frame.Contacts...)
+		frame.Sweeps = append([]Sweep{}, frame.Sweeps...)
+		frame.Resolutions = append([]Resolution{}, frame.Resolutions...)
+		frames = append(frames, frame)
+	}
+	return frames
+}
+
+// Clear drops every recorded frame
+func (r *Recorder) Clear() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.frames {
+		r.frames[i] = Frame{}
+	}
+	r.next = 0
+	r.count = 0
+	r.current = -1
+}
+
+// Enabled returns whether the global recorder is recording, so that callers can skip
+// building what they would record
+func Enabled() bool {
+	return GlobalRecorder.Enabled()
+}
+
+// BeginFrame starts a frame on the global recorder
+func BeginFrame(commandFrame int) {
+	GlobalRecorder.BeginFrame(commandFrame)
+}
+
+func RecordPair(entityID int, otherEntityID int) {
+	GlobalRecorder.RecordPair(entityID, otherEntityID)
+}
+
+func RecordContact(contact Contact) {
+	GlobalRecorder.RecordContact(contact)
+}
+
+func RecordSweep(sweep Sweep) {
+	GlobalRecorder.RecordSweep(sweep)
+}
+
+func RecordResolutions(counts map[int]int, maxed map[int]bool) {
+	GlobalRecorder.RecordResolutions(counts, maxed)
+}
@@ -0,0 +1,56 @@
+package physicsdebug_test
+
+import (
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/kkevinchou/kito/lib/physicsdebug"
+)
+
+func TestRecorder(t *testing.T) {
+	r := physicsdebug.New(2)
+
+	// nothing is recorded until the recorder is enabled and a frame has begun
+	r.BeginFrame(1)
+	r.SetEnabled(true)
+	r.RecordPair(1, 2)
+	if frames := r.Frames(); len(frames) != 0 {
+		t.Fatalf("expected no frames but got %+v", frames)
+	}
+
+	for commandFrame := 2; commandFrame <= 4; commandFrame++ {
+		r.BeginFrame(commandFrame)
+		r.RecordPair(1, commandFrame)
+		r.RecordContact(physicsdebug.Contact{EntityID: 1, SourceEntityID: commandFrame, Normal: mgl64.Vec3{0, 1, 0}})
+		r.RecordSweep(physicsdebug.Sweep{EntityID: 1, HitEntityID: commandFrame, TimeOfImpact: 0.5})
+		r.RecordResolutions(map[int]int{commandFrame: 4, 1: 1}, map[int]bool{commandFrame: true})
+	}
+
+	frames := r.Frames()
+	if len(frames) != 2 {
+		t.Fatalf("expected the 2 newest frames but got %d", len(frames))
+	}
+	for i, frame := range frames {
+		commandFrame := i + 3
+		if frame.CommandFrame != commandFrame {
+			t.Fatalf("expected frame %d to be command frame %d but got %d", i, commandFrame, frame.CommandFrame)
+		}
+		if len(frame.Pairs) != 1 || frame.Pairs[0] != [2]int{1, commandFrame} {
+			t.Fatalf("expected the pair recorded on command frame %d but got %v", commandFrame, frame.Pairs)
+		}
+		if len(frame.Contacts) != 1 || len(frame.Sweeps) != 1 {
+			t.Fatalf("expected a contact and a sweep but got %+v", frame)
+		}
+
+		expected := []physicsdebug.Resolution{{EntityID: 1, Count: 1}, {EntityID: commandFrame, Count: 4, Maxed: true}}
+		if len(frame.Resolutions) != len(expected) || frame.Resolutions[0] != expected[0] || frame.Resolutions[1] != expected[1] {
+			t.Fatalf("expected resolutions ordered by entity id %v but got %v", expected, frame.Resolutions)
+		}
+	}
+
+	r.SetEnabled(false)
+	r.RecordPair(1, 5)
+	if frames := r.Frames(); len(frames[1].Pairs) != 1 {
+		t.Fatalf("expected nothing to be recorded once disabled but got %v", frames[1].Pairs)
+	}
+}
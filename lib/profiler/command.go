@@ -0,0 +1,47 @@
+package profiler
+
+import (
+	"fmt"
+	"time"
+)
+
+// HandleCommand executes a console command against the global profiler and returns its
+// output. Supported commands are:
+//
+//	start
+//	stop
+//	clear
+//	export [path]
+func HandleCommand(args []string) (string, error) {
+	if len(args) == 0 {
+		return "", fmt.Errorf("expected one of start, stop, clear, export")
+	}
+
+	switch args[0] {
+	case "start":
+		GlobalProfiler.SetEnabled(true)
+		return "profiler started", nil
+	case "stop":
+		GlobalProfiler.SetEnabled(false)
+		return "profiler stopped", nil
+	case "clear":
+		GlobalProfiler.Clear()
+		return "profiler cleared", nil
+	case "export":
+		path := DefaultExportPath()
+		if len(args) > 1 {
+			path = args[1]
+		}
+		if err := GlobalProfiler.ExportChromeTrace(path); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("exported profile to %s", path), nil
+	}
+
+	return "", fmt.Errorf("unknown profiler command %s", args[0])
+}
+
+// DefaultExportPath is a timestamped trace file in the working directory
+func DefaultExportPath() string {
+	return fmt.Sprintf("profile_%s.json", time.Now().Format("20060102_150405"))
+}
@@ -0,0 +1,261 @@
+package profiler
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// defaultFrameCount is how many frames the global profiler keeps, about ten seconds
+	// of command frames
+	defaultFrameCount = 600
+)
+
+// GlobalProfiler records the scopes of the game. It starts enabled since recording is
+// cheap and spikes can't be captured after the fact
+var GlobalProfiler = New(defaultFrameCount)
+
+// Scope is a timed section of a frame. Start and End are relative to the start of the
+// frame. Scopes on the same track nest, Depth is how many scopes enclosed it when it
+// began
+type Scope struct {
+	Name  string
+	Track int
+	Depth int
+	Start time.Duration
+	End   time.Duration
+}
+
+func (s Scope) Duration() time.Duration {
+	return s.End - s.Start
+}
+
+// Frame holds the scopes recorded from the start of a command frame until the start of
+// the next one, which includes any rendering done in between
+type Frame struct {
+	CommandFrame int
+	Start        time.Time
+	Scopes       []Scope
+}
+
+// Duration is the time from the start of the frame to the end of its last scope
+func (f Frame) Duration() time.Duration {
+	var duration time.Duration
+	for _, scope := range f.Scopes {
+		if scope.End > duration {
+			duration = scope.End
+		}
+	}
+	return duration
+}
+
+// Profiler records nested scopes into a ring buffer of frames. Scopes can be recorded
+// from multiple goroutines, each goroutine that runs concurrently with others should
+// begin its outermost scope on its own track and begin the scopes inside of it from
+// that scope's timer so that they stay on its track
+type Profiler struct {
+	mu sync.Mutex
+
+	// enabled is read without the lock so that beginning scopes is free while the
+	// profiler is disabled
+	enabled int32
+
+	frames  []Frame
+	next    int
+	count   int
+	current int
+
+	// open is the number of scopes currently open on each track
+	open map[int]int
+}
+
+func New(frameCount int) *Profiler {
+	return &Profiler{
+		enabled: 1,
+		frames:  make([]Frame, frameCount),
+		current: -1,
+		open:    map[int]int{},
+	}
+}
+
+func (p *Profiler) SetEnabled(enabled bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if enabled {
+		atomic.StoreInt32(&p.enabled, 1)
+		return
+	}
+	atomic.StoreInt32(&p.enabled, 0)
+	p.current = -1
+	p.open = map[int]int{}
+}
+
+func (p *Profiler) Enabled() bool {
+	return atomic.LoadInt32(&p.enabled) == 1
+}
+
+// BeginFrame starts recording a new frame, replacing the oldest frame once the buffer
+// is full
+func (p *Profiler) BeginFrame(commandFrame int) {
+	if !p.Enabled() {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.frames[p.next] = Frame{CommandFrame: commandFrame, Start: time.Now()}
+	p.current = p.next
+	p.next = (p.next + 1) % len(p.frames)
+	if p.count < len(p.frames) {
+		p.count++
+	}
+	p.open = map[int]int{}
+}
+
+// Begin starts a scope on the main track. Scopes begun this way nest under whatever is
+// open on the main track, so it's only meant for the main goroutine. The returned timer
+// must be ended, typically
+//
+//	defer profiler.Begin("render").End()
+func (p *Profiler) Begin(name string) Timer {
+	return p.BeginTrack(name, 0)
+}
+
+// BeginTrack starts a scope on a track, nested under whatever is open on it
+func (p *Profiler) BeginTrack(name string, track int) Timer {
+	if !p.Enabled() {
+		return Timer{}
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.begin(name, track, p.open[track])
+}
+
+// begin starts a scope at the depth on the track, it must be called with the lock held
+func (p *Profiler) begin(name string, track int, depth int) Timer {
+	if p.current < 0 {
+		return Timer{}
+	}
+
+	p.open[track]++
+	return Timer{
+		profiler: p,
+		frame:    p.current,
+		name:     name,
+		track:    track,
+		depth:    depth,
+		start:    time.Now(),
+	}
+}
+
+// Timer is an open scope. The zero value is a scope that isn't being recorded, ending it
+// or beginning scopes inside of it does nothing
+type Timer struct {
+	profiler *Profiler
+	frame    int
+	name     string
+	track    int
+	depth    int
+	start    time.Time
+}
+
+// Begin starts a scope inside of the timer's scope, on the same track. Code that can run
+// concurrently is handed the timer of the scope it runs in and begins its scopes from
+// it, typically
+//
+//	defer timer.Begin("collision").End()
+func (t Timer) Begin(name string) Timer {
+	p := t.profiler
+	if p == nil || !p.Enabled() {
+		return Timer{}
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if t.frame != p.current {
+		return Timer{}
+	}
+	return p.begin(name, t.track, t.depth+1)
+}
+
+// End records the scope. Scopes that end after their frame was replaced are dropped
+func (t Timer) End() {
+	if t.profiler == nil {
+		return
+	}
+	end := time.Now()
+
+	p := t.profiler
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.open[t.track] > 0 {
+		p.open[t.track]--
+	}
+	if t.frame != p.current {
+		return
+	}
+
+	frame := &p.frames[t.frame]
+	frame.Scopes = append(frame.Scopes, Scope{
+		Name:  t.name,
+		Track: t.track,
+		Depth: t.depth,
+		Start: t.start.Sub(frame.Start),
+		End:   end.Sub(frame.Start),
+	})
+}
+
+// Frames returns copies of the recorded frames from oldest to newest with their scopes
+// sorted by track and start time
+func (p *Profiler) Frames() []Frame {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	frames := make([]Frame, 0, p.count)
+	start := (p.next - p.count + len(p.frames)) % len(p.frames)
+	for i := 0; i < p.count; i++ {
+		frame := p.frames[(start+i)%len(p.frames)]
+		frame.Scopes = append([]Scope{}, frame.Scopes...)
+		sort.SliceStable(frame.Scopes, func(i, j int) bool {
+			a, b := frame.Scopes[i], frame.Scopes[j]
+			if a.Track != b.Track {
+				return a.Track < b.Track
+			}
+			if a.Start != b.Start {
+				return a.Start < b.Start
+			}
+			return a.Depth < b.Depth
+		})
+		frames = append(frames, frame)
+	}
+	return frames
+}
+
+// Clear drops every recorded frame
+func (p *Profiler) Clear() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for i := range p.frames {
+		p.frames[i] = Frame{}
+	}
+	p.next = 0
+	p.count = 0
+	p.current = -1
+	p.open = map[int]int{}
+}
+
+// BeginFrame starts a frame on the global profiler
+func BeginFrame(commandFrame int) {
+	GlobalProfiler.BeginFrame(commandFrame)
+}
+
+// Begin starts a scope on the main track of the global profiler
+func Begin(name string) Timer {
+	return GlobalProfiler.Begin(name)
+}
+
+// BeginTrack starts a scope on a track of the global profiler
+func BeginTrack(name string, track int) Timer {
+	return GlobalProfiler.BeginTrack(name, track)
+}
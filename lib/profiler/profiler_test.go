@@ -0,0 +1,205 @@
+package profiler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"sync"
+	"testing"
+
+	"github.com/kkevinchou/kito/lib/profiler"
+)
+
+func TestNestedScopes(t *testing.T) {
+	p := profiler.New(4)
+
+	// scopes outside of a frame are ignored
+	p.Begin("ignored").End()
+
+	p.BeginFrame(1)
+	outer := p.Begin("command frame")
+	inner := p.Begin("collision")
+	p.Begin("collision.resolve").End()
+	inner.End()
+	p.BeginTrack("parallel system", 1).End()
+	outer.End()
+
+	frames := p.Frames()
+	if len(frames) != 1 {
+		t.Fatalf("expected 1 frame but got %d", len(frames))
+	}
+
+	expected := []struct {
+		name  string
+		track int
+		depth int
+	}{
+		{"command frame", 0, 0},
+		{"collision", 0, 1},
+		{"collision.resolve", 0, 2},
+		{"parallel system", 1, 0},
+	}
+	scopes := frames[0].Scopes
+	if len(scopes) != len(expected) {
+		t.Fatalf("expected %d scopes but got %+v", len(expected), scopes)
+	}
+	for i, e := range expected {
+		scope := scopes[i]
+		if scope.Name != e.name || scope.Track != e.track || scope.Depth != e.depth {
+			t.Fatalf("expected scope %d to be %+v but got %+v", i, e, scope)
+		}
+		if scope.Start < 0 || scope.End < scope.Start {
+			t.Fatalf("unexpected times for scope %+v", scope)
+		}
+	}
+	if frames[0].Duration() < scopes[0].End {
+		t.Fatalf("expected the frame to last until its last scope ended")
+	}
+}
+
+func TestChildScopesStayOnTheirParentsTrack(t *testing.T) {
+	p := profiler.New(4)
+	p.BeginFrame(1)
+	frame := p.Begin("command frame")
+
+	// systems running in parallel begin nested scopes from the scope they're handed
+	var wg sync.WaitGroup
+	for track := 1; track <= 4; track++ {
+		wg.Add(1)
+		go func(track int) {
+			defer wg.Done()
+			system := p.BeginTrack("system", track)
+			collision := system.Begin("collision")
+			collision.Begin("collision.resolve").End()
+			collision.End()
+			system.End()
+		}(track)
+	}
+	wg.Wait()
+
+	frame.Begin("render").End()
+	frame.End()
+
+	scopesPerTrack := map[int]int{}
+	for _, scope := range p.Frames()[0].Scopes {
+		scopesPerTrack[scope.Track]++
+		expectedDepth := map[string]int{"command frame": 0, "render": 1, "system": 0, "collision": 1, "collision.resolve": 2}[scope.Name]
+		if scope.Depth != expectedDepth {
+			t.Fatalf("expected %s at depth %d but got %+v", scope.Name, expectedDepth, scope)
+		}
+		if (scope.Name == "command frame" || scope.Name == "render") != (scope.Track == 0) {
+			t.Fatalf("expected only the main goroutine's scopes on the main track but got %+v", scope)
+		}
+	}
+	for track := 1; track <= 4; track++ {
+		if scopesPerTrack[track] != 3 {
+			t.Fatalf("expected 3 scopes on each system's track but got %v", scopesPerTrack)
+		}
+	}
+}
+
+func TestDisabledTimers(t *testing.T) {
+	p := profiler.New(4)
+	p.BeginFrame(1)
+	p.SetEnabled(false)
+
+	timer := p.Begin("system")
+	if timer != (profiler.Timer{}) {
+		t.Fatalf("expected a no-op timer while disabled but got %+v", timer)
+	}
+	if child := timer.Begin("collision"); child != (profiler.Timer{}) {
+		t.Fatalf("expected a no-op timer from a no-op timer but got %+v", child)
+	}
+	timer.End()
+
+	// scopes begun before the profiler was disabled have nothing to nest under
+	p.SetEnabled(true)
+	p.BeginFrame(2)
+	timer = p.Begin("system")
+	p.SetEnabled(false)
+	if child := timer.Begin("collision"); child != (profiler.Timer{}) {
+		t.Fatalf("expected a no-op timer once disabled but got %+v", child)
+	}
+}
+
+func TestRingBuffer(t *testing.T) {
+	p := profiler.New(3)
+	for i := 1; i <= 5; i++ {
+		p.BeginFrame(i)
+		p.Begin("system").End()
+	}
+
+	frames := p.Frames()
+	if len(frames) != 3 {
+		t.Fatalf("expected 3 frames but got %d", len(frames))
+	}
+	for i, frame := range frames {
+		if frame.CommandFrame != i+3 {
+			t.Fatalf("expected frames 3 to 5 in order but got %d at %d", frame.CommandFrame, i)
+		}
+		if len(frame.Scopes) != 1 {
+			t.Fatalf("expected 1 scope in frame %d but got %d", frame.CommandFrame, len(frame.Scopes))
+		}
+	}
+
+	// scopes that outlive their frame are dropped rather than attached to the next one
+	timer := p.Begin("late")
+	p.BeginFrame(6)
+	timer.End()
+	frames = p.Frames()
+	if len(frames[len(frames)-1].Scopes) != 0 {
+		t.Fatal("expected the late scope to be dropped")
+	}
+
+	p.SetEnabled(false)
+	p.BeginFrame(7)
+	p.Begin("disabled").End()
+	frames = p.Frames()
+	if frames[len(frames)-1].CommandFrame != 6 {
+		t.Fatal("expected no frames to be recorded while disabled")
+	}
+
+	p.Clear()
+	if len(p.Frames()) != 0 {
+		t.Fatal("expected no frames after clearing")
+	}
+}
+
+func TestChromeTrace(t *testing.T) {
+	p := profiler.New(4)
+	p.BeginFrame(10)
+	p.Begin("command frame").End()
+	p.BeginTrack("parallel system", 2).End()
+
+	var buffer bytes.Buffer
+	if err := profiler.WriteChromeTrace(&buffer, p.Frames()); err != nil {
+		t.Fatal(err)
+	}
+
+	var trace struct {
+		TraceEvents []struct {
+			Name  string         `json:"name"`
+			Phase string         `json:"ph"`
+			TID   int            `json:"tid"`
+			Args  map[string]any `json:"args"`
+		} `json:"traceEvents"`
+	}
+	if err := json.Unmarshal(buffer.Bytes(), &trace); err != nil {
+		t.Fatal(err)
+	}
+
+	phases := map[string]int{}
+	for _, event := range trace.TraceEvents {
+		phases[event.Phase]++
+		if event.Phase == "X" {
+			if event.Args["cf"] != float64(10) {
+				t.Fatalf("expected scope %s to carry its command frame", event.Name)
+			}
+			if event.Name == "parallel system" && event.TID != 2 {
+				t.Fatalf("expected the parallel system on thread 2 but got %d", event.TID)
+			}
+		}
+	}
+	if phases["i"] != 1 || phases["X"] != 2 || phases["M"] != 2 {
+		t.Fatalf("unexpected events %v", phases)
+	}
+}
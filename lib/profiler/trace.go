@@ -0,0 +1,99 @@
+package profiler
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// traceEvent is an event in the Chrome trace event format, which can be loaded in
+// chrome://tracing or https://ui.perfetto.dev
+type traceEvent struct {
+	Name     string         `json:"name"`
+	Category string         `json:"cat,omitempty"`
+	Phase    string         `json:"ph"`
+	Scope    string         `json:"s,omitempty"`
+	Time     float64        `json:"ts"`
+	Duration float64        `json:"dur,omitempty"`
+	PID      int            `json:"pid"`
+	TID      int            `json:"tid"`
+	Args     map[string]any `json:"args,omitempty"`
+}
+
+type trace struct {
+	TraceEvents     []traceEvent `json:"traceEvents"`
+	DisplayTimeUnit string       `json:"displayTimeUnit"`
+}
+
+// WriteChromeTrace writes the frames in the Chrome trace event format. Every scope is a
+// complete event on the thread of its track and the start of every frame is marked with
+// an instant event
+func WriteChromeTrace(w io.Writer, frames []Frame) error {
+	t := trace{TraceEvents: []traceEvent{}, DisplayTimeUnit: "ms"}
+	if len(frames) == 0 {
+		return json.NewEncoder(w).Encode(t)
+	}
+
+	origin := frames[0].Start
+	tracks := map[int]bool{}
+	for _, frame := range frames {
+		frameStart := microseconds(frame.Start.Sub(origin))
+		t.TraceEvents = append(t.TraceEvents, traceEvent{
+			Name:  fmt.Sprintf("command frame %d", frame.CommandFrame),
+			Phase: "i",
+			Scope: "g",
+			Time:  frameStart,
+			PID:   1,
+			Args:  map[string]any{"cf": frame.CommandFrame},
+		})
+
+		for _, scope := range frame.Scopes {
+			tracks[scope.Track] = true
+			t.TraceEvents = append(t.TraceEvents, traceEvent{
+				Name:     scope.Name,
+				Category: "kito",
+				Phase:    "X",
+				Time:     frameStart + microseconds(scope.Start),
+				Duration: microseconds(scope.Duration()),
+				PID:      1,
+				TID:      scope.Track,
+				Args:     map[string]any{"cf": frame.CommandFrame},
+			})
+		}
+	}
+
+	for track := range tracks {
+		name := "main"
+		if track > 0 {
+			name = fmt.Sprintf("parallel %d", track)
+		}
+		t.TraceEvents = append(t.TraceEvents, traceEvent{
+			Name:  "thread_name",
+			Phase: "M",
+			PID:   1,
+			TID:   track,
+			Args:  map[string]any{"name": name},
+		})
+	}
+
+	return json.NewEncoder(w).Encode(t)
+}
+
+// ExportChromeTrace writes the recorded frames of the profiler to a file
+func (p *Profiler) ExportChromeTrace(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := WriteChromeTrace(file, p.Frames()); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+func microseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Microsecond)
+}
@@ -3,6 +3,7 @@ package shaders
 import (
 	"fmt"
 	"io/ioutil"
+	"path/filepath"
 	"strings"
 
 	"github.com/go-gl/gl/v4.1-core/gl"
@@ -23,6 +24,15 @@ type ShaderManager struct {
 	vertexShaders   map[string]uint32
 	fragmentShaders map[string]uint32
 	shaderPrograms  map[string]*ShaderProgram
+
+	// programSources tracks the shaders each program was linked from so that programs
+	// can be relinked when one of their shaders is reloaded
+	programSources map[string]programSource
+}
+
+type programSource struct {
+	vertexShader   string
+	fragmentShader string
 }
 
 func NewShaderManager(directory string) *ShaderManager {
@@ -30,12 +40,25 @@ func NewShaderManager(directory string) *ShaderManager {
 		vertexShaders:   loadShaders(directory, gl.VERTEX_SHADER, vertexShaderExtension),
 		fragmentShaders: loadShaders(directory, gl.FRAGMENT_SHADER, fragmentShaderExtension),
 		shaderPrograms:  map[string]*ShaderProgram{},
+		programSources:  map[string]programSource{},
 	}
 
 	return &shaderManager
 }
 
 func (s *ShaderManager) CompileShaderProgram(name, vertexShader, fragmentShader string) error {
+	shaderProgram, err := s.linkProgram(vertexShader, fragmentShader)
+	if err != nil {
+		return err
+	}
+
+	s.shaderPrograms[name] = &ShaderProgram{ID: shaderProgram}
+	s.programSources[name] = programSource{vertexShader: vertexShader, fragmentShader: fragmentShader}
+
+	return nil
+}
+
+func (s *ShaderManager) linkProgram(vertexShader, fragmentShader string) (uint32, error) {
 	shaderProgram := gl.CreateProgram()
 	gl.AttachShader(shaderProgram, s.vertexShaders[vertexShader])
 	gl.AttachShader(shaderProgram, s.fragmentShaders[fragmentShader])
@@ -48,14 +71,71 @@ func (s *ShaderManager) CompileShaderProgram(name, vertexShader, fragmentShader
 		gl.GetProgramiv(shaderProgram, gl.INFO_LOG_LENGTH, &logLength)
 		log := strings.Repeat("\x00", int(logLength+1))
 		gl.GetProgramInfoLog(shaderProgram, logLength, nil, gl.Str(log))
-		return fmt.Errorf("failed to link shader program:\n%s", log)
+		gl.DeleteProgram(shaderProgram)
+		return 0, fmt.Errorf("failed to link shader program:\n%s", log)
 	}
 
 	// gl.DeleteShader(vertexShader)
 	// gl.DeleteShader(fragmentShader)
 
-	s.shaderPrograms[name] = &ShaderProgram{ID: shaderProgram}
+	return shaderProgram, nil
+}
 
+// ReloadShader recompiles the shader at the path and relinks every program that uses
+// it. Programs are updated in place so that callers holding on to a program pick up the
+// change. If the shader fails to compile or a program fails to link, the previous
+// shader and programs are kept
+func (s *ShaderManager) ReloadShader(path string) error {
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+
+	var shaderType uint32
+	var shaders map[string]uint32
+	switch filepath.Ext(path) {
+	case vertexShaderExtension:
+		shaderType, shaders = gl.VERTEX_SHADER, s.vertexShaders
+	case fragmentShaderExtension:
+		shaderType, shaders = gl.FRAGMENT_SHADER, s.fragmentShaders
+	default:
+		return fmt.Errorf("%s is not a shader", path)
+	}
+
+	shader, err := compileShader(path, shaderType)
+	if err != nil {
+		return err
+	}
+
+	previous, hadPrevious := shaders[name]
+	shaders[name] = shader
+
+	relinked := map[string]uint32{}
+	for programName, source := range s.programSources {
+		if (shaderType == gl.VERTEX_SHADER && source.vertexShader != name) || (shaderType == gl.FRAGMENT_SHADER && source.fragmentShader != name) {
+			continue
+		}
+
+		program, err := s.linkProgram(source.vertexShader, source.fragmentShader)
+		if err != nil {
+			for _, program := range relinked {
+				gl.DeleteProgram(program)
+			}
+			gl.DeleteShader(shader)
+			if hadPrevious {
+				shaders[name] = previous
+			} else {
+				delete(shaders, name)
+			}
+			return fmt.Errorf("failed to relink %s: %w", programName, err)
+		}
+		relinked[programName] = program
+	}
+
+	for programName, program := range relinked {
+		gl.DeleteProgram(s.shaderPrograms[programName].ID)
+		s.shaderPrograms[programName].ID = program
+	}
+	if hadPrevious {
+		gl.DeleteShader(previous)
+	}
 	return nil
 }
 
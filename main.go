@@ -1,19 +1,21 @@
 package main
 
 import (
-	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
-	"io"
-	"log"
+	"io/fs"
 	"net/http"
 	"os"
 	"runtime"
-	"strings"
 
 	_ "net/http/pprof"
 
 	"github.com/kkevinchou/kito/kito"
+	"github.com/kkevinchou/kito/kito/config"
 	"github.com/kkevinchou/kito/kito/settings"
+	"github.com/kkevinchou/kito/lib/logger"
+	"github.com/kkevinchou/kito/lib/metrics"
 	"github.com/veandco/go-sdl2/sdl"
 )
 
@@ -26,13 +28,16 @@ func init() {
 }
 
 const (
-	modeLocal  string = "LOCAL"
-	modeClient string = "CLIENT"
-	modeServer string = "SERVER"
+	// log files are rotated once they reach logFileMaxBytes, keeping logFileCount old files
+	logFileMaxBytes = 10 << 20
+	logFileCount    = 5
 )
 
+var log = logger.New("main")
+
 type Game interface {
 	Start()
+	MetricsRegistry() *metrics.MetricsRegistry
 }
 
 func main() {
@@ -40,74 +45,63 @@ func main() {
 	ballast := make([]byte, 1<<34)
 	_ = ballast
 
-	configFile, err := os.Open("config.json")
-	if err != nil {
-		fmt.Printf("failed to load config.json, using defaults: %s\n", err)
-	} else {
-		configBytes, err := io.ReadAll(configFile)
-		if err != nil {
-			fmt.Println(err)
-			return
-		}
+	// settings are layered from lowest to highest precedence as defaults, the config
+	// file, KITO_ environment variables and then command line flags
+	configLayers, err := config.ParseLayers(os.Args[1:], os.Environ())
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	} else if err != nil {
+		// the flag package has already printed the error and usage
+		os.Exit(2)
+	}
+	if _, err := os.Stat(configLayers.Path); errors.Is(err, fs.ErrNotExist) {
+		log.Info("config file not found, using defaults", logger.F("path", configLayers.Path))
+	}
 
-		if err = configFile.Close(); err != nil {
-			fmt.Println(err)
-			return
-		}
+	configSettings, err := configLayers.Resolve()
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	config.Apply(configSettings)
+	mode := configSettings.Mode
 
-		var configSettings Config
-		err = json.Unmarshal(configBytes, &configSettings)
+	if configSettings.LogFile != "" {
+		fileSink, err := logger.NewRotatingFileSink(configSettings.LogFile, logFileMaxBytes, logFileCount)
 		if err != nil {
 			fmt.Println(err)
-			return
-		}
-
-		loadConfig(configSettings)
-	}
-
-	var mode string = modeClient
-	if len(os.Args) > 1 {
-		mode = strings.ToUpper(os.Args[1])
-		if mode != modeLocal && mode != modeClient && mode != modeServer {
-			panic(fmt.Sprintf("unexpected mode %s", mode))
+			os.Exit(1)
 		}
+		defer fileSink.Close()
+		logger.AddSink(fileSink)
 	}
 
 	if settings.PProfEnabled {
 		go func() {
-			if mode == modeClient {
-				log.Println(http.ListenAndServe(fmt.Sprintf("localhost:%d", settings.PProfClientPort), nil))
+			if mode == "client" {
+				log.Error("pprof server stopped", logger.Err(http.ListenAndServe(fmt.Sprintf("localhost:%d", settings.PProfClientPort), nil)))
 			} else {
-				log.Println(http.ListenAndServe(fmt.Sprintf("localhost:%d", settings.PProfServerPort), nil))
+				log.Error("pprof server stopped", logger.Err(http.ListenAndServe(fmt.Sprintf("localhost:%d", settings.PProfServerPort), nil)))
 			}
 		}()
 	}
 
-	fmt.Println("starting game on mode:", mode)
+	log.Info("starting game", logger.F("mode", mode))
 	var game Game
-	if mode == modeClient {
-		game = kito.NewClientGame("_assets", "shaders")
-	} else if mode == modeServer {
-		game = kito.NewServerGame("_assets")
+	if mode == "client" {
+		game = kito.NewClientGame(configSettings.AssetsDirectory, configSettings.ShaderDirectory, configLayers)
+	} else {
+		game = kito.NewServerGame(configSettings.AssetsDirectory, configLayers)
+	}
+
+	if mode == "server" && settings.MetricsEnabled {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", game.MetricsRegistry().Handler())
+		go func() {
+			log.Error("metrics server stopped", logger.Err(http.ListenAndServe(fmt.Sprintf("localhost:%d", settings.MetricsPort), mux)))
+		}()
 	}
 
 	game.Start()
 	sdl.Quit()
 }
-
-func loadConfig(c Config) {
-	settings.Host = c.ServerIP
-	settings.Port = c.ServerPort
-	settings.Width = c.Width
-	settings.Height = c.Height
-	settings.Fullscreen = c.Fullscreen
-}
-
-type Config struct {
-	ServerIP   string
-	ServerPort int
-	Mode       string
-	Width      int
-	Height     int
-	Fullscreen bool
-}